@@ -1,6 +1,10 @@
 package v1alpha1
 
-import "strings"
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 const (
 	Force  = "force"
@@ -16,7 +20,8 @@ type FalconAdvanced struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Sensor Update Policy",order=1
 	UpdatePolicy *string `json:"updatePolicy,omitempty"`
 
-	// AutoUpdate determines whether to install new versions of the sensor as they become available. Defaults to "off" and is ignored if FalconAPI is not set.
+	// AutoUpdate determines whether to install new versions of the sensor as they become available. It is ignored if FalconAPI is not set.
+	// When unset, new versions are not tracked. Setting this to "off" tracks new versions and reports them in status.availableUpdate without installing them.
 	// Setting this to "force" causes the reconciler to run on every polling cycle, even if a new sensor version is not available.
 	// Setting it to "normal" only reconciles when a new version is detected.
 	// +kubebuilder:validation:Enum=off;normal;force
@@ -44,6 +49,11 @@ func (advanced FalconAdvanced) IsAutoUpdating() bool {
 	return *advanced.AutoUpdate != "off"
 }
 
+// IsTrackingUpdates reports whether new sensor versions are tracked. Tracking is opt-in, by setting AutoUpdate or an UpdatePolicy.
+func (advanced FalconAdvanced) IsTrackingUpdates() bool {
+	return advanced.AutoUpdate != nil || advanced.HasUpdatePolicy()
+}
+
 func (advanced FalconAdvanced) IsAutoUpdatingForced() bool {
 	if advanced.AutoUpdate == nil {
 		return false
@@ -51,3 +61,30 @@ func (advanced FalconAdvanced) IsAutoUpdatingForced() bool {
 
 	return *advanced.AutoUpdate == "force"
 }
+
+// FalconAvailableUpdate describes a sensor version that has been detected but not yet deployed.
+type FalconAvailableUpdate struct {
+	// Version is the image tag of the detected sensor version.
+	Version string `json:"version"`
+
+	// Source of the detected version: latest or policy.
+	// +kubebuilder:validation:Enum=latest;policy
+	Source string `json:"source"`
+
+	// DetectedAt is the time the version was first detected.
+	DetectedAt metav1.Time `json:"detectedAt"`
+}
+
+const (
+	AvailableUpdateSourceLatest = "latest"
+	AvailableUpdateSourcePolicy = "policy"
+)
+
+// AvailableUpdateSource returns where the sensor version of a pending update comes from.
+func (advanced FalconAdvanced) AvailableUpdateSource() string {
+	if advanced.HasUpdatePolicy() {
+		return AvailableUpdateSourcePolicy
+	}
+
+	return AvailableUpdateSourceLatest
+}
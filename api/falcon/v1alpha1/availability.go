@@ -0,0 +1,50 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// FalconAvailability configures how the pods of a Deployment are spread across the cluster and protected against voluntary disruptions, such as node drains.
+type FalconAvailability struct {
+	// PodDisruptionBudget configures the PodDisruptionBudget of the Deployment.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Pod Disruption Budget",order=1
+	PodDisruptionBudget *FalconPodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+
+	// TopologySpreadConstraints replace the default constraint, which spreads the pods across nodes.
+	// Constraints without a label selector select the pods of the Deployment.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Topology Spread Constraints",order=2
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// PodAntiAffinity configures the pod anti-affinity of the Deployment. Terms without a label selector select the pods of the Deployment.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Pod Anti-Affinity",order=3
+	PodAntiAffinity *corev1.PodAntiAffinity `json:"podAntiAffinity,omitempty"`
+}
+
+// FalconPodDisruptionBudget configures the PodDisruptionBudget of a Deployment.
+// +kubebuilder:validation:XValidation:rule="!(has(self.minAvailable) && has(self.maxUnavailable))",message="minAvailable and maxUnavailable are mutually exclusive"
+type FalconPodDisruptionBudget struct {
+	// Enabled determines whether to create the PodDisruptionBudget. By default, it is only created when the Deployment runs more than one replica,
+	// as a PodDisruptionBudget for a single replica either blocks node drains or does not protect the pod.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable Pod Disruption Budget",order=1,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// MinAvailable is the number or percentage of pods that must remain available during a disruption.
+	// +kubebuilder:validation:XIntOrString
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Minimum Available Pods",order=2
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the number or percentage of pods that may be unavailable during a disruption. Defaults to 1 when minAvailable is not set.
+	// +kubebuilder:validation:XIntOrString
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Maximum Unavailable Pods",order=3
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// PodDisruptionBudgetEnabled reports whether a Deployment with the given number of replicas gets a PodDisruptionBudget
+func (availability FalconAvailability) PodDisruptionBudgetEnabled(replicas int32) bool {
+	if availability.PodDisruptionBudget != nil && availability.PodDisruptionBudget.Enabled != nil {
+		return *availability.PodDisruptionBudget.Enabled
+	}
+
+	return replicas > 1
+}
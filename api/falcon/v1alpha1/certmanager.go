@@ -0,0 +1,27 @@
+package v1alpha1
+
+// FalconCertManager configures cert-manager to issue a serving certificate in place of the self-signed certificate generated by the operator.
+// The operator falls back to the self-signed certificate when cert-manager is not installed in the cluster.
+type FalconCertManager struct {
+	// IssuerRef references the cert-manager issuer that signs the certificate.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="cert-manager Issuer Reference",order=1
+	IssuerRef FalconIssuerReference `json:"issuerRef"`
+}
+
+// FalconIssuerReference references a cert-manager Issuer, ClusterIssuer or external issuer.
+type FalconIssuerReference struct {
+	// Name of the issuer.
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Issuer Name",order=1
+	Name string `json:"name"`
+
+	// Kind of the issuer. An Issuer must be in the install namespace.
+	// +kubebuilder:default:=Issuer
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Issuer Kind",order=2
+	Kind string `json:"kind,omitempty"`
+
+	// Group of the issuer. Set it for external issuers.
+	// +kubebuilder:default:=cert-manager.io
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Issuer Group",order=3
+	Group string `json:"group,omitempty"`
+}
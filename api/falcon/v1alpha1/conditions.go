@@ -5,18 +5,25 @@ import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 const (
 	// Following strings are condition types
 
-	ConditionUnknown         string = "Unknown"
-	ConditionSuccess         string = "Success"
-	ConditionFailed          string = "Failed"
-	ConditionPending         string = "Pending"
-	ConditionImageReady      string = "ImageReady"
-	ConditionConfigMapReady  string = "ConfigMapReady"
-	ConditionDaemonSetReady  string = "DaemonSetReady"
-	ConditionDeploymentReady string = "DeploymentReady"
-	ConditionServiceReady    string = "ServiceReady"
-	ConditionRouteReady      string = "RouteReady"
-	ConditionSecretReady     string = "SecretReady"
-	ConditionWebhookReady    string = "WebhookReady"
+	ConditionUnknown           string = "Unknown"
+	ConditionSuccess           string = "Success"
+	ConditionFailed            string = "Failed"
+	ConditionPending           string = "Pending"
+	ConditionImageReady        string = "ImageReady"
+	ConditionConfigMapReady    string = "ConfigMapReady"
+	ConditionDaemonSetReady    string = "DaemonSetReady"
+	ConditionDeploymentReady   string = "DeploymentReady"
+	ConditionServiceReady      string = "ServiceReady"
+	ConditionRouteReady        string = "RouteReady"
+	ConditionSecretReady       string = "SecretReady"
+	ConditionWebhookReady      string = "WebhookReady"
+	ConditionReleaseTrainReady string = "ReleaseTrainReady"
+	ConditionUpdatePolicyReady string = "UpdatePolicyReady"
+	ConditionNodesCompatible   string = "NodesCompatible"
+	ConditionClusterNameReady  string = "ClusterNameReady"
+	ConditionWebhookHealthy    string = "WebhookHealthy"
+	ConditionProfileApplied    string = "ProfileApplied"
+	ConditionResourceQuotaOK   string = "ResourceQuotaOK"
 
 	// Following strings are condition reasons
 
@@ -31,6 +38,60 @@ const (
 	ReasonDeleteFailed     string = "DeleteFailed"
 	ReasonFailed           string = "Failed"
 	ReasonDiscovered       string = "Discovered"
+
+	// Following strings are update policy condition reasons
+
+	ReasonUpdatePolicyNotFound       string = "UpdatePolicyNotFound"
+	ReasonUpdatePolicyDisabled       string = "UpdatePolicyDisabled"
+	ReasonUpdatePolicyUnsupported    string = "UpdatePolicyUnsupported"
+	ReasonSensorVersionNotFound      string = "SensorVersionNotFound"
+	ReasonInvalidSensorVersion       string = "InvalidSensorVersion"
+	ReasonUninstallProtectionEnabled string = "UninstallProtectionEnabled"
+	ReasonMaintenanceModeEnabled     string = "MaintenanceModeEnabled"
+
+	// ReasonReleaseTrainDegraded is used when some components of a FalconDeployment are kept on their current version
+	ReasonReleaseTrainDegraded string = "ReleaseTrainDegraded"
+
+	// Following strings are cluster name condition reasons
+
+	ReasonClusterNameConfigured string = "ClusterNameConfigured"
+	ReasonClusterNameDetected   string = "ClusterNameDetected"
+	ReasonClusterNameNotFound   string = "ClusterNameNotFound"
+	ReasonInvalidClusterName    string = "InvalidClusterName"
+	// ReasonClusterNameDetectionFailed is used when the cluster name could not be detected because the cluster could not be queried
+	ReasonClusterNameDetectionFailed string = "ClusterNameDetectionFailed"
+
+	// Following strings are webhook watchdog condition reasons
+
+	ReasonWebhookHealthy    string = "WebhookHealthy"
+	ReasonWebhookFailedOpen string = "WebhookFailedOpen"
+
+	// Following strings are resource quota condition reasons
+
+	ReasonResourceQuotaSufficient string = "ResourceQuotaSufficient"
+	ReasonResourceQuotaExceeded   string = "ResourceQuotaExceeded"
+
+	// Following strings are container profile condition reasons
+
+	ReasonProfileApplied    string = "ProfileApplied"
+	ReasonInvalidProfile    string = "InvalidProfile"
+	ReasonProfileConflict   string = "ProfileConflict"
+	ReasonProfileNotApplied string = "ProfileNotApplied"
+	ReasonInjectorPending   string = "InjectorPending"
+
+	// Following strings are node sensor condition reasons
+
+	ReasonNodeSelectorOverlap        string = "NodeSelectorOverlap"
+	ReasonInvalidTagTemplate         string = "InvalidTagTemplate"
+	ReasonNodeNotCovered             string = "NodeNotCovered"
+	ReasonCleanupIncomplete          string = "CleanupIncomplete"
+	ReasonNodeCleanupFailed          string = "NodeCleanupFailed"
+	ReasonNodeCleanedUp              string = "NodeCleanedUp"
+	ReasonUnsupportedNodes           string = "UnsupportedNodes"
+	ReasonInvalidCompatibilityMatrix string = "InvalidCompatibilityMatrix"
+
+	// ReasonInvalidMaintenanceToken is used when the maintenance token of the FalconSecret cannot be passed to the sensor
+	ReasonInvalidMaintenanceToken string = "InvalidMaintenanceToken"
 )
 
 // FalconAdmissionStatus defines the observed state of FalconAdmission
@@ -41,9 +102,18 @@ type FalconCRStatus struct {
 	// Version of the CrowdStrike Falcon Sensor
 	Sensor *string `json:"sensor,omitempty"`
 
+	// AvailableUpdate is the sensor version the operator would deploy next, when it differs from the deployed version.
+	// Approve it with the falcon.crowdstrike.com/approve-version annotation.
+	// +optional
+	AvailableUpdate *FalconAvailableUpdate `json:"availableUpdate,omitempty"`
+
 	// Version of the CrowdStrike Falcon Operator
 	Version string `json:"version,omitempty"`
 
+	// CertificateExpiry is when the serving certificate of the webhook managed by the operator expires. The operator renews it ahead of that time.
+	// +optional
+	CertificateExpiry *metav1.Time `json:"certificateExpiry,omitempty"`
+
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
@@ -1,5 +1,9 @@
 package v1alpha1
 
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
 // CrowdStrike Falcon Sensor configuration settings.
 // +k8s:openapi-gen=true
 type FalconSensor struct {
@@ -42,4 +46,34 @@ type FalconSensor struct {
 	// +kubebuilder:default:=none
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Trace Level",order=7
 	Trace string `json:"trace,omitempty"`
+
+	// Sensor features to enable with the falconctl --feature option, e.g. enableLog to write the sensor logs to disk.
+	// +kubebuilder:validation:items:Enum:=none;enableLog;disableLogBuffer;disableOsfm;emulateUpdate
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Sensor Features",order=9
+	Feature []string `json:"feature,omitempty"`
+
+	// Log sensor messages to the system log of the host.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Message Log",order=10,xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	MessageLog *bool `json:"messageLog,omitempty"`
+
+	// Time in milliseconds that the sensor waits for the provisioning with the CrowdStrike cloud to complete.
+	// +kubebuilder:validation:Minimum:=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Provisioning Wait Time",order=11,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	ProvisioningWaitTime *int64 `json:"provisioningWaitTime,omitempty"`
+
+	// Key of a Secret in the install namespace that holds the maintenance token used by the sensor to authorize changes to its
+	// configuration when uninstall and maintenance protection is enabled. The token is passed to the sensor from the Secret and is
+	// never written to the sensor ConfigMap. It can also be provided with the falcon-maintenance-token key of the FalconSecret.
+	// Not supported by the Falcon Container sidecar.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Maintenance Token Secret",order=12
+	MaintenanceTokenSecretRef *corev1.SecretKeySelector `json:"maintenanceTokenSecretRef,omitempty"`
+
+	// Additional falconctl options, keyed by the option name without the leading dashes, e.g. metadata-query: enable.
+	// Each option is passed to the sensor as a FALCONCTL_OPT_<OPTION> environment variable. Options that have a field in
+	// this configuration or that the operator manages, such as cid, cannot be set.
+	// +kubebuilder:validation:MaxProperties=64
+	// +kubebuilder:validation:XValidation:rule="self.all(k, k.matches('^[a-z][a-z0-9-]*$'))",message="falconctl option names must be lowercase, e.g. metadata-query"
+	// +kubebuilder:validation:XValidation:rule="self.all(k, !(k in ['aid', 'apd', 'aph', 'app', 'backend', 'billing', 'cid', 'cloud', 'feature', 'maintenance-token', 'message-log', 'provisioning-token', 'provisioning-wait-time', 'tags', 'trace']))",message="falconctl options with a dedicated field or managed by the operator cannot be set in extraOptions"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Extra falconctl Options",order=13
+	ExtraOptions map[string]string `json:"extraOptions,omitempty"`
 }
@@ -16,6 +16,7 @@ const (
 	WatcherEnabledDefault          = true
 	AdmissionControlEnabledDefault = true
 	ConfigMapWatcherEnabledDefault = true
+	ResourceQuotaEnabledDefault    = true
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -50,13 +51,15 @@ type FalconAdmissionSpec struct {
 	// The following Falcon values are supported by k8s secret injection:
 	//   falcon-cid
 	//   falcon-provisioning-token
+	//   falcon-maintenance-token
 	//   falcon-client-id
 	//   falcon-client-secret
 	// +kubebuilder:default={"enabled": false}
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Platform Secrets Configuration",order=7
 	FalconSecret FalconSecret `json:"falconSecret,omitempty"`
 
-	// ResourceQuota configures the ResourceQuota for the Falcon Admission Controller. This is useful for limiting the number of pods that can be created in the namespace.
+	// ResourceQuota configures the ResourceQuota for the Falcon Admission Controller. It limits the number of pods that can be created in the namespace
+	// to the pods the Falcon Admission Controller Deployments need, including the ones surged during a rolling update.
 	// +kubebuilder:default:={}
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Admission Controller Resource Quota",order=4
 	ResQuota FalconAdmissionRQSpec `json:"resourcequota,omitempty"`
@@ -82,14 +85,38 @@ type FalconAdmissionSpec struct {
 	// Cluster Name if Falcon KAC cannot discover the cluster name. This will be overwritten if Falcon KAC is able to discover the cluster name.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Admission Cluster Name",order=10
 	ClusterName *string `json:"clusterName,omitempty"`
+
+	// Detect the cluster name from the kubeadm configuration, the OpenShift infrastructure or the node labels and provider IDs of EKS, AKS and GKE clusters
+	// when clusterName is not set or invalid.
+	// +kubebuilder:default=false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Detect Cluster Name",order=10
+	DetectClusterName bool `json:"detectClusterName,omitempty"`
+
+	// Advanced configures various options that go against industry practices or are otherwise not recommended for use.
+	// Adjusting these settings may result in incorrect or undesirable behavior. Proceed at your own risk.
+	// For more information, please see https://github.com/CrowdStrike/falcon-operator/blob/main/docs/ADVANCED.md.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Admission Controller Advanced Settings",order=11
+	Advanced FalconAdvanced `json:"advanced,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:rule="!(has(self.pods) && has(self.minPods))",message="pods and minPods are mutually exclusive"
 type FalconAdmissionRQSpec struct {
-	// Limits the number of admission controller pods that can be created in the namespace.
-	// +kubebuilder:default:="2"
+	// Determines if the ResourceQuota is created in the namespace of the Falcon Admission Controller.
+	// +kubebuilder:default:=true
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable Resource Quota",order=2,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Limits the number of admission controller pods that can be created in the namespace. When it is not set, the limit is
+	// computed from the replicas and the maxSurge of the Deployments.
 	// +kubebuilder:validation:String
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Resource Quota Pod Limit",order=1,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
 	PodLimit string `json:"pods,omitempty"`
+
+	// Minimum pod limit of the ResourceQuota when pods is not set. The limit computed from the replicas and the maxSurge
+	// of the Deployments is raised to this value when it is lower.
+	// +kubebuilder:validation:String
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Resource Quota Minimum Pod Limit",order=3,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
+	MinPodLimit string `json:"minPods,omitempty"`
 }
 
 type FalconAdmissionConfigSpec struct {
@@ -127,7 +154,7 @@ type FalconAdmissionConfigSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Ignore Namespace List",order=12
 	DisabledNamespaces FalconAdmissionNamespace `json:"disabledNamespaces,omitempty"`
 
-	// Determines if with falcon-watcher container is included in the Pod
+	// Determines if the falcon-watcher Deployment is created alongside the Falcon Admission Controller
 	// +kubebuilder:default:=true
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Deploy Watcher Container",order=13
 	DeployWatcher *bool `json:"deployWatcher,omitempty"`
@@ -164,7 +191,8 @@ type FalconAdmissionConfigSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Image Analyzer Namespace",order=20
 	FalconImageAnalyzerNamespace string `json:"falconImageAnalyzerNamespace,omitempty"`
 
-	// Currently ignored and internally set to 1
+	// Number of replicas of the Deployment serving the admission webhook. The falcon-watcher runs in its own Deployment
+	// with a single replica, so it is not scaled by this setting.
 	// +kubebuilder:default:=2
 	// +kubebuilder:validation:XIntOrString
 	// +kubebuilder:validation:Minimum:=0
@@ -210,6 +238,18 @@ type FalconAdmissionConfigSpec struct {
 	// +kubebuilder:default:={}
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=20
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Availability configures the PodDisruptionBudget, topology spread constraints and pod anti-affinity of the Admission Controller.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Admission Controller Availability",order=21
+	Availability FalconAvailability `json:"availability,omitempty"`
+
+	// Webhook configures which namespaces, objects and resources the validating webhook reviews.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Admission Controller Webhook Scope",order=22
+	Webhook FalconAdmissionWebhook `json:"webhook,omitempty"`
+
+	// Watchdog has the operator probe the Falcon Admission Controller, and set the webhook failure policy to Ignore while it is unhealthy.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Admission Controller Webhook Watchdog",order=23
+	Watchdog *FalconWebhookWatchdog `json:"watchdog,omitempty"`
 }
 
 type FalconAdmissionServiceAccount struct {
@@ -240,11 +280,16 @@ type FalconAdmissionRollingUpdate struct {
 }
 
 type FalconAdmissionTLS struct {
-	// Validity of the TLS certificate in days. Default is 3650 days.
+	// Validity of the TLS certificate in days. Default is 3650 days, and the minimum is 7 days.
 	// +kubebuilder:validation:XIntOrString
 	// +kubebuilder:validation:Pattern="^[0-9]{1-4}$"
+	// +kubebuilder:validation:Minimum=7
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Admission Controller TLS Validity Length (days)",order=1,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
 	Validity *int `json:"validity,omitempty"`
+
+	// CertManager has cert-manager issue the TLS certificate instead of the operator. The validity is used as the certificate duration.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Admission Controller cert-manager Certificate",order=2
+	CertManager *FalconCertManager `json:"certManager,omitempty"`
 }
 
 type FalconAdmissionNamespace struct {
@@ -253,6 +298,58 @@ type FalconAdmissionNamespace struct {
 	Namespaces []string `json:"namespaces,omitempty"`
 }
 
+type FalconAdmissionWebhook struct {
+	// NamespaceSelector restricts the reviewed namespaces to the ones matching the selector.
+	// It is combined with disabledNamespaces and the falcon.crowdstrike.com/admission-review label.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Namespace Selector",order=1
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// ObjectSelector restricts the reviewed objects to the ones whose labels match the selector.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Object Selector",order=2
+	ObjectSelector *metav1.LabelSelector `json:"objectSelector,omitempty"`
+
+	// Timeout in seconds of the pod review. The failure policy applies when it is exceeded.
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=30
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Pod Review Timeout",order=3,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// Timeout in seconds of the workload review used for cluster visibility, which always ignores failures.
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=30
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Workload Review Timeout",order=4,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	WorkloadTimeoutSeconds *int32 `json:"workloadTimeoutSeconds,omitempty"`
+
+	// AdditionalResources are reviewed with the configured failure policy in addition to pods,
+	// so that policies are enforced when a workload is created rather than when its pods are.
+	// +kubebuilder:validation:MaxItems:=6
+	// +listType=map
+	// +listMapKey=resource
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Additional Reviewed Resources",order=5
+	AdditionalResources []FalconAdmissionWebhookResource `json:"additionalResources,omitempty"`
+}
+
+type FalconAdmissionWebhookResource struct {
+	// Resource to review.
+	// +kubebuilder:validation:Enum=deployments;daemonsets;statefulsets;replicasets;jobs;cronjobs
+	Resource string `json:"resource"`
+
+	// Operations to review.
+	// +kubebuilder:default:={"CREATE","UPDATE"}
+	// +kubebuilder:validation:MinItems:=1
+	// +kubebuilder:validation:items:Enum=CREATE;UPDATE
+	// +listType=set
+	Operations []arv1.OperationType `json:"operations,omitempty"`
+
+	// Timeout in seconds of the review. The failure policy applies when it is exceeded.
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=30
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
 // FalconAdmissionStatus defines the observed state of FalconAdmission
 type FalconAdmissionStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -359,6 +456,14 @@ func (ac *FalconAdmission) GetAdmissionControlEnabled() bool {
 	return *ac.Spec.AdmissionConfig.AdmissionControlEnabled
 }
 
+func (rq FalconAdmissionRQSpec) GetEnabled() bool {
+	if rq.Enabled == nil {
+		return ResourceQuotaEnabledDefault
+	}
+
+	return *rq.Enabled
+}
+
 func (ac *FalconAdmission) GetFalconSecretSpec() FalconSecret {
 	return ac.Spec.FalconSecret
 }
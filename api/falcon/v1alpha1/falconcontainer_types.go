@@ -137,6 +137,44 @@ type FalconContainerInjectorSpec struct {
 	// +kubebuilder:default={}
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="AITap AI-DR Configuration", order=15
 	AITap AITapSpec `json:"aitap,omitempty"`
+
+	// Availability configures the PodDisruptionBudget, topology spread constraints and pod anti-affinity of the injector.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Injector Availability",order=16
+	Availability FalconAvailability `json:"availability,omitempty"`
+
+	// Watchdog has the operator probe the injector, and set the webhook failure policy to Ignore while it is unhealthy,
+	// so that pods can still be created without the sidecar.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Injector Webhook Watchdog",order=17
+	Watchdog *FalconWebhookWatchdog `json:"watchdog,omitempty"`
+
+	// ReportCoverage has the operator periodically report the pods targeted by the injector that run without the Falcon Container sensor
+	// in the status and metrics. It watches every pod of the cluster, so it is disabled by default.
+	// +kubebuilder:default=false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Report Injection Coverage",order=18,xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	ReportCoverage bool `json:"reportCoverage,omitempty"`
+
+	// RestartUninjected has the operator restart the Deployments, StatefulSets and DaemonSets whose pods are targeted by the injector
+	// but run without the Falcon Container sensor, such as pods created before the injector was installed. It implies ReportCoverage.
+	// Each workload is restarted at most once for a given injector webhook, and only a few workloads are restarted per coverage check.
+	// +kubebuilder:default=false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Restart Uninjected Workloads",order=19,xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	RestartUninjected bool `json:"restartUninjected,omitempty"`
+
+	// ProfileNamespaces lists the namespaces whose FalconContainerProfile is applied. Each applied profile runs an injector of its own
+	// in the install namespace, so the FalconContainerProfiles of the other namespaces are reported as not applied.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="FalconContainerProfile Namespaces",order=20
+	ProfileNamespaces []string `json:"profileNamespaces,omitempty"`
+
+	// MaxProfiles limits the number of FalconContainerProfiles applied, the oldest first. The others are reported as not applied.
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum:=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Maximum FalconContainerProfiles",order=21
+	MaxProfiles *int32 `json:"maxProfiles,omitempty"`
+}
+
+// CoverageEnabled reports whether the pods targeted by the injector are checked for the sensor
+func (injector FalconContainerInjectorSpec) CoverageEnabled() bool {
+	return injector.ReportCoverage || injector.RestartUninjected
 }
 
 type FalconContainerServiceAccount struct {
@@ -146,10 +184,16 @@ type FalconContainerServiceAccount struct {
 }
 
 type FalconContainerInjectorTLS struct {
+	// Validity of the injector TLS certificate in days. Default is 3650 days, and the minimum is 7 days.
 	// +kubebuilder:validation:XIntOrString
 	// +kubebuilder:validation:Pattern="^[0-9]{1-4}$"
+	// +kubebuilder:validation:Minimum=7
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Container Injector TLS Validity Length (days)",order=1
 	Validity *int `json:"validity,omitempty"`
+
+	// CertManager has cert-manager issue the injector certificate instead of the operator. The validity is used as the certificate duration.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Container Injector cert-manager Certificate",order=2
+	CertManager *FalconCertManager `json:"certManager,omitempty"`
 }
 
 // AITapSpec defines the AI Detection and Response configuration
@@ -229,13 +273,56 @@ type FalconContainerStatus struct {
 	// Version of the CrowdStrike Falcon Sensor
 	Sensor *string `json:"sensor,omitempty"`
 
+	// AvailableUpdate is the sensor version the operator would deploy next, when it differs from the deployed version.
+	// Approve it with the falcon.crowdstrike.com/approve-version annotation.
+	// +optional
+	AvailableUpdate *FalconAvailableUpdate `json:"availableUpdate,omitempty"`
+
 	// Version of the CrowdStrike Falcon Operator
 	Version string `json:"version,omitempty"`
 
+	// CertificateExpiry is when the serving certificate of the injector webhook expires. The operator renews it ahead of that time.
+	// +optional
+	CertificateExpiry *metav1.Time `json:"certificateExpiry,omitempty"`
+
+	// Coverage reports the pods targeted by the injector that run without the Falcon Container sensor
+	// +optional
+	Coverage *FalconContainerCoverage `json:"coverage,omitempty"`
+
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// FalconContainerCoverage summarizes the injection of the Falcon Container sensor in the pods targeted by the injector.
+// Namespaces are sorted by name and truncated to keep the status within the object size limit of large clusters.
+type FalconContainerCoverage struct {
+	// Pods is the number of running pods targeted by the injector
+	Pods int32 `json:"pods"`
+
+	// Uninjected is the number of running pods targeted by the injector that run without the sensor
+	Uninjected int32 `json:"uninjected"`
+
+	// Namespaces lists the namespaces with pods running without the sensor
+	// +optional
+	Namespaces []FalconContainerNamespaceCoverage `json:"namespaces,omitempty"`
+}
+
+// FalconContainerNamespaceCoverage reports the pods of a namespace running without the Falcon Container sensor
+type FalconContainerNamespaceCoverage struct {
+	// Namespace is the name of the namespace
+	Namespace string `json:"namespace"`
+
+	// Pods is the number of running pods of the namespace targeted by the injector
+	Pods int32 `json:"pods"`
+
+	// Uninjected is the number of these pods that run without the sensor
+	Uninjected int32 `json:"uninjected"`
+
+	// CreatedBeforeWebhook is the number of uninjected pods created before the injector webhook, which a restart injects
+	// +optional
+	CreatedBeforeWebhook int32 `json:"createdBeforeWebhook,omitempty"`
+}
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:scope=Cluster
@@ -0,0 +1,162 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	profileTagPattern = regexp.MustCompile(`^[a-zA-Z0-9/_-]+$`)
+	profileEnvPattern = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+
+	// Environment variables set by the operator from the FalconContainer, or from the dedicated profile fields.
+	// Profiles cannot override them through additionalEnvironmentVariables.
+	profileReservedEnv = []string{
+		"CP_NAMESPACE",
+		"FALCON_IMAGE",
+		"FALCON_IMAGE_PULL_POLICY",
+		"FALCON_IMAGE_PULL_SECRET",
+		"FALCON_INJECTOR_LISTEN_PORT",
+		"FALCON_LOG_VOLUME",
+		"FALCON_MOUNT_ENABLED",
+		"FALCON_RESOURCES",
+		"INJECTION_DEFAULT_DISABLED",
+	}
+	profileReservedEnvPrefixes = []string{
+		"FALCONCTL_OPT_",
+		"FALCON_AITAP_",
+	}
+)
+
+// FalconContainerProfileSpec overrides the sidecar injection settings of the FalconContainer for the pods of its namespace.
+// Fields that are not set keep the value configured in the FalconContainer injector.
+// +k8s:openapi-gen=true
+type FalconContainerProfileSpec struct {
+	// SensorResources replaces the resource requirements of the injected Falcon Container Sensor container.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Container Sensor Resources",order=1
+	SensorResources *corev1.ResourceRequirements `json:"sensorResources,omitempty"`
+
+	// ImagePullPolicy replaces the image pull policy of the injected Falcon Container Sensor container.
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Container Image Pull Policy",order=2
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// LogVolume replaces the volume shared with the injected Falcon Container Sensor for its logs.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Container Shared Log Volume",order=3
+	LogVolume *FalconContainerProfileLogVolume `json:"logVolume,omitempty"`
+
+	// Tags replaces the sensor grouping tags of the FalconContainer for the pods of the namespace.
+	// +kubebuilder:validation:MaxItems=64
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Sensor Grouping Tags",order=4
+	Tags []string `json:"tags,omitempty"`
+
+	// AdditionalEnvironmentVariables are merged over the additional environment variables of the FalconContainer,
+	// the profile winning for variables set in both. Variables managed by the operator cannot be set.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Container Additional Environment Variables",order=5
+	AdditionalEnvironmentVariables map[string]string `json:"additionalEnvironmentVariables,omitempty"`
+}
+
+// FalconContainerProfileLogVolume is the volume shared with the injected Falcon Container Sensor for its logs.
+// Profiles are managed by the teams of the namespace, so hostPath and other volumes that reach outside of the
+// namespace cannot be used.
+// +kubebuilder:validation:XValidation:rule="has(self.emptyDir) != has(self.persistentVolumeClaim)",message="exactly one of emptyDir or persistentVolumeClaim must be set"
+type FalconContainerProfileLogVolume struct {
+	// Name of the volume in the injected pods.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// EmptyDir volume that lives as long as the pod.
+	// +optional
+	EmptyDir *corev1.EmptyDirVolumeSource `json:"emptyDir,omitempty"`
+
+	// PersistentVolumeClaim of the namespace that keeps the logs after the pod is deleted.
+	// +optional
+	PersistentVolumeClaim *corev1.PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty"`
+}
+
+// Volume returns the log volume the injector adds to the pods
+func (v FalconContainerProfileLogVolume) Volume() corev1.Volume {
+	return corev1.Volume{
+		Name: v.Name,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir:              v.EmptyDir,
+			PersistentVolumeClaim: v.PersistentVolumeClaim,
+		},
+	}
+}
+
+// Validate checks FalconContainerProfileSpec for settings the CRD schema does not reject.
+func (p FalconContainerProfileSpec) Validate() error {
+	for _, tag := range p.Tags {
+		if !profileTagPattern.MatchString(tag) {
+			return fmt.Errorf("tag %q may only contain letters, digits, '/', '-' and '_'", tag)
+		}
+	}
+
+	if p.LogVolume != nil && (p.LogVolume.EmptyDir == nil) == (p.LogVolume.PersistentVolumeClaim == nil) {
+		return fmt.Errorf("log volume %q must set exactly one of emptyDir or persistentVolumeClaim", p.LogVolume.Name)
+	}
+
+	for name := range p.AdditionalEnvironmentVariables {
+		env := strings.ToUpper(name)
+		if !profileEnvPattern.MatchString(env) {
+			return fmt.Errorf("additional environment variable %q is not a valid variable name", name)
+		}
+		if slices.Contains(profileReservedEnv, env) {
+			return fmt.Errorf("additional environment variable %q is managed by the operator", name)
+		}
+		for _, prefix := range profileReservedEnvPrefixes {
+			if strings.HasPrefix(env, prefix) {
+				return fmt.Errorf("additional environment variable %q is managed by the operator: variables starting with %s cannot be set in a profile", name, prefix)
+			}
+		}
+	}
+
+	return nil
+}
+
+// FalconContainerProfileStatus defines the observed state of FalconContainerProfile
+// +k8s:openapi-gen=true
+type FalconContainerProfileStatus struct {
+	// Injector is the name of the injector Deployment that injects the pods of the namespace with the settings of
+	// the profile, in the install namespace of the FalconContainer
+	// +optional
+	Injector string `json:"injector,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Applied",type="string",JSONPath=".status.conditions[?(@.type==\"ProfileApplied\")].status",description="Whether the profile is applied to the namespace"
+//+kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type==\"ProfileApplied\")].reason"
+
+// FalconContainerProfile is the Schema for the falconcontainerprofiles API. It overrides the sidecar injection
+// settings of the FalconContainer in its namespace.
+type FalconContainerProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FalconContainerProfileSpec   `json:"spec,omitempty"`
+	Status FalconContainerProfileStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// FalconContainerProfileList contains a list of FalconContainerProfile
+type FalconContainerProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FalconContainerProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FalconContainerProfile{}, &FalconContainerProfileList{})
+}
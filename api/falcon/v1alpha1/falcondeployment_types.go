@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -30,6 +31,7 @@ type FalconDeploymentSpec struct {
 	// The following Falcon values are supported by k8s secret injection:
 	//   falcon-cid
 	//   falcon-provisioning-token
+	//   falcon-maintenance-token
 	//   falcon-client-id
 	//   falcon-client-secret
 	// +kubebuilder:default={"enabled": false}
@@ -66,6 +68,14 @@ type FalconDeploymentSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Node Sensor Configuration",order=9
 	FalconNodeSensor FalconNodeSensorSpec `json:"falconNodeSensor,omitempty"`
 
+	// NodeSensorProfiles deploys one FalconNodeSensor per node pool instead of a single FalconNodeSensor for all nodes.
+	// Each profile is applied on top of the FalconNodeSensor configuration, and profiles must select disjoint sets of nodes.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Node Sensor Profiles"
+	NodeSensorProfiles []FalconNodeSensorProfile `json:"nodeSensorProfiles,omitempty"`
+
 	// Falcon Image Analyzer Configuration
 	// +kubebuilder:default:={}
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Image Analyzer Configuration",order=10
@@ -75,6 +85,106 @@ type FalconDeploymentSpec struct {
 	// +kubebuilder:default:={}
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Container Sensor Configuration",order=11
 	FalconContainerSensor FalconContainerSpec `json:"falconContainerSensor,omitempty"`
+
+	// Version of the sensor release train to deploy for all components, e.g. 7.31 or 7.31.0.
+	// When set, or when Advanced selects an update policy or automatic updates, a single release train is resolved
+	// and compatible versions are pinned in the FalconNodeSensor, FalconAdmission, FalconContainer and FalconImageAnalyzer specs.
+	// Component-specific versions configured in this resource take precedence.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Sensor Release Train Version",order=12
+	Version *string `json:"version,omitempty"`
+
+	// Advanced configures the sensor update policy and automatic updates for all components together.
+	// For more information, please see https://github.com/CrowdStrike/falcon-operator/blob/main/docs/ADVANCED.md.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Deployment Advanced Settings",order=13
+	Advanced FalconAdvanced `json:"advanced,omitempty"`
+}
+
+// FalconNodeSensorProfile configures the FalconNodeSensor deployed to a pool of nodes.
+// Settings left empty are inherited from the FalconDeployment FalconNodeSensor configuration.
+type FalconNodeSensorProfile struct {
+	// Name of the profile. The FalconNodeSensor deployed for the profile is named falcon-node-sensor-<name>.
+	// +kubebuilder:validation:Pattern="^[a-z0-9]([-a-z0-9]*[a-z0-9])?$"
+	// +kubebuilder:validation:MaxLength=32
+	Name string `json:"name"`
+
+	// NodeSelector selects the nodes of the pool.
+	// +kubebuilder:validation:MinProperties=1
+	NodeSelector map[string]string `json:"nodeSelector"`
+
+	// Tolerations replaces the FalconNodeSensor tolerations for the pool.
+	// +optional
+	Tolerations *[]corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Image replaces the FalconNodeSensor image for the pool.
+	// +kubebuilder:validation:Pattern="^.*:.*$"
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Resources replaces the FalconNodeSensor resource requests and limits for the pool.
+	// +optional
+	Resources *Resources `json:"resources,omitempty"`
+
+	// Tags replaces the sensor grouping tags for the pool.
+	// +kubebuilder:validation:items:Pattern=`^[a-zA-Z0-9/_-]+$`
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// TagTemplates replaces the FalconNodeSensor sensor grouping tag templates for the pool.
+	// +kubebuilder:validation:items:Pattern=`^([a-zA-Z0-9/_-]|\{\{[^{}]+\}\})+$`
+	// +optional
+	TagTemplates []string `json:"tagTemplates,omitempty"`
+
+	// Trace replaces the sensor trace level for the pool.
+	// +kubebuilder:validation:Enum:=none;err;warn;info;debug
+	// +optional
+	Trace string `json:"trace,omitempty"`
+}
+
+// FalconReleaseTrain is the set of sensor versions selected for all components of a FalconDeployment.
+type FalconReleaseTrain struct {
+	// Version is the major.minor sensor release shared by all components.
+	Version string `json:"version"`
+
+	// Source of the release train: version, updatePolicy or latest.
+	Source string `json:"source"`
+
+	// NodeSensor is the version pinned in the FalconNodeSensor spec.
+	// +optional
+	NodeSensor string `json:"nodeSensor,omitempty"`
+
+	// Admission is the version pinned in the FalconAdmission spec.
+	// +optional
+	Admission string `json:"admission,omitempty"`
+
+	// ContainerSensor is the version pinned in the FalconContainer spec.
+	// +optional
+	ContainerSensor string `json:"containerSensor,omitempty"`
+
+	// ImageAnalyzer is the version pinned in the FalconImageAnalyzer spec.
+	// The image analyzer is released independently, so the latest version available at resolution time is used.
+	// +optional
+	ImageAnalyzer string `json:"imageAnalyzer,omitempty"`
+
+	// ObservedGeneration is the FalconDeployment generation the release train was resolved for.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ResolvedAt is the time the release train was resolved.
+	ResolvedAt metav1.Time `json:"resolvedAt,omitempty"`
+}
+
+const (
+	ReleaseTrainSourceVersion      = "version"
+	ReleaseTrainSourceUpdatePolicy = "updatePolicy"
+	ReleaseTrainSourceLatest       = "latest"
+)
+
+// HasCoordinatedVersions returns true when sensor versions are resolved once for all components.
+func (spec FalconDeploymentSpec) HasCoordinatedVersions() bool {
+	if spec.FalconAPI == nil {
+		return false
+	}
+
+	return (spec.Version != nil && *spec.Version != "") || spec.Advanced.HasUpdatePolicy() || spec.Advanced.IsAutoUpdating()
 }
 
 // FalconDeploymentStatus defines the observed state of FalconDeployment
@@ -88,6 +198,10 @@ type FalconDeploymentStatus struct {
 	// Version of the CrowdStrike Falcon Operator
 	Version string `json:"version,omitempty"`
 
+	// ReleaseTrain is the set of sensor versions selected for all components when versions are coordinated.
+	// +optional
+	ReleaseTrain *FalconReleaseTrain `json:"releaseTrain,omitempty"`
+
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
@@ -97,6 +211,7 @@ type FalconDeploymentStatus struct {
 //+kubebuilder:resource:scope=Cluster
 //+kubebuilder:printcolumn:name="Operator Version",type="string",JSONPath=".status.version",description="Version of the Operator"
 //+kubebuilder:printcolumn:name="Falcon Sensor",type="string",JSONPath=".status.sensor",description="Version of the Falcon Container"
+//+kubebuilder:printcolumn:name="Release Train",type="string",JSONPath=".status.releaseTrain.version",description="Sensor release train shared by all components"
 
 // FalconDeployment is the Schema for the falcondeployments API
 type FalconDeployment struct {
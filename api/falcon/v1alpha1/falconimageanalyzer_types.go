@@ -63,6 +63,16 @@ type FalconImageAnalyzerSpec struct {
 	// +kubebuilder:default:={}
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=9
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Availability configures the PodDisruptionBudget, topology spread constraints and pod anti-affinity of the Falcon Image Analyzer.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Image Analyzer Availability",order=11
+	Availability FalconAvailability `json:"availability,omitempty"`
+
+	// Advanced configures various options that go against industry practices or are otherwise not recommended for use.
+	// Adjusting these settings may result in incorrect or undesirable behavior. Proceed at your own risk.
+	// For more information, please see https://github.com/CrowdStrike/falcon-operator/blob/main/docs/ADVANCED.md.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Image Analyzer Advanced Settings",order=10
+	Advanced FalconAdvanced `json:"advanced,omitempty"`
 }
 
 type FalconImageAnalyzerConfigSpec struct {
@@ -108,6 +118,12 @@ type FalconImageAnalyzerConfigSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Image Analyzer Cluster Name",order=10
 	ClusterName string `json:"clusterName,omitempty"`
 
+	// Detect the cluster name from the kubeadm configuration, the OpenShift infrastructure or the node labels and provider IDs of EKS, AKS and GKE clusters
+	// when clusterName is not set.
+	// +kubebuilder:default=false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Detect Cluster Name",order=10
+	DetectClusterName bool `json:"detectClusterName,omitempty"`
+
 	// Exclusions for the Falcon Image Analyzer.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Image Analyzer Exclusions",order=11
 	Exclusions Exclusions `json:"exclusions,omitempty"`
@@ -182,6 +198,10 @@ type FalconImageAnalyzerAgentServiceSpec struct {
 	// service.my-namespace.svc.testing.io, you would add testing.io as the value below.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Domain Name",order=4
 	DomainName string `json:"domainName,omitempty"`
+
+	// CertManager has cert-manager issue the IAR Agent Service certificate instead of the operator. The certificate expiration is used as the certificate duration.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Falcon Image Analyzer cert-manager Certificate",order=5
+	CertManager *FalconCertManager `json:"certManager,omitempty"`
 }
 
 type FalconImageAnalyzerKACSpec struct {
@@ -43,6 +43,7 @@ type FalconNodeSensorSpec struct {
 	// The following Falcon values are supported by k8s secret injection:
 	//   falcon-cid
 	//   falcon-provisioning-token
+	//   falcon-maintenance-token
 	//   falcon-client-id
 	//   falcon-client-secret
 	// +kubebuilder:default={"enabled": false}
@@ -62,10 +63,22 @@ type FalconNodeSensorConfig struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=4
 	Tolerations *[]corev1.Toleration `json:"tolerations"`
 
+	// Tolerates every taint so that the sensor runs on all Linux nodes, including nodes with custom taints.
+	// When enabled, the tolerations are not used.
+	// +kubebuilder:default=false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Tolerate All Taints"
+	TolerateAllTaints bool `json:"tolerateAllTaints,omitempty"`
+
 	// Specifies node affinity for scheduling the DaemonSet. Defaults to allowing scheduling on all nodes.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=5
 	NodeAffinity corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
 
+	// Specifies node labels that a node must match to run the DaemonSet. Defaults to all Linux nodes.
+	// Multiple FalconNodeSensors can target disjoint node pools when each sets a nodeSelector that requires a different value for a shared label.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Node Selector"
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
 	// +kubebuilder:default=Always
 	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=3
@@ -100,6 +113,13 @@ type FalconNodeSensorConfig struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=8
 	NodeCleanup *bool `json:"disableCleanup,omitempty"`
 
+	// Removes the sensor from nodes that are no longer targeted by the DaemonSet, for example after a change to the node selector,
+	// node affinity, tolerations or the labels and taints of a node, by running a one-shot cleanup pod on each of these nodes.
+	// Not supported on GKE Autopilot.
+	// +kubebuilder:default=false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Cleanup Removed Nodes"
+	CleanupRemovedNodes bool `json:"cleanupRemovedNodes,omitempty"`
+
 	// Configure resource requests and limits for the DaemonSet Sensor.
 	// The sensor uses eBPF by default, but falls back to kernel mode on unsupported kernel versions.
 	// Resource limits are not recommended when running in kernel mode.
@@ -131,9 +151,29 @@ type FalconNodeSensorConfig struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="DaemonSet Advanced Settings"
 	Advanced FalconAdvanced `json:"advanced,omitempty"`
 
+	// Sensor grouping tags resolved for each node and added to falcon.tags, e.g. pool/{{ .Labels["eks.amazonaws.com/nodegroup"] }} or zone/{{ .Zone }}.
+	// Templates can reference {{ .NodeName }}, {{ .Zone }}, {{ .Region }}, {{ .InstanceType }} and node labels with {{ .Labels["<label>"] }}.
+	// Characters that are not allowed in grouping tags are replaced with '-', and templates that reference a missing label are skipped.
+	// +kubebuilder:validation:items:Pattern=`^([a-zA-Z0-9/_-]|\{\{[^{}]+\}\})+$`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Sensor Grouping Tag Templates"
+	TagTemplates []string `json:"tagTemplates,omitempty"`
+
+	// Name of a ConfigMap in the install namespace holding a node compatibility matrix under the compatibility.yaml key.
+	// It replaces the compatibility matrix shipped with the operator, which only checks architectures, so OS images and kernels
+	// are only checked when it is set. Target nodes that the sensor does not support according to the matrix are reported in
+	// the NodesCompatible condition.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Compatibility Matrix ConfigMap"
+	CompatibilityConfigMap string `json:"compatibilityConfigMap,omitempty"`
+
 	// When running on an unmanaged K8S cluster, set a cluster name. When running on managed, K8S cluster name is resolved cloud-side
 	// +kubebuilder:validation:Pattern="^[0-9a-zA-Z]{1}[0-9a-zA-Z_-]{1,99}$"
 	ClusterName *string `json:"clusterName,omitempty"`
+
+	// Detect the cluster name from the kubeadm configuration, the OpenShift infrastructure or the node labels and provider IDs of EKS, AKS and GKE clusters
+	// when clusterName is not set or invalid.
+	// +kubebuilder:default=false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Detect Cluster Name"
+	DetectClusterName bool `json:"detectClusterName,omitempty"`
 }
 
 type PriorityClassConfig struct {
@@ -221,18 +261,135 @@ type FalconNodeSensorStatus struct {
 	// Version of the CrowdStrike Falcon Sensor
 	Sensor *string `json:"sensor,omitempty"`
 
+	// AvailableUpdate is the sensor version the operator would deploy next, when it differs from the deployed version.
+	// Approve it with the falcon.crowdstrike.com/approve-version annotation.
+	// +optional
+	AvailableUpdate *FalconAvailableUpdate `json:"availableUpdate,omitempty"`
+
 	// Version of the CrowdStrike Falcon Operator
 	Version string `json:"version,omitempty"`
 
+	// Nodes summarizes the sensor pods running on the nodes targeted by the DaemonSet
+	// +optional
+	Nodes *FalconNodeSensorNodes `json:"nodes,omitempty"`
+
+	// RemovedNodes lists the nodes that are no longer targeted by the DaemonSet and still need the sensor to be removed.
+	// Only reported when cleanupRemovedNodes is enabled.
+	// +optional
+	RemovedNodes []FalconNodeSensorRemovedNode `json:"removedNodes,omitempty"`
+
+	// Cleanup reports the progress of the removal of the sensor from the nodes while the FalconNodeSensor is deleted
+	// +optional
+	Cleanup *FalconNodeSensorCleanup `json:"cleanup,omitempty"`
+
 	// Conditions represent the latest available observations of an object's state
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// FalconNodeSensorRemovedNode describes a node that is no longer targeted by the DaemonSet
+type FalconNodeSensorRemovedNode struct {
+	// Name of the node
+	Name string `json:"name"`
+
+	// RemovedAt is the time the node was detected to no longer be targeted by the DaemonSet
+	RemovedAt metav1.Time `json:"removedAt"`
+}
+
+// FalconNodeSensorCleanup tracks the cleanup of the /opt/CrowdStrike directory on the nodes that ran the sensor
+type FalconNodeSensorCleanup struct {
+	// StartedAt is the time the sensor DaemonSet was deleted and the cleanup started
+	StartedAt metav1.Time `json:"startedAt"`
+
+	// Nodes is the number of nodes that ran the sensor and need to be cleaned up
+	Nodes int32 `json:"nodes"`
+
+	// Completed is the number of nodes that have been cleaned up
+	Completed int32 `json:"completed"`
+
+	// Remaining lists the nodes that have not been cleaned up, either because the cleanup is in progress or because it could not run on the node
+	// +optional
+	Remaining []FalconNodeSensorCleanupNode `json:"remaining,omitempty"`
+}
+
+// FalconNodeSensorCleanupNode describes the cleanup state of a node
+type FalconNodeSensorCleanupNode struct {
+	// Name of the node
+	Name string `json:"name"`
+
+	// State of the cleanup on the node
+	// +kubebuilder:validation:Enum=Pending;Failed;Skipped;TimedOut
+	State string `json:"state"`
+
+	// Message explains why the node could not be cleaned up
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	CleanupStatePending  = "Pending"
+	CleanupStateFailed   = "Failed"
+	CleanupStateSkipped  = "Skipped"
+	CleanupStateTimedOut = "TimedOut"
+)
+
+// FalconNodeSensorNodes summarizes the state of the sensor on the nodes of the cluster.
+// Node lists are sorted by node name and truncated to keep the status within the object size limit of large clusters.
+type FalconNodeSensorNodes struct {
+	// Desired is the number of nodes that should run the sensor
+	Desired int32 `json:"desired"`
+
+	// Ready is the number of nodes running a ready sensor pod
+	Ready int32 `json:"ready"`
+
+	// Updated is the number of nodes running the latest sensor pod template
+	Updated int32 `json:"updated"`
+
+	// Unavailable is the number of nodes that should run the sensor but have no available sensor pod
+	Unavailable int32 `json:"unavailable"`
+
+	// CrashLooping lists the nodes whose sensor pod has a container or init container in CrashLoopBackOff
+	// +optional
+	CrashLooping []string `json:"crashLooping,omitempty"`
+
+	// ExcludedCount is the number of Linux nodes selected by the nodeSelector that cannot run the sensor
+	// +optional
+	ExcludedCount int32 `json:"excludedCount,omitempty"`
+
+	// Excluded lists the Linux nodes selected by the nodeSelector that cannot run the sensor because of their taints,
+	// the node affinity or a missing kubernetes.io/os label
+	// +optional
+	Excluded []FalconNodeSensorExcludedNode `json:"excluded,omitempty"`
+
+	// Sensors lists the sensor version running on each node
+	// +optional
+	Sensors []FalconNodeSensorNodeVersion `json:"sensors,omitempty"`
+}
+
+// FalconNodeSensorExcludedNode describes a node that cannot run the sensor
+type FalconNodeSensorExcludedNode struct {
+	// Name of the node
+	Name string `json:"name"`
+
+	// Reason the sensor cannot run on the node
+	Reason string `json:"reason"`
+}
+
+// FalconNodeSensorNodeVersion describes the sensor version running on a node
+type FalconNodeSensorNodeVersion struct {
+	// Name of the node
+	Name string `json:"name"`
+
+	// Version of the sensor image running on the node
+	Version string `json:"version"`
+}
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:scope=Cluster
 //+kubebuilder:printcolumn:name="Operator Version",type="string",JSONPath=".status.version",description="Version of the Operator"
 //+kubebuilder:printcolumn:name="Falcon Sensor",type="string",JSONPath=".status.sensor",description="Version of the Falcon Sensor"
+//+kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.nodes.ready",description="Number of nodes running a ready sensor"
+//+kubebuilder:printcolumn:name="Desired",type="integer",JSONPath=".status.nodes.desired",description="Number of nodes that should run the sensor"
 
 // FalconNodeSensor is the Schema for the falconnodesensors API
 // +k8s:openapi-gen=true
@@ -256,7 +413,14 @@ func init() {
 	SchemeBuilder.Register(&FalconNodeSensor{}, &FalconNodeSensorList{})
 }
 
+// AllTaintsToleration is the toleration used by the DaemonSet when tolerateAllTaints is enabled
+var AllTaintsToleration = corev1.Toleration{Operator: corev1.TolerationOpExists}
+
 func (node *FalconNodeSensor) GetTolerations() *[]corev1.Toleration {
+	if node.Spec.Node.TolerateAllTaints {
+		return &[]corev1.Toleration{AllTaintsToleration}
+	}
+
 	if node.Spec.Node.Tolerations == nil {
 		return &[]corev1.Toleration{}
 	}
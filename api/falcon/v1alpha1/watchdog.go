@@ -0,0 +1,35 @@
+package v1alpha1
+
+// FalconWebhookWatchdog configures the operator to probe the health of a webhook, and to fail open while it is unhealthy.
+// The failure policy of the webhook is set to Ignore after failureThreshold consecutive failed probes, and restored after
+// successThreshold consecutive successful probes.
+type FalconWebhookWatchdog struct {
+	// Enabled determines whether the operator probes the webhook.
+	// +kubebuilder:default:=false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable Webhook Watchdog",order=1,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Enabled bool `json:"enabled,omitempty"`
+
+	// PeriodSeconds is the interval between two probes.
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=300
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Probe Period (seconds)",order=2,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+
+	// FailureThreshold is the number of consecutive failed probes after which the webhook fails open.
+	// +kubebuilder:default:=3
+	// +kubebuilder:validation:Minimum:=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Failure Threshold",order=3,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+
+	// SuccessThreshold is the number of consecutive successful probes after which the failure policy of the webhook is restored.
+	// +kubebuilder:default:=2
+	// +kubebuilder:validation:Minimum:=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Success Threshold",order=4,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	SuccessThreshold *int32 `json:"successThreshold,omitempty"`
+}
+
+// IsEnabled reports whether the webhook watchdog is enabled
+func (w *FalconWebhookWatchdog) IsEnabled() bool {
+	return w != nil && w.Enabled
+}
@@ -210,8 +210,9 @@
 package v1alpha1
 
 import (
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -376,7 +377,7 @@ func (in *FalconAdmissionConfigSpec) DeepCopyInto(out *FalconAdmissionConfigSpec
 	}
 	if in.SnapshotsInterval != nil {
 		in, out := &in.SnapshotsInterval, &out.SnapshotsInterval
-		*out = new(v1.Duration)
+		*out = new(metav1.Duration)
 		**out = **in
 	}
 	if in.AdmissionControlEnabled != nil {
@@ -396,42 +397,49 @@ func (in *FalconAdmissionConfigSpec) DeepCopyInto(out *FalconAdmissionConfigSpec
 	}
 	if in.ImagePullSecrets != nil {
 		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]corev1.LocalObjectReference, len(*in))
+		*out = make([]v1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
 	if in.ResourcesClient != nil {
 		in, out := &in.ResourcesClient, &out.ResourcesClient
-		*out = new(corev1.ResourceRequirements)
+		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.ResourcesClientNoWebhook != nil {
 		in, out := &in.ResourcesClientNoWebhook, &out.ResourcesClientNoWebhook
-		*out = new(corev1.ResourceRequirements)
+		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.ResourcesWatcher != nil {
 		in, out := &in.ResourcesWatcher, &out.ResourcesWatcher
-		*out = new(corev1.ResourceRequirements)
+		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.ResourcesAC != nil {
 		in, out := &in.ResourcesAC, &out.ResourcesAC
-		*out = new(corev1.ResourceRequirements)
+		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
 	in.DepUpdateStrategy.DeepCopyInto(&out.DepUpdateStrategy)
 	if in.NodeAffinity != nil {
 		in, out := &in.NodeAffinity, &out.NodeAffinity
-		*out = new(corev1.NodeAffinity)
+		*out = new(v1.NodeAffinity)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]corev1.Toleration, len(*in))
+		*out = make([]v1.Toleration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.Availability.DeepCopyInto(&out.Availability)
+	in.Webhook.DeepCopyInto(&out.Webhook)
+	if in.Watchdog != nil {
+		in, out := &in.Watchdog, &out.Watchdog
+		*out = new(FalconWebhookWatchdog)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconAdmissionConfigSpec.
@@ -499,6 +507,11 @@ func (in *FalconAdmissionNamespace) DeepCopy() *FalconAdmissionNamespace {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FalconAdmissionRQSpec) DeepCopyInto(out *FalconAdmissionRQSpec) {
 	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconAdmissionRQSpec.
@@ -568,7 +581,7 @@ func (in *FalconAdmissionSpec) DeepCopyInto(out *FalconAdmissionSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	out.FalconSecret = in.FalconSecret
-	out.ResQuota = in.ResQuota
+	in.ResQuota.DeepCopyInto(&out.ResQuota)
 	in.Registry.DeepCopyInto(&out.Registry)
 	in.AdmissionConfig.DeepCopyInto(&out.AdmissionConfig)
 	if in.Version != nil {
@@ -581,6 +594,7 @@ func (in *FalconAdmissionSpec) DeepCopyInto(out *FalconAdmissionSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	in.Advanced.DeepCopyInto(&out.Advanced)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconAdmissionSpec.
@@ -603,7 +617,7 @@ func (in *FalconAdmissionStatus) DeepCopyInto(out *FalconAdmissionStatus) {
 	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -628,6 +642,11 @@ func (in *FalconAdmissionTLS) DeepCopyInto(out *FalconAdmissionTLS) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(FalconCertManager)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconAdmissionTLS.
@@ -656,6 +675,73 @@ func (in *FalconAdmissionUpdateStrategy) DeepCopy() *FalconAdmissionUpdateStrate
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconAdmissionWebhook) DeepCopyInto(out *FalconAdmissionWebhook) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ObjectSelector != nil {
+		in, out := &in.ObjectSelector, &out.ObjectSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WorkloadTimeoutSeconds != nil {
+		in, out := &in.WorkloadTimeoutSeconds, &out.WorkloadTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AdditionalResources != nil {
+		in, out := &in.AdditionalResources, &out.AdditionalResources
+		*out = make([]FalconAdmissionWebhookResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconAdmissionWebhook.
+func (in *FalconAdmissionWebhook) DeepCopy() *FalconAdmissionWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconAdmissionWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconAdmissionWebhookResource) DeepCopyInto(out *FalconAdmissionWebhookResource) {
+	*out = *in
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make([]admissionregistrationv1.OperationType, len(*in))
+		copy(*out, *in)
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconAdmissionWebhookResource.
+func (in *FalconAdmissionWebhookResource) DeepCopy() *FalconAdmissionWebhookResource {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconAdmissionWebhookResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FalconAdvanced) DeepCopyInto(out *FalconAdvanced) {
 	*out = *in
@@ -681,6 +767,54 @@ func (in *FalconAdvanced) DeepCopy() *FalconAdvanced {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconAvailability) DeepCopyInto(out *FalconAvailability) {
+	*out = *in
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(FalconPodDisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodAntiAffinity != nil {
+		in, out := &in.PodAntiAffinity, &out.PodAntiAffinity
+		*out = new(v1.PodAntiAffinity)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconAvailability.
+func (in *FalconAvailability) DeepCopy() *FalconAvailability {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconAvailability)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconAvailableUpdate) DeepCopyInto(out *FalconAvailableUpdate) {
+	*out = *in
+	in.DetectedAt.DeepCopyInto(&out.DetectedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconAvailableUpdate.
+func (in *FalconAvailableUpdate) DeepCopy() *FalconAvailableUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconAvailableUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FalconCRStatus) DeepCopyInto(out *FalconCRStatus) {
 	*out = *in
@@ -689,9 +823,18 @@ func (in *FalconCRStatus) DeepCopyInto(out *FalconCRStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.AvailableUpdate != nil {
+		in, out := &in.AvailableUpdate, &out.AvailableUpdate
+		*out = new(FalconAvailableUpdate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertificateExpiry != nil {
+		in, out := &in.CertificateExpiry, &out.CertificateExpiry
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -708,6 +851,22 @@ func (in *FalconCRStatus) DeepCopy() *FalconCRStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconCertManager) DeepCopyInto(out *FalconCertManager) {
+	*out = *in
+	out.IssuerRef = in.IssuerRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconCertManager.
+func (in *FalconCertManager) DeepCopy() *FalconCertManager {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconCertManager)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FalconContainer) DeepCopyInto(out *FalconContainer) {
 	*out = *in
@@ -735,6 +894,26 @@ func (in *FalconContainer) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconContainerCoverage) DeepCopyInto(out *FalconContainerCoverage) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]FalconContainerNamespaceCoverage, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconContainerCoverage.
+func (in *FalconContainerCoverage) DeepCopy() *FalconContainerCoverage {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconContainerCoverage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FalconContainerInjectorSpec) DeepCopyInto(out *FalconContainerInjectorSpec) {
 	*out = *in
@@ -747,17 +926,17 @@ func (in *FalconContainerInjectorSpec) DeepCopyInto(out *FalconContainerInjector
 	in.TLS.DeepCopyInto(&out.TLS)
 	if in.LogVolume != nil {
 		in, out := &in.LogVolume, &out.LogVolume
-		*out = new(corev1.Volume)
+		*out = new(v1.Volume)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
-		*out = new(corev1.ResourceRequirements)
+		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.SensorResources != nil {
 		in, out := &in.SensorResources, &out.SensorResources
-		*out = new(corev1.ResourceRequirements)
+		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.AdditionalEnvironmentVariables != nil {
@@ -777,6 +956,22 @@ func (in *FalconContainerInjectorSpec) DeepCopyInto(out *FalconContainerInjector
 		**out = **in
 	}
 	in.AITap.DeepCopyInto(&out.AITap)
+	in.Availability.DeepCopyInto(&out.Availability)
+	if in.Watchdog != nil {
+		in, out := &in.Watchdog, &out.Watchdog
+		*out = new(FalconWebhookWatchdog)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProfileNamespaces != nil {
+		in, out := &in.ProfileNamespaces, &out.ProfileNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxProfiles != nil {
+		in, out := &in.MaxProfiles, &out.MaxProfiles
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconContainerInjectorSpec.
@@ -797,6 +992,11 @@ func (in *FalconContainerInjectorTLS) DeepCopyInto(out *FalconContainerInjectorT
 		*out = new(int)
 		**out = **in
 	}
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(FalconCertManager)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconContainerInjectorTLS.
@@ -841,6 +1041,164 @@ func (in *FalconContainerList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconContainerNamespaceCoverage) DeepCopyInto(out *FalconContainerNamespaceCoverage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconContainerNamespaceCoverage.
+func (in *FalconContainerNamespaceCoverage) DeepCopy() *FalconContainerNamespaceCoverage {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconContainerNamespaceCoverage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconContainerProfile) DeepCopyInto(out *FalconContainerProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconContainerProfile.
+func (in *FalconContainerProfile) DeepCopy() *FalconContainerProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconContainerProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FalconContainerProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconContainerProfileList) DeepCopyInto(out *FalconContainerProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FalconContainerProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconContainerProfileList.
+func (in *FalconContainerProfileList) DeepCopy() *FalconContainerProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconContainerProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FalconContainerProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconContainerProfileLogVolume) DeepCopyInto(out *FalconContainerProfileLogVolume) {
+	*out = *in
+	if in.EmptyDir != nil {
+		in, out := &in.EmptyDir, &out.EmptyDir
+		*out = new(v1.EmptyDirVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(v1.PersistentVolumeClaimVolumeSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconContainerProfileLogVolume.
+func (in *FalconContainerProfileLogVolume) DeepCopy() *FalconContainerProfileLogVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconContainerProfileLogVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconContainerProfileSpec) DeepCopyInto(out *FalconContainerProfileSpec) {
+	*out = *in
+	if in.SensorResources != nil {
+		in, out := &in.SensorResources, &out.SensorResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogVolume != nil {
+		in, out := &in.LogVolume, &out.LogVolume
+		*out = new(FalconContainerProfileLogVolume)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdditionalEnvironmentVariables != nil {
+		in, out := &in.AdditionalEnvironmentVariables, &out.AdditionalEnvironmentVariables
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconContainerProfileSpec.
+func (in *FalconContainerProfileSpec) DeepCopy() *FalconContainerProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconContainerProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconContainerProfileStatus) DeepCopyInto(out *FalconContainerProfileStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconContainerProfileStatus.
+func (in *FalconContainerProfileStatus) DeepCopy() *FalconContainerProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconContainerProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FalconContainerServiceAccount) DeepCopyInto(out *FalconContainerServiceAccount) {
 	*out = *in
@@ -887,7 +1245,7 @@ func (in *FalconContainerSpec) DeepCopyInto(out *FalconContainerSpec) {
 	}
 	if in.NodeAffinity != nil {
 		in, out := &in.NodeAffinity, &out.NodeAffinity
-		*out = new(corev1.NodeAffinity)
+		*out = new(v1.NodeAffinity)
 		(*in).DeepCopyInto(*out)
 	}
 	in.Advanced.DeepCopyInto(&out.Advanced)
@@ -911,9 +1269,23 @@ func (in *FalconContainerStatus) DeepCopyInto(out *FalconContainerStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.AvailableUpdate != nil {
+		in, out := &in.AvailableUpdate, &out.AvailableUpdate
+		*out = new(FalconAvailableUpdate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertificateExpiry != nil {
+		in, out := &in.CertificateExpiry, &out.CertificateExpiry
+		*out = (*in).DeepCopy()
+	}
+	if in.Coverage != nil {
+		in, out := &in.Coverage, &out.Coverage
+		*out = new(FalconContainerCoverage)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1021,8 +1393,21 @@ func (in *FalconDeploymentSpec) DeepCopyInto(out *FalconDeploymentSpec) {
 	}
 	in.FalconAdmission.DeepCopyInto(&out.FalconAdmission)
 	in.FalconNodeSensor.DeepCopyInto(&out.FalconNodeSensor)
+	if in.NodeSensorProfiles != nil {
+		in, out := &in.NodeSensorProfiles, &out.NodeSensorProfiles
+		*out = make([]FalconNodeSensorProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.FalconImageAnalyzer.DeepCopyInto(&out.FalconImageAnalyzer)
 	in.FalconContainerSensor.DeepCopyInto(&out.FalconContainerSensor)
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
+	in.Advanced.DeepCopyInto(&out.Advanced)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconDeploymentSpec.
@@ -1043,9 +1428,14 @@ func (in *FalconDeploymentStatus) DeepCopyInto(out *FalconDeploymentStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ReleaseTrain != nil {
+		in, out := &in.ReleaseTrain, &out.ReleaseTrain
+		*out = new(FalconReleaseTrain)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1092,6 +1482,11 @@ func (in *FalconImageAnalyzer) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FalconImageAnalyzerAgentServiceSpec) DeepCopyInto(out *FalconImageAnalyzerAgentServiceSpec) {
 	*out = *in
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(FalconCertManager)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconImageAnalyzerAgentServiceSpec.
@@ -1110,19 +1505,19 @@ func (in *FalconImageAnalyzerConfigSpec) DeepCopyInto(out *FalconImageAnalyzerCo
 	in.ServiceAccount.DeepCopyInto(&out.ServiceAccount)
 	if in.ImagePullSecrets != nil {
 		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]corev1.LocalObjectReference, len(*in))
+		*out = make([]v1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
-		*out = new(corev1.ResourceRequirements)
+		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
 	out.PriorityClass = in.PriorityClass
 	in.DepUpdateStrategy.DeepCopyInto(&out.DepUpdateStrategy)
 	in.Exclusions.DeepCopyInto(&out.Exclusions)
 	in.RegistryConfig.DeepCopyInto(&out.RegistryConfig)
-	out.IARAgentService = in.IARAgentService
+	in.IARAgentService.DeepCopyInto(&out.IARAgentService)
 	out.KAC = in.KAC
 }
 
@@ -1263,16 +1658,18 @@ func (in *FalconImageAnalyzerSpec) DeepCopyInto(out *FalconImageAnalyzerSpec) {
 	}
 	if in.NodeAffinity != nil {
 		in, out := &in.NodeAffinity, &out.NodeAffinity
-		*out = new(corev1.NodeAffinity)
+		*out = new(v1.NodeAffinity)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]corev1.Toleration, len(*in))
+		*out = make([]v1.Toleration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.Availability.DeepCopyInto(&out.Availability)
+	in.Advanced.DeepCopyInto(&out.Advanced)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconImageAnalyzerSpec.
@@ -1295,7 +1692,7 @@ func (in *FalconImageAnalyzerStatus) DeepCopyInto(out *FalconImageAnalyzerStatus
 	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1348,6 +1745,21 @@ func (in *FalconInternal) DeepCopy() *FalconInternal {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconIssuerReference) DeepCopyInto(out *FalconIssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconIssuerReference.
+func (in *FalconIssuerReference) DeepCopy() *FalconIssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconIssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FalconNodeSensor) DeepCopyInto(out *FalconNodeSensor) {
 	*out = *in
@@ -1375,24 +1787,67 @@ func (in *FalconNodeSensor) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconNodeSensorCleanup) DeepCopyInto(out *FalconNodeSensorCleanup) {
+	*out = *in
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+	if in.Remaining != nil {
+		in, out := &in.Remaining, &out.Remaining
+		*out = make([]FalconNodeSensorCleanupNode, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconNodeSensorCleanup.
+func (in *FalconNodeSensorCleanup) DeepCopy() *FalconNodeSensorCleanup {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconNodeSensorCleanup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconNodeSensorCleanupNode) DeepCopyInto(out *FalconNodeSensorCleanupNode) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconNodeSensorCleanupNode.
+func (in *FalconNodeSensorCleanupNode) DeepCopy() *FalconNodeSensorCleanupNode {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconNodeSensorCleanupNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FalconNodeSensorConfig) DeepCopyInto(out *FalconNodeSensorConfig) {
 	*out = *in
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
-		*out = new([]corev1.Toleration)
+		*out = new([]v1.Toleration)
 		if **in != nil {
 			in, out := *in, *out
-			*out = make([]corev1.Toleration, len(*in))
+			*out = make([]v1.Toleration, len(*in))
 			for i := range *in {
 				(*in)[i].DeepCopyInto(&(*out)[i])
 			}
 		}
 	}
 	in.NodeAffinity.DeepCopyInto(&out.NodeAffinity)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.ImagePullSecrets != nil {
 		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]corev1.LocalObjectReference, len(*in))
+		*out = make([]v1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
 	in.DSUpdateStrategy.DeepCopyInto(&out.DSUpdateStrategy)
@@ -1411,6 +1866,11 @@ func (in *FalconNodeSensorConfig) DeepCopyInto(out *FalconNodeSensorConfig) {
 		**out = **in
 	}
 	in.Advanced.DeepCopyInto(&out.Advanced)
+	if in.TagTemplates != nil {
+		in, out := &in.TagTemplates, &out.TagTemplates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.ClusterName != nil {
 		in, out := &in.ClusterName, &out.ClusterName
 		*out = new(string)
@@ -1428,6 +1888,21 @@ func (in *FalconNodeSensorConfig) DeepCopy() *FalconNodeSensorConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconNodeSensorExcludedNode) DeepCopyInto(out *FalconNodeSensorExcludedNode) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconNodeSensorExcludedNode.
+func (in *FalconNodeSensorExcludedNode) DeepCopy() *FalconNodeSensorExcludedNode {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconNodeSensorExcludedNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FalconNodeSensorList) DeepCopyInto(out *FalconNodeSensorList) {
 	*out = *in
@@ -1460,6 +1935,115 @@ func (in *FalconNodeSensorList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconNodeSensorNodeVersion) DeepCopyInto(out *FalconNodeSensorNodeVersion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconNodeSensorNodeVersion.
+func (in *FalconNodeSensorNodeVersion) DeepCopy() *FalconNodeSensorNodeVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconNodeSensorNodeVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconNodeSensorNodes) DeepCopyInto(out *FalconNodeSensorNodes) {
+	*out = *in
+	if in.CrashLooping != nil {
+		in, out := &in.CrashLooping, &out.CrashLooping
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Excluded != nil {
+		in, out := &in.Excluded, &out.Excluded
+		*out = make([]FalconNodeSensorExcludedNode, len(*in))
+		copy(*out, *in)
+	}
+	if in.Sensors != nil {
+		in, out := &in.Sensors, &out.Sensors
+		*out = make([]FalconNodeSensorNodeVersion, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconNodeSensorNodes.
+func (in *FalconNodeSensorNodes) DeepCopy() *FalconNodeSensorNodes {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconNodeSensorNodes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconNodeSensorProfile) DeepCopyInto(out *FalconNodeSensorProfile) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = new([]v1.Toleration)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]v1.Toleration, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(Resources)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TagTemplates != nil {
+		in, out := &in.TagTemplates, &out.TagTemplates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconNodeSensorProfile.
+func (in *FalconNodeSensorProfile) DeepCopy() *FalconNodeSensorProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconNodeSensorProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconNodeSensorRemovedNode) DeepCopyInto(out *FalconNodeSensorRemovedNode) {
+	*out = *in
+	in.RemovedAt.DeepCopyInto(&out.RemovedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconNodeSensorRemovedNode.
+func (in *FalconNodeSensorRemovedNode) DeepCopy() *FalconNodeSensorRemovedNode {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconNodeSensorRemovedNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FalconNodeSensorSpec) DeepCopyInto(out *FalconNodeSensorSpec) {
 	*out = *in
@@ -1492,9 +2076,31 @@ func (in *FalconNodeSensorStatus) DeepCopyInto(out *FalconNodeSensorStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.AvailableUpdate != nil {
+		in, out := &in.AvailableUpdate, &out.AvailableUpdate
+		*out = new(FalconAvailableUpdate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = new(FalconNodeSensorNodes)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemovedNodes != nil {
+		in, out := &in.RemovedNodes, &out.RemovedNodes
+		*out = make([]FalconNodeSensorRemovedNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Cleanup != nil {
+		in, out := &in.Cleanup, &out.Cleanup
+		*out = new(FalconNodeSensorCleanup)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1549,6 +2155,52 @@ func (in *FalconNodeUpdateStrategy) DeepCopy() *FalconNodeUpdateStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconPodDisruptionBudget) DeepCopyInto(out *FalconPodDisruptionBudget) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconPodDisruptionBudget.
+func (in *FalconPodDisruptionBudget) DeepCopy() *FalconPodDisruptionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconPodDisruptionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconReleaseTrain) DeepCopyInto(out *FalconReleaseTrain) {
+	*out = *in
+	in.ResolvedAt.DeepCopyInto(&out.ResolvedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconReleaseTrain.
+func (in *FalconReleaseTrain) DeepCopy() *FalconReleaseTrain {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconReleaseTrain)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FalconSecret) DeepCopyInto(out *FalconSecret) {
 	*out = *in
@@ -1587,6 +2239,33 @@ func (in *FalconSensor) DeepCopyInto(out *FalconSensor) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Feature != nil {
+		in, out := &in.Feature, &out.Feature
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MessageLog != nil {
+		in, out := &in.MessageLog, &out.MessageLog
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ProvisioningWaitTime != nil {
+		in, out := &in.ProvisioningWaitTime, &out.ProvisioningWaitTime
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaintenanceTokenSecretRef != nil {
+		in, out := &in.MaintenanceTokenSecretRef, &out.MaintenanceTokenSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExtraOptions != nil {
+		in, out := &in.ExtraOptions, &out.ExtraOptions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconSensor.
@@ -1615,6 +2294,36 @@ func (in *FalconUnified) DeepCopy() *FalconUnified {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FalconWebhookWatchdog) DeepCopyInto(out *FalconWebhookWatchdog) {
+	*out = *in
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SuccessThreshold != nil {
+		in, out := &in.SuccessThreshold, &out.SuccessThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FalconWebhookWatchdog.
+func (in *FalconWebhookWatchdog) DeepCopy() *FalconWebhookWatchdog {
+	if in == nil {
+		return nil
+	}
+	out := new(FalconWebhookWatchdog)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PriorityClassConfig) DeepCopyInto(out *PriorityClassConfig) {
 	*out = *in
@@ -37,6 +37,7 @@ import (
 	arv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	schedulingv1 "k8s.io/api/scheduling/v1"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -44,6 +45,7 @@ import (
 
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
 	admissioncontroller "github.com/crowdstrike/falcon-operator/internal/controller/admission"
+	k8sutils "github.com/crowdstrike/falcon-operator/internal/controller/common"
 	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensorversion"
 	containercontroller "github.com/crowdstrike/falcon-operator/internal/controller/falcon_container"
 	falcondeployment "github.com/crowdstrike/falcon-operator/internal/controller/falcon_deployment"
@@ -57,16 +59,18 @@ import (
 const defaultSensorAutoUpdateInterval = time.Hour * 24
 const defaultLeaseDuration = time.Second * 30
 const defaultRenewDeadline = time.Second * 20
+const sensorUpdateWebhookSecretEnv = "SENSOR_UPDATE_WEBHOOK_SECRET"
 
 var (
 	scheme            = runtime.NewScheme()
 	setupLog          = ctrl.Log.WithName("setup")
 	environment       = "Kubernetes"
 	requiredCacheObjs = map[client.Object]cache.ByObject{
-		&falconv1alpha1.FalconAdmission{}:  {},
-		&falconv1alpha1.FalconNodeSensor{}: {},
-		&falconv1alpha1.FalconContainer{}:  {},
-		&falconv1alpha1.FalconDeployment{}: {},
+		&falconv1alpha1.FalconAdmission{}:        {},
+		&falconv1alpha1.FalconNodeSensor{}:       {},
+		&falconv1alpha1.FalconContainer{}:        {},
+		&falconv1alpha1.FalconContainerProfile{}: {},
+		&falconv1alpha1.FalconDeployment{}:       {},
 		&schedulingv1.PriorityClass{}: {
 			Label: labels.SelectorFromSet(labels.Set{common.FalconComponentKey: common.FalconKernelSensor}),
 		},
@@ -79,12 +83,18 @@ var (
 		&appsv1.Deployment{}: {
 			Label: labels.SelectorFromSet(labels.Set{common.FalconProviderKey: common.FalconProviderValue}),
 		},
+		&policyv1.PodDisruptionBudget{}: {
+			Label: labels.SelectorFromSet(labels.Set{common.FalconProviderKey: common.FalconProviderValue}),
+		},
 		&corev1.ConfigMap{}: {
 			Label: labels.SelectorFromSet(labels.Set{common.FalconProviderKey: common.FalconProviderValue}),
 		},
 		&appsv1.DaemonSet{}: {
 			Label: labels.SelectorFromSet(labels.Set{common.FalconComponentKey: common.FalconKernelSensor}),
 		},
+		&corev1.Pod{}: {
+			Label: labels.SelectorFromSet(labels.Set{common.FalconComponentKey: common.FalconKernelSensor}),
+		},
 		&arv1.MutatingWebhookConfiguration{}: {
 			Label: labels.SelectorFromSet(labels.Set{common.FalconComponentKey: common.FalconSidecarSensor}),
 		},
@@ -109,6 +119,7 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(imagev1.AddToScheme(scheme))
+	utilruntime.Must(certv1.AddToScheme(scheme))
 
 	utilruntime.Must(falconv1alpha1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
@@ -126,6 +137,7 @@ func main() {
 	var ver bool
 	var err error
 	var sensorAutoUpdateInterval time.Duration
+	var sensorUpdateWebhookAddr string
 	var leaseDuration time.Duration
 	var renewDeadline time.Duration
 
@@ -143,6 +155,8 @@ func main() {
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.BoolVar(&ver, "version", false, "Print version")
 	flag.DurationVar(&sensorAutoUpdateInterval, "sensor-auto-update-interval", defaultSensorAutoUpdateInterval, "The rate at which the Falcon API is queried for new sensor versions")
+	flag.StringVar(&sensorUpdateWebhookAddr, "sensor-update-webhook-bind-address", "0", "The address the sensor update webhook endpoint binds to. "+
+		"Requests must be signed with the secret in the "+sensorUpdateWebhookSecretEnv+" environment variable. Use the default value \"0\" to disable it.")
 	flag.DurationVar(&leaseDuration, "lease-duration", defaultLeaseDuration, "The duration that non-leader candidates will wait to force acquire leadership.")
 	flag.DurationVar(&renewDeadline, "renew-deadline", defaultRenewDeadline, "the duration that the acting controlplane will retry refreshing leadership before giving up.")
 
@@ -279,11 +293,12 @@ func main() {
 	tracker := sensorversion.NewTracker(ctx, sensorAutoUpdateInterval)
 
 	if err = (&containercontroller.FalconContainerReconciler{
-		Client:     mgr.GetClient(),
-		Reader:     mgr.GetAPIReader(),
-		Scheme:     mgr.GetScheme(),
-		RestConfig: mgr.GetConfig(),
-		OpenShift:  openShift,
+		Client:      mgr.GetClient(),
+		Reader:      mgr.GetAPIReader(),
+		Scheme:      mgr.GetScheme(),
+		RestConfig:  mgr.GetConfig(),
+		OpenShift:   openShift,
+		CertManager: certManager,
 	}).SetupWithManager(mgr, tracker); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "FalconContainer")
 		os.Exit(1)
@@ -296,21 +311,37 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "FalconNodeSensor")
 		os.Exit(1)
 	}
-	if err = (&admissioncontroller.FalconAdmissionReconciler{
-		Client:    mgr.GetClient(),
-		Reader:    mgr.GetAPIReader(),
-		Scheme:    mgr.GetScheme(),
-		OpenShift: openShift,
+	if err = (&nodecontroller.NodeStatusReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "FalconNodeSensorNodes")
+		os.Exit(1)
+	}
+	if err = (&nodecontroller.SensorTagsReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "FalconNodeSensorTags")
+		os.Exit(1)
+	}
+	if err = (&admissioncontroller.FalconAdmissionReconciler{
+		Client:      mgr.GetClient(),
+		Reader:      mgr.GetAPIReader(),
+		Scheme:      mgr.GetScheme(),
+		OpenShift:   openShift,
+		CertManager: certManager,
+	}).SetupWithManager(mgr, tracker); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "FalconAdmission")
 		os.Exit(1)
 	}
 	if err = (&imageanalyzercontroller.FalconImageAnalyzerReconciler{
-		Client:    mgr.GetClient(),
-		Reader:    mgr.GetAPIReader(),
-		Scheme:    mgr.GetScheme(),
-		OpenShift: openShift,
-	}).SetupWithManager(mgr); err != nil {
+		Client:      mgr.GetClient(),
+		Reader:      mgr.GetAPIReader(),
+		Scheme:      mgr.GetScheme(),
+		OpenShift:   openShift,
+		CertManager: certManager,
+	}).SetupWithManager(mgr, tracker); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "FalconImageAnalyzer")
 		os.Exit(1)
 	}
@@ -318,12 +349,17 @@ func main() {
 		Client: mgr.GetClient(),
 		Reader: mgr.GetAPIReader(),
 		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	}).SetupWithManager(mgr, tracker); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "FalconDeployment")
 		os.Exit(1)
 	}
 	// +kubebuilder:scaffold:builder
 
+	if err := mgr.Add(k8sutils.OrphanedWebhookCleanup(mgr.GetClient(), mgr.GetAPIReader(), ctrl.Log.WithName("webhook-cleanup"))); err != nil {
+		setupLog.Error(err, "unable to set up orphaned webhook cleanup")
+		os.Exit(1)
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -333,6 +369,23 @@ func main() {
 		os.Exit(1)
 	}
 
+	if sensorUpdateWebhookAddr != "0" {
+		secret := os.Getenv(sensorUpdateWebhookSecretEnv)
+		if secret == "" {
+			setupLog.Error(nil, "sensor update webhook requires a secret", "env", sensorUpdateWebhookSecretEnv)
+			os.Exit(1)
+		}
+
+		if err := mgr.Add(sensorversion.RefreshServer{
+			Addr:    sensorUpdateWebhookAddr,
+			Handler: sensorversion.NewRefreshHandler(ctx, tracker, []byte(secret)),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up sensor update webhook")
+			os.Exit(1)
+		}
+		setupLog.Info("sensor update webhook enabled", "address", sensorUpdateWebhookAddr, "path", sensorversion.RefreshPath)
+	}
+
 	if enableProfiling {
 		setupLog.Info("Establishing profile endpoint.")
 		go func() {
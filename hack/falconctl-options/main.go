@@ -0,0 +1,33 @@
+// Generates the extraOptions validation of FalconSensor from common.ReservedFalconctlOptions
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+)
+
+const falconSensorFile = "api/falcon/v1alpha1/falcon.go"
+
+var reservedOptionsRule = regexp.MustCompile(`self\.all\(k, !\(k in \[[^\]]*\]\)\)`)
+
+func main() {
+	src, err := os.ReadFile(falconSensorFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if !reservedOptionsRule.Match(src) {
+		fmt.Fprintf(os.Stderr, "the reserved falconctl options rule was not found in %s\n", falconSensorFile)
+		os.Exit(1)
+	}
+
+	src = reservedOptionsRule.ReplaceAllLiteral(src, []byte(common.ReservedFalconctlOptionsRule()))
+	if err := os.WriteFile(falconSensorFile, src, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
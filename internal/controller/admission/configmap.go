@@ -107,16 +107,17 @@ func (r *FalconAdmissionReconciler) newConfigMap(ctx context.Context, name strin
 	return assets.SensorConfigMap(name, falconAdmission.Spec.InstallNamespace, common.FalconAdmissionController, data), nil
 }
 
-func (r *FalconAdmissionReconciler) reconcileClusterNameConfigMap(ctx context.Context, req ctrl.Request, log logr.Logger, falconAdmission *falconv1alpha1.FalconAdmission) (bool, error) {
-	if falconAdmission.Spec.ClusterName == nil {
+// reconcileClusterNameConfigMap writes the configured or detected cluster name to the ConfigMap read by the KAC
+func (r *FalconAdmissionReconciler) reconcileClusterNameConfigMap(ctx context.Context, req ctrl.Request, log logr.Logger, falconAdmission *falconv1alpha1.FalconAdmission, clusterName string) (bool, error) {
+	if clusterName == "" {
 		changed, err := r.removeClusterNameConfigMapData(ctx, req, log, falconAdmission)
 		return changed, err
 	}
-	return r.reconcileGenericConfigMap(common.FalconAdmissionClusterNameConfigMapName, r.newClusterNameConfigMap, ctx, req, log, falconAdmission)
-}
 
-func (r *FalconAdmissionReconciler) newClusterNameConfigMap(ctx context.Context, name string, falconAdmission *falconv1alpha1.FalconAdmission) (*corev1.ConfigMap, error) {
-	return assets.SensorConfigMap(name, falconAdmission.Spec.InstallNamespace, common.FalconAdmissionController, map[string]string{"ClusterName": *falconAdmission.Spec.ClusterName}), nil
+	newClusterNameConfigMap := func(ctx context.Context, name string, falconAdmission *falconv1alpha1.FalconAdmission) (*corev1.ConfigMap, error) {
+		return assets.SensorConfigMap(name, falconAdmission.Spec.InstallNamespace, common.FalconAdmissionController, map[string]string{"ClusterName": clusterName}), nil
+	}
+	return r.reconcileGenericConfigMap(common.FalconAdmissionClusterNameConfigMapName, newClusterNameConfigMap, ctx, req, log, falconAdmission)
 }
 
 func (r *FalconAdmissionReconciler) removeClusterNameConfigMapData(ctx context.Context, req ctrl.Request, log logr.Logger, falconAdmission *falconv1alpha1.FalconAdmission) (bool, error) {
@@ -2,59 +2,96 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
 	"strconv"
 	"time"
 
+	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
 	"github.com/crowdstrike/falcon-operator/internal/controller/assets"
 	k8sutils "github.com/crowdstrike/falcon-operator/internal/controller/common"
+	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensor"
+	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensorversion"
 	"github.com/crowdstrike/falcon-operator/pkg/aws"
 	"github.com/crowdstrike/falcon-operator/pkg/common"
 	"github.com/crowdstrike/falcon-operator/pkg/registry/pulltoken"
 	"github.com/crowdstrike/falcon-operator/pkg/tls"
 	"github.com/crowdstrike/falcon-operator/version"
+	"github.com/crowdstrike/gofalcon/falcon"
 	"github.com/go-logr/logr"
 	imagev1 "github.com/openshift/api/image/v1"
 	"github.com/operator-framework/operator-lib/proxy"
 	arv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // FalconAdmissionReconciler reconciles a FalconAdmission object
 type FalconAdmissionReconciler struct {
 	client.Client
-	Reader    client.Reader
-	Scheme    *runtime.Scheme
-	OpenShift bool
+	Reader          client.Reader
+	Scheme          *runtime.Scheme
+	OpenShift       bool
+	CertManager     bool
+	reconcileObject func(client.Object)
+	tracker         sensorversion.Tracker
+	watchdog        *k8sutils.WebhookWatchdog
+	recorder        events.EventRecorder
 }
 
 // SetupWithManager sets up the controller with the Manager.
-func (r *FalconAdmissionReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+func (r *FalconAdmissionReconciler) SetupWithManager(mgr ctrl.Manager, tracker sensorversion.Tracker) error {
+	admissionBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&falconv1alpha1.FalconAdmission{}).
 		Owns(&corev1.Namespace{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.ResourceQuota{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Owns(&corev1.Secret{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
-		Owns(&arv1.ValidatingWebhookConfiguration{}).
-		Complete(r)
+		Owns(&arv1.ValidatingWebhookConfiguration{})
+	// Reconcile when cert-manager issues or renews the webhook certificate
+	if r.CertManager {
+		admissionBuilder = admissionBuilder.Owns(&certv1.Certificate{})
+	}
+
+	admissionController, err := admissionBuilder.Build(r)
+	if err != nil {
+		return err
+	}
+
+	r.reconcileObject, err = k8sutils.NewReconcileTrigger(admissionController)
+	if err != nil {
+		return err
+	}
+
+	r.watchdog = k8sutils.NewWebhookWatchdog(mgr.GetLogger().WithName("falconadmission-watchdog"), func(name types.NamespacedName) {
+		r.reconcileObject(&falconv1alpha1.FalconAdmission{ObjectMeta: metav1.ObjectMeta{Name: name.Name}})
+	})
+	if err := mgr.Add(r.watchdog); err != nil {
+		return err
+	}
+
+	r.tracker = tracker
+	r.recorder = mgr.GetEventRecorder("falconadmission")
+	return nil
 }
 
 func (r *FalconAdmissionReconciler) GetK8sClient() client.Client {
@@ -68,6 +105,7 @@ func (r *FalconAdmissionReconciler) GetK8sReader() client.Reader {
 //+kubebuilder:rbac:groups=falcon.crowdstrike.com,resources=falconadmissions,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=falcon.crowdstrike.com,resources=falconadmissions/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=falcon.crowdstrike.com,resources=falconadmissions/finalizers,verbs=update
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;update;delete
 //+kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch;create;update;delete
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;delete
@@ -81,7 +119,10 @@ func (r *FalconAdmissionReconciler) GetK8sReader() client.Reader {
 //+kubebuilder:rbac:groups="apps",resources=statefulsets,verbs=get;list;watch
 //+kubebuilder:rbac:groups="batch",resources=cronjobs;jobs,verbs=get;list;watch
 //+kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups="policy",resources=poddisruptionbudgets,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups="cert-manager.io",resources=certificates,verbs=get;list;watch;create;update;delete
 //+kubebuilder:rbac:groups="coordination.k8s.io",resources=leases,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups="config.openshift.io",resources=infrastructures,verbs=get
 //+kubebuilder:rbac:groups="image.openshift.io",resources=imagestreams,verbs=get;list;watch;create;update;delete
 //+kubebuilder:rbac:groups="admissionregistration.k8s.io",resources=validatingwebhookconfigurations,verbs=get;list;watch;create;update;delete
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=create;get;list;update;watch;delete
@@ -109,11 +150,17 @@ func (r *FalconAdmissionReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	log := log.FromContext(ctx)
 	log.Info("reconciling FalconAdmission")
 
+	// The update policy is checked for its condition and again when selecting the image, but only fetched once
+	ctx = sensor.WithUpdatePolicyCache(ctx)
+
 	// Fetch the FalconAdmission instance
 	falconAdmission := &falconv1alpha1.FalconAdmission{}
 	err := r.Get(ctx, req.NamespacedName, falconAdmission)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
+			r.tracker.StopTracking(sensorVersionTrackKey(req.NamespacedName))
+			r.watchdog.Unwatch(req.NamespacedName)
+
 			// If the custom resource is not found then, it usually means that it was deleted or not created
 			// In this way, we will stop the reconciliation
 			log.Info("FalconAdmission resource not found. Ignoring since object must be deleted")
@@ -124,7 +171,27 @@ func (r *FalconAdmissionReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, err
 	}
 
-	validate, err := k8sutils.CheckRunningPodLabels(r.Reader, ctx, falconAdmission.Spec.InstallNamespace, common.CRLabels("deployment", falconAdmission.Name, common.FalconAdmissionController))
+	// The validating webhook is removed before the Falcon Admission Controller is garbage collected,
+	// so that the API server does not keep calling a service without pods.
+	if falconAdmission.GetDeletionTimestamp() != nil {
+		r.tracker.StopTracking(sensorVersionTrackKey(req.NamespacedName))
+		r.watchdog.Unwatch(req.NamespacedName)
+		return r.finalizeAdmission(ctx, log, falconAdmission)
+	}
+
+	if !controllerutil.ContainsFinalizer(falconAdmission, common.FalconFinalizer) {
+		controllerutil.AddFinalizer(falconAdmission, common.FalconFinalizer)
+		if err := r.Update(ctx, falconAdmission); err != nil {
+			log.Error(err, "Unable to update finalizer")
+			return ctrl.Result{}, err
+		}
+		log.Info("Adding finalizer")
+	}
+
+	// The webhook and watcher pods only differ by their instance label
+	podLabels := common.CRLabels("deployment", falconAdmission.Name, common.FalconAdmissionController)
+	delete(podLabels, common.FalconInstanceKey)
+	validate, err := k8sutils.CheckRunningPodLabels(r.Reader, ctx, falconAdmission.Spec.InstallNamespace, podLabels)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -174,6 +241,58 @@ func (r *FalconAdmissionReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, err
 	}
 
+	if shouldTrackSensorVersions(falconAdmission) {
+		apiConfig, err := r.falconApiConfig(ctx, falconAdmission)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		getSensorVersion := sensorversion.NewPreferredImageQuery(falcon.KacSensor, apiConfig, falconAdmission.Spec.Version, falconAdmission.Spec.Advanced.UpdatePolicy, nil)
+		r.tracker.TrackWithInitialVersion(sensorVersionTrackKey(req.NamespacedName), falcon.KacSensor, getSensorVersion, r.handleSensorVersion, falconAdmission.Spec.Advanced.IsAutoUpdatingForced())
+	} else {
+		r.tracker.StopTracking(sensorVersionTrackKey(req.NamespacedName))
+	}
+
+	if falconAdmission.Status.AvailableUpdate != nil &&
+		(!shouldTrackSensorVersions(falconAdmission) || sensorversion.IsDeployed(falconAdmission.Status.AvailableUpdate, falconAdmission.Status.Sensor)) {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			err := r.Get(ctx, req.NamespacedName, falconAdmission)
+			if err != nil {
+				return err
+			}
+
+			falconAdmission.Status.AvailableUpdate = nil
+			return r.Status().Update(ctx, falconAdmission)
+		})
+		if err != nil {
+			log.Error(err, "Failed to update FalconAdmission status for falconAdmission.Status.AvailableUpdate")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if shouldCheckUpdatePolicy(falconAdmission) {
+		apiConfig, err := r.falconApiConfig(ctx, falconAdmission)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		condition, err := sensor.UpdatePolicyCondition(ctx, apiConfig, falcon.KacSensor, falconAdmission.Spec.Advanced.GetUpdatePolicy())
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		condition.ObservedGeneration = falconAdmission.GetGeneration()
+		if err := k8sutils.ConditionsUpdateWithReason(r.Client, ctx, req, log, falconAdmission, &falconAdmission.Status, condition); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if condition.Status == metav1.ConditionFalse {
+			log.Info("sensor update policy cannot be applied", "reason", condition.Reason, "message", condition.Message)
+			// The sensor version tracker polls the policy and reconciles again once it selects a version
+			return ctrl.Result{}, nil
+		}
+	}
+
 	// Image being set will override other image based settings
 	if falconAdmission.Spec.Image != "" {
 		if _, err := r.setImageTag(ctx, falconAdmission); err != nil {
@@ -249,14 +368,59 @@ func (r *FalconAdmissionReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 
 	admissionTLSSecret, err := r.reconcileTLSSecret(ctx, req, log, falconAdmission)
+	if errors.Is(err, k8sutils.ErrCertificateNotIssued) {
+		log.Info(err.Error())
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	certificateRenewal, err := r.updateCertificateExpiry(ctx, req, log, falconAdmission, admissionTLSSecret)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	clusterName, err := r.resolveClusterName(ctx, req, log, falconAdmission)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
 	if falconAdmission.Spec.FalconSecret.Enabled {
-		if err = r.injectFalconSecretData(ctx, falconAdmission, log); err != nil {
+		err = r.injectFalconSecretData(ctx, falconAdmission, log)
+	}
+
+	// Status updates fetch the FalconAdmission again, so they are made on a copy to keep the injected secret data
+	switch status := falconAdmission.DeepCopy(); {
+	case errors.Is(err, k8sutils.ErrMaintenanceTokenNamespace):
+		// The sensor is deployed without the maintenance token rather than failing to start
+		log.Error(err, "FalconAdmission maintenance token is not passed to the sensor. Please move the FalconSecret to the install namespace.")
+		err = k8sutils.ConditionsUpdateWithReason(r.Client, ctx, req, log, status, &status.Status, metav1.Condition{
+			Status:             metav1.ConditionFalse,
+			Reason:             falconv1alpha1.ReasonInvalidMaintenanceToken,
+			Type:               falconv1alpha1.ConditionFailed,
+			Message:            err.Error(),
+			ObservedGeneration: falconAdmission.GetGeneration(),
+		})
+		if err != nil {
 			return ctrl.Result{}, err
 		}
+	case err != nil:
+		return ctrl.Result{}, err
+	default:
+		if condition := meta.FindStatusCondition(status.Status.Conditions, falconv1alpha1.ConditionFailed); condition != nil && condition.Reason == falconv1alpha1.ReasonInvalidMaintenanceToken {
+			err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				if err := r.Get(ctx, req.NamespacedName, status); err != nil {
+					return err
+				}
+
+				meta.RemoveStatusCondition(&status.Status.Conditions, falconv1alpha1.ConditionFailed)
+				return r.Status().Update(ctx, status)
+			})
+			if err != nil {
+				log.Error(err, "Failed to update FalconAdmission status")
+				return ctrl.Result{}, err
+			}
+		}
 	}
 
 	configUpdated, err := r.reconcileConfigMap(ctx, req, log, falconAdmission)
@@ -264,7 +428,7 @@ func (r *FalconAdmissionReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, err
 	}
 
-	clusterNameConfigUpdated, err := r.reconcileClusterNameConfigMap(ctx, req, log, falconAdmission)
+	clusterNameConfigUpdated, err := r.reconcileClusterNameConfigMap(ctx, req, log, falconAdmission, clusterName)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -279,12 +443,20 @@ func (r *FalconAdmissionReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, err
 	}
 
-	err = r.reconcileAdmissionDeployment(ctx, req, log, falconAdmission)
+	err = r.reconcileAdmissionDeployment(ctx, req, log, falconAdmission, tls.Fingerprint(admissionTLSSecret.Data["tls.crt"]))
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	pod, err := k8sutils.GetReadyPod(r.Reader, ctx, falconAdmission.Spec.InstallNamespace, map[string]string{common.FalconComponentKey: common.FalconAdmissionController})
+	if err := r.reconcilePodDisruptionBudget(ctx, req, log, falconAdmission); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileResourceQuotaStatus(ctx, req, log, falconAdmission); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	pod, err := k8sutils.GetReadyPod(r.Reader, ctx, falconAdmission.Spec.InstallNamespace, map[string]string{common.FalconComponentKey: common.FalconAdmissionController, common.FalconInstanceKey: falconAdmission.Name})
 	if err != nil && err != k8sutils.ErrNoWebhookServicePodReady {
 		log.Error(err, "Failed to find Ready admission controller pod")
 		return ctrl.Result{}, err
@@ -313,78 +485,83 @@ func (r *FalconAdmissionReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, fmt.Errorf("failed to update FalconAdmission installation completion condition: %v", err)
 	}
 
-	return ctrl.Result{}, nil
+	useCertManager := falconAdmission.Spec.AdmissionConfig.TLS.CertManager != nil && r.CertManager
+	return ctrl.Result{RequeueAfter: k8sutils.RenewalRequeue(certificateRenewal, useCertManager)}, nil
 }
 
-func (r *FalconAdmissionReconciler) reconcileResourceQuota(ctx context.Context, req ctrl.Request, log logr.Logger, falconAdmission *falconv1alpha1.FalconAdmission) error {
-	existingRQ := &corev1.ResourceQuota{}
-	defaultPodLimit := "5"
-
-	if falconAdmission.Spec.ResQuota.PodLimit != "" {
-		defaultPodLimit = falconAdmission.Spec.ResQuota.PodLimit
-	}
-
-	rq := assets.ResourceQuota(falconAdmission.Name, falconAdmission.Spec.InstallNamespace, common.FalconAdmissionController, defaultPodLimit)
-
-	err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: falconAdmission.Name, Namespace: falconAdmission.Spec.InstallNamespace}, existingRQ)
-	if err != nil && apierrors.IsNotFound(err) {
-		err = k8sutils.Create(r.Client, r.Scheme, ctx, req, log, falconAdmission, &falconAdmission.Status, rq)
-		if err != nil {
-			return err
-		}
-
-		return nil
-	} else if err != nil {
-		log.Error(err, "Failed to get FalconAdmission ResourceQuota")
+// reconcilePodDisruptionBudget creates, updates or removes the PodDisruptionBudget of the admission controller Deployment
+func (r *FalconAdmissionReconciler) reconcilePodDisruptionBudget(ctx context.Context, req ctrl.Request, log logr.Logger, falconAdmission *falconv1alpha1.FalconAdmission) error {
+	dep := &appsv1.Deployment{}
+	err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: falconAdmission.Name, Namespace: falconAdmission.Spec.InstallNamespace}, dep)
+	if err != nil {
+		log.Error(err, "Failed to get FalconAdmission Deployment")
 		return err
 	}
 
-	podLimit := resource.MustParse(defaultPodLimit)
-	if existingRQ.Spec.Hard["pods"] != podLimit {
-		rq.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ResourceQuota"))
-		err = k8sutils.Update(r.Client, ctx, req, log, falconAdmission, &falconAdmission.Status, rq)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return k8sutils.ReconcilePodDisruptionBudget(ctx, r, falconAdmission, dep, common.FalconAdmissionController, falconAdmission.Spec.AdmissionConfig.Availability,
+		func(obj client.Object) error {
+			return k8sutils.Create(r.Client, r.Scheme, ctx, req, log, falconAdmission, &falconAdmission.Status, obj)
+		},
+		func(obj client.Object) error {
+			return k8sutils.Update(r.Client, ctx, req, log, falconAdmission, &falconAdmission.Status, obj)
+		},
+		func(obj client.Object) error {
+			return k8sutils.Delete(r.Client, ctx, req, log, falconAdmission, &falconAdmission.Status, obj)
+		})
 }
 
+// reconcileTLSSecret creates the TLS Secret of the webhook and renews its certificate when it is due for renewal.
+// When cert-manager issues the certificate, it reconciles the Certificate instead and returns the Secret written by cert-manager.
 func (r *FalconAdmissionReconciler) reconcileTLSSecret(ctx context.Context, req ctrl.Request, log logr.Logger, falconAdmission *falconv1alpha1.FalconAdmission) (*corev1.Secret, error) {
 	existingTLSSecret := &corev1.Secret{}
 	name := falconAdmission.Name + "-tls"
 
-	err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: name, Namespace: falconAdmission.Spec.InstallNamespace}, existingTLSSecret)
+	validity := 3650
+	if falconAdmission.Spec.AdmissionConfig.TLS.Validity != nil {
+		validity = max(*falconAdmission.Spec.AdmissionConfig.TLS.Validity, tls.MinValidityDays)
+	}
 
-	if err != nil && apierrors.IsNotFound(err) {
-		validity := 3650
-		if falconAdmission.Spec.AdmissionConfig.TLS.Validity != nil {
-			validity = *falconAdmission.Spec.AdmissionConfig.TLS.Validity
-		}
+	certInfo := tls.CertInfo{
+		CommonName: fmt.Sprintf("%s.%s.svc", falconAdmission.Name, falconAdmission.Spec.InstallNamespace),
+		DNSNames: []string{fmt.Sprintf("%s.%s.svc", falconAdmission.Name, falconAdmission.Spec.InstallNamespace), fmt.Sprintf("%s.%s.svc.cluster.local", falconAdmission.Name, falconAdmission.Spec.InstallNamespace),
+			fmt.Sprintf("%s.%s", falconAdmission.Name, falconAdmission.Spec.InstallNamespace), falconAdmission.Name},
+	}
 
-		certInfo := tls.CertInfo{
-			CommonName: fmt.Sprintf("%s.%s.svc", falconAdmission.Name, falconAdmission.Spec.InstallNamespace),
-			DNSNames: []string{fmt.Sprintf("%s.%s.svc", falconAdmission.Name, falconAdmission.Spec.InstallNamespace), fmt.Sprintf("%s.%s.svc.cluster.local", falconAdmission.Name, falconAdmission.Spec.InstallNamespace),
-				fmt.Sprintf("%s.%s", falconAdmission.Name, falconAdmission.Spec.InstallNamespace), falconAdmission.Name},
-		}
+	labels := common.CRLabels("secret", name, common.FalconAdmissionController)
+	labels[common.AppLabelKey] = common.FalconAdmissionServiceApp
+	labels[common.KubernetesComponentKey] = common.FalconAdmissionComponentName
+	labels[common.KubernetesNameKey] = falconAdmission.Name
+
+	if k8sutils.UseCertManager(log, falconAdmission.Spec.AdmissionConfig.TLS.CertManager, r.CertManager) {
+		certificate := assets.Certificate(name, falconAdmission.Spec.InstallNamespace, common.FalconAdmissionController, certInfo.CommonName, certInfo.DNSNames, validity,
+			falconAdmission.Spec.AdmissionConfig.TLS.CertManager.IssuerRef, labels)
+		return k8sutils.ReconcileCertificate(ctx, r, certificate,
+			func(obj client.Object) error {
+				return k8sutils.Create(r.Client, r.Scheme, ctx, req, log, falconAdmission, &falconAdmission.Status, obj)
+			},
+			func(obj client.Object) error {
+				return k8sutils.Update(r.Client, ctx, req, log, falconAdmission, &falconAdmission.Status, obj)
+			})
+	}
 
-		c, k, b, err := tls.CertSetup(falconAdmission.Spec.InstallNamespace, validity, certInfo)
+	genSecretData := func() (map[string][]byte, error) {
+		bundle, err := tls.NewBundle(falconAdmission.Spec.InstallNamespace, validity, certInfo)
 		if err != nil {
 			log.Error(err, "Failed to generate FalconAdmission PKI")
-			return &corev1.Secret{}, err
+			return nil, err
 		}
 
-		secretData := map[string][]byte{
-			"tls.crt": c,
-			"tls.key": k,
-			"ca.crt":  b,
+		return bundle.SecretData(), nil
+	}
+
+	err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: name, Namespace: falconAdmission.Spec.InstallNamespace}, existingTLSSecret)
+
+	if err != nil && apierrors.IsNotFound(err) {
+		secretData, err := genSecretData()
+		if err != nil {
+			return &corev1.Secret{}, err
 		}
 
-		labels := common.CRLabels("secret", name, common.FalconAdmissionController)
-		labels[common.AppLabelKey] = common.FalconAdmissionServiceApp
-		labels[common.KubernetesComponentKey] = common.FalconAdmissionComponentName
-		labels[common.KubernetesNameKey] = falconAdmission.Name
 		admissionTLSSecret := assets.SecretWithCustomLabels(name, falconAdmission.Spec.InstallNamespace, secretData, corev1.SecretTypeTLS, labels)
 		err = k8sutils.Create(r.Client, r.Scheme, ctx, req, log, falconAdmission, &falconAdmission.Status, admissionTLSSecret)
 		if err != nil {
@@ -396,12 +573,64 @@ func (r *FalconAdmissionReconciler) reconcileTLSSecret(ctx context.Context, req
 		return &corev1.Secret{}, err
 	}
 
+	cert, err := tls.ParseCertificate(existingTLSSecret.Data["tls.crt"])
+	if err == nil && time.Now().Before(tls.RenewalTime(cert)) {
+		return existingTLSSecret, nil
+	}
+
+	if err != nil {
+		log.Error(err, "Failed to parse FalconAdmission TLS certificate, generating a new one")
+	} else {
+		log.Info("Renewing FalconAdmission TLS certificate", "expiry", cert.NotAfter)
+	}
+
+	secretData, err := genSecretData()
+	if err != nil {
+		return &corev1.Secret{}, err
+	}
+
+	// Keep trusting the previous CA until the pods serve the new certificate
+	secretData["ca.crt"] = tls.CABundle(time.Now(), secretData["ca.crt"], existingTLSSecret.Data["ca.crt"])
+	existingTLSSecret.Data = secretData
+	existingTLSSecret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	if err := k8sutils.Update(r.Client, ctx, req, log, falconAdmission, &falconAdmission.Status, existingTLSSecret); err != nil {
+		return &corev1.Secret{}, err
+	}
+
 	return existingTLSSecret, nil
 }
 
+// updateCertificateExpiry records the expiry of the webhook certificate in the status, and returns when the certificate is due for renewal
+func (r *FalconAdmissionReconciler) updateCertificateExpiry(ctx context.Context, req ctrl.Request, log logr.Logger, falconAdmission *falconv1alpha1.FalconAdmission, tlsSecret *corev1.Secret) (time.Time, error) {
+	cert, err := tls.ParseCertificate(tlsSecret.Data["tls.crt"])
+	if err != nil {
+		log.Error(err, "Failed to parse FalconAdmission TLS certificate")
+		return time.Time{}, err
+	}
+
+	expiry := metav1.NewTime(cert.NotAfter)
+	if falconAdmission.Status.CertificateExpiry == nil || !falconAdmission.Status.CertificateExpiry.Equal(&expiry) {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			err := r.Get(ctx, req.NamespacedName, falconAdmission)
+			if err != nil {
+				return err
+			}
+			falconAdmission.Status.CertificateExpiry = &expiry
+			return r.Status().Update(ctx, falconAdmission)
+		})
+		if err != nil {
+			log.Error(err, "Failed to update FalconAdmission status for falconAdmission.Status.CertificateExpiry")
+			return time.Time{}, err
+		}
+	}
+
+	return tls.RenewalTime(cert), nil
+}
+
 func (r *FalconAdmissionReconciler) reconcileService(ctx context.Context, req ctrl.Request, log logr.Logger, falconAdmission *falconv1alpha1.FalconAdmission) (bool, error) {
 	existingService := &corev1.Service{}
-	selector := map[string]string{common.FalconComponentKey: common.FalconAdmissionController}
+	// Select the webhook pods only, not the ones of the watcher
+	selector := map[string]string{common.FalconComponentKey: common.FalconAdmissionController, common.FalconInstanceKey: falconAdmission.Name}
 	port := int32(443)
 
 	if falconAdmission.Spec.AdmissionConfig.Port != nil {
@@ -446,8 +675,9 @@ func (r *FalconAdmissionReconciler) reconcileService(ctx context.Context, req ct
 		return false, err
 	}
 
-	if !reflect.DeepEqual(service.Spec.Ports, existingService.Spec.Ports) {
+	if !reflect.DeepEqual(service.Spec.Ports, existingService.Spec.Ports) || !reflect.DeepEqual(service.Spec.Selector, existingService.Spec.Selector) {
 		existingService.Spec.Ports = service.Spec.Ports
+		existingService.Spec.Selector = service.Spec.Selector
 		existingService.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Service"))
 		if err := k8sutils.Update(r.Client, ctx, req, log, falconAdmission, &falconAdmission.Status, existingService); err != nil {
 			return false, err
@@ -488,10 +718,33 @@ func (r *FalconAdmissionReconciler) reconcileAdmissionValidatingWebHook(ctx cont
 		port = *falconAdmission.Spec.AdmissionConfig.Port
 	}
 
-	webhook := assets.ValidatingWebhook(falconAdmission.Name, falconAdmission.Spec.InstallNamespace, common.FalconAdmissionValidatingWebhookName, cabundle, port, failPolicy, disabledNamespaces)
+	failingOpen, err := r.reconcileWatchdog(ctx, req, log, falconAdmission, cabundle, port)
+	if err != nil {
+		return false, err
+	}
+	if failingOpen {
+		failPolicy = arv1.Ignore
+	}
+
+	webhook := assets.ValidatingWebhook(falconAdmission.Name, falconAdmission.Spec.InstallNamespace, common.FalconAdmissionValidatingWebhookName, cabundle, port, failPolicy, disabledNamespaces, falconAdmission.Spec.AdmissionConfig.Webhook)
+	// When cert-manager issues the certificate, the caBundle is left to the cert-manager CA injector
+	injectCAFrom := ""
+	if falconAdmission.Spec.AdmissionConfig.TLS.CertManager != nil && r.CertManager {
+		injectCAFrom = falconAdmission.Spec.InstallNamespace + "/" + falconAdmission.Name + "-tls"
+	}
+	k8sutils.SetInjectCAFrom(webhook, injectCAFrom)
 	updated := false
 
 	err = common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: common.FalconAdmissionValidatingWebhookName}, existingWebhook)
+	if injectCAFrom != "" {
+		var injectedCABundle []byte
+		if err == nil && len(existingWebhook.Webhooks) > 0 {
+			injectedCABundle = existingWebhook.Webhooks[0].ClientConfig.CABundle
+		}
+		for i := range webhook.Webhooks {
+			webhook.Webhooks[i].ClientConfig.CABundle = injectedCABundle
+		}
+	}
 	switch {
 	case err == nil && !falconAdmission.GetAdmissionControlEnabled():
 		err = k8sutils.Delete(r.Client, ctx, req, log, falconAdmission, &falconAdmission.Status, webhook)
@@ -512,32 +765,123 @@ func (r *FalconAdmissionReconciler) reconcileAdmissionValidatingWebHook(ctx cont
 		return false, err
 	}
 
-	if !reflect.DeepEqual(webhook.Webhooks[0].FailurePolicy, existingWebhook.Webhooks[0].FailurePolicy) {
+	// The failure policy only changes how the API server handles webhook errors, so changing it, for example when the watchdog
+	// fails the webhook open, updates the webhook without restarting the Falcon Admission Controller
+	failurePolicyUpdated := false
+	if len(webhook.Webhooks) != len(existingWebhook.Webhooks) {
 		updated = true
-	}
+	} else {
+		for i := range webhook.Webhooks {
+			if !equality.Semantic.DeepEqual(webhook.Webhooks[i].FailurePolicy, existingWebhook.Webhooks[i].FailurePolicy) {
+				failurePolicyUpdated = true
+			}
 
-	if !reflect.DeepEqual(webhook.Webhooks[0].ClientConfig, existingWebhook.Webhooks[0].ClientConfig) {
-		updated = true
+			if webhook.Webhooks[i].Name != existingWebhook.Webhooks[i].Name ||
+				!equality.Semantic.DeepEqual(webhook.Webhooks[i].ClientConfig, existingWebhook.Webhooks[i].ClientConfig) ||
+				!equality.Semantic.DeepEqual(webhook.Webhooks[i].NamespaceSelector, existingWebhook.Webhooks[i].NamespaceSelector) ||
+				!equality.Semantic.DeepEqual(webhook.Webhooks[i].ObjectSelector, existingWebhook.Webhooks[i].ObjectSelector) ||
+				!equality.Semantic.DeepEqual(webhook.Webhooks[i].TimeoutSeconds, existingWebhook.Webhooks[i].TimeoutSeconds) ||
+				!equality.Semantic.DeepEqual(webhook.Webhooks[i].Rules, existingWebhook.Webhooks[i].Rules) {
+				updated = true
+			}
+		}
 	}
 
-	if !reflect.DeepEqual(webhook.Webhooks[0].NamespaceSelector, existingWebhook.Webhooks[0].NamespaceSelector) {
+	if k8sutils.SetInjectCAFrom(existingWebhook, injectCAFrom) {
 		updated = true
 	}
 
-	if updated {
+	if updated || failurePolicyUpdated {
 		existingWebhook.Webhooks = webhook.Webhooks
 		existingWebhook.SetGroupVersionKind(arv1.SchemeGroupVersion.WithKind("ValidatingWebhookConfiguration"))
 		if err := k8sutils.Update(r.Client, ctx, req, log, falconAdmission, &falconAdmission.Status, existingWebhook); err != nil {
 			return false, err
 		}
 
-		return true, nil
+		return updated, nil
 	}
 
 	return false, nil
 }
 
-func (r *FalconAdmissionReconciler) reconcileAdmissionDeployment(ctx context.Context, req ctrl.Request, log logr.Logger, falconAdmission *falconv1alpha1.FalconAdmission) error {
+// reconcileWatchdog has the watchdog probe the Falcon Admission Controller when it is enabled, reports its health in the status,
+// and returns whether the webhook should fail open
+func (r *FalconAdmissionReconciler) reconcileWatchdog(ctx context.Context, req ctrl.Request, log logr.Logger, falconAdmission *falconv1alpha1.FalconAdmission, cabundle []byte, port int32) (bool, error) {
+	watchdog := falconAdmission.Spec.AdmissionConfig.Watchdog
+	if r.watchdog == nil || !watchdog.IsEnabled() || !falconAdmission.GetAdmissionControlEnabled() {
+		r.watchdog.Unwatch(req.NamespacedName)
+
+		if meta.FindStatusCondition(falconAdmission.Status.Conditions, falconv1alpha1.ConditionWebhookHealthy) == nil {
+			return false, nil
+		}
+
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			if err := r.Get(ctx, req.NamespacedName, falconAdmission); err != nil {
+				return err
+			}
+			meta.RemoveStatusCondition(&falconAdmission.Status.Conditions, falconv1alpha1.ConditionWebhookHealthy)
+			return r.Status().Update(ctx, falconAdmission)
+		})
+		if err != nil {
+			log.Error(err, "Failed to update FalconAdmission status")
+		}
+		return false, err
+	}
+
+	url := fmt.Sprintf("https://%s.%s.svc:%d%s", falconAdmission.Name, falconAdmission.Spec.InstallNamespace, port, common.FalconAdmissionLivenessProbePath)
+	if err := r.watchdog.Watch(req.NamespacedName, k8sutils.NewWebhookProbe(url, cabundle, watchdog)); err != nil {
+		return false, err
+	}
+
+	health := r.watchdog.Health(req.NamespacedName)
+	if !health.Probed {
+		return false, nil
+	}
+
+	condition := health.Condition(falconAdmission.GetGeneration())
+	previous := meta.FindStatusCondition(falconAdmission.Status.Conditions, falconv1alpha1.ConditionWebhookHealthy)
+	if previous != nil && previous.Status == condition.Status {
+		return health.FailingOpen, nil
+	}
+
+	if health.FailingOpen {
+		r.recorder.Eventf(falconAdmission, nil, corev1.EventTypeWarning, condition.Reason, "Watchdog", "%s", condition.Message)
+	} else if previous != nil {
+		r.recorder.Eventf(falconAdmission, nil, corev1.EventTypeNormal, condition.Reason, "Watchdog", "The webhook failure policy is restored")
+	}
+
+	return health.FailingOpen, k8sutils.ConditionsUpdateWithReason(r.Client, ctx, req, log, falconAdmission, &falconAdmission.Status, condition)
+}
+
+// finalizeAdmission deletes the validating webhook, and removes the finalizer once the webhook is gone
+func (r *FalconAdmissionReconciler) finalizeAdmission(ctx context.Context, log logr.Logger, falconAdmission *falconv1alpha1.FalconAdmission) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(falconAdmission, common.FalconFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	webhook := &arv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: common.FalconAdmissionValidatingWebhookName}}
+	gone, err := k8sutils.RemoveWebhook(ctx, r.Client, r.Reader, webhook)
+	if err != nil {
+		log.Error(err, "Failed to delete FalconAdmission Validating Webhook")
+		return ctrl.Result{}, err
+	}
+	if !gone {
+		log.Info("Waiting for FalconAdmission Validating Webhook to be deleted")
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	}
+
+	// Once the finalizer is removed, the Falcon Admission Controller resources are garbage collected
+	controllerutil.RemoveFinalizer(falconAdmission, common.FalconFinalizer)
+	if err := r.Update(ctx, falconAdmission); err != nil {
+		return ctrl.Result{}, err
+	}
+	log.Info("Removing finalizer")
+
+	return ctrl.Result{}, nil
+}
+
+// reconcileAdmissionDeployment reconciles the Deployment serving the webhook and, when it is deployed, the Deployment of the falcon-watcher
+func (r *FalconAdmissionReconciler) reconcileAdmissionDeployment(ctx context.Context, req ctrl.Request, log logr.Logger, falconAdmission *falconv1alpha1.FalconAdmission, certFingerprint string) error {
 	imageUri, err := r.imageUri(ctx, falconAdmission)
 	if err != nil {
 		return fmt.Errorf("unable to determine falcon container image URI: %v", err)
@@ -548,8 +892,40 @@ func (r *FalconAdmissionReconciler) reconcileAdmissionDeployment(ctx context.Con
 		falconAdmission.Spec.AdmissionConfig.ContainerPort = &port
 	}
 
-	existingDeployment := &appsv1.Deployment{}
 	dep := assets.AdmissionDeployment(falconAdmission.Name, falconAdmission.Spec.InstallNamespace, common.FalconAdmissionController, imageUri, falconAdmission, log)
+	// Restart the pods when the serving certificate is renewed
+	dep.Spec.Template.Annotations[common.FalconTLSCertificateKey] = certFingerprint
+
+	if err := r.reconcileDeployment(ctx, req, log, falconAdmission, dep); err != nil {
+		return err
+	}
+
+	watcherDep := assets.AdmissionWatcherDeployment(falconAdmission.Name, falconAdmission.Spec.InstallNamespace, common.FalconAdmissionController, imageUri, falconAdmission)
+	if falconAdmission.Spec.AdmissionConfig.DeployWatcherContainer() {
+		return r.reconcileDeployment(ctx, req, log, falconAdmission, watcherDep)
+	}
+
+	existingWatcherDep := &appsv1.Deployment{}
+	err = common.GetNamespacedObject(ctx, r.Client, r.Reader, client.ObjectKeyFromObject(watcherDep), existingWatcherDep)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		log.Error(err, "Failed to get FalconAdmission watcher Deployment")
+		return err
+	}
+
+	if !metav1.IsControlledBy(existingWatcherDep, falconAdmission) {
+		return nil
+	}
+
+	existingWatcherDep.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+	return k8sutils.Delete(r.Client, ctx, req, log, falconAdmission, &falconAdmission.Status, existingWatcherDep)
+}
+
+// reconcileDeployment creates the Deployment, or updates the settings of the existing one the operator manages
+func (r *FalconAdmissionReconciler) reconcileDeployment(ctx context.Context, req ctrl.Request, log logr.Logger, falconAdmission *falconv1alpha1.FalconAdmission, dep *appsv1.Deployment) error {
+	existingDeployment := &appsv1.Deployment{}
+	log = log.WithValues("deployment", dep.Name)
 
 	if len(proxy.ReadProxyVarsFromEnv()) > 0 {
 		for i, container := range dep.Spec.Template.Spec.Containers {
@@ -557,7 +933,7 @@ func (r *FalconAdmissionReconciler) reconcileAdmissionDeployment(ctx context.Con
 		}
 	}
 
-	err = common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: falconAdmission.Name, Namespace: falconAdmission.Spec.InstallNamespace}, existingDeployment)
+	err := common.GetNamespacedObject(ctx, r.Client, r.Reader, client.ObjectKeyFromObject(dep), existingDeployment)
 	if err != nil && apierrors.IsNotFound(err) {
 		err = k8sutils.Create(r.Client, r.Scheme, ctx, req, log, falconAdmission, &falconAdmission.Status, dep)
 		if err != nil {
@@ -581,13 +957,22 @@ func (r *FalconAdmissionReconciler) reconcileAdmissionDeployment(ctx context.Con
 
 	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		// Re-fetch the latest version of the deployment to avoid conflicts
-		err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: falconAdmission.Name, Namespace: falconAdmission.Spec.InstallNamespace}, existingDeployment)
+		err := common.GetNamespacedObject(ctx, r.Client, r.Reader, client.ObjectKeyFromObject(dep), existingDeployment)
 		if err != nil {
 			return err
 		}
 
 		updated := false
 
+		if certFingerprint, ok := dep.Spec.Template.Annotations[common.FalconTLSCertificateKey]; ok && existingDeployment.Spec.Template.Annotations[common.FalconTLSCertificateKey] != certFingerprint {
+			log.V(1).Info("Updating FalconAdmission Deployment: TLS certificate changed")
+			if existingDeployment.Spec.Template.Annotations == nil {
+				existingDeployment.Spec.Template.Annotations = map[string]string{}
+			}
+			existingDeployment.Spec.Template.Annotations[common.FalconTLSCertificateKey] = certFingerprint
+			updated = true
+		}
+
 		if !reflect.DeepEqual(dep.Spec.Template.Spec.ImagePullSecrets, existingDeployment.Spec.Template.Spec.ImagePullSecrets) {
 			log.V(1).Info("Updating FalconAdmission Deployment: ImagePullSecrets changed",
 				"old", existingDeployment.Spec.Template.Spec.ImagePullSecrets,
@@ -596,11 +981,11 @@ func (r *FalconAdmissionReconciler) reconcileAdmissionDeployment(ctx context.Con
 			updated = true
 		}
 
-		if !equality.Semantic.DeepEqual(existingDeployment.Spec.Strategy.RollingUpdate, dep.Spec.Strategy.RollingUpdate) {
-			log.V(1).Info("Updating FalconAdmission Deployment: RollingUpdate strategy changed",
-				"old", existingDeployment.Spec.Strategy.RollingUpdate,
-				"new", dep.Spec.Strategy.RollingUpdate)
-			existingDeployment.Spec.Strategy.RollingUpdate = dep.Spec.Strategy.RollingUpdate
+		if !equality.Semantic.DeepEqual(existingDeployment.Spec.Strategy, dep.Spec.Strategy) {
+			log.V(1).Info("Updating FalconAdmission Deployment: update strategy changed",
+				"old", existingDeployment.Spec.Strategy,
+				"new", dep.Spec.Strategy)
+			existingDeployment.Spec.Strategy = dep.Spec.Strategy
 			updated = true
 		}
 
@@ -618,11 +1003,11 @@ func (r *FalconAdmissionReconciler) reconcileAdmissionDeployment(ctx context.Con
 			updated = true
 		}
 
-		if !reflect.DeepEqual(dep.Spec.Template.Spec.Affinity.NodeAffinity, existingDeployment.Spec.Template.Spec.Affinity.NodeAffinity) {
-			log.V(1).Info("Updating FalconAdmission Deployment: NodeAffinity changed",
-				"old", existingDeployment.Spec.Template.Spec.Affinity.NodeAffinity,
-				"new", dep.Spec.Template.Spec.Affinity.NodeAffinity)
-			existingDeployment.Spec.Template.Spec.Affinity.NodeAffinity = dep.Spec.Template.Spec.Affinity.NodeAffinity
+		if !reflect.DeepEqual(dep.Spec.Template.Spec.Affinity, existingDeployment.Spec.Template.Spec.Affinity) {
+			log.V(1).Info("Updating FalconAdmission Deployment: Affinity changed",
+				"old", existingDeployment.Spec.Template.Spec.Affinity,
+				"new", dep.Spec.Template.Spec.Affinity)
+			existingDeployment.Spec.Template.Spec.Affinity = dep.Spec.Template.Spec.Affinity
 			updated = true
 		}
 
@@ -852,40 +1237,116 @@ func (r *FalconAdmissionReconciler) reconcileNamespace(ctx context.Context, req
 	return nil
 }
 
+// admissionDeploymentUpdate rolls the webhook and watcher Deployments, as both read the configuration the change applies to
 func (r *FalconAdmissionReconciler) admissionDeploymentUpdate(ctx context.Context, req ctrl.Request, log logr.Logger, falconAdmission *falconv1alpha1.FalconAdmission) error {
-	existingDeployment := &appsv1.Deployment{}
 	configVersion := "falcon.config.version"
-	err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: falconAdmission.Name, Namespace: falconAdmission.Spec.InstallNamespace}, existingDeployment)
-	if err != nil && apierrors.IsNotFound(err) {
-		return err
-	} else if err != nil {
-		log.Error(err, "Failed to get FalconAdmission Deployment")
-		return err
+
+	for _, name := range []string{falconAdmission.Name, assets.AdmissionWatcherName(falconAdmission.Name)} {
+		existingDeployment := &appsv1.Deployment{}
+		err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: name, Namespace: falconAdmission.Spec.InstallNamespace}, existingDeployment)
+		if err != nil && apierrors.IsNotFound(err) {
+			if name != falconAdmission.Name {
+				continue
+			}
+			return err
+		} else if err != nil {
+			log.Error(err, "Failed to get FalconAdmission Deployment", "deployment", name)
+			return err
+		}
+
+		if existingDeployment.Spec.Template.Annotations == nil {
+			existingDeployment.Spec.Template.Annotations = map[string]string{}
+		}
+
+		_, ok := existingDeployment.Spec.Template.Annotations[configVersion]
+		if ok {
+			i, err := strconv.Atoi(existingDeployment.Spec.Template.Annotations[configVersion])
+			if err != nil {
+				return err
+			}
+
+			existingDeployment.Spec.Template.Annotations[configVersion] = strconv.Itoa(i + 1)
+		} else {
+			existingDeployment.Spec.Template.Annotations[configVersion] = "1"
+		}
+
+		log.Info("Rolling FalconAdmission Deployment due to non-deployment configuration change", "deployment", name)
+		existingDeployment.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+		if err := k8sutils.Update(r.Client, ctx, req, log, falconAdmission, &falconAdmission.Status, existingDeployment); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveClusterName returns the cluster name reported by the KAC, detecting it when it is not configured and detection is enabled,
+// and reports it with the ClusterNameReady condition
+func (r *FalconAdmissionReconciler) resolveClusterName(ctx context.Context, req ctrl.Request, log logr.Logger, falconAdmission *falconv1alpha1.FalconAdmission) (string, error) {
+	configured := ""
+	if falconAdmission.Spec.ClusterName != nil {
+		configured = *falconAdmission.Spec.ClusterName
 	}
 
-	_, ok := existingDeployment.Spec.Template.Annotations[configVersion]
-	if ok {
-		i, err := strconv.Atoi(existingDeployment.Spec.Template.Annotations[configVersion])
+	clusterName, condition := k8sutils.ResolveClusterName(ctx, r.Reader, configured, falconAdmission.Spec.DetectClusterName)
+
+	return clusterName, k8sutils.ConditionsUpdateWithReason(r.Client, ctx, req, log, falconAdmission, &falconAdmission.Status, condition)
+}
+
+func (r *FalconAdmissionReconciler) injectFalconSecretData(ctx context.Context, falconAdmission *falconv1alpha1.FalconAdmission, logger logr.Logger) error {
+	logger.Info("injecting Falcon secret data into Spec.Falcon and Spec.FalconAPI - sensitive manifest values will be overwritten with values in k8s secret")
+
+	return k8sutils.InjectFalconSecretData(ctx, r, falconAdmission, falconAdmission.Spec.InstallNamespace)
+}
+
+// handleSensorVersion records the sensor version selected by the tracker as a pending update, and reconciles the object to deploy it when automatic updates are enabled
+// or to retry an update policy that could not select a version.
+func (r *FalconAdmissionReconciler) handleSensorVersion(ctx context.Context, name types.NamespacedName, sensorVersion string) error {
+	obj := &falconv1alpha1.FalconAdmission{}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := r.Get(ctx, name, obj)
 		if err != nil {
 			return err
 		}
 
-		existingDeployment.Spec.Template.Annotations[configVersion] = strconv.Itoa(i + 1)
-	} else {
-		existingDeployment.Spec.Template.Annotations[configVersion] = "1"
-	}
+		update := sensorversion.AvailableUpdate(obj.Status.AvailableUpdate, obj.Status.Sensor, sensorVersion, obj.Spec.Advanced.AvailableUpdateSource())
+		if update == obj.Status.AvailableUpdate {
+			return nil
+		}
 
-	log.Info("Rolling FalconAdmission Deployment due to non-deployment configuration change")
-	existingDeployment.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
-	if err := k8sutils.Update(r.Client, ctx, req, log, falconAdmission, &falconAdmission.Status, existingDeployment); err != nil {
+		obj.Status.AvailableUpdate = update
+		return r.Status().Update(ctx, obj)
+	})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
 		return err
 	}
 
+	if obj.Spec.Advanced.IsAutoUpdating() || sensorversion.IsBlockedByUpdatePolicy(obj.Status.Conditions) {
+		log.FromContext(ctx).Info("reconciling FalconAdmission object", "namespace", obj.Namespace, "name", obj.Name)
+		r.reconcileObject(obj)
+	}
+
 	return nil
 }
 
-func (r *FalconAdmissionReconciler) injectFalconSecretData(ctx context.Context, falconAdmission *falconv1alpha1.FalconAdmission, logger logr.Logger) error {
-	logger.Info("injecting Falcon secret data into Spec.Falcon and Spec.FalconAPI - sensitive manifest values will be overwritten with values in k8s secret")
+// sensorVersionTrackKey identifies the sensor version track of a FalconAdmission.
+func sensorVersionTrackKey(name types.NamespacedName) sensorversion.Key {
+	return sensorversion.NewKey(falconv1alpha1.GroupVersion.WithKind("FalconAdmission"), name)
+}
+
+// shouldTrackSensorVersions reports whether new sensor versions are tracked, either to deploy them automatically or to report them as pending updates.
+// Tracking is opt-in, so resources that only use the Falcon API to pick the initial image do not poll it.
+func shouldTrackSensorVersions(obj *falconv1alpha1.FalconAdmission) bool {
+	return obj.Spec.FalconAPI != nil && obj.Spec.Image == "" &&
+		obj.Spec.Advanced.IsTrackingUpdates()
+}
 
-	return k8sutils.InjectFalconSecretData(ctx, r, falconAdmission)
+// shouldCheckUpdatePolicy reports whether the sensor version is selected by an update policy rather than an explicit image or version.
+func shouldCheckUpdatePolicy(obj *falconv1alpha1.FalconAdmission) bool {
+	return obj.Spec.FalconAPI != nil && obj.Spec.Image == "" &&
+		(obj.Spec.Version == nil || *obj.Spec.Version == "") &&
+		obj.Spec.Advanced.HasUpdatePolicy()
 }
@@ -8,6 +8,7 @@ import (
 
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
 	k8sutils "github.com/crowdstrike/falcon-operator/internal/controller/common"
+	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensorversion"
 	internalErrors "github.com/crowdstrike/falcon-operator/internal/errors"
 	"github.com/crowdstrike/falcon-operator/pkg/common"
 	. "github.com/onsi/ginkgo/v2"
@@ -124,6 +125,11 @@ var _ = Describe("FalconAdmission controller", func() {
 			// Delete FalconAdmission custom resource
 			falconAdmissionCR := &falconv1alpha1.FalconAdmission{}
 			Expect(k8sClient.Get(ctx, admissionNamespacedName, falconAdmissionCR)).To(Succeed())
+			// Remove finalizer for successful FalconAdmission CR deletion
+			patch := client.MergeFrom(falconAdmissionCR.DeepCopy())
+			falconAdmissionCR.SetFinalizers(nil)
+			_ = k8sClient.Patch(ctx, falconAdmissionCR, patch)
+
 			Expect(k8sClient.Delete(ctx, falconAdmissionCR)).To(Succeed())
 
 			// Delete cluster level resources
@@ -156,10 +162,14 @@ var _ = Describe("FalconAdmission controller", func() {
 			}, 20*time.Second, time.Second).Should(Succeed())
 
 			By("Reconciling the custom resource created")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconAdmissionReconciler := &FalconAdmissionReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 			_, err = falconAdmissionReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: admissionNamespacedName,
@@ -286,10 +296,14 @@ var _ = Describe("FalconAdmission controller", func() {
 			}, 6*time.Second, time.Second).Should(Succeed())
 
 			By("Reconciling the custom resource created")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconAdmissionReconciler := &FalconAdmissionReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			_, err = falconAdmissionReconciler.Reconcile(ctx, reconcile.Request{
@@ -419,10 +433,14 @@ var _ = Describe("FalconAdmission controller", func() {
 			}, 6*time.Second, time.Second).Should(Succeed())
 
 			By("Reconciling the custom resource created")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconAdmissionReconciler := &FalconAdmissionReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			_, err = falconAdmissionReconciler.Reconcile(ctx, reconcile.Request{
@@ -451,10 +469,14 @@ var _ = Describe("FalconAdmission controller", func() {
 			}, time.Minute, time.Second).Should(Succeed())
 
 			By("Reconciling the custom resource create - with admission control disabled")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconAdmissionReconciler := &FalconAdmissionReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 			_, err = falconAdmissionReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: admissionNamespacedName,
@@ -514,10 +536,14 @@ var _ = Describe("FalconAdmission controller", func() {
 			}, 20*time.Second, time.Second).Should(Succeed())
 
 			By("Creating the reconciler")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconAdmissionReconciler := &FalconAdmissionReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			By("Calling reconcileServiceAccount when no service account exists")
@@ -552,10 +578,14 @@ var _ = Describe("FalconAdmission controller", func() {
 			}, 20*time.Second, time.Second).Should(Succeed())
 
 			By("Creating the reconciler")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconAdmissionReconciler := &FalconAdmissionReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			By("Creating initial service account")
@@ -603,10 +633,14 @@ var _ = Describe("FalconAdmission controller", func() {
 			}, 20*time.Second, time.Second).Should(Succeed())
 
 			By("Creating the reconciler")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconAdmissionReconciler := &FalconAdmissionReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			By("Creating initial service account with default imagePullSecrets")
@@ -669,10 +703,14 @@ var _ = Describe("FalconAdmission controller", func() {
 			}, 20*time.Second, time.Second).Should(Succeed())
 
 			By("Creating the reconciler")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconAdmissionReconciler := &FalconAdmissionReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			By("Creating initial service account")
@@ -708,10 +746,14 @@ var _ = Describe("FalconAdmission controller", func() {
 			}, 20*time.Second, time.Second).Should(Succeed())
 
 			By("Creating the reconciler")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconAdmissionReconciler := &FalconAdmissionReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			By("Creating initial service account with multiple imagePullSecrets")
@@ -772,10 +814,14 @@ var _ = Describe("FalconAdmission controller", func() {
 			}, 20*time.Second, time.Second).Should(Succeed())
 
 			By("Creating the reconciler")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconAdmissionReconciler := &FalconAdmissionReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			By("Creating initial service account with operator-managed annotations")
@@ -853,10 +899,14 @@ var _ = Describe("FalconAdmission controller", func() {
 			}, 20*time.Second, time.Second).Should(Succeed())
 
 			By("Creating the reconciler")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconAdmissionReconciler := &FalconAdmissionReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			By("Running initial reconciliation to create deployment")
@@ -960,10 +1010,14 @@ var _ = Describe("FalconAdmission controller", func() {
 			}, 20*time.Second, time.Second).Should(Succeed())
 
 			By("Creating the reconciler")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconAdmissionReconciler := &FalconAdmissionReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			By("Running initial reconciliation to create deployment without proxy vars")
@@ -1143,10 +1197,14 @@ var _ = Describe("FalconAdmission controller", func() {
 			}, 20*time.Second, time.Second).Should(Succeed())
 
 			By("Reconciling the custom resource created")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconAdmissionReconciler := &FalconAdmissionReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 			_, err = falconAdmissionReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: admissionNamespacedName,
@@ -1203,10 +1261,14 @@ var _ = Describe("FalconAdmission controller", func() {
 			}, 20*time.Second, time.Second).Should(Succeed())
 
 			By("Reconciling the custom resource")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconAdmissionReconciler := &FalconAdmissionReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 			_, err = falconAdmissionReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: admissionNamespacedName,
@@ -1257,10 +1319,14 @@ var _ = Describe("FalconAdmission controller", func() {
 
 			Expect(k8sClient.Create(ctx, falconAdmission)).To(Succeed())
 
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconAdmissionReconciler := &FalconAdmissionReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			_, err := falconAdmissionReconciler.Reconcile(ctx, reconcile.Request{
@@ -1313,10 +1379,14 @@ var _ = Describe("FalconAdmission controller", func() {
 
 			Expect(k8sClient.Create(ctx, falconAdmission)).To(Succeed())
 
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconAdmissionReconciler := &FalconAdmissionReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			_, err := falconAdmissionReconciler.Reconcile(ctx, reconcile.Request{
@@ -1419,10 +1489,14 @@ var _ = Describe("FalconAdmission controller", func() {
 
 			Expect(k8sClient.Create(ctx, falconAdmission)).To(Succeed())
 
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconAdmissionReconciler := &FalconAdmissionReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			_, err := falconAdmissionReconciler.Reconcile(ctx, reconcile.Request{
@@ -1522,10 +1596,14 @@ var _ = Describe("FalconAdmission controller", func() {
 
 			Expect(k8sClient.Create(ctx, falconAdmission)).To(Succeed())
 
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconAdmissionReconciler := &FalconAdmissionReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			_, err := falconAdmissionReconciler.Reconcile(ctx, reconcile.Request{
@@ -1639,10 +1717,14 @@ var _ = Describe("FalconAdmission controller", func() {
 
 			Expect(k8sClient.Create(ctx, falconAdmission)).To(Succeed())
 
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconAdmissionReconciler := &FalconAdmissionReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			_, err := falconAdmissionReconciler.Reconcile(ctx, reconcile.Request{
@@ -10,6 +10,8 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensor"
+	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensorversion"
 	"github.com/crowdstrike/falcon-operator/internal/controller/image"
 	"github.com/crowdstrike/falcon-operator/pkg/aws"
 	"github.com/crowdstrike/falcon-operator/pkg/common"
@@ -48,6 +50,9 @@ func (r *FalconAdmissionReconciler) PushImage(ctx context.Context, log logr.Logg
 
 	imageRefresher := image.NewImageRefresher(ctx, log, apiConfig, pushAuth, falconAdmission.Spec.Registry.TLS.InsecureSkipVerify)
 	version := falconAdmission.Spec.Version
+	if approved, ok := sensorversion.ApprovedVersion(falconAdmission, falconAdmission.Status.AvailableUpdate); ok {
+		version = &approved
+	}
 
 	tag, err := imageRefresher.Refresh(registryUri, falcon.KacSensor, version)
 	if err != nil {
@@ -209,18 +214,23 @@ func (r *FalconAdmissionReconciler) setImageTag(ctx context.Context, falconAdmis
 		return *falconAdmission.Status.Sensor, r.Client.Status().Update(ctx, falconAdmission)
 	}
 
+	if approved, ok := sensorversion.ApprovedVersion(falconAdmission, falconAdmission.Status.AvailableUpdate); ok {
+		falconAdmission.Status.Sensor = &approved
+		return approved, nil
+	}
+
 	// Otherwise, get the newest version matching the requested version string
 	apiConfig, err := r.falconApiConfig(ctx, falconAdmission)
 	if err != nil {
 		return "", err
 	}
 
-	registry, err := falcon_registry.NewFalconRegistry(ctx, apiConfig)
+	imageRepo, err := sensor.NewImageRepository(ctx, apiConfig)
 	if err != nil {
 		return "", err
 	}
 
-	tag, err := registry.LastContainerTag(ctx, falcon.KacSensor, falconAdmission.Spec.Version)
+	tag, err := imageRepo.GetPreferredImage(ctx, falcon.KacSensor, falconAdmission.Spec.Version, falconAdmission.Spec.Advanced.UpdatePolicy)
 	if err == nil {
 		falconAdmission.Status.Sensor = common.ImageVersion(tag)
 	}
@@ -257,5 +267,9 @@ func (r *FalconAdmissionReconciler) imageMirroringEnabled(falconAdmission *falco
 }
 
 func (r *FalconAdmissionReconciler) versionLock(falconAdmission *falconv1alpha1.FalconAdmission) bool {
+	if _, approved := sensorversion.ApprovedVersion(falconAdmission, falconAdmission.Status.AvailableUpdate); approved || falconAdmission.Spec.Advanced.HasUpdatePolicy() || falconAdmission.Spec.Advanced.IsAutoUpdating() {
+		return false
+	}
+
 	return (falconAdmission.Spec.Version != nil && falconAdmission.Status.Sensor != nil && strings.Contains(*falconAdmission.Status.Sensor, *falconAdmission.Spec.Version)) || (falconAdmission.Spec.Version == nil && falconAdmission.Status.Sensor != nil)
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -36,6 +37,40 @@ func TestVersionLock_WithSameVersion(t *testing.T) {
 	assert.True(t, reconciler.versionLock(admission))
 }
 
+func TestVersionLock_WithAutoUpdate(t *testing.T) {
+	reconciler := &FalconAdmissionReconciler{}
+	admission := &falconv1alpha1.FalconAdmission{}
+	admission.Status.Sensor = stringPointer("some sensor")
+	admission.Spec.Advanced.AutoUpdate = stringPointer(falconv1alpha1.Normal)
+	assert.False(t, reconciler.versionLock(admission))
+}
+
+func TestVersionLock_WithUpdatePolicy(t *testing.T) {
+	reconciler := &FalconAdmissionReconciler{}
+	admission := &falconv1alpha1.FalconAdmission{}
+	admission.Status.Sensor = stringPointer("some sensor")
+	admission.Spec.Advanced.UpdatePolicy = stringPointer("some policy")
+	assert.False(t, reconciler.versionLock(admission))
+}
+
+func TestVersionLock_WithApprovedUpdate(t *testing.T) {
+	reconciler := &FalconAdmissionReconciler{}
+	admission := &falconv1alpha1.FalconAdmission{}
+	admission.Status.Sensor = stringPointer("some sensor")
+	admission.Status.AvailableUpdate = &falconv1alpha1.FalconAvailableUpdate{Version: "newer sensor"}
+	admission.SetAnnotations(map[string]string{common.FalconApproveVersionKey: "newer sensor"})
+	assert.False(t, reconciler.versionLock(admission))
+}
+
+func TestVersionLock_WithUnapprovedUpdate(t *testing.T) {
+	reconciler := &FalconAdmissionReconciler{}
+	admission := &falconv1alpha1.FalconAdmission{}
+	admission.Status.Sensor = stringPointer("some sensor")
+	admission.Status.AvailableUpdate = &falconv1alpha1.FalconAvailableUpdate{Version: "newer sensor"}
+	admission.SetAnnotations(map[string]string{common.FalconApproveVersionKey: "other sensor"})
+	assert.True(t, reconciler.versionLock(admission))
+}
+
 func stringPointer(s string) *string {
 	return &s
 }
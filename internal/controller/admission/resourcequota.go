@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/internal/controller/assets"
+	k8sutils "github.com/crowdstrike/falcon-operator/internal/controller/common"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultMaxSurge is the maxSurge Kubernetes applies to a rolling update that does not set it
+var defaultMaxSurge = intstr.FromString("25%")
+
+// reconcileResourceQuota limits the pods of the namespace to the configured limit, or to the ones the Deployments of the
+// Falcon Admission Controller need, and removes the ResourceQuota when it is disabled
+func (r *FalconAdmissionReconciler) reconcileResourceQuota(ctx context.Context, req ctrl.Request, log logr.Logger, falconAdmission *falconv1alpha1.FalconAdmission) error {
+	existingRQ := &corev1.ResourceQuota{}
+	err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: falconAdmission.Name, Namespace: falconAdmission.Spec.InstallNamespace}, existingRQ)
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Error(err, "Failed to get FalconAdmission ResourceQuota")
+		return err
+	}
+	exists := err == nil
+	existingRQ.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ResourceQuota"))
+
+	if !falconAdmission.Spec.ResQuota.GetEnabled() {
+		if exists && metav1.IsControlledBy(existingRQ, falconAdmission) {
+			return k8sutils.Delete(r.Client, ctx, req, log, falconAdmission, &falconAdmission.Status, existingRQ)
+		}
+		return nil
+	}
+
+	podLimit, err := resourceQuotaPodLimit(falconAdmission)
+	if err != nil {
+		return err
+	}
+
+	rq := assets.ResourceQuota(falconAdmission.Name, falconAdmission.Spec.InstallNamespace, common.FalconAdmissionController, podLimit.String())
+	if !exists {
+		return k8sutils.Create(r.Client, r.Scheme, ctx, req, log, falconAdmission, &falconAdmission.Status, rq)
+	}
+
+	if existingRQ.Spec.Hard.Pods().Cmp(podLimit) != 0 {
+		log.V(1).Info("Updating FalconAdmission ResourceQuota", "old", existingRQ.Spec.Hard.Pods().String(), "new", podLimit.String())
+		existingRQ.Spec.Hard = rq.Spec.Hard
+		return k8sutils.Update(r.Client, ctx, req, log, falconAdmission, &falconAdmission.Status, existingRQ)
+	}
+
+	return nil
+}
+
+// resourceQuotaPodLimit returns the configured pod limit. Without one, it returns the number of pods the Deployments of the
+// Falcon Admission Controller run at most, which is during a rolling update, or the configured minimum when it is higher.
+func resourceQuotaPodLimit(falconAdmission *falconv1alpha1.FalconAdmission) (resource.Quantity, error) {
+	if podLimit := falconAdmission.Spec.ResQuota.PodLimit; podLimit != "" {
+		limit, err := resource.ParseQuantity(podLimit)
+		if err != nil {
+			return resource.Quantity{}, fmt.Errorf("invalid resource quota pod limit %q: %v", podLimit, err)
+		}
+		return limit, nil
+	}
+
+	admissionConfig := falconAdmission.Spec.AdmissionConfig
+
+	replicas := int32(1)
+	if admissionConfig.Replicas != nil {
+		replicas = *admissionConfig.Replicas
+	}
+
+	surge, err := maxSurgePods(admissionConfig.DepUpdateStrategy.RollingUpdate.MaxSurge, replicas)
+	if err != nil {
+		return resource.Quantity{}, err
+	}
+	pods := replicas + surge
+
+	// The falcon-watcher is recreated on updates, so it never runs more than one pod
+	if admissionConfig.DeployWatcherContainer() {
+		pods++
+	}
+
+	limit := *resource.NewQuantity(int64(pods), resource.DecimalSI)
+	if minPodLimit := falconAdmission.Spec.ResQuota.MinPodLimit; minPodLimit != "" {
+		minimum, err := resource.ParseQuantity(minPodLimit)
+		if err != nil {
+			return resource.Quantity{}, fmt.Errorf("invalid resource quota minimum pod limit %q: %v", minPodLimit, err)
+		}
+
+		if minimum.Cmp(limit) > 0 {
+			limit = minimum
+		}
+	}
+
+	return limit, nil
+}
+
+// maxSurgePods returns the number of pods a rolling update of the given replicas creates above them
+func maxSurgePods(maxSurge *intstr.IntOrString, replicas int32) (int32, error) {
+	if maxSurge == nil {
+		maxSurge = &defaultMaxSurge
+	}
+
+	surge, err := intstr.GetScaledValueFromIntOrPercent(maxSurge, int(replicas), true)
+	if err != nil {
+		return 0, fmt.Errorf("invalid maxSurge %q: %v", maxSurge.String(), err)
+	}
+
+	return int32(surge), nil
+}
+
+// reconcileResourceQuotaStatus reports with the ResourceQuotaOK condition whether a resource quota of the namespace prevents a
+// ReplicaSet of the Falcon Admission Controller from creating its pods, which stalls the rollout of its Deployment
+func (r *FalconAdmissionReconciler) reconcileResourceQuotaStatus(ctx context.Context, req ctrl.Request, log logr.Logger, falconAdmission *falconv1alpha1.FalconAdmission) error {
+	replicaSets := &appsv1.ReplicaSetList{}
+	if err := r.Reader.List(ctx, replicaSets, client.InNamespace(falconAdmission.Spec.InstallNamespace), client.MatchingLabels{common.FalconComponentKey: common.FalconAdmissionController}); err != nil {
+		return fmt.Errorf("unable to list FalconAdmission ReplicaSets: %v", err)
+	}
+
+	condition := metav1.Condition{
+		Type:               falconv1alpha1.ConditionResourceQuotaOK,
+		Status:             metav1.ConditionTrue,
+		Reason:             falconv1alpha1.ReasonResourceQuotaSufficient,
+		Message:            "No rollout of the Falcon Admission Controller is blocked by a resource quota",
+		ObservedGeneration: falconAdmission.GetGeneration(),
+	}
+
+	if message := quotaExceededMessage(replicaSets.Items); message != "" {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = falconv1alpha1.ReasonResourceQuotaExceeded
+		condition.Message = message
+		log.Info("FalconAdmission rollout is blocked by a resource quota", "message", message)
+
+		if existing := meta.FindStatusCondition(falconAdmission.Status.Conditions, condition.Type); r.recorder != nil && (existing == nil || existing.Reason != condition.Reason) {
+			r.recorder.Eventf(falconAdmission, nil, corev1.EventTypeWarning, condition.Reason, "ResourceQuota", "%s", condition.Message)
+		}
+	}
+
+	return k8sutils.ConditionsUpdateWithReason(r.Client, ctx, req, log, falconAdmission, &falconAdmission.Status, condition)
+}
+
+// quotaExceededMessage returns the pod creation failure of the first ReplicaSet that a resource quota prevents from creating pods.
+// The ReplicaSet controller reports the failure with the ReplicaFailure condition, along with a FailedCreate event.
+func quotaExceededMessage(replicaSets []appsv1.ReplicaSet) string {
+	for _, replicaSet := range replicaSets {
+		for _, condition := range replicaSet.Status.Conditions {
+			if condition.Type == appsv1.ReplicaSetReplicaFailure && condition.Status == corev1.ConditionTrue && strings.Contains(condition.Message, "exceeded quota") {
+				return fmt.Sprintf("ReplicaSet %s cannot create pods: %s", replicaSet.Name, condition.Message)
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,239 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func TestResourceQuotaPodLimit(t *testing.T) {
+	surge := func(s string) *intstr.IntOrString {
+		v := intstr.Parse(s)
+		return &v
+	}
+
+	tests := []struct {
+		name        string
+		config      falconv1alpha1.FalconAdmissionConfigSpec
+		podLimit    string
+		minPodLimit string
+		want        int64
+		wantErr     bool
+	}{
+		{
+			name:   "defaults surge 25% of the webhook replicas and run one watcher",
+			config: falconv1alpha1.FalconAdmissionConfigSpec{Replicas: int32Ptr(2)},
+			want:   4,
+		},
+		{
+			name: "absolute maxSurge",
+			config: falconv1alpha1.FalconAdmissionConfigSpec{
+				Replicas:          int32Ptr(5),
+				DepUpdateStrategy: falconv1alpha1.FalconAdmissionUpdateStrategy{RollingUpdate: falconv1alpha1.FalconAdmissionRollingUpdate{MaxSurge: surge("3")}},
+			},
+			want: 9,
+		},
+		{
+			name: "percent maxSurge is rounded up",
+			config: falconv1alpha1.FalconAdmissionConfigSpec{
+				Replicas:          int32Ptr(5),
+				DepUpdateStrategy: falconv1alpha1.FalconAdmissionUpdateStrategy{RollingUpdate: falconv1alpha1.FalconAdmissionRollingUpdate{MaxSurge: surge("50%")}},
+			},
+			want: 9,
+		},
+		{
+			name: "watcher is recreated without surge",
+			config: falconv1alpha1.FalconAdmissionConfigSpec{
+				Replicas:          int32Ptr(2),
+				DepUpdateStrategy: falconv1alpha1.FalconAdmissionUpdateStrategy{RollingUpdate: falconv1alpha1.FalconAdmissionRollingUpdate{MaxSurge: surge("1")}},
+			},
+			want: 4,
+		},
+		{
+			name: "no watcher",
+			config: falconv1alpha1.FalconAdmissionConfigSpec{
+				Replicas:          int32Ptr(2),
+				DeployWatcher:     boolPtr(false),
+				DepUpdateStrategy: falconv1alpha1.FalconAdmissionUpdateStrategy{RollingUpdate: falconv1alpha1.FalconAdmissionRollingUpdate{MaxSurge: surge("0")}},
+			},
+			want: 2,
+		},
+		{
+			name:     "configured limit caps the pods below the computed limit",
+			config:   falconv1alpha1.FalconAdmissionConfigSpec{Replicas: int32Ptr(2)},
+			podLimit: "2",
+			want:     2,
+		},
+		{
+			name:     "configured limit above the computed limit is kept",
+			config:   falconv1alpha1.FalconAdmissionConfigSpec{Replicas: int32Ptr(2)},
+			podLimit: "10",
+			want:     10,
+		},
+		{
+			name:     "invalid configured limit",
+			config:   falconv1alpha1.FalconAdmissionConfigSpec{Replicas: int32Ptr(2)},
+			podLimit: "many",
+			wantErr:  true,
+		},
+		{
+			name:        "minimum below the computed limit is ignored",
+			config:      falconv1alpha1.FalconAdmissionConfigSpec{Replicas: int32Ptr(2)},
+			minPodLimit: "2",
+			want:        4,
+		},
+		{
+			name:        "minimum above the computed limit raises it",
+			config:      falconv1alpha1.FalconAdmissionConfigSpec{Replicas: int32Ptr(2)},
+			minPodLimit: "10",
+			want:        10,
+		},
+		{
+			name:        "invalid minimum",
+			config:      falconv1alpha1.FalconAdmissionConfigSpec{Replicas: int32Ptr(2)},
+			minPodLimit: "many",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			falconAdmission := &falconv1alpha1.FalconAdmission{}
+			falconAdmission.Spec.AdmissionConfig = tt.config
+			falconAdmission.Spec.ResQuota.PodLimit = tt.podLimit
+			falconAdmission.Spec.ResQuota.MinPodLimit = tt.minPodLimit
+
+			got, err := resourceQuotaPodLimit(falconAdmission)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Value())
+		})
+	}
+}
+
+func TestReconcileResourceQuota(t *testing.T) {
+	ctx := context.Background()
+	log := zap.New(zap.UseDevMode(true))
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, falconv1alpha1.AddToScheme(scheme))
+
+	falconAdmission := &falconv1alpha1.FalconAdmission{
+		ObjectMeta: metav1.ObjectMeta{Name: "falcon-kac", UID: "falcon-kac-uid"},
+		Spec: falconv1alpha1.FalconAdmissionSpec{
+			InstallNamespace: "falcon-kac",
+			AdmissionConfig:  falconv1alpha1.FalconAdmissionConfigSpec{Replicas: int32Ptr(2)},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(falconAdmission).
+		WithStatusSubresource(falconAdmission).
+		Build()
+
+	reconciler := &FalconAdmissionReconciler{
+		Client: fakeClient,
+		Reader: fakeClient,
+		Scheme: scheme,
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: falconAdmission.Name}}
+	key := types.NamespacedName{Name: "falcon-kac", Namespace: "falcon-kac"}
+
+	require.NoError(t, reconciler.reconcileResourceQuota(ctx, req, log, falconAdmission))
+	rq := &corev1.ResourceQuota{}
+	require.NoError(t, fakeClient.Get(ctx, key, rq))
+	assert.Equal(t, int64(4), rq.Spec.Hard.Pods().Value())
+
+	falconAdmission.Spec.AdmissionConfig.Replicas = int32Ptr(6)
+	require.NoError(t, reconciler.reconcileResourceQuota(ctx, req, log, falconAdmission))
+	require.NoError(t, fakeClient.Get(ctx, key, rq))
+	assert.Equal(t, int64(9), rq.Spec.Hard.Pods().Value(), "the quota follows the replicas")
+
+	falconAdmission.Spec.ResQuota.Enabled = boolPtr(false)
+	require.NoError(t, reconciler.reconcileResourceQuota(ctx, req, log, falconAdmission))
+	assert.True(t, apierrors.IsNotFound(fakeClient.Get(ctx, key, rq)), "a disabled quota is removed")
+}
+
+func TestReconcileResourceQuotaStatus(t *testing.T) {
+	ctx := context.Background()
+	log := zap.New(zap.UseDevMode(true))
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, falconv1alpha1.AddToScheme(scheme))
+
+	falconAdmission := &falconv1alpha1.FalconAdmission{
+		ObjectMeta: metav1.ObjectMeta{Name: "falcon-kac"},
+		Spec:       falconv1alpha1.FalconAdmissionSpec{InstallNamespace: "falcon-kac"},
+	}
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "falcon-kac-6c9f",
+			Namespace: "falcon-kac",
+			Labels:    map[string]string{common.FalconComponentKey: common.FalconAdmissionController},
+		},
+		Status: appsv1.ReplicaSetStatus{
+			Conditions: []appsv1.ReplicaSetCondition{
+				{
+					Type:    appsv1.ReplicaSetReplicaFailure,
+					Status:  corev1.ConditionTrue,
+					Reason:  "FailedCreate",
+					Message: `pods "falcon-kac-6c9f-x2" is forbidden: exceeded quota: falcon-kac, requested: pods=1, used: pods=3, limited: pods=3`,
+				},
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(falconAdmission, replicaSet).
+		WithStatusSubresource(falconAdmission, replicaSet).
+		Build()
+
+	reconciler := &FalconAdmissionReconciler{
+		Client: fakeClient,
+		Reader: fakeClient,
+		Scheme: scheme,
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: falconAdmission.Name}}
+
+	require.NoError(t, reconciler.reconcileResourceQuotaStatus(ctx, req, log, falconAdmission))
+	condition := meta.FindStatusCondition(falconAdmission.Status.Conditions, falconv1alpha1.ConditionResourceQuotaOK)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, falconv1alpha1.ReasonResourceQuotaExceeded, condition.Reason)
+	assert.Contains(t, condition.Message, "falcon-kac-6c9f")
+
+	replicaSet.Status.Conditions = nil
+	require.NoError(t, fakeClient.Status().Update(ctx, replicaSet))
+	require.NoError(t, reconciler.reconcileResourceQuotaStatus(ctx, req, log, falconAdmission))
+	condition = meta.FindStatusCondition(falconAdmission.Status.Conditions, falconv1alpha1.ConditionResourceQuotaOK)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/internal/controller/assets"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	arv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestReconcileAdmissionValidatingWebHook(t *testing.T) {
+	ctx := context.Background()
+	log := zap.New(zap.UseDevMode(true))
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, arv1.AddToScheme(scheme))
+	require.NoError(t, falconv1alpha1.AddToScheme(scheme))
+
+	falconAdmission := &falconv1alpha1.FalconAdmission{
+		ObjectMeta: metav1.ObjectMeta{Name: "falcon-kac", UID: "falcon-kac-uid"},
+		Spec: falconv1alpha1.FalconAdmissionSpec{
+			InstallNamespace: "falcon-kac",
+			AdmissionConfig:  falconv1alpha1.FalconAdmissionConfigSpec{FailurePolicy: arv1.Fail},
+		},
+	}
+
+	cabundle := []byte("ca")
+	existing := assets.ValidatingWebhook(falconAdmission.Name, falconAdmission.Spec.InstallNamespace, common.FalconAdmissionValidatingWebhookName,
+		cabundle, 443, arv1.Fail, common.DefaultDisabledNamespaces, falconAdmission.Spec.AdmissionConfig.Webhook)
+	// The webhook configuration is cluster scoped
+	existing.Namespace = ""
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(falconAdmission, existing).
+		WithStatusSubresource(falconAdmission).
+		Build()
+
+	reconciler := &FalconAdmissionReconciler{
+		Client: fakeClient,
+		Reader: fakeClient,
+		Scheme: scheme,
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: falconAdmission.Name}}
+	key := types.NamespacedName{Name: common.FalconAdmissionValidatingWebhookName}
+
+	falconAdmission.Spec.AdmissionConfig.FailurePolicy = arv1.Ignore
+	updated, err := reconciler.reconcileAdmissionValidatingWebHook(ctx, req, log, falconAdmission, cabundle)
+	require.NoError(t, err)
+	assert.False(t, updated, "a failure policy change does not restart the pods")
+
+	webhook := &arv1.ValidatingWebhookConfiguration{}
+	require.NoError(t, fakeClient.Get(ctx, key, webhook))
+	for _, w := range webhook.Webhooks {
+		assert.Equal(t, arv1.Ignore, *w.FailurePolicy)
+	}
+
+	updated, err = reconciler.reconcileAdmissionValidatingWebHook(ctx, req, log, falconAdmission, []byte("new ca"))
+	require.NoError(t, err)
+	assert.True(t, updated, "a CA bundle change restarts the pods")
+}
@@ -0,0 +1,47 @@
+package assets
+
+import (
+	"time"
+
+	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Certificate returns a cert-manager Certificate object that issues a serving certificate into the TLS Secret of the same name.
+// The secret labels are applied to the Secret written by cert-manager.
+func Certificate(name string, namespace string, component string, commonName string, dnsNames []string, days int, issuer falconv1alpha1.FalconIssuerReference, secretLabels map[string]string) *certv1.Certificate {
+	labels := common.CRLabels("certificate", name, component)
+
+	return &certv1.Certificate{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: certv1.SchemeGroupVersion.String(),
+			Kind:       certv1.CertificateKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: certv1.CertificateSpec{
+			SecretName: name,
+			SecretTemplate: &certv1.CertificateSecretTemplate{
+				Labels: secretLabels,
+			},
+			CommonName: commonName,
+			DNSNames:   dnsNames,
+			Duration:   &metav1.Duration{Duration: time.Duration(days) * 24 * time.Hour},
+			IssuerRef: cmmeta.ObjectReference{
+				Name:  issuer.Name,
+				Kind:  issuer.Kind,
+				Group: issuer.Group,
+			},
+			Usages: []certv1.KeyUsage{certv1.UsageServerAuth, certv1.UsageDigitalSignature, certv1.UsageKeyEncipherment},
+			PrivateKey: &certv1.CertificatePrivateKey{
+				RotationPolicy: certv1.RotationPolicyAlways,
+			},
+		},
+	}
+}
@@ -0,0 +1,47 @@
+package assets
+
+import (
+	"testing"
+	"time"
+
+	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestCertificate tests the Certificate function
+func TestCertificate(t *testing.T) {
+	issuer := falconv1alpha1.FalconIssuerReference{Name: "test-issuer", Kind: "ClusterIssuer", Group: "cert-manager.io"}
+	secretLabels := map[string]string{"app": "test"}
+	dnsNames := []string{"test.test.svc", "test.test.svc.cluster.local"}
+
+	want := &certv1.Certificate{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: certv1.SchemeGroupVersion.String(),
+			Kind:       "Certificate",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+			Labels:    common.CRLabels("certificate", "test", "test"),
+		},
+		Spec: certv1.CertificateSpec{
+			SecretName:     "test",
+			SecretTemplate: &certv1.CertificateSecretTemplate{Labels: secretLabels},
+			CommonName:     "test.test.svc",
+			DNSNames:       dnsNames,
+			Duration:       &metav1.Duration{Duration: 90 * 24 * time.Hour},
+			IssuerRef:      cmmeta.ObjectReference{Name: "test-issuer", Kind: "ClusterIssuer", Group: "cert-manager.io"},
+			Usages:         []certv1.KeyUsage{certv1.UsageServerAuth, certv1.UsageDigitalSignature, certv1.UsageKeyEncipherment},
+			PrivateKey:     &certv1.CertificatePrivateKey{RotationPolicy: certv1.RotationPolicyAlways},
+		},
+	}
+
+	got := Certificate("test", "test", "test", "test.test.svc", dnsNames, 90, issuer, secretLabels)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Certificate() mismatch (-want +got): %s", diff)
+	}
+}
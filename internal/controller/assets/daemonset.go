@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"maps"
 	"reflect"
+	"strings"
 
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
 	"github.com/crowdstrike/falcon-operator/pkg/common"
@@ -15,6 +16,13 @@ import (
 
 const (
 	nobodyGroup = 65534
+
+	// NodeCleanupInstanceName is the instance name label of the pods that remove the sensor from nodes that are no longer targeted by the DaemonSet
+	NodeCleanupInstanceName = "node-cleanup"
+
+	sensorTagsVolumeName = "sensor-tags"
+	sensorTagsMountPath  = "/etc/falcon-sensor-tags"
+	sensorTagsFile       = "annotations"
 )
 
 func getTermGracePeriod(node *falconv1alpha1.FalconNodeSensor) *int64 {
@@ -34,6 +42,18 @@ func nodeAffinity(node *falconv1alpha1.FalconNodeSensor) *corev1.Affinity {
 	return &corev1.Affinity{}
 }
 
+// nodeSelector restricts the DaemonSet to Linux nodes matching the FalconNodeSensor node selector
+func nodeSelector(node *falconv1alpha1.FalconNodeSensor) map[string]string {
+	selector := maps.Clone(node.Spec.Node.NodeSelector)
+	if selector == nil {
+		selector = map[string]string{}
+	}
+
+	// The Linux node selector takes precedence until windows containers are supported for the Falcon sensor
+	maps.Copy(selector, common.NodeSelector)
+	return selector
+}
+
 func pullSecrets(node *falconv1alpha1.FalconNodeSensor) []corev1.LocalObjectReference {
 	if len(node.Spec.Node.ImagePullSecrets) == 0 {
 		return []corev1.LocalObjectReference{
@@ -223,11 +243,111 @@ func volumes() []corev1.Volume {
 	}
 }
 
-func DaemonsetConfigMapName(node *falconv1alpha1.FalconNodeSensor) string {
-	if *node.Spec.Node.GKE.Enabled {
-		return common.GKEAutoPilotConfigMapName
+// usesTagTemplates reports whether sensor grouping tags are resolved for each node.
+// The operator records the tags of each node in an annotation of the sensor pod, which the sensor reads through the downward API.
+func usesTagTemplates(node *falconv1alpha1.FalconNodeSensor) bool {
+	return len(node.Spec.Node.TagTemplates) > 0
+}
+
+// initContainerArgs waits for the operator to resolve the sensor grouping tags of the node before installing the sensor.
+// The tags are passed to the sensor through an environment variable, which is only read when the sensor container starts.
+func initContainerArgs(node *falconv1alpha1.FalconNodeSensor) []string {
+	args := common.InitContainerArgs()
+	if !usesTagTemplates(node) {
+		return args
+	}
+
+	wait := fmt.Sprintf("until grep -q '^%[1]s=' %[2]s/%[3]s; do echo \"Waiting for the sensor tags of node $POD_NODE_NAME\"; sleep 2; done; ", common.FalconSensorTagsKey, sensorTagsMountPath, sensorTagsFile)
+	return []string{args[0], wait + args[1]}
+}
+
+func podNodeNameEnv() corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: "POD_NODE_NAME",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				APIVersion: "v1",
+				FieldPath:  "spec.nodeName",
+			},
+		},
+	}
+}
+
+func initContainerEnv(node *falconv1alpha1.FalconNodeSensor) []corev1.EnvVar {
+	env := []corev1.EnvVar{podNodeNameEnv()}
+
+	if usesTagTemplates(node) {
+		// Restarts the sensor pods so that the tags are resolved again when the templates change
+		env = append(env, corev1.EnvVar{
+			Name:  "FALCON_SENSOR_TAG_TEMPLATES",
+			Value: strings.Join(node.Spec.Node.TagTemplates, ","),
+		})
+	}
+
+	return env
+}
+
+func initContainerVolumeMounts(node *falconv1alpha1.FalconNodeSensor) []corev1.VolumeMount {
+	if !usesTagTemplates(node) {
+		return nil
+	}
+
+	return []corev1.VolumeMount{
+		{
+			Name:      sensorTagsVolumeName,
+			MountPath: sensorTagsMountPath,
+			ReadOnly:  true,
+		},
+	}
+}
+
+func containerEnv(node *falconv1alpha1.FalconNodeSensor) []corev1.EnvVar {
+	env := []corev1.EnvVar{podNodeNameEnv()}
+	env = append(env, common.MaintenanceTokenEnvVars(node.Spec.Falcon.FalconSensor)...)
+
+	if usesTagTemplates(node) {
+		// Takes precedence over the static tags in the sensor ConfigMap
+		env = append(env, corev1.EnvVar{
+			Name: "FALCONCTL_OPT_TAGS",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					APIVersion: "v1",
+					FieldPath:  fmt.Sprintf("metadata.annotations['%s']", common.FalconSensorTagsKey),
+				},
+			},
+		})
+	}
+
+	return env
+}
+
+func daemonsetVolumes(node *falconv1alpha1.FalconNodeSensor) []corev1.Volume {
+	dsVolumes := volumes()
+	if !usesTagTemplates(node) {
+		return dsVolumes
 	}
 
+	return append(dsVolumes, corev1.Volume{
+		Name: sensorTagsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			DownwardAPI: &corev1.DownwardAPIVolumeSource{
+				Items: []corev1.DownwardAPIVolumeFile{
+					{
+						Path: sensorTagsFile,
+						FieldRef: &corev1.ObjectFieldSelector{
+							APIVersion: "v1",
+							FieldPath:  "metadata.annotations",
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+// DaemonsetConfigMapName returns the name of the sensor ConfigMap of the FalconNodeSensor DaemonSet.
+// Each FalconNodeSensor has its own ConfigMap, as several FalconNodeSensors can share the install namespace.
+func DaemonsetConfigMapName(node *falconv1alpha1.FalconNodeSensor) string {
 	return node.Name + "-config"
 }
 
@@ -265,8 +385,7 @@ func Daemonset(dsName, image, serviceAccount string, node *falconv1alpha1.Falcon
 					},
 				},
 				Spec: corev1.PodSpec{
-					// NodeSelector is set to linux until windows containers are supported for the Falcon sensor
-					NodeSelector:                  common.NodeSelector,
+					NodeSelector:                  nodeSelector(node),
 					Affinity:                      nodeAffinity(node),
 					Tolerations:                   *node.GetTolerations(),
 					HostPID:                       hostpid,
@@ -278,11 +397,12 @@ func Daemonset(dsName, image, serviceAccount string, node *falconv1alpha1.Falcon
 					SecurityContext:               &podSecuityContext,
 					InitContainers: []corev1.Container{
 						{
-							Name:      "init-falconstore",
-							Image:     image,
-							Command:   common.FalconShellCommand,
-							Args:      common.InitContainerArgs(),
-							Resources: initContainerResources(node),
+							Name:         "init-falconstore",
+							Image:        image,
+							Command:      common.FalconShellCommand,
+							Args:         initContainerArgs(node),
+							Resources:    initContainerResources(node),
+							VolumeMounts: initContainerVolumeMounts(node),
 							SecurityContext: &corev1.SecurityContext{
 								Privileged:               &privileged,
 								RunAsUser:                &runAsRoot,
@@ -290,17 +410,7 @@ func Daemonset(dsName, image, serviceAccount string, node *falconv1alpha1.Falcon
 								AllowPrivilegeEscalation: &escalation,
 								Capabilities:             sensorCapabilities(node, true),
 							},
-							Env: []corev1.EnvVar{
-								{
-									Name: "POD_NODE_NAME",
-									ValueFrom: &corev1.EnvVarSource{
-										FieldRef: &corev1.ObjectFieldSelector{
-											APIVersion: "v1",
-											FieldPath:  "spec.nodeName",
-										},
-									},
-								},
-							},
+							Env: initContainerEnv(node),
 						},
 					},
 					ServiceAccountName: serviceAccount,
@@ -316,17 +426,7 @@ func Daemonset(dsName, image, serviceAccount string, node *falconv1alpha1.Falcon
 							Name:            "falcon-node-sensor",
 							Image:           image,
 							ImagePullPolicy: node.Spec.Node.ImagePullPolicy,
-							Env: []corev1.EnvVar{
-								{
-									Name: "POD_NODE_NAME",
-									ValueFrom: &corev1.EnvVarSource{
-										FieldRef: &corev1.ObjectFieldSelector{
-											APIVersion: "v1",
-											FieldPath:  "spec.nodeName",
-										},
-									},
-								},
-							},
+							Env:             containerEnv(node),
 							EnvFrom: []corev1.EnvFromSource{
 								{
 									ConfigMapRef: &corev1.ConfigMapEnvSource{
@@ -349,7 +449,7 @@ func Daemonset(dsName, image, serviceAccount string, node *falconv1alpha1.Falcon
 							Resources: dsResources(node),
 						},
 					},
-					Volumes:           volumes(),
+					Volumes:           daemonsetVolumes(node),
 					PriorityClassName: node.Spec.Node.PriorityClass.Name,
 				},
 			},
@@ -385,8 +485,7 @@ func RemoveNodeDirDaemonset(dsName, image, serviceAccount string, node *falconv1
 					},
 				},
 				Spec: corev1.PodSpec{
-					// NodeSelector is set to linux until windows containers are supported for the Falcon sensor
-					NodeSelector:                  common.NodeSelector,
+					NodeSelector:                  nodeSelector(node),
 					Affinity:                      nodeAffinity(node),
 					Tolerations:                   *node.GetTolerations(),
 					HostPID:                       hostpid,
@@ -432,3 +531,47 @@ func isInitReadOnlyRootFilesystem(node *falconv1alpha1.FalconNodeSensor) *bool {
 	disabled := node.Spec.Node.GKE.Enabled != nil && *node.Spec.Node.GKE.Enabled
 	return &disabled
 }
+
+// NodeCleanupPod removes the sensor from a node that is no longer targeted by the FalconNodeSensor DaemonSet.
+// The pod is bound to the node and tolerates every taint, so that it also runs on nodes that were removed from the DaemonSet by a taint.
+func NodeCleanupPod(nodeName, image, serviceAccount string, node *falconv1alpha1.FalconNodeSensor) *corev1.Pod {
+	privileged := true
+	escalation := true
+	runAsRoot := int64(0)
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: node.Name + "-cleanup-",
+			Namespace:    node.Spec.InstallNamespace,
+			Labels:       common.CRLabels(NodeCleanupInstanceName, node.Name, common.FalconKernelSensor),
+			Annotations: map[string]string{
+				common.FalconContainerInjection: "disabled",
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:                      nodeName,
+			RestartPolicy:                 corev1.RestartPolicyNever,
+			Tolerations:                   []corev1.Toleration{falconv1alpha1.AllTaintsToleration},
+			HostPID:                       true,
+			TerminationGracePeriodSeconds: getTermGracePeriod(node),
+			ImagePullSecrets:              pullSecrets(node),
+			ServiceAccountName:            serviceAccount,
+			Containers: []corev1.Container{
+				{
+					Name:      "cleanup-opt-crowdstrike",
+					Image:     image,
+					Command:   common.FalconShellCommand,
+					Args:      common.InitCleanupArgs(),
+					Resources: initContainerResources(node),
+					SecurityContext: &corev1.SecurityContext{
+						Privileged:               &privileged,
+						RunAsUser:                &runAsRoot,
+						ReadOnlyRootFilesystem:   isInitReadOnlyRootFilesystem(node),
+						AllowPrivilegeEscalation: &escalation,
+						Capabilities:             sensorCapabilities(node, true),
+					},
+				},
+			},
+		},
+	}
+}
@@ -3,6 +3,8 @@ package assets
 import (
 	"fmt"
 	"maps"
+	"slices"
+	"strings"
 	"testing"
 
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
@@ -66,6 +68,34 @@ func TestNodeAffinity(t *testing.T) {
 	}
 }
 
+func TestNodeSelector(t *testing.T) {
+	falconNode := falconv1alpha1.FalconNodeSensor{}
+
+	got := nodeSelector(&falconNode)
+	want := common.NodeSelector
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("nodeSelector() mismatch (-want +got): %s", diff)
+	}
+
+	falconNode.Spec.Node.NodeSelector = map[string]string{
+		"node.kubernetes.io/pool": "gpu",
+		"kubernetes.io/os":        "windows",
+	}
+	want = map[string]string{
+		"node.kubernetes.io/pool": "gpu",
+		"kubernetes.io/os":        "linux",
+	}
+
+	got = nodeSelector(&falconNode)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("nodeSelector() mismatch (-want +got): %s", diff)
+	}
+
+	if diff := cmp.Diff(map[string]string{"kubernetes.io/os": "linux"}, common.NodeSelector); diff != "" {
+		t.Errorf("nodeSelector() modified common.NodeSelector (-want +got): %s", diff)
+	}
+}
+
 func TestPullSecrets(t *testing.T) {
 	falconNode := falconv1alpha1.FalconNodeSensor{}
 
@@ -248,11 +278,16 @@ func TestDaemonsetConfigMapName(t *testing.T) {
 	}
 
 	autopilot = true
-	want = common.GKEAutoPilotConfigMapName
 	got = DaemonsetConfigMapName(&falconNode)
 	if diff := cmp.Diff(want, got); diff != "" {
 		t.Errorf("DaemonsetConfigMapName() mismatch with Autopilot Enabled (-want +got): %s", diff)
 	}
+
+	profile := falconNode.DeepCopy()
+	profile.Name = "test-name-gpu"
+	if DaemonsetConfigMapName(profile) == DaemonsetConfigMapName(&falconNode) {
+		t.Errorf("DaemonsetConfigMapName() must differ between FalconNodeSensors")
+	}
 }
 
 func TestDaemonset(t *testing.T) {
@@ -713,3 +748,97 @@ func TestDsResources(t *testing.T) {
 		})
 	}
 }
+
+func TestDaemonsetWithTagTemplates(t *testing.T) {
+	falconNode := falconv1alpha1.FalconNodeSensor{}
+	falconNode.Name = "test"
+	falconNode.Spec.Node.Tolerations = &[]corev1.Toleration{}
+	autopilot := false
+	falconNode.Spec.Node.GKE.Enabled = &autopilot
+	falconNode.Spec.Node.TagTemplates = []string{"zone/{{ .Zone }}", "pool/{{ .Labels[\"pool\"] }}"}
+
+	ds := Daemonset("test", "testImage", "testSA", &falconNode)
+	initContainer := ds.Spec.Template.Spec.InitContainers[0]
+	container := ds.Spec.Template.Spec.Containers[0]
+
+	wantTagsEnv := corev1.EnvVar{
+		Name: "FALCONCTL_OPT_TAGS",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				APIVersion: "v1",
+				FieldPath:  "metadata.annotations['falcon.crowdstrike.com/sensor-tags']",
+			},
+		},
+	}
+	if !slices.ContainsFunc(container.Env, func(env corev1.EnvVar) bool { return cmp.Equal(env, wantTagsEnv) }) {
+		t.Errorf("Daemonset() container env %v does not contain %v", container.Env, wantTagsEnv)
+	}
+
+	wantTemplatesEnv := corev1.EnvVar{Name: "FALCON_SENSOR_TAG_TEMPLATES", Value: "zone/{{ .Zone }},pool/{{ .Labels[\"pool\"] }}"}
+	if !slices.Contains(initContainer.Env, wantTemplatesEnv) {
+		t.Errorf("Daemonset() init container env %v does not contain %v", initContainer.Env, wantTemplatesEnv)
+	}
+
+	if !strings.HasPrefix(initContainer.Args[1], "until grep -q '^falcon.crowdstrike.com/sensor-tags=' /etc/falcon-sensor-tags/annotations;") {
+		t.Errorf("Daemonset() init container does not wait for the sensor tags: %s", initContainer.Args[1])
+	}
+
+	if diff := cmp.Diff([]corev1.VolumeMount{{Name: "sensor-tags", MountPath: "/etc/falcon-sensor-tags", ReadOnly: true}}, initContainer.VolumeMounts); diff != "" {
+		t.Errorf("Daemonset() init container volumeMounts mismatch (-want +got): %s", diff)
+	}
+
+	volumes := ds.Spec.Template.Spec.Volumes
+	if got := volumes[len(volumes)-1]; got.Name != "sensor-tags" || got.DownwardAPI == nil {
+		t.Errorf("Daemonset() missing the sensor tags downward API volume: %v", got)
+	}
+}
+
+func TestNodeCleanupPod(t *testing.T) {
+	falconNode := falconv1alpha1.FalconNodeSensor{}
+	falconNode.Name = "test"
+	falconNode.Spec.InstallNamespace = "falcon-system"
+	image := "testImage"
+	privileged := true
+	escalation := true
+	runAsRoot := int64(0)
+	autopilot := false
+	falconNode.Spec.Node.GKE.Enabled = &autopilot
+
+	want := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "test-cleanup-",
+			Namespace:    "falcon-system",
+			Labels:       common.CRLabels(NodeCleanupInstanceName, "test", common.FalconKernelSensor),
+			Annotations:  map[string]string{common.FalconContainerInjection: "disabled"},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:                      "node-a",
+			RestartPolicy:                 corev1.RestartPolicyNever,
+			Tolerations:                   []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+			HostPID:                       true,
+			TerminationGracePeriodSeconds: getTermGracePeriod(&falconNode),
+			ImagePullSecrets:              pullSecrets(&falconNode),
+			ServiceAccountName:            common.NodeServiceAccountName,
+			Containers: []corev1.Container{
+				{
+					Name:      "cleanup-opt-crowdstrike",
+					Image:     image,
+					Command:   common.FalconShellCommand,
+					Args:      common.InitCleanupArgs(),
+					Resources: initContainerResources(&falconNode),
+					SecurityContext: &corev1.SecurityContext{
+						Privileged:               &privileged,
+						RunAsUser:                &runAsRoot,
+						ReadOnlyRootFilesystem:   isInitReadOnlyRootFilesystem(&falconNode),
+						AllowPrivilegeEscalation: &escalation,
+					},
+				},
+			},
+		},
+	}
+
+	got := NodeCleanupPod("node-a", image, common.NodeServiceAccountName, &falconNode)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("NodeCleanupPod() mismatch (-want +got): %s", diff)
+	}
+}
@@ -25,10 +25,10 @@ const (
 
 var enforcedSingleReplica = int32(1)
 
-// SideCarDeployment returns a Deployment object for the CrowdStrike Falcon sidecar
+// SideCarDeployment returns a Deployment object for the CrowdStrike Falcon sidecar injector, configured from the <name>-config ConfigMap
 func SideCarDeployment(name string, namespace string, component string, imageUri string, falconContainer *falconv1alpha1.FalconContainer) *appsv1.Deployment {
 	initContainerName := "crowdstrike-falcon-init-container"
-	injectorConfigMapName := "falcon-sidecar-injector-config"
+	injectorConfigMapName := name + "-config"
 	registryCABundleConfigMapName := "falcon-sidecar-registry-certs"
 	injectorTLSSecretName := "falcon-sidecar-injector-tls"
 	falconVolumeName := "crowdstrike-falcon-volume"
@@ -166,17 +166,8 @@ func SideCarDeployment(name string, namespace string, component string, imageUri
 					},
 				},
 				Spec: corev1.PodSpec{
-					Affinity: getNodeAffinity(falconContainer.Spec.NodeAffinity),
-					TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
-						{
-							MaxSkew:           1,
-							TopologyKey:       "kubernetes.io/hostname",
-							WhenUnsatisfiable: corev1.ScheduleAnyway,
-							LabelSelector: &metav1.LabelSelector{
-								MatchLabels: map[string]string{common.FalconInstanceNameKey: name},
-							},
-						},
-					},
+					Affinity:                  getAffinity(falconContainer.Spec.NodeAffinity, falconContainer.Spec.Injector.Availability, labels),
+					TopologySpreadConstraints: getTopologySpreadConstraints(falconContainer.Spec.Injector.Availability, labels, defaultTopologySpreadConstraints(name)),
 					SecurityContext: &corev1.PodSecurityContext{
 						RunAsNonRoot: &runNonRoot,
 						SeccompProfile: &corev1.SeccompProfile{
@@ -346,7 +337,8 @@ func ImageAnalyzerDeployment(name string, namespace string, component string, im
 					},
 				},
 				Spec: corev1.PodSpec{
-					Affinity: getNodeAffinity(falconImageAnalyzer.Spec.NodeAffinity),
+					Affinity:                  getAffinity(falconImageAnalyzer.Spec.NodeAffinity, falconImageAnalyzer.Spec.Availability, labels),
+					TopologySpreadConstraints: getTopologySpreadConstraints(falconImageAnalyzer.Spec.Availability, labels, nil),
 					Containers: []corev1.Container{
 						{
 							Name: "falcon-image-analyzer",
@@ -405,14 +397,12 @@ func AdmissionDeployment(name string, namespace string, component string, imageU
 	allowPrivilegeEscalation := false
 	shareProcessNamespace := true
 	resourcesClient := &corev1.ResourceRequirements{}
-	resourcesWatcher := &corev1.ResourceRequirements{}
 	resourcesAC := &corev1.ResourceRequirements{}
 	sizeLimitTmp := resource.MustParse("256Mi")
 	sizeLimitPrivate := resource.MustParse("4Ki")
 	sizeLimitWatcher := resource.MustParse("64Mi")
 	labels := common.CRLabels("deployment", name, component)
-	registryCAConfigMapName := ""
-	registryCABundleConfigMapName := name + "-registry-certs"
+	registryCAConfigMapName := admissionRegistryCAConfigMapName(name, falconAdmission)
 
 	if falconAdmission.Spec.AdmissionConfig.ResourcesClient != nil {
 		resourcesClient = falconAdmission.Spec.AdmissionConfig.ResourcesClient
@@ -422,10 +412,6 @@ func AdmissionDeployment(name string, namespace string, component string, imageU
 		resourcesClient = falconAdmission.Spec.AdmissionConfig.ResourcesClientNoWebhook
 	}
 
-	if falconAdmission.Spec.AdmissionConfig.ResourcesWatcher != nil {
-		resourcesWatcher = falconAdmission.Spec.AdmissionConfig.ResourcesWatcher
-	}
-
 	if falconAdmission.Spec.AdmissionConfig.ResourcesAC != nil {
 		resourcesAC = falconAdmission.Spec.AdmissionConfig.ResourcesAC
 	}
@@ -465,29 +451,13 @@ func AdmissionDeployment(name string, namespace string, component string, imageU
 		},
 	}
 
-	if falconAdmission.Spec.Registry.TLS.CACertificateConfigMap != "" {
-		registryCAConfigMapName = falconAdmission.Spec.Registry.TLS.CACertificateConfigMap
-	}
-
-	if falconAdmission.Spec.Registry.TLS.CACertificate != "" {
-		registryCAConfigMapName = registryCABundleConfigMapName
-	}
-
 	if registryCAConfigMapName != "" {
-		volumes = append(volumes, corev1.Volume{
-			Name: registryCAConfigMapName,
-			VolumeSource: corev1.VolumeSource{
-				ConfigMap: &corev1.ConfigMapVolumeSource{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: registryCAConfigMapName,
-					},
-				},
-			},
-		})
+		volumes = append(volumes, admissionRegistryCAVolume(registryCAConfigMapName))
 	}
 
-	if falconAdmission.Spec.AdmissionConfig.Replicas == nil || *falconAdmission.Spec.AdmissionConfig.Replicas != 1 {
-		log.V(1).Info("ignoring Replicas setting as only one is currently supported")
+	replicas := enforcedSingleReplica
+	if falconAdmission.Spec.AdmissionConfig.Replicas != nil {
+		replicas = *falconAdmission.Spec.AdmissionConfig.Replicas
 	}
 
 	falconClientEnv := []corev1.EnvVar{
@@ -526,6 +496,7 @@ func AdmissionDeployment(name string, namespace string, component string, imageU
 			Value: falconAdmission.Spec.AdmissionConfig.FalconImageAnalyzerNamespace,
 		})
 	}
+	falconClientEnv = append(falconClientEnv, common.MaintenanceTokenEnvVars(falconAdmission.Spec.Falcon)...)
 	falconClientEnv = common.AppendUniqueEnvVars(falconClientEnv, common.OperatorMetaEnvVars())
 
 	kacContainers := &[]corev1.Container{
@@ -653,79 +624,103 @@ func AdmissionDeployment(name string, namespace string, component string, imageU
 		},
 	}
 
-	if falconAdmission.Spec.AdmissionConfig.DeployWatcherContainer() {
-		*kacContainers = append(*kacContainers, corev1.Container{
-			Name:            "falcon-watcher",
-			Image:           imageUri,
-			ImagePullPolicy: falconAdmission.Spec.AdmissionConfig.ImagePullPolicy,
-			Args: []string{
-				"client",
-				"-app=watcher",
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
 			},
-			SecurityContext: &corev1.SecurityContext{
-				ReadOnlyRootFilesystem:   &readOnlyRootFilesystem,
-				AllowPrivilegeEscalation: &allowPrivilegeEscalation,
-				RunAsNonRoot:             &runNonRoot,
-				Capabilities: &corev1.Capabilities{
-					Drop: []corev1.Capability{
-						"ALL",
+			Strategy: admissionDepUpdateStrategy(falconAdmission),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						common.FalconContainerInjection: "disabled",
 					},
 				},
-			},
-			Env: admissionDepWatcherEnvVars(falconAdmission),
-			EnvFrom: []corev1.EnvFromSource{
-				{
-					ConfigMapRef: &corev1.ConfigMapEnvSource{
-						LocalObjectReference: corev1.LocalObjectReference{
-							Name: name + "-config",
+				Spec: corev1.PodSpec{
+					Affinity:                  getAffinity(falconAdmission.Spec.AdmissionConfig.NodeAffinity, falconAdmission.Spec.AdmissionConfig.Availability, labels),
+					TopologySpreadConstraints: getTopologySpreadConstraints(falconAdmission.Spec.AdmissionConfig.Availability, labels, defaultTopologySpreadConstraints(name)),
+					ShareProcessNamespace:     &shareProcessNamespace,
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot: &runNonRoot,
+						SeccompProfile: &corev1.SeccompProfile{
+							Type: corev1.SeccompProfileTypeRuntimeDefault,
 						},
 					},
+					ServiceAccountName: common.AdmissionServiceAccountName,
+					NodeSelector:       common.NodeSelector,
+					PriorityClassName:  common.FalconPriorityClassName,
+					Containers:         *kacContainers,
+					Volumes:            volumes,
+					Tolerations:        falconAdmission.Spec.AdmissionConfig.Tolerations,
 				},
 			},
-			Ports: []corev1.ContainerPort{
-				{
-					ContainerPort: common.FalconAdmissionWatcherPort,
-					Name:          common.FalconAdmissionWatcherPortName,
-					Protocol:      corev1.ProtocolTCP,
+		},
+	}
+}
+
+// AdmissionWatcherName returns the name of the falcon-watcher Deployment
+func AdmissionWatcherName(name string) string {
+	return name + "-watcher"
+}
+
+// AdmissionWatcherDeployment returns the Deployment of the falcon-watcher of the CrowdStrike Falcon Admission Controller.
+// The watcher streams snapshots and events of the cluster, so it runs a single replica that is not scaled with the webhook.
+func AdmissionWatcherDeployment(name string, namespace string, component string, imageUri string, falconAdmission *falconv1alpha1.FalconAdmission) *appsv1.Deployment {
+	runNonRoot := true
+	readOnlyRootFilesystem := true
+	allowPrivilegeEscalation := false
+	resourcesWatcher := &corev1.ResourceRequirements{}
+	sizeLimitTmp := resource.MustParse("256Mi")
+	sizeLimitPrivate := resource.MustParse("4Ki")
+	sizeLimitWatcher := resource.MustParse("64Mi")
+	watcherName := AdmissionWatcherName(name)
+	labels := common.CRLabels("deployment", watcherName, component)
+	registryCAConfigMapName := admissionRegistryCAConfigMapName(name, falconAdmission)
+
+	if falconAdmission.Spec.AdmissionConfig.ResourcesWatcher != nil {
+		resourcesWatcher = falconAdmission.Spec.AdmissionConfig.ResourcesWatcher
+	}
+
+	volumes := []corev1.Volume{
+		{
+			Name: "crowdstrike-falcon-vol0",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					SizeLimit: &sizeLimitTmp,
 				},
 			},
-			VolumeMounts: admissionDepVolumeMounts(name, registryCAConfigMapName, FalconWatcher),
-			StartupProbe: &corev1.Probe{
-				ProbeHandler: corev1.ProbeHandler{
-					HTTPGet: &corev1.HTTPGetAction{
-						Path: common.FalconAdmissionClientStartupProbePath,
-						Port: intstr.IntOrString{
-							Type:   intstr.Int,
-							IntVal: common.FalconAdmissionWatcherPort,
-						},
-						Scheme: corev1.URISchemeHTTP,
-					},
+		},
+		{
+			Name: "crowdstrike-falcon-vol1",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					SizeLimit: &sizeLimitPrivate,
 				},
-				InitialDelaySeconds: 5,
-				TimeoutSeconds:      1,
-				PeriodSeconds:       2,
-				SuccessThreshold:    1,
-				FailureThreshold:    30,
 			},
-			LivenessProbe: &corev1.Probe{
-				ProbeHandler: corev1.ProbeHandler{
-					HTTPGet: &corev1.HTTPGetAction{
-						Path: common.FalconAdmissionClientLivenessProbePath,
-						Port: intstr.IntOrString{
-							Type:   intstr.Int,
-							IntVal: common.FalconAdmissionWatcherPort,
-						},
-						Scheme: corev1.URISchemeHTTP,
-					},
+		},
+		{
+			Name: "crowdstrike-falcon-vol2",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					SizeLimit: &sizeLimitWatcher,
 				},
-				InitialDelaySeconds: 5,
-				TimeoutSeconds:      1,
-				PeriodSeconds:       10,
-				SuccessThreshold:    1,
-				FailureThreshold:    3,
 			},
-			Resources: *resourcesWatcher,
-		})
+		},
+	}
+
+	if registryCAConfigMapName != "" {
+		volumes = append(volumes, admissionRegistryCAVolume(registryCAConfigMapName))
 	}
 
 	return &appsv1.Deployment{
@@ -734,7 +729,7 @@ func AdmissionDeployment(name string, namespace string, component string, imageU
 			Kind:       "Deployment",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
+			Name:      watcherName,
 			Namespace: namespace,
 			Labels:    labels,
 		},
@@ -743,7 +738,10 @@ func AdmissionDeployment(name string, namespace string, component string, imageU
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
-			Strategy: admissionDepUpdateStrategy(falconAdmission),
+			// Only one watcher streams to the cloud, so the running watcher is stopped before the new one starts
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RecreateDeploymentStrategyType,
+			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: labels,
@@ -753,17 +751,6 @@ func AdmissionDeployment(name string, namespace string, component string, imageU
 				},
 				Spec: corev1.PodSpec{
 					Affinity: getNodeAffinity(falconAdmission.Spec.AdmissionConfig.NodeAffinity),
-					TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
-						{
-							MaxSkew:           1,
-							TopologyKey:       "kubernetes.io/hostname",
-							WhenUnsatisfiable: corev1.ScheduleAnyway,
-							LabelSelector: &metav1.LabelSelector{
-								MatchLabels: map[string]string{common.FalconInstanceNameKey: name},
-							},
-						},
-					},
-					ShareProcessNamespace: &shareProcessNamespace,
 					SecurityContext: &corev1.PodSecurityContext{
 						RunAsNonRoot: &runNonRoot,
 						SeccompProfile: &corev1.SeccompProfile{
@@ -773,9 +760,103 @@ func AdmissionDeployment(name string, namespace string, component string, imageU
 					ServiceAccountName: common.AdmissionServiceAccountName,
 					NodeSelector:       common.NodeSelector,
 					PriorityClassName:  common.FalconPriorityClassName,
-					Containers:         *kacContainers,
-					Volumes:            volumes,
-					Tolerations:        falconAdmission.Spec.AdmissionConfig.Tolerations,
+					Containers: []corev1.Container{
+						{
+							Name:            "falcon-watcher",
+							Image:           imageUri,
+							ImagePullPolicy: falconAdmission.Spec.AdmissionConfig.ImagePullPolicy,
+							Args: []string{
+								"client",
+								"-app=watcher",
+							},
+							SecurityContext: &corev1.SecurityContext{
+								ReadOnlyRootFilesystem:   &readOnlyRootFilesystem,
+								AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+								RunAsNonRoot:             &runNonRoot,
+								Capabilities: &corev1.Capabilities{
+									Drop: []corev1.Capability{
+										"ALL",
+									},
+								},
+							},
+							Env: admissionDepWatcherEnvVars(falconAdmission),
+							EnvFrom: []corev1.EnvFromSource{
+								{
+									ConfigMapRef: &corev1.ConfigMapEnvSource{
+										LocalObjectReference: corev1.LocalObjectReference{
+											Name: name + "-config",
+										},
+									},
+								},
+							},
+							Ports: []corev1.ContainerPort{
+								{
+									ContainerPort: common.FalconAdmissionWatcherPort,
+									Name:          common.FalconAdmissionWatcherPortName,
+									Protocol:      corev1.ProtocolTCP,
+								},
+							},
+							VolumeMounts: admissionDepVolumeMounts(name, registryCAConfigMapName, FalconWatcher),
+							StartupProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path: common.FalconAdmissionClientStartupProbePath,
+										Port: intstr.IntOrString{
+											Type:   intstr.Int,
+											IntVal: common.FalconAdmissionWatcherPort,
+										},
+										Scheme: corev1.URISchemeHTTP,
+									},
+								},
+								InitialDelaySeconds: 5,
+								TimeoutSeconds:      1,
+								PeriodSeconds:       2,
+								SuccessThreshold:    1,
+								FailureThreshold:    30,
+							},
+							LivenessProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path: common.FalconAdmissionClientLivenessProbePath,
+										Port: intstr.IntOrString{
+											Type:   intstr.Int,
+											IntVal: common.FalconAdmissionWatcherPort,
+										},
+										Scheme: corev1.URISchemeHTTP,
+									},
+								},
+								InitialDelaySeconds: 5,
+								TimeoutSeconds:      1,
+								PeriodSeconds:       10,
+								SuccessThreshold:    1,
+								FailureThreshold:    3,
+							},
+							Resources: *resourcesWatcher,
+						},
+					},
+					Volumes:     volumes,
+					Tolerations: falconAdmission.Spec.AdmissionConfig.Tolerations,
+				},
+			},
+		},
+	}
+}
+
+func admissionRegistryCAConfigMapName(name string, falconAdmission *falconv1alpha1.FalconAdmission) string {
+	if falconAdmission.Spec.Registry.TLS.CACertificate != "" {
+		return name + "-registry-certs"
+	}
+
+	return falconAdmission.Spec.Registry.TLS.CACertificateConfigMap
+}
+
+func admissionRegistryCAVolume(registryCAConfigMapName string) corev1.Volume {
+	return corev1.Volume{
+		Name: registryCAConfigMapName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: registryCAConfigMapName,
 				},
 			},
 		},
@@ -890,6 +971,62 @@ func admissionDepWatcherEnvVars(admission *falconv1alpha1.FalconAdmission) []cor
 	return common.AppendUniqueEnvVars(envVars, common.OperatorMetaEnvVars())
 }
 
+// getAffinity returns the node affinity and the pod anti-affinity of a Deployment. Pod anti-affinity terms without a label selector select the pods of the Deployment.
+func getAffinity(nodeAffinity *corev1.NodeAffinity, availability falconv1alpha1.FalconAvailability, podLabels map[string]string) *corev1.Affinity {
+	affinity := getNodeAffinity(nodeAffinity)
+	if availability.PodAntiAffinity == nil {
+		return affinity
+	}
+
+	podAntiAffinity := availability.PodAntiAffinity.DeepCopy()
+	for i := range podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+		defaultPodAffinityTermSelector(&podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution[i], podLabels)
+	}
+	for i := range podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		defaultPodAffinityTermSelector(&podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[i].PodAffinityTerm, podLabels)
+	}
+	affinity.PodAntiAffinity = podAntiAffinity
+
+	return affinity
+}
+
+func defaultPodAffinityTermSelector(term *corev1.PodAffinityTerm, podLabels map[string]string) {
+	if term.LabelSelector == nil {
+		term.LabelSelector = &metav1.LabelSelector{MatchLabels: podLabels}
+	}
+}
+
+// getTopologySpreadConstraints returns the configured topology spread constraints of a Deployment, or the defaults when none are configured.
+// Constraints without a label selector select the pods of the Deployment.
+func getTopologySpreadConstraints(availability falconv1alpha1.FalconAvailability, podLabels map[string]string, defaults []corev1.TopologySpreadConstraint) []corev1.TopologySpreadConstraint {
+	if len(availability.TopologySpreadConstraints) == 0 {
+		return defaults
+	}
+
+	constraints := make([]corev1.TopologySpreadConstraint, len(availability.TopologySpreadConstraints))
+	for i, constraint := range availability.TopologySpreadConstraints {
+		constraint.DeepCopyInto(&constraints[i])
+		if constraints[i].LabelSelector == nil {
+			constraints[i].LabelSelector = &metav1.LabelSelector{MatchLabels: podLabels}
+		}
+	}
+
+	return constraints
+}
+
+func defaultTopologySpreadConstraints(name string) []corev1.TopologySpreadConstraint {
+	return []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       "kubernetes.io/hostname",
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{common.FalconInstanceNameKey: name},
+			},
+		},
+	}
+}
+
 func getNodeAffinity(nodeAffinity *corev1.NodeAffinity) *corev1.Affinity {
 	if nodeAffinity != nil && !reflect.DeepEqual(nodeAffinity, corev1.NodeAffinity{}) {
 		return &corev1.Affinity{NodeAffinity: nodeAffinity}
@@ -41,8 +41,9 @@ func TestAdmissionDeployment(t *testing.T) {
 	falconAdmission.Spec.AdmissionConfig.ResourcesAC = &corev1.ResourceRequirements{}
 
 	port := int32(1)
+	replicas := int32(3)
 	falconAdmission.Spec.AdmissionConfig.Port = &port
-	falconAdmission.Spec.AdmissionConfig.Replicas = &port
+	falconAdmission.Spec.AdmissionConfig.Replicas = &replicas
 	falconAdmission.Spec.AdmissionConfig.ContainerPort = &port
 
 	var deployWatcher *bool = new(bool)
@@ -56,16 +57,32 @@ func TestAdmissionDeployment(t *testing.T) {
 		t.Errorf("Deployment() mismatch (-want +got): %s", diff)
 	}
 
+	// The watcher runs in its own Deployment, so the webhook Deployment does not depend on it
 	*deployWatcher = true
 	falconAdmission.Spec.AdmissionConfig.DeployWatcher = deployWatcher
 
-	want = testAdmissionDeployment("test", "test", "test", "test", falconAdmission)
 	got = AdmissionDeployment("test", "test", "test", "test", falconAdmission, logger)
 	if diff := cmp.Diff(want, got); diff != "" {
 		t.Errorf("Deployment() mismatch (-want +got): %s", diff)
 	}
 }
 
+// TestAdmissionWatcherDeployment tests the Admission Controller watcher Deployment function
+func TestAdmissionWatcherDeployment(t *testing.T) {
+	falconAdmission := &falconv1alpha1.FalconAdmission{}
+	falconAdmission.Spec.AdmissionConfig.ResourcesWatcher = &corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+	}
+	replicas := int32(3)
+	falconAdmission.Spec.AdmissionConfig.Replicas = &replicas
+
+	want := testAdmissionWatcherDeployment("test", "test", "test", "test", falconAdmission)
+	got := AdmissionWatcherDeployment("test", "test", "test", "test", falconAdmission)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("AdmissionWatcherDeployment() mismatch (-want +got): %s", diff)
+	}
+}
+
 // TestAdmissionDepUpdateStrategy tests the Admission Controller Deployment Update Strategy function
 func TestAdmissionDepUpdateStrategy(t *testing.T) {
 	falconAdmission := falconv1alpha1.FalconAdmission{}
@@ -122,11 +139,58 @@ func TestImageAnalyzerDepUpdateStrategy(t *testing.T) {
 	}
 }
 
+// TestDeploymentAvailability tests the topology spread constraints and pod anti-affinity of the Deployments
+func TestDeploymentAvailability(t *testing.T) {
+	podLabels := common.CRLabels("deployment", "test", "test")
+	zoneSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}}
+	availability := falconv1alpha1.FalconAvailability{
+		TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+			{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule},
+			{MaxSkew: 2, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.ScheduleAnyway, LabelSelector: zoneSelector},
+		},
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{Weight: 100, PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: "kubernetes.io/hostname"}},
+			},
+		},
+	}
+
+	wantConstraints := []corev1.TopologySpreadConstraint{
+		{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule, LabelSelector: &metav1.LabelSelector{MatchLabels: podLabels}},
+		{MaxSkew: 2, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.ScheduleAnyway, LabelSelector: zoneSelector},
+	}
+	if diff := cmp.Diff(wantConstraints, getTopologySpreadConstraints(availability, podLabels, defaultTopologySpreadConstraints("test"))); diff != "" {
+		t.Errorf("getTopologySpreadConstraints() mismatch (-want +got): %s", diff)
+	}
+
+	if diff := cmp.Diff(defaultTopologySpreadConstraints("test"), getTopologySpreadConstraints(falconv1alpha1.FalconAvailability{}, podLabels, defaultTopologySpreadConstraints("test"))); diff != "" {
+		t.Errorf("getTopologySpreadConstraints() mismatch (-want +got): %s", diff)
+	}
+
+	wantAntiAffinity := &corev1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+			{Weight: 100, PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: "kubernetes.io/hostname", LabelSelector: &metav1.LabelSelector{MatchLabels: podLabels}}},
+		},
+	}
+	got := getAffinity(nil, availability, podLabels)
+	if diff := cmp.Diff(wantAntiAffinity, got.PodAntiAffinity); diff != "" {
+		t.Errorf("getAffinity() mismatch (-want +got): %s", diff)
+	}
+	if diff := cmp.Diff(getNodeAffinity(nil).NodeAffinity, got.NodeAffinity); diff != "" {
+		t.Errorf("getAffinity() mismatch (-want +got): %s", diff)
+	}
+
+	// The configured anti-affinity must not be modified
+	if availability.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].PodAffinityTerm.LabelSelector != nil {
+		t.Errorf("getAffinity() modified the configured pod anti-affinity")
+	}
+}
+
 // testSideCarDeployment is a helper function to create a Deployment object for testing
 func testSideCarDeployment(name string, namespace string, component string, imageUri string, falconContainer *falconv1alpha1.FalconContainer) *appsv1.Deployment {
 	replicas := int32(123)
 	initContainerName := "crowdstrike-falcon-init-container"
-	injectorConfigMapName := "falcon-sidecar-injector-config"
+	injectorConfigMapName := name + "-config"
 	registryCABundleConfigMapName := "falcon-sidecar-registry-certs"
 	injectorTLSSecretName := "falcon-sidecar-injector-tls"
 	falconVolumeName := "crowdstrike-falcon-volume"
@@ -376,7 +440,6 @@ func testAdmissionDeployment(name string, namespace string, component string, im
 	allowPrivilegeEscalation := false
 	shareProcessNamespace := true
 	resourcesClient := &corev1.ResourceRequirements{}
-	resourcesWatcher := &corev1.ResourceRequirements{}
 	resourcesAC := &corev1.ResourceRequirements{}
 	sizeLimitTmp := resource.MustParse("256Mi")
 	sizeLimitPrivate := resource.MustParse("4Ki")
@@ -387,10 +450,6 @@ func testAdmissionDeployment(name string, namespace string, component string, im
 		resourcesClient = falconAdmission.Spec.AdmissionConfig.ResourcesClient
 	}
 
-	if falconAdmission.Spec.AdmissionConfig.ResourcesWatcher != nil {
-		resourcesWatcher = falconAdmission.Spec.AdmissionConfig.ResourcesWatcher
-	}
-
 	if falconAdmission.Spec.AdmissionConfig.ResourcesAC != nil {
 		resourcesAC = falconAdmission.Spec.AdmissionConfig.ResourcesAC
 	}
@@ -575,122 +634,6 @@ func testAdmissionDeployment(name string, namespace string, component string, im
 		},
 	}
 
-	if *falconAdmission.Spec.AdmissionConfig.DeployWatcher {
-		*kacContainers = append(*kacContainers, corev1.Container{
-			Name:            "falcon-watcher",
-			Image:           imageUri,
-			ImagePullPolicy: falconAdmission.Spec.AdmissionConfig.ImagePullPolicy,
-			Args: []string{
-				"client",
-				"-app=watcher",
-			},
-			SecurityContext: &corev1.SecurityContext{
-				ReadOnlyRootFilesystem:   &readOnlyRootFilesystem,
-				AllowPrivilegeEscalation: &allowPrivilegeEscalation,
-				RunAsNonRoot:             &runNonRoot,
-				Capabilities: &corev1.Capabilities{
-					Drop: []corev1.Capability{
-						"ALL",
-					},
-				},
-			},
-			Env: common.AppendUniqueEnvVars([]corev1.EnvVar{
-				{
-					Name: "__CS_POD_NAMESPACE",
-					ValueFrom: &corev1.EnvVarSource{
-						FieldRef: &corev1.ObjectFieldSelector{
-							APIVersion: "v1",
-							FieldPath:  "metadata.namespace",
-						},
-					},
-				},
-				{
-					Name: "__CS_POD_NAME",
-					ValueFrom: &corev1.EnvVarSource{
-						FieldRef: &corev1.ObjectFieldSelector{
-							APIVersion: "v1",
-							FieldPath:  "metadata.name",
-						},
-					},
-				},
-				{
-					Name: "__CS_POD_NODENAME",
-					ValueFrom: &corev1.EnvVarSource{
-						FieldRef: &corev1.ObjectFieldSelector{
-							APIVersion: "v1",
-							FieldPath:  "spec.nodeName",
-						},
-					},
-				},
-			}, common.OperatorMetaEnvVars()),
-			EnvFrom: []corev1.EnvFromSource{
-				{
-					ConfigMapRef: &corev1.ConfigMapEnvSource{
-						LocalObjectReference: corev1.LocalObjectReference{
-							Name: name + "-config",
-						},
-					},
-				},
-			},
-			Ports: []corev1.ContainerPort{
-				{
-					ContainerPort: common.FalconAdmissionWatcherPort,
-					Name:          common.FalconAdmissionWatcherPortName,
-					Protocol:      corev1.ProtocolTCP,
-				},
-			},
-			VolumeMounts: []corev1.VolumeMount{
-				{
-					Name:      "crowdstrike-falcon-vol0",
-					MountPath: "/tmp",
-				},
-				{
-					Name:      "crowdstrike-falcon-vol1",
-					MountPath: "/var/private",
-				},
-				{
-					Name:      "crowdstrike-falcon-vol2",
-					MountPath: "/var/falcon-watcher",
-				},
-			},
-			StartupProbe: &corev1.Probe{
-				ProbeHandler: corev1.ProbeHandler{
-					HTTPGet: &corev1.HTTPGetAction{
-						Path: common.FalconAdmissionClientStartupProbePath,
-						Port: intstr.IntOrString{
-							Type:   intstr.Int,
-							IntVal: common.FalconAdmissionWatcherPort,
-						},
-						Scheme: corev1.URISchemeHTTP,
-					},
-				},
-				InitialDelaySeconds: 5,
-				TimeoutSeconds:      1,
-				PeriodSeconds:       2,
-				SuccessThreshold:    1,
-				FailureThreshold:    30,
-			},
-			LivenessProbe: &corev1.Probe{
-				ProbeHandler: corev1.ProbeHandler{
-					HTTPGet: &corev1.HTTPGetAction{
-						Path: common.FalconAdmissionClientLivenessProbePath,
-						Port: intstr.IntOrString{
-							Type:   intstr.Int,
-							IntVal: common.FalconAdmissionWatcherPort,
-						},
-						Scheme: corev1.URISchemeHTTP,
-					},
-				},
-				InitialDelaySeconds: 5,
-				TimeoutSeconds:      1,
-				PeriodSeconds:       10,
-				SuccessThreshold:    1,
-				FailureThreshold:    3,
-			},
-			Resources: *resourcesWatcher,
-		})
-	}
-
 	return &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: appsv1.SchemeGroupVersion.String(),
@@ -796,3 +739,202 @@ func testAdmissionDeployment(name string, namespace string, component string, im
 		},
 	}
 }
+
+// testAdmissionWatcherDeployment is a helper function to create the falcon-watcher Deployment object for testing
+func testAdmissionWatcherDeployment(name string, namespace string, component string, imageUri string, falconAdmission *falconv1alpha1.FalconAdmission) *appsv1.Deployment {
+	runNonRoot := true
+	readOnlyRootFilesystem := true
+	allowPrivilegeEscalation := false
+	replicas := int32(1)
+	resourcesWatcher := &corev1.ResourceRequirements{}
+	sizeLimitTmp := resource.MustParse("256Mi")
+	sizeLimitPrivate := resource.MustParse("4Ki")
+	sizeLimitWatcher := resource.MustParse("64Mi")
+	labels := common.CRLabels("deployment", name+"-watcher", component)
+
+	if falconAdmission.Spec.AdmissionConfig.ResourcesWatcher != nil {
+		resourcesWatcher = falconAdmission.Spec.AdmissionConfig.ResourcesWatcher
+	}
+
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name + "-watcher",
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RecreateDeploymentStrategyType,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						common.FalconContainerInjection: "disabled",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Affinity: getNodeAffinity(falconAdmission.Spec.AdmissionConfig.NodeAffinity),
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot: &runNonRoot,
+						SeccompProfile: &corev1.SeccompProfile{
+							Type: corev1.SeccompProfileTypeRuntimeDefault,
+						},
+					},
+					ServiceAccountName: common.AdmissionServiceAccountName,
+					NodeSelector:       common.NodeSelector,
+					PriorityClassName:  common.FalconPriorityClassName,
+					Containers: []corev1.Container{
+						{
+							Name:            "falcon-watcher",
+							Image:           imageUri,
+							ImagePullPolicy: falconAdmission.Spec.AdmissionConfig.ImagePullPolicy,
+							Args: []string{
+								"client",
+								"-app=watcher",
+							},
+							SecurityContext: &corev1.SecurityContext{
+								ReadOnlyRootFilesystem:   &readOnlyRootFilesystem,
+								AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+								RunAsNonRoot:             &runNonRoot,
+								Capabilities: &corev1.Capabilities{
+									Drop: []corev1.Capability{
+										"ALL",
+									},
+								},
+							},
+							Env: common.AppendUniqueEnvVars([]corev1.EnvVar{
+								{
+									Name: "__CS_POD_NAMESPACE",
+									ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{
+											APIVersion: "v1",
+											FieldPath:  "metadata.namespace",
+										},
+									},
+								},
+								{
+									Name: "__CS_POD_NAME",
+									ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{
+											APIVersion: "v1",
+											FieldPath:  "metadata.name",
+										},
+									},
+								},
+								{
+									Name: "__CS_POD_NODENAME",
+									ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{
+											APIVersion: "v1",
+											FieldPath:  "spec.nodeName",
+										},
+									},
+								},
+							}, common.OperatorMetaEnvVars()),
+							EnvFrom: []corev1.EnvFromSource{
+								{
+									ConfigMapRef: &corev1.ConfigMapEnvSource{
+										LocalObjectReference: corev1.LocalObjectReference{
+											Name: name + "-config",
+										},
+									},
+								},
+							},
+							Ports: []corev1.ContainerPort{
+								{
+									ContainerPort: common.FalconAdmissionWatcherPort,
+									Name:          common.FalconAdmissionWatcherPortName,
+									Protocol:      corev1.ProtocolTCP,
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "crowdstrike-falcon-vol0",
+									MountPath: "/tmp",
+								},
+								{
+									Name:      "crowdstrike-falcon-vol1",
+									MountPath: "/var/private",
+								},
+								{
+									Name:      "crowdstrike-falcon-vol2",
+									MountPath: "/var/falcon-watcher",
+								},
+							},
+							StartupProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path: common.FalconAdmissionClientStartupProbePath,
+										Port: intstr.IntOrString{
+											Type:   intstr.Int,
+											IntVal: common.FalconAdmissionWatcherPort,
+										},
+										Scheme: corev1.URISchemeHTTP,
+									},
+								},
+								InitialDelaySeconds: 5,
+								TimeoutSeconds:      1,
+								PeriodSeconds:       2,
+								SuccessThreshold:    1,
+								FailureThreshold:    30,
+							},
+							LivenessProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path: common.FalconAdmissionClientLivenessProbePath,
+										Port: intstr.IntOrString{
+											Type:   intstr.Int,
+											IntVal: common.FalconAdmissionWatcherPort,
+										},
+										Scheme: corev1.URISchemeHTTP,
+									},
+								},
+								InitialDelaySeconds: 5,
+								TimeoutSeconds:      1,
+								PeriodSeconds:       10,
+								SuccessThreshold:    1,
+								FailureThreshold:    3,
+							},
+							Resources: *resourcesWatcher,
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "crowdstrike-falcon-vol0",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{
+									SizeLimit: &sizeLimitTmp,
+								},
+							},
+						},
+						{
+							Name: "crowdstrike-falcon-vol1",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{
+									SizeLimit: &sizeLimitPrivate,
+								},
+							},
+						},
+						{
+							Name: "crowdstrike-falcon-vol2",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{
+									SizeLimit: &sizeLimitWatcher,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
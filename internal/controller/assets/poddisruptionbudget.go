@@ -0,0 +1,39 @@
+package assets
+
+import (
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// PodDisruptionBudget returns a PodDisruptionBudget object for the pods of a Deployment matching the selector
+func PodDisruptionBudget(name string, namespace string, component string, selector *metav1.LabelSelector, pdb *falconv1alpha1.FalconPodDisruptionBudget) *policyv1.PodDisruptionBudget {
+	labels := common.CRLabels("poddisruptionbudget", name, component)
+	maxUnavailable := intstr.FromInt32(1)
+	spec := policyv1.PodDisruptionBudgetSpec{
+		Selector:       selector,
+		MaxUnavailable: &maxUnavailable,
+	}
+
+	if pdb != nil && pdb.MinAvailable != nil {
+		spec.MinAvailable = pdb.MinAvailable
+		spec.MaxUnavailable = nil
+	} else if pdb != nil && pdb.MaxUnavailable != nil {
+		spec.MaxUnavailable = pdb.MaxUnavailable
+	}
+
+	return &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: policyv1.SchemeGroupVersion.String(),
+			Kind:       "PodDisruptionBudget",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: spec,
+	}
+}
@@ -0,0 +1,62 @@
+package assets
+
+import (
+	"testing"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/google/go-cmp/cmp"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// TestPodDisruptionBudget tests the PodDisruptionBudget function
+func TestPodDisruptionBudget(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: common.CRLabels("deployment", "test", "test")}
+	one := intstr.FromInt32(1)
+	half := intstr.FromString("50%")
+
+	tests := []struct {
+		name string
+		pdb  *falconv1alpha1.FalconPodDisruptionBudget
+		want policyv1.PodDisruptionBudgetSpec
+	}{
+		{
+			name: "default",
+			want: policyv1.PodDisruptionBudgetSpec{Selector: selector, MaxUnavailable: &one},
+		},
+		{
+			name: "minAvailable",
+			pdb:  &falconv1alpha1.FalconPodDisruptionBudget{MinAvailable: &half},
+			want: policyv1.PodDisruptionBudgetSpec{Selector: selector, MinAvailable: &half},
+		},
+		{
+			name: "maxUnavailable",
+			pdb:  &falconv1alpha1.FalconPodDisruptionBudget{MaxUnavailable: &half},
+			want: policyv1.PodDisruptionBudgetSpec{Selector: selector, MaxUnavailable: &half},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := &policyv1.PodDisruptionBudget{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: policyv1.SchemeGroupVersion.String(),
+					Kind:       "PodDisruptionBudget",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "test",
+					Labels:    common.CRLabels("poddisruptionbudget", "test", "test"),
+				},
+				Spec: tt.want,
+			}
+
+			got := PodDisruptionBudget("test", "test", "test", selector, tt.pdb)
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("PodDisruptionBudget() mismatch (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -1,22 +1,78 @@
 package assets
 
 import (
+	"slices"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
 	"github.com/crowdstrike/falcon-operator/pkg/common"
 	"golang.org/x/exp/maps"
 	arv1 "k8s.io/api/admissionregistration/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// webhookResourceGroups maps the resources that can additionally be reviewed to their API group
+var webhookResourceGroups = map[string]string{
+	"cronjobs":     "batch",
+	"daemonsets":   "apps",
+	"deployments":  "apps",
+	"jobs":         "batch",
+	"replicasets":  "apps",
+	"statefulsets": "apps",
+}
+
+// workloadResources lists the resources reviewed for cluster visibility by API group
+var workloadResources = []struct {
+	group     string
+	resources []string
+}{
+	{group: "", resources: []string{"replicationcontrollers", "services"}},
+	{group: "apps", resources: []string{"daemonsets", "deployments", "replicasets", "statefulsets"}},
+	{group: "batch", resources: []string{"cronjobs", "jobs"}},
+}
+
 // ValidatingWebhook returns a ValidatingWebhookConfiguration object
-func ValidatingWebhook(name string, namespace string, webhookName string, caBundle []byte, port int32, failPolicy arv1.FailurePolicyType, disabledNamespaces []string) *arv1.ValidatingWebhookConfiguration {
+func ValidatingWebhook(name string, namespace string, webhookName string, caBundle []byte, port int32, failPolicy arv1.FailurePolicyType, disabledNamespaces []string, webhookSpec falconv1alpha1.FalconAdmissionWebhook) *arv1.ValidatingWebhookConfiguration {
 	failurePolicy := arv1.Ignore
 	matchPolicy := arv1.Equivalent
 	sideEffects := arv1.SideEffectClassNone
-	timeoutSeconds := int32(10)
+	timeoutSeconds := webhookTimeout(webhookSpec.TimeoutSeconds)
+	workloadTimeoutSeconds := webhookTimeout(webhookSpec.WorkloadTimeoutSeconds)
 	operatorSelector := metav1.LabelSelectorOpNotIn
 	path := "/validate"
 	scope := arv1.AllScopes
 	admissionOperatorValues := []string{"disabled"}
+	namespaceSelector := &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      "kubernetes.io/metadata.name",
+				Operator: operatorSelector,
+				Values:   disabledNamespaces,
+			},
+			{
+				Key:      common.FalconAdmissionReviewKey,
+				Operator: operatorSelector,
+				Values:   admissionOperatorValues,
+			},
+		},
+	}
+	if webhookSpec.NamespaceSelector != nil {
+		namespaceSelector.MatchLabels = webhookSpec.NamespaceSelector.MatchLabels
+		namespaceSelector.MatchExpressions = append(namespaceSelector.MatchExpressions, webhookSpec.NamespaceSelector.MatchExpressions...)
+	}
+	// The API server defaults a missing object selector to an empty one, which matches every object
+	objectSelector := &metav1.LabelSelector{}
+	if webhookSpec.ObjectSelector != nil {
+		objectSelector = webhookSpec.ObjectSelector.DeepCopy()
+	}
+	clientConfig := arv1.WebhookClientConfig{
+		CABundle: caBundle,
+		Service: &arv1.ServiceReference{
+			Name:      name,
+			Namespace: namespace,
+			Path:      &path,
+			Port:      &port,
+		},
+	}
 	labels := common.CRLabels("validatingwebhook", name, common.FalconAdmissionController)
 	helmLabels := map[string]string{
 		"app":                         "falcon-kac",
@@ -25,7 +81,7 @@ func ValidatingWebhook(name string, namespace string, webhookName string, caBund
 	}
 	maps.Copy(labels, helmLabels)
 
-	return &arv1.ValidatingWebhookConfiguration{
+	webhook := &arv1.ValidatingWebhookConfiguration{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: arv1.SchemeGroupVersion.String(),
 			Kind:       "ValidatingWebhookConfiguration",
@@ -45,30 +101,10 @@ func ValidatingWebhook(name string, namespace string, webhookName string, caBund
 				SideEffects:             &sideEffects,
 				FailurePolicy:           &failPolicy,
 				MatchPolicy:             &matchPolicy,
-				ClientConfig: arv1.WebhookClientConfig{
-					CABundle: caBundle,
-					Service: &arv1.ServiceReference{
-						Name:      name,
-						Namespace: namespace,
-						Path:      &path,
-						Port:      &port,
-					},
-				},
-				TimeoutSeconds: &timeoutSeconds,
-				NamespaceSelector: &metav1.LabelSelector{
-					MatchExpressions: []metav1.LabelSelectorRequirement{
-						{
-							Key:      "kubernetes.io/metadata.name",
-							Operator: operatorSelector,
-							Values:   disabledNamespaces,
-						},
-						{
-							Key:      common.FalconAdmissionReviewKey,
-							Operator: operatorSelector,
-							Values:   admissionOperatorValues,
-						},
-					},
-				},
+				ClientConfig:            clientConfig,
+				TimeoutSeconds:          &timeoutSeconds,
+				NamespaceSelector:       namespaceSelector,
+				ObjectSelector:          objectSelector,
 				Rules: []arv1.RuleWithOperations{
 					{
 						Operations: []arv1.OperationType{
@@ -97,92 +133,105 @@ func ValidatingWebhook(name string, namespace string, webhookName string, caBund
 				SideEffects:             &sideEffects,
 				FailurePolicy:           &failurePolicy,
 				MatchPolicy:             &matchPolicy,
-				ClientConfig: arv1.WebhookClientConfig{
-					CABundle: caBundle,
-					Service: &arv1.ServiceReference{
-						Name:      name,
-						Namespace: namespace,
-						Path:      &path,
-						Port:      &port,
-					},
-				},
-				TimeoutSeconds: &timeoutSeconds,
-				NamespaceSelector: &metav1.LabelSelector{
-					MatchExpressions: []metav1.LabelSelectorRequirement{
-						{
-							Key:      "kubernetes.io/metadata.name",
-							Operator: operatorSelector,
-							Values:   disabledNamespaces,
-						},
-						{
-							Key:      common.FalconAdmissionReviewKey,
-							Operator: operatorSelector,
-							Values:   admissionOperatorValues,
-						},
-					},
-				},
-				Rules: []arv1.RuleWithOperations{
-					{
-						Operations: []arv1.OperationType{
-							arv1.Create,
-							arv1.Update,
-						},
-						Rule: arv1.Rule{
-							APIGroups: []string{
-								"",
-							},
-							APIVersions: []string{
-								"v1",
-							},
-							Resources: []string{
-								"replicationcontrollers",
-								"services",
-							},
-							Scope: &scope,
-						},
-					},
-					{
-						Operations: []arv1.OperationType{
-							arv1.Create,
-							arv1.Update,
-						},
-						Rule: arv1.Rule{
-							APIGroups: []string{
-								"apps",
-							},
-							APIVersions: []string{
-								"v1",
-							},
-							Resources: []string{
-								"daemonsets",
-								"deployments",
-								"replicasets",
-								"statefulsets",
-							},
-							Scope: &scope,
-						},
-					},
-					{
-						Operations: []arv1.OperationType{
-							arv1.Create,
-							arv1.Update,
-						},
-						Rule: arv1.Rule{
-							APIGroups: []string{
-								"batch",
-							},
-							APIVersions: []string{
-								"v1",
-							},
-							Resources: []string{
-								"cronjobs",
-								"jobs",
-							},
-							Scope: &scope,
-						},
+				ClientConfig:            clientConfig,
+				TimeoutSeconds:          &workloadTimeoutSeconds,
+				NamespaceSelector:       namespaceSelector,
+				ObjectSelector:          objectSelector,
+				Rules:                   workloadRules(webhookSpec.AdditionalResources, &scope),
+			},
+		},
+	}
+	for _, resource := range webhookSpec.AdditionalResources {
+		operations := resource.Operations
+		if len(operations) == 0 {
+			operations = []arv1.OperationType{arv1.Create, arv1.Update}
+		}
+		resourceTimeoutSeconds := webhookTimeout(resource.TimeoutSeconds)
+
+		webhook.Webhooks = append(webhook.Webhooks, arv1.ValidatingWebhook{
+			Name:                    resource.Resource + "." + webhookName,
+			AdmissionReviewVersions: []string{"v1"},
+			SideEffects:             &sideEffects,
+			FailurePolicy:           &failPolicy,
+			MatchPolicy:             &matchPolicy,
+			ClientConfig:            clientConfig,
+			TimeoutSeconds:          &resourceTimeoutSeconds,
+			NamespaceSelector:       namespaceSelector,
+			ObjectSelector:          objectSelector,
+			Rules: []arv1.RuleWithOperations{
+				{
+					Operations: operations,
+					Rule: arv1.Rule{
+						APIGroups:   []string{webhookResourceGroups[resource.Resource]},
+						APIVersions: []string{"v1"},
+						Resources:   []string{resource.Resource},
+						Scope:       &scope,
 					},
 				},
 			},
-		},
+		})
+	}
+
+	return webhook
+}
+
+// workloadRules returns the rules of the workload webhook used for cluster visibility.
+// The operations of the additional resources are left out, as their own webhook already sends them to the Falcon Admission Controller.
+func workloadRules(additionalResources []falconv1alpha1.FalconAdmissionWebhookResource, scope *arv1.ScopeType) []arv1.RuleWithOperations {
+	operations := []arv1.OperationType{arv1.Create, arv1.Update}
+	reviewed := map[string][]arv1.OperationType{}
+	for _, resource := range additionalResources {
+		reviewed[resource.Resource] = resource.Operations
+		if len(resource.Operations) == 0 {
+			reviewed[resource.Resource] = operations
+		}
 	}
+
+	rule := func(group string, resources []string, operations []arv1.OperationType) arv1.RuleWithOperations {
+		return arv1.RuleWithOperations{
+			Operations: operations,
+			Rule: arv1.Rule{
+				APIGroups:   []string{group},
+				APIVersions: []string{"v1"},
+				Resources:   resources,
+				Scope:       scope,
+			},
+		}
+	}
+
+	rules := []arv1.RuleWithOperations{}
+	for _, workload := range workloadResources {
+		resources := []string{}
+		partialRules := []arv1.RuleWithOperations{}
+		for _, resource := range workload.resources {
+			reviewedOperations, ok := reviewed[resource]
+			if !ok {
+				resources = append(resources, resource)
+				continue
+			}
+
+			remaining := slices.DeleteFunc(slices.Clone(operations), func(operation arv1.OperationType) bool {
+				return slices.Contains(reviewedOperations, operation)
+			})
+			if len(remaining) > 0 {
+				partialRules = append(partialRules, rule(workload.group, []string{resource}, remaining))
+			}
+		}
+
+		if len(resources) > 0 {
+			rules = append(rules, rule(workload.group, resources, operations))
+		}
+		rules = append(rules, partialRules...)
+	}
+
+	return rules
+}
+
+// webhookTimeout returns the configured timeout of a webhook, or the default of 10 seconds
+func webhookTimeout(timeoutSeconds *int32) int32 {
+	if timeoutSeconds == nil {
+		return 10
+	}
+
+	return *timeoutSeconds
 }
@@ -3,6 +3,7 @@ package assets
 import (
 	"testing"
 
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
 	"github.com/crowdstrike/falcon-operator/pkg/common"
 	"github.com/google/go-cmp/cmp"
 	"golang.org/x/exp/maps"
@@ -14,12 +15,105 @@ import (
 func TestValidatingWebhook(t *testing.T) {
 	want := testValidatingWebhook("test", "test", "test", []byte("test"), 123, arv1.Ignore, []string{"ns1", "ns2"})
 
-	got := ValidatingWebhook("test", "test", "test", []byte("test"), 123, arv1.Ignore, []string{"ns1", "ns2"})
+	got := ValidatingWebhook("test", "test", "test", []byte("test"), 123, arv1.Ignore, []string{"ns1", "ns2"}, falconv1alpha1.FalconAdmissionWebhook{})
 	if diff := cmp.Diff(want, got); diff != "" {
 		t.Errorf("ValidatingWebhook() mismatch (-want +got): %s", diff)
 	}
 }
 
+// TestValidatingWebhookScope tests the selectors, timeouts and additional resources of the ValidatingWebhook function
+func TestValidatingWebhookScope(t *testing.T) {
+	timeout := int32(5)
+	workloadTimeout := int32(3)
+	jobTimeout := int32(20)
+	webhookSpec := falconv1alpha1.FalconAdmissionWebhook{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"team": "payments"},
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "env", Operator: metav1.LabelSelectorOpIn, Values: []string{"prod"}},
+			},
+		},
+		ObjectSelector:         &metav1.LabelSelector{MatchLabels: map[string]string{"reviewed": "true"}},
+		TimeoutSeconds:         &timeout,
+		WorkloadTimeoutSeconds: &workloadTimeout,
+		AdditionalResources: []falconv1alpha1.FalconAdmissionWebhookResource{
+			{Resource: "deployments"},
+			{Resource: "jobs", Operations: []arv1.OperationType{arv1.Create}, TimeoutSeconds: &jobTimeout},
+		},
+	}
+
+	got := ValidatingWebhook("test", "test", "test", []byte("test"), 123, arv1.Fail, []string{"ns1"}, webhookSpec)
+
+	wantNames := []string{"test", "workload.test", "deployments.test", "jobs.test"}
+	if len(got.Webhooks) != len(wantNames) {
+		t.Fatalf("ValidatingWebhook() has %d webhooks, want %d", len(got.Webhooks), len(wantNames))
+	}
+
+	wantTimeouts := []int32{5, 3, 10, 20}
+	wantNamespaceSelector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"team": "payments"},
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "kubernetes.io/metadata.name", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"ns1"}},
+			{Key: common.FalconAdmissionReviewKey, Operator: metav1.LabelSelectorOpNotIn, Values: []string{"disabled"}},
+			{Key: "env", Operator: metav1.LabelSelectorOpIn, Values: []string{"prod"}},
+		},
+	}
+
+	for i, webhook := range got.Webhooks {
+		if webhook.Name != wantNames[i] {
+			t.Errorf("webhook %d name = %s, want %s", i, webhook.Name, wantNames[i])
+		}
+		if *webhook.TimeoutSeconds != wantTimeouts[i] {
+			t.Errorf("webhook %s timeout = %d, want %d", webhook.Name, *webhook.TimeoutSeconds, wantTimeouts[i])
+		}
+		if diff := cmp.Diff(wantNamespaceSelector, webhook.NamespaceSelector); diff != "" {
+			t.Errorf("webhook %s namespaceSelector mismatch (-want +got): %s", webhook.Name, diff)
+		}
+		if diff := cmp.Diff(webhookSpec.ObjectSelector, webhook.ObjectSelector); diff != "" {
+			t.Errorf("webhook %s objectSelector mismatch (-want +got): %s", webhook.Name, diff)
+		}
+	}
+
+	wantRules := []arv1.RuleWithOperations{
+		{
+			Operations: []arv1.OperationType{arv1.Create},
+			Rule: arv1.Rule{
+				APIGroups:   []string{"batch"},
+				APIVersions: []string{"v1"},
+				Resources:   []string{"jobs"},
+				Scope:       got.Webhooks[3].Rules[0].Scope,
+			},
+		},
+	}
+	if diff := cmp.Diff(wantRules, got.Webhooks[3].Rules); diff != "" {
+		t.Errorf("jobs webhook rules mismatch (-want +got): %s", diff)
+	}
+	if got.Webhooks[2].Rules[0].APIGroups[0] != "apps" || len(got.Webhooks[2].Rules[0].Operations) != 2 {
+		t.Errorf("deployments webhook rules = %v, want CREATE and UPDATE of apps deployments", got.Webhooks[2].Rules)
+	}
+
+	scope := got.Webhooks[1].Rules[0].Scope
+	createUpdate := []arv1.OperationType{arv1.Create, arv1.Update}
+	wantWorkloadRules := []arv1.RuleWithOperations{
+		{Operations: createUpdate, Rule: arv1.Rule{APIGroups: []string{""}, APIVersions: []string{"v1"}, Resources: []string{"replicationcontrollers", "services"}, Scope: scope}},
+		{Operations: createUpdate, Rule: arv1.Rule{APIGroups: []string{"apps"}, APIVersions: []string{"v1"}, Resources: []string{"daemonsets", "replicasets", "statefulsets"}, Scope: scope}},
+		{Operations: createUpdate, Rule: arv1.Rule{APIGroups: []string{"batch"}, APIVersions: []string{"v1"}, Resources: []string{"cronjobs"}, Scope: scope}},
+		{Operations: []arv1.OperationType{arv1.Update}, Rule: arv1.Rule{APIGroups: []string{"batch"}, APIVersions: []string{"v1"}, Resources: []string{"jobs"}, Scope: scope}},
+	}
+	if diff := cmp.Diff(wantWorkloadRules, got.Webhooks[1].Rules); diff != "" {
+		t.Errorf("workload webhook rules mismatch, additional resources must not be reviewed twice (-want +got): %s", diff)
+	}
+
+	for _, webhook := range got.Webhooks[2:] {
+		if *webhook.FailurePolicy != arv1.Fail {
+			t.Errorf("webhook %s failurePolicy = %s, want Fail", webhook.Name, *webhook.FailurePolicy)
+		}
+	}
+	if *got.Webhooks[1].FailurePolicy != arv1.Ignore {
+		t.Errorf("workload webhook failurePolicy = %s, want Ignore", *got.Webhooks[1].FailurePolicy)
+	}
+}
+
 // testValidatingWebhook is a helper function to create a ValidatingWebhookConfiguration
 func testValidatingWebhook(name string, namespace string, webhookName string, caBundle []byte, port int32, failPolicy arv1.FailurePolicyType, disabledNamespaces []string) *arv1.ValidatingWebhookConfiguration {
 	failurePolicy := arv1.Ignore
@@ -66,6 +160,7 @@ func testValidatingWebhook(name string, namespace string, webhookName string, ca
 					},
 				},
 				TimeoutSeconds: &timeoutSeconds,
+				ObjectSelector: &metav1.LabelSelector{},
 				NamespaceSelector: &metav1.LabelSelector{
 					MatchExpressions: []metav1.LabelSelectorRequirement{
 						{
@@ -118,6 +213,7 @@ func testValidatingWebhook(name string, namespace string, webhookName string, ca
 					},
 				},
 				TimeoutSeconds: &timeoutSeconds,
+				ObjectSelector: &metav1.LabelSelector{},
 				NamespaceSelector: &metav1.LabelSelector{
 					MatchExpressions: []metav1.LabelSelectorRequirement{
 						{
@@ -0,0 +1,109 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrCertificateNotIssued is returned while cert-manager has not written the certificate to its Secret yet
+var ErrCertificateNotIssued = errors.New("waiting for cert-manager to issue the TLS certificate")
+
+// minRenewalRequeue is the shortest delay before reconciling again to renew a self-signed certificate
+const minRenewalRequeue = time.Minute
+
+// UseCertManager reports whether cert-manager issues a serving certificate, which requires it to be configured and installed in the cluster
+func UseCertManager(log logr.Logger, certManager *falconv1alpha1.FalconCertManager, installed bool) bool {
+	if certManager == nil {
+		return false
+	}
+
+	if !installed {
+		log.Info("cert-manager is configured but not installed, falling back to a self-signed certificate")
+		return false
+	}
+
+	return true
+}
+
+// CertificateIssued reports whether cert-manager has written the certificate of the given name to the Secret
+func CertificateIssued(secret *corev1.Secret, certificateName string) bool {
+	return secret.Annotations[certv1.CertificateNameKey] == certificateName && len(secret.Data[corev1.TLSCertKey]) > 0
+}
+
+// SetInjectCAFrom sets the annotation that has the cert-manager CA injector keep the caBundle of a webhook configuration in sync with
+// the given namespace/name Certificate, or removes it when certificate is empty. It reports whether the annotations changed.
+func SetInjectCAFrom(obj metav1.Object, certificate string) bool {
+	annotations := obj.GetAnnotations()
+	if annotations[certv1.WantInjectAnnotation] == certificate {
+		return false
+	}
+
+	if certificate == "" {
+		delete(annotations, certv1.WantInjectAnnotation)
+	} else {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[certv1.WantInjectAnnotation] = certificate
+	}
+
+	obj.SetAnnotations(annotations)
+	return true
+}
+
+// ReconcileCertificate creates or updates the cert-manager Certificate, and returns its Secret once cert-manager has issued the certificate.
+// It returns ErrCertificateNotIssued until then. The create and update functions persist the Certificate and record it in the status of
+// the custom resource, which create also sets as the controller of the Certificate.
+func ReconcileCertificate[T FalconReconciler[T]](ctx context.Context, reconciler T, certificate *certv1.Certificate, create, update func(client.Object) error) (*corev1.Secret, error) {
+	existingCertificate := &certv1.Certificate{}
+	err := common.GetNamespacedObject(ctx, reconciler.GetK8sClient(), reconciler.GetK8sReader(), client.ObjectKeyFromObject(certificate), existingCertificate)
+	if apierrors.IsNotFound(err) {
+		if err := create(certificate); err != nil {
+			return &corev1.Secret{}, err
+		}
+		return &corev1.Secret{}, ErrCertificateNotIssued
+	} else if err != nil {
+		return &corev1.Secret{}, fmt.Errorf("unable to get Certificate %s: %w", certificate.Name, err)
+	}
+
+	if !equality.Semantic.DeepEqual(existingCertificate.Spec, certificate.Spec) {
+		existingCertificate.Spec = certificate.Spec
+		existingCertificate.SetGroupVersionKind(certv1.SchemeGroupVersion.WithKind(certv1.CertificateKind))
+		if err := update(existingCertificate); err != nil {
+			return &corev1.Secret{}, err
+		}
+	}
+
+	secret := &corev1.Secret{}
+	err = common.GetNamespacedObject(ctx, reconciler.GetK8sClient(), reconciler.GetK8sReader(), types.NamespacedName{Name: certificate.Spec.SecretName, Namespace: certificate.Namespace}, secret)
+	if apierrors.IsNotFound(err) || (err == nil && !CertificateIssued(secret, certificate.Name)) {
+		return &corev1.Secret{}, ErrCertificateNotIssued
+	} else if err != nil {
+		return &corev1.Secret{}, fmt.Errorf("unable to get TLS Secret %s: %w", certificate.Spec.SecretName, err)
+	}
+
+	return secret, nil
+}
+
+// RenewalRequeue returns how long to wait before reconciling again to renew a self-signed certificate due for renewal at the given time.
+// It returns 0 when cert-manager issues the certificate, since cert-manager renews it and the owned Certificate triggers a reconciliation.
+func RenewalRequeue(renewal time.Time, useCertManager bool) time.Duration {
+	if useCertManager {
+		return 0
+	}
+
+	return max(time.Until(renewal), minRenewalRequeue)
+}
@@ -0,0 +1,154 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	arv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type testReconciler struct {
+	client client.Client
+}
+
+func (r *testReconciler) GetK8sClient() client.Client {
+	return r.client
+}
+
+func (r *testReconciler) GetK8sReader() client.Reader {
+	return r.client
+}
+
+func TestCertificateIssued(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret *corev1.Secret
+		want   bool
+	}{
+		{
+			name: "issued",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"cert-manager.io/certificate-name": "test-tls"}},
+				Data:       map[string][]byte{"tls.crt": []byte("cert")},
+			},
+			want: true,
+		},
+		{
+			name: "self-signed secret",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{"tls.crt": []byte("cert")},
+			},
+		},
+		{
+			name: "pending issuance",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"cert-manager.io/certificate-name": "test-tls"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CertificateIssued(tt.secret, "test-tls"); got != tt.want {
+				t.Errorf("CertificateIssued() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetInjectCAFrom(t *testing.T) {
+	webhook := &arv1.ValidatingWebhookConfiguration{}
+
+	if !SetInjectCAFrom(webhook, "falcon-kac/falcon-kac-tls") {
+		t.Error("SetInjectCAFrom() should report the added annotation")
+	}
+	if got := webhook.Annotations["cert-manager.io/inject-ca-from"]; got != "falcon-kac/falcon-kac-tls" {
+		t.Errorf("SetInjectCAFrom() annotation = %q, want falcon-kac/falcon-kac-tls", got)
+	}
+
+	if SetInjectCAFrom(webhook, "falcon-kac/falcon-kac-tls") {
+		t.Error("SetInjectCAFrom() should not report an unchanged annotation")
+	}
+
+	if !SetInjectCAFrom(webhook, "") {
+		t.Error("SetInjectCAFrom() should report the removed annotation")
+	}
+	if _, ok := webhook.Annotations["cert-manager.io/inject-ca-from"]; ok {
+		t.Error("SetInjectCAFrom() should remove the annotation")
+	}
+
+	if SetInjectCAFrom(&arv1.MutatingWebhookConfiguration{}, "") {
+		t.Error("SetInjectCAFrom() should not report a change without annotations")
+	}
+}
+
+func TestReconcileCertificate(t *testing.T) {
+	ctx := context.Background()
+
+	fakeClient, err := getFakeClient()
+	if err != nil {
+		t.Fatalf("TestReconcileCertificate getFakeClient() error = %v", err)
+	}
+	r := &testReconciler{client: fakeClient}
+
+	certificate := &certv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-tls", Namespace: "falcon-system"},
+		Spec:       certv1.CertificateSpec{SecretName: "test-tls", DNSNames: []string{"test.falcon-system.svc"}},
+	}
+	create := func(obj client.Object) error { return fakeClient.Create(ctx, obj) }
+	update := func(obj client.Object) error { return fakeClient.Update(ctx, obj) }
+
+	if _, err := ReconcileCertificate(ctx, r, certificate.DeepCopy(), create, update); !errors.Is(err, ErrCertificateNotIssued) {
+		t.Fatalf("ReconcileCertificate() error = %v, want ErrCertificateNotIssued after creating the Certificate", err)
+	}
+
+	certificate.Spec.DNSNames = append(certificate.Spec.DNSNames, "test.falcon-system.svc.cluster.local")
+	if _, err := ReconcileCertificate(ctx, r, certificate.DeepCopy(), create, update); !errors.Is(err, ErrCertificateNotIssued) {
+		t.Fatalf("ReconcileCertificate() error = %v, want ErrCertificateNotIssued without a Secret", err)
+	}
+
+	existing := &certv1.Certificate{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(certificate), existing); err != nil {
+		t.Fatalf("failed to get Certificate: %v", err)
+	}
+	if len(existing.Spec.DNSNames) != 2 {
+		t.Errorf("ReconcileCertificate() did not update the Certificate spec, DNSNames = %v", existing.Spec.DNSNames)
+	}
+
+	// Issuers that do not provide the CA only write the certificate and the key
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-tls", Namespace: "falcon-system", Annotations: map[string]string{certv1.CertificateNameKey: "test-tls"}},
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+	}
+	if err := fakeClient.Create(ctx, secret); err != nil {
+		t.Fatalf("failed to create Secret: %v", err)
+	}
+
+	got, err := ReconcileCertificate(ctx, r, certificate.DeepCopy(), create, update)
+	if err != nil {
+		t.Fatalf("ReconcileCertificate() error = %v", err)
+	}
+	if string(got.Data[corev1.TLSCertKey]) != "cert" {
+		t.Errorf("ReconcileCertificate() returned Secret data %v, want the issued certificate", got.Data)
+	}
+}
+
+func TestRenewalRequeue(t *testing.T) {
+	if got := RenewalRequeue(time.Now().Add(-time.Hour), false); got != minRenewalRequeue {
+		t.Errorf("RenewalRequeue() for an overdue renewal = %v, want %v", got, minRenewalRequeue)
+	}
+
+	if got := RenewalRequeue(time.Now().Add(48*time.Hour), false); got < 47*time.Hour {
+		t.Errorf("RenewalRequeue() = %v, want about 48h", got)
+	}
+
+	if got := RenewalRequeue(time.Now().Add(-time.Hour), true); got != 0 {
+		t.Errorf("RenewalRequeue() with cert-manager = %v, want 0", got)
+	}
+}
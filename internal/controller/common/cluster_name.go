@@ -0,0 +1,309 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// kubeadmDefaultClusterName is the cluster name kubeadm uses when none is configured, which does not identify the cluster
+	kubeadmDefaultClusterName = "kubernetes"
+
+	// eksctlClusterNameLabel is set by eksctl on the nodes of EKS clusters
+	eksctlClusterNameLabel = "alpha.eksctl.io/cluster-name"
+
+	// aksClusterLabel is set by AKS to the node resource group of the cluster, MC_<resource group>_<cluster name>_<location>
+	aksClusterLabel = "kubernetes.azure.com/cluster"
+
+	// gkeClusterNameLabel holds the cluster name on GKE node VMs, and is read when it is also set on the nodes
+	gkeClusterNameLabel = "goog-k8s-cluster-name"
+
+	// gkeNodePoolLabel is set by GKE to the node pool of the node
+	gkeNodePoolLabel = "cloud.google.com/gke-nodepool"
+
+	// clusterNameRedetectInterval is how long the operator waits before detecting the cluster name again when none was found
+	clusterNameRedetectInterval = 10 * time.Minute
+)
+
+var (
+	openShiftInfrastructureGVK = schema.GroupVersionKind{Group: "config.openshift.io", Version: "v1", Kind: "Infrastructure"}
+
+	// openShiftInfraIDSuffix matches the random suffix that the OpenShift installer appends to the cluster name in the infrastructure name
+	openShiftInfraIDSuffix = regexp.MustCompile(`-[a-z0-9]{5}$`)
+
+	// aksProviderIDResourceGroup matches the node resource group in the provider ID of AKS nodes
+	aksProviderIDResourceGroup = regexp.MustCompile(`(?i)^azure:///subscriptions/[^/]+/resourceGroups/([^/]+)/`)
+
+	// detectedClusterName caches the detected cluster name, which does not change while the operator runs
+	detectedClusterName struct {
+		sync.Mutex
+		name       string
+		source     string
+		detectedAt time.Time
+	}
+)
+
+// clusterNameSource detects the cluster name from one source. It returns an empty name when the source is not available.
+type clusterNameSource struct {
+	name   string
+	detect func(ctx context.Context, reader client.Reader) (string, error)
+}
+
+var clusterNameSources = []clusterNameSource{
+	{name: "the kubeadm-config ConfigMap", detect: kubeadmClusterName},
+	{name: "the OpenShift Infrastructure", detect: openShiftClusterName},
+	{name: "the nodes", detect: nodeClusterName},
+}
+
+// ValidClusterName reports whether the cluster name can be reported by the Falcon components.
+// Those rules had been taken from EKS (Amazon AWS).
+// See more at: https://docs.aws.amazon.com/eks/latest/APIReference/API_CreateCluster.html#API_CreateCluster_RequestSyntax
+func ValidClusterName(clusterName string) bool {
+	if len(clusterName) > 100 || len(clusterName) == 0 {
+		return false
+	}
+
+	if !unicode.IsLetter(rune(clusterName[0])) && !unicode.IsNumber(rune(clusterName[0])) {
+		return false
+	}
+
+	return !strings.ContainsFunc(clusterName, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '-' && r != '_'
+	})
+}
+
+// ResolveClusterName returns the cluster name reported by the Falcon components: the configured name when it is valid, otherwise
+// the detected one when detection is enabled. The returned ClusterNameReady condition reports where the name comes from, whether
+// the configured name is invalid, and why the detection failed.
+func ResolveClusterName(ctx context.Context, reader client.Reader, configured string, detect bool) (string, metav1.Condition) {
+	if configured != "" && ValidClusterName(configured) {
+		return configured, metav1.Condition{
+			Type:    falconv1alpha1.ConditionClusterNameReady,
+			Status:  metav1.ConditionTrue,
+			Reason:  falconv1alpha1.ReasonClusterNameConfigured,
+			Message: fmt.Sprintf("Using the configured cluster name %s", configured),
+		}
+	}
+
+	invalid := ""
+	if configured != "" {
+		invalid = fmt.Sprintf("The cluster name %q is invalid: it must start with a letter or digit and only contain letters, digits, '-' and '_', up to 100 characters.", configured)
+	}
+
+	detected, source := "", ""
+	if detect {
+		var err error
+		detected, source, err = DetectClusterName(ctx, reader)
+		if err != nil {
+			return "", metav1.Condition{
+				Type:    falconv1alpha1.ConditionClusterNameReady,
+				Status:  metav1.ConditionFalse,
+				Reason:  falconv1alpha1.ReasonClusterNameDetectionFailed,
+				Message: strings.TrimSpace(fmt.Sprintf("%s %s", invalid, err)),
+			}
+		}
+	}
+
+	switch {
+	case invalid != "":
+		if detected != "" {
+			invalid += fmt.Sprintf(" Using the cluster name %s detected from %s instead.", detected, source)
+		}
+
+		return detected, metav1.Condition{
+			Type:    falconv1alpha1.ConditionClusterNameReady,
+			Status:  metav1.ConditionFalse,
+			Reason:  falconv1alpha1.ReasonInvalidClusterName,
+			Message: invalid,
+		}
+	case detected != "":
+		return detected, metav1.Condition{
+			Type:    falconv1alpha1.ConditionClusterNameReady,
+			Status:  metav1.ConditionTrue,
+			Reason:  falconv1alpha1.ReasonClusterNameDetected,
+			Message: fmt.Sprintf("Using the cluster name %s detected from %s", detected, source),
+		}
+	case detect:
+		return "", metav1.Condition{
+			Type:    falconv1alpha1.ConditionClusterNameReady,
+			Status:  metav1.ConditionUnknown,
+			Reason:  falconv1alpha1.ReasonClusterNameNotFound,
+			Message: "No cluster name is configured and none could be detected. Managed clusters resolve the cluster name in the CrowdStrike cloud.",
+		}
+	default:
+		return "", metav1.Condition{
+			Type:    falconv1alpha1.ConditionClusterNameReady,
+			Status:  metav1.ConditionUnknown,
+			Reason:  falconv1alpha1.ReasonClusterNameNotFound,
+			Message: "No cluster name is configured. Managed clusters resolve the cluster name in the CrowdStrike cloud.",
+		}
+	}
+}
+
+// DetectClusterName returns the cluster name and the source it was detected from, trying the kubeadm configuration, the OpenShift
+// infrastructure and the labels and provider ID of the nodes in that order. It returns an empty name when no source reports a valid name.
+// A detected name is kept for the lifetime of the operator, while failed detections are retried after clusterNameRedetectInterval.
+func DetectClusterName(ctx context.Context, reader client.Reader) (string, string, error) {
+	detectedClusterName.Lock()
+	defer detectedClusterName.Unlock()
+
+	if detectedClusterName.name != "" || time.Since(detectedClusterName.detectedAt) < clusterNameRedetectInterval {
+		return detectedClusterName.name, detectedClusterName.source, nil
+	}
+
+	for _, source := range clusterNameSources {
+		name, err := source.detect(ctx, reader)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to detect the cluster name from %s: %w", source.name, err)
+		}
+
+		if ValidClusterName(name) {
+			detectedClusterName.name, detectedClusterName.source = name, source.name
+			break
+		}
+	}
+	detectedClusterName.detectedAt = time.Now()
+
+	return detectedClusterName.name, detectedClusterName.source, nil
+}
+
+// kubeadmClusterName reads the clusterName of the kubeadm ClusterConfiguration
+func kubeadmClusterName(ctx context.Context, reader client.Reader) (string, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: "kubeadm-config", Namespace: metav1.NamespaceSystem}, configMap); err != nil {
+		return "", ignoreUnavailable(err)
+	}
+
+	clusterConfiguration := struct {
+		ClusterName string `json:"clusterName"`
+	}{}
+	if err := yaml.Unmarshal([]byte(configMap.Data["ClusterConfiguration"]), &clusterConfiguration); err != nil {
+		return "", nil
+	}
+
+	if clusterConfiguration.ClusterName == kubeadmDefaultClusterName {
+		return "", nil
+	}
+
+	return clusterConfiguration.ClusterName, nil
+}
+
+// openShiftClusterName reads the cluster name from the API server URL of the OpenShift cluster, https://api.<cluster name>.<base domain>:6443,
+// or else from its infrastructure name without the random suffix of the infrastructure ID
+func openShiftClusterName(ctx context.Context, reader client.Reader) (string, error) {
+	infrastructure := &unstructured.Unstructured{}
+	infrastructure.SetGroupVersionKind(openShiftInfrastructureGVK)
+	if err := reader.Get(ctx, types.NamespacedName{Name: "cluster"}, infrastructure); err != nil {
+		return "", ignoreUnavailable(err)
+	}
+
+	apiServerURL, _, _ := unstructured.NestedString(infrastructure.Object, "status", "apiServerURL")
+	infrastructureName, _, _ := unstructured.NestedString(infrastructure.Object, "status", "infrastructureName")
+	return clusterNameFromOpenShiftInfrastructure(apiServerURL, infrastructureName), nil
+}
+
+// clusterNameFromOpenShiftInfrastructure returns the cluster name of the OpenShift API server URL or infrastructure name
+func clusterNameFromOpenShiftInfrastructure(apiServerURL, infrastructureName string) string {
+	if u, err := url.Parse(apiServerURL); err == nil {
+		if labels := strings.Split(u.Hostname(), "."); len(labels) > 2 && labels[0] == "api" {
+			return labels[1]
+		}
+	}
+
+	return openShiftInfraIDSuffix.ReplaceAllString(infrastructureName, "")
+}
+
+// nodeClusterName reads the cluster name from the labels and provider ID of the nodes
+func nodeClusterName(ctx context.Context, reader client.Reader) (string, error) {
+	nodes := &corev1.NodeList{}
+	if err := reader.List(ctx, nodes, client.Limit(1)); err != nil {
+		return "", ignoreUnavailable(err)
+	}
+
+	if len(nodes.Items) == 0 {
+		return "", nil
+	}
+
+	return clusterNameFromNode(&nodes.Items[0]), nil
+}
+
+// clusterNameFromNode returns the cluster name set in the labels or the provider ID of the node, or an empty string when it cannot be told apart
+func clusterNameFromNode(node *corev1.Node) string {
+	for _, label := range []string{eksctlClusterNameLabel, gkeClusterNameLabel} {
+		if name := node.Labels[label]; name != "" {
+			return name
+		}
+	}
+
+	resourceGroup := node.Labels[aksClusterLabel]
+	if resourceGroup == "" {
+		if match := aksProviderIDResourceGroup.FindStringSubmatch(node.Spec.ProviderID); match != nil {
+			resourceGroup = match[1]
+		}
+	}
+	if name := clusterNameFromAKSResourceGroup(resourceGroup); name != "" {
+		return name
+	}
+
+	return clusterNameFromGKEProviderID(node.Spec.ProviderID, node.Labels[gkeNodePoolLabel])
+}
+
+// clusterNameFromAKSResourceGroup returns the cluster name of an AKS node resource group, MC_<resource group>_<cluster name>_<location>.
+// Both the resource group and the cluster name may contain '_', so the node resource group only identifies the cluster when neither does.
+func clusterNameFromAKSResourceGroup(resourceGroup string) string {
+	if !strings.HasPrefix(strings.ToUpper(resourceGroup), "MC_") {
+		return ""
+	}
+
+	if parts := strings.Split(resourceGroup, "_"); len(parts) == 4 {
+		return parts[2]
+	}
+
+	return ""
+}
+
+// clusterNameFromGKEProviderID returns the cluster name in the instance name of a GKE provider ID, gce://<project>/<zone>/gke-<cluster name>-<node pool>-<hash>-<suffix>.
+// The cluster name is only used when the full node pool name follows it, since GKE shortens the instance names of long cluster and node pool names.
+func clusterNameFromGKEProviderID(providerID, nodePool string) string {
+	if nodePool == "" || !strings.HasPrefix(providerID, "gce://") {
+		return ""
+	}
+
+	instance := providerID[strings.LastIndex(providerID, "/")+1:]
+	if !strings.HasPrefix(instance, "gke-") {
+		return ""
+	}
+
+	end := strings.LastIndex(instance, "-"+nodePool+"-")
+	if end <= len("gke-") {
+		return ""
+	}
+
+	return instance[len("gke-"):end]
+}
+
+// ignoreUnavailable ignores the errors of cluster name sources that do not exist in the cluster or that the operator cannot read
+func ignoreUnavailable(err error) error {
+	if errors.IsNotFound(err) || errors.IsForbidden(err) || meta.IsNoMatchError(err) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,276 @@
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func resetDetectedClusterName() {
+	detectedClusterName.name = ""
+	detectedClusterName.source = ""
+	detectedClusterName.detectedAt = time.Time{}
+}
+
+func TestValidClusterName(t *testing.T) {
+	tests := map[string]bool{
+		"my-cluster_1":            true,
+		"1cluster":                true,
+		"":                        false,
+		"-cluster":                false,
+		"my.cluster":              false,
+		"my cluster":              false,
+		string(make([]byte, 101)): false,
+	}
+
+	for name, want := range tests {
+		if got := ValidClusterName(name); got != want {
+			t.Errorf("ValidClusterName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestClusterNameFromNode(t *testing.T) {
+	tests := []struct {
+		name string
+		node corev1.Node
+		want string
+	}{
+		{
+			name: "eks",
+			node: corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{eksctlClusterNameLabel: "eks-cluster"}}},
+			want: "eks-cluster",
+		},
+		{
+			name: "aks",
+			node: corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{aksClusterLabel: "MC_my-resource-group_aks-cluster_eastus"}}},
+			want: "aks-cluster",
+		},
+		{
+			name: "aks with '_' in the resource group or cluster name",
+			node: corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{aksClusterLabel: "MC_my_resource_group_aks-cluster_eastus"}}},
+			want: "",
+		},
+		{
+			name: "aks provider id",
+			node: corev1.Node{Spec: corev1.NodeSpec{
+				ProviderID: "azure:///subscriptions/0000/resourceGroups/mc_my-resource-group_aks-cluster_eastus/providers/Microsoft.Compute/virtualMachineScaleSets/aks-nodepool1-12345678-vmss/virtualMachines/0",
+			}},
+			want: "aks-cluster",
+		},
+		{
+			name: "gke label",
+			node: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{gkeClusterNameLabel: "gke-cluster", gkeNodePoolLabel: "default-pool"}},
+				Spec:       corev1.NodeSpec{ProviderID: "gce://my-project/us-central1-a/gke-gke-clus-default-po-1a2b3c4d-x7yz"},
+			},
+			want: "gke-cluster",
+		},
+		{
+			name: "gke provider id",
+			node: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{gkeNodePoolLabel: "default-pool"}},
+				Spec:       corev1.NodeSpec{ProviderID: "gce://my-project/us-central1-a/gke-gke-cluster-default-pool-1a2b3c4d-x7yz"},
+			},
+			want: "gke-cluster",
+		},
+		{
+			name: "gke provider id with a shortened node pool name",
+			node: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{gkeNodePoolLabel: "default-pool"}},
+				Spec:       corev1.NodeSpec{ProviderID: "gce://my-project/us-central1-a/gke-gke-clus-default-po-1a2b3c4d-x7yz"},
+			},
+			want: "",
+		},
+		{
+			name: "unmanaged",
+			node: corev1.Node{Spec: corev1.NodeSpec{ProviderID: "kind://docker/kind/kind-control-plane"}},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, clusterNameFromNode(&tt.node)); diff != "" {
+				t.Errorf("clusterNameFromNode() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClusterNameFromOpenShiftInfrastructure(t *testing.T) {
+	tests := []struct {
+		name               string
+		apiServerURL       string
+		infrastructureName string
+		want               string
+	}{
+		{
+			name:               "api server url",
+			apiServerURL:       "https://api.mycluster.example.com:6443",
+			infrastructureName: "mycluster-x7k2p",
+			want:               "mycluster",
+		},
+		{
+			name:               "infrastructure name without the infra id suffix",
+			apiServerURL:       "https://10.0.0.1:6443",
+			infrastructureName: "mycluster-x7k2p",
+			want:               "mycluster",
+		},
+		{
+			name: "not set",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, clusterNameFromOpenShiftInfrastructure(tt.apiServerURL, tt.infrastructureName)); diff != "" {
+				t.Errorf("clusterNameFromOpenShiftInfrastructure() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestResolveClusterName(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(resetDetectedClusterName)
+
+	kubeadmConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubeadm-config", Namespace: metav1.NamespaceSystem},
+		Data:       map[string]string{"ClusterConfiguration": "apiVersion: kubeadm.k8s.io/v1beta3\nkind: ClusterConfiguration\nclusterName: kubeadm-cluster\n"},
+	}
+	defaultKubeadmConfig := kubeadmConfig.DeepCopy()
+	defaultKubeadmConfig.Data["ClusterConfiguration"] = "clusterName: kubernetes\n"
+	eksNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: map[string]string{eksctlClusterNameLabel: "eks-cluster"}}}
+
+	tests := []struct {
+		name       string
+		objects    []corev1.ConfigMap
+		nodes      []corev1.Node
+		configured string
+		detect     bool
+		wantName   string
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:       "configured",
+			objects:    []corev1.ConfigMap{*kubeadmConfig},
+			configured: "my-cluster",
+			wantName:   "my-cluster",
+			wantStatus: metav1.ConditionTrue,
+			wantReason: falconv1alpha1.ReasonClusterNameConfigured,
+		},
+		{
+			name:       "kubeadm",
+			objects:    []corev1.ConfigMap{*kubeadmConfig},
+			nodes:      []corev1.Node{*eksNode},
+			detect:     true,
+			wantName:   "kubeadm-cluster",
+			wantStatus: metav1.ConditionTrue,
+			wantReason: falconv1alpha1.ReasonClusterNameDetected,
+		},
+		{
+			name:       "kubeadm default name falls back to the nodes",
+			objects:    []corev1.ConfigMap{*defaultKubeadmConfig},
+			nodes:      []corev1.Node{*eksNode},
+			detect:     true,
+			wantName:   "eks-cluster",
+			wantStatus: metav1.ConditionTrue,
+			wantReason: falconv1alpha1.ReasonClusterNameDetected,
+		},
+		{
+			name:       "invalid configured name",
+			nodes:      []corev1.Node{*eksNode},
+			configured: "my.cluster",
+			detect:     true,
+			wantName:   "eks-cluster",
+			wantStatus: metav1.ConditionFalse,
+			wantReason: falconv1alpha1.ReasonInvalidClusterName,
+		},
+		{
+			name:       "invalid configured name without detection",
+			nodes:      []corev1.Node{*eksNode},
+			configured: "my.cluster",
+			wantName:   "",
+			wantStatus: metav1.ConditionFalse,
+			wantReason: falconv1alpha1.ReasonInvalidClusterName,
+		},
+		{
+			name:       "not found",
+			objects:    []corev1.ConfigMap{*defaultKubeadmConfig},
+			detect:     true,
+			wantName:   "",
+			wantStatus: metav1.ConditionUnknown,
+			wantReason: falconv1alpha1.ReasonClusterNameNotFound,
+		},
+		{
+			name:       "detection disabled",
+			objects:    []corev1.ConfigMap{*kubeadmConfig},
+			nodes:      []corev1.Node{*eksNode},
+			wantName:   "",
+			wantStatus: metav1.ConditionUnknown,
+			wantReason: falconv1alpha1.ReasonClusterNameNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetDetectedClusterName()
+
+			fakeClient, err := getFakeClient()
+			if err != nil {
+				t.Fatalf("TestResolveClusterName getFakeClient() error = %v", err)
+			}
+			for i := range tt.objects {
+				if err := fakeClient.Create(ctx, &tt.objects[i]); err != nil {
+					t.Fatalf("TestResolveClusterName Create() error = %v", err)
+				}
+			}
+			for i := range tt.nodes {
+				if err := fakeClient.Create(ctx, &tt.nodes[i]); err != nil {
+					t.Fatalf("TestResolveClusterName Create() error = %v", err)
+				}
+			}
+
+			name, condition := ResolveClusterName(ctx, fakeClient, tt.configured, tt.detect)
+
+			if diff := cmp.Diff(tt.wantName, name); diff != "" {
+				t.Errorf("ResolveClusterName() name mismatch (-want +got):\n%s", diff)
+			}
+			if condition.Status != tt.wantStatus || condition.Reason != tt.wantReason {
+				t.Errorf("ResolveClusterName() condition = %s/%s, want %s/%s", condition.Status, condition.Reason, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
+
+// unavailableReader fails every read, as when the API server cannot be reached
+type unavailableReader struct {
+	client.Reader
+}
+
+func (unavailableReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	return apierrors.NewServiceUnavailable("unavailable")
+}
+
+func TestResolveClusterName_WhenDetectionFails_ReportsCondition(t *testing.T) {
+	t.Cleanup(resetDetectedClusterName)
+	resetDetectedClusterName()
+
+	name, condition := ResolveClusterName(context.Background(), unavailableReader{}, "", true)
+	if name != "" {
+		t.Errorf("ResolveClusterName() name = %q, want none", name)
+	}
+	if condition.Status != metav1.ConditionFalse || condition.Reason != falconv1alpha1.ReasonClusterNameDetectionFailed {
+		t.Errorf("ResolveClusterName() condition = %s/%s, want %s/%s", condition.Status, condition.Reason, metav1.ConditionFalse, falconv1alpha1.ReasonClusterNameDetectionFailed)
+	}
+}
@@ -2,6 +2,8 @@ package common
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
 	"github.com/crowdstrike/falcon-operator/pkg/common"
@@ -16,10 +18,18 @@ type FalconReconciler[T any] interface {
 	GetK8sReader() client.Reader
 }
 
+// ErrMaintenanceTokenNamespace is returned when the FalconSecret holds a maintenance token that the sensor pods cannot read.
+var ErrMaintenanceTokenNamespace = errors.New("the maintenance token of the FalconSecret is not used")
+
+// InjectFalconSecretData replaces the sensitive values of the Falcon CRD with the values of its FalconSecret.
+// The sensor pods read the maintenance token through a secret key reference, which only resolves in their own namespace,
+// so the token is only referenced when the FalconSecret is in installNamespace. Otherwise, ErrMaintenanceTokenNamespace is returned
+// once the other values are injected. An empty installNamespace skips the maintenance token, for resources whose pods do not read it.
 func InjectFalconSecretData[T FalconReconciler[T], U FalconCRD](
 	ctx context.Context,
 	reconciler T,
 	falconCrd U,
+	installNamespace string,
 ) error {
 	secret := &corev1.Secret{}
 	falconSecret := falconCrd.GetFalconSecretSpec()
@@ -39,6 +49,16 @@ func InjectFalconSecretData[T FalconReconciler[T], U FalconCRD](
 
 	falconSpec.CID = cid
 	falconSpec.PToken = provisioningToken
+
+	var maintenanceTokenErr error
+	if maintenanceTokenRef := falcon_secret.GetFalconMaintenanceTokenRefFromSecret(secret); maintenanceTokenRef != nil && installNamespace != "" {
+		if falconSecret.Namespace == installNamespace {
+			falconSpec.MaintenanceTokenSecretRef = maintenanceTokenRef
+		} else {
+			maintenanceTokenErr = fmt.Errorf("%w: FalconSecret %s/%s must be in the install namespace %s to provide the falcon-maintenance-token key to the sensor",
+				ErrMaintenanceTokenNamespace, falconSecret.Namespace, falconSecret.SecretName, installNamespace)
+		}
+	}
 	falconCrd.SetFalconSpec(falconSpec)
 
 	falconApi := falconCrd.GetFalconAPISpec()
@@ -52,5 +72,5 @@ func InjectFalconSecretData[T FalconReconciler[T], U FalconCRD](
 	falconApi.CID = cid
 	falconCrd.SetFalconAPISpec(falconApi)
 
-	return nil
+	return maintenanceTokenErr
 }
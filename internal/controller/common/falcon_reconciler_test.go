@@ -0,0 +1,54 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestInjectFalconSecretData_MaintenanceToken(t *testing.T) {
+	ctx := context.Background()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "falcon-secrets", Namespace: "falcon-secrets"},
+		Data: map[string][]byte{
+			"falcon-cid":               []byte("ABCDEF0123456789ABCDEF0123456789-AB"),
+			"falcon-maintenance-token": []byte("token"),
+		},
+	}
+	fakeClient, err := getFakeClient(secret)
+	require.NoError(t, err)
+	r := &testReconciler{client: fakeClient}
+
+	newNodeSensor := func() *falconv1alpha1.FalconNodeSensor {
+		nodesensor := &falconv1alpha1.FalconNodeSensor{}
+		nodesensor.Spec.FalconSecret = falconv1alpha1.FalconSecret{Enabled: true, Namespace: secret.Namespace, SecretName: secret.Name}
+		return nodesensor
+	}
+
+	// A secret key reference only resolves in the namespace of the sensor pods
+	nodesensor := newNodeSensor()
+	err = InjectFalconSecretData(ctx, r, nodesensor, "falcon-system")
+	assert.True(t, errors.Is(err, ErrMaintenanceTokenNamespace))
+	assert.Nil(t, nodesensor.Spec.Falcon.MaintenanceTokenSecretRef)
+	if assert.NotNil(t, nodesensor.Spec.Falcon.CID) {
+		assert.Equal(t, "ABCDEF0123456789ABCDEF0123456789-AB", *nodesensor.Spec.Falcon.CID)
+	}
+
+	nodesensor = newNodeSensor()
+	require.NoError(t, InjectFalconSecretData(ctx, r, nodesensor, secret.Namespace))
+	assert.Equal(t, &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+		Key:                  "falcon-maintenance-token",
+	}, nodesensor.Spec.Falcon.MaintenanceTokenSecretRef)
+
+	nodesensor = newNodeSensor()
+	require.NoError(t, InjectFalconSecretData(ctx, r, nodesensor, ""))
+	assert.Nil(t, nodesensor.Spec.Falcon.MaintenanceTokenSecretRef)
+}
@@ -0,0 +1,58 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/internal/controller/assets"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReconcilePodDisruptionBudget creates, updates or removes the PodDisruptionBudget of the Deployment, which has the name of the Deployment,
+// according to the availability settings of the owner. The create, update and remove functions persist the PodDisruptionBudget and record
+// it in the status of the owner, which create also sets as the controller of the PodDisruptionBudget.
+func ReconcilePodDisruptionBudget[T FalconReconciler[T]](ctx context.Context, reconciler T, owner metav1.Object, deployment *appsv1.Deployment, component string,
+	availability falconv1alpha1.FalconAvailability, create, update, remove func(client.Object) error) error {
+	existingPDB := &policyv1.PodDisruptionBudget{}
+	err := common.GetNamespacedObject(ctx, reconciler.GetK8sClient(), reconciler.GetK8sReader(), client.ObjectKeyFromObject(deployment), existingPDB)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("unable to get PodDisruptionBudget %s: %w", deployment.Name, err)
+	}
+	exists := err == nil
+	existingPDB.SetGroupVersionKind(policyv1.SchemeGroupVersion.WithKind("PodDisruptionBudget"))
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	if !availability.PodDisruptionBudgetEnabled(replicas) {
+		if exists && metav1.IsControlledBy(existingPDB, owner) {
+			return remove(existingPDB)
+		}
+		return nil
+	}
+
+	pdb := assets.PodDisruptionBudget(deployment.Name, deployment.Namespace, component, deployment.Spec.Selector, availability.PodDisruptionBudget)
+	if !exists {
+		return create(pdb)
+	}
+
+	if !equality.Semantic.DeepEqual(existingPDB.Spec.MinAvailable, pdb.Spec.MinAvailable) ||
+		!equality.Semantic.DeepEqual(existingPDB.Spec.MaxUnavailable, pdb.Spec.MaxUnavailable) ||
+		!equality.Semantic.DeepEqual(existingPDB.Spec.Selector, pdb.Spec.Selector) {
+		existingPDB.Spec.MinAvailable = pdb.Spec.MinAvailable
+		existingPDB.Spec.MaxUnavailable = pdb.Spec.MaxUnavailable
+		existingPDB.Spec.Selector = pdb.Spec.Selector
+		return update(existingPDB)
+	}
+
+	return nil
+}
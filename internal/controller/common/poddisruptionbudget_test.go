@@ -0,0 +1,77 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReconcilePodDisruptionBudget(t *testing.T) {
+	ctx := context.Background()
+
+	fakeClient, err := getFakeClient()
+	if err != nil {
+		t.Fatalf("TestReconcilePodDisruptionBudget getFakeClient() error = %v", err)
+	}
+	r := &testReconciler{client: fakeClient}
+
+	owner := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "falcon-system", UID: "owner-uid"}}
+	replicas := int32(2)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "falcon-kac", Namespace: "falcon-system"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "falcon-kac"}},
+		},
+	}
+
+	create := func(obj client.Object) error {
+		obj.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(owner, appsv1.SchemeGroupVersion.WithKind("Deployment"))})
+		return fakeClient.Create(ctx, obj)
+	}
+	update := func(obj client.Object) error { return fakeClient.Update(ctx, obj) }
+	remove := func(obj client.Object) error { return fakeClient.Delete(ctx, obj) }
+
+	availability := falconv1alpha1.FalconAvailability{}
+	if err := ReconcilePodDisruptionBudget(ctx, r, owner, deployment, "test", availability, create, update, remove); err != nil {
+		t.Fatalf("ReconcilePodDisruptionBudget() error = %v", err)
+	}
+
+	pdb := &policyv1.PodDisruptionBudget{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), pdb); err != nil {
+		t.Fatalf("ReconcilePodDisruptionBudget() did not create the PodDisruptionBudget: %v", err)
+	}
+	if pdb.Spec.MaxUnavailable == nil || pdb.Spec.MaxUnavailable.IntValue() != 1 {
+		t.Errorf("PodDisruptionBudget maxUnavailable = %v, want 1", pdb.Spec.MaxUnavailable)
+	}
+
+	minAvailable := intstr.FromInt32(1)
+	availability.PodDisruptionBudget = &falconv1alpha1.FalconPodDisruptionBudget{MinAvailable: &minAvailable}
+	if err := ReconcilePodDisruptionBudget(ctx, r, owner, deployment, "test", availability, create, update, remove); err != nil {
+		t.Fatalf("ReconcilePodDisruptionBudget() error = %v", err)
+	}
+
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), pdb); err != nil {
+		t.Fatalf("failed to get PodDisruptionBudget: %v", err)
+	}
+	if pdb.Spec.MinAvailable == nil || pdb.Spec.MinAvailable.IntValue() != 1 || pdb.Spec.MaxUnavailable != nil {
+		t.Errorf("PodDisruptionBudget spec = %+v, want minAvailable 1", pdb.Spec)
+	}
+
+	replicas = 1
+	availability.PodDisruptionBudget = nil
+	if err := ReconcilePodDisruptionBudget(ctx, r, owner, deployment, "test", availability, create, update, remove); err != nil {
+		t.Fatalf("ReconcilePodDisruptionBudget() error = %v", err)
+	}
+
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), pdb); !apierrors.IsNotFound(err) {
+		t.Errorf("ReconcilePodDisruptionBudget() should remove the PodDisruptionBudget of a single replica, got error %v", err)
+	}
+}
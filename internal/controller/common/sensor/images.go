@@ -7,6 +7,7 @@ import (
 	"runtime"
 	"strings"
 
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
 	"github.com/crowdstrike/falcon-operator/pkg/registry/falcon_registry"
 	"github.com/crowdstrike/gofalcon/falcon"
 	"github.com/crowdstrike/gofalcon/falcon/client/sensor_update_policies"
@@ -17,9 +18,15 @@ import (
 )
 
 const amd64 = "amd64"
+const amd64Platform = "Linux"
 const arm64 = "arm64"
 const arm64Platform = "LinuxArm64"
 
+// variantPlatforms maps Go architecture names to update policy variant platforms where the names differ in more than case.
+var variantPlatforms = map[string]string{
+	arm64: arm64Platform,
+}
+
 var (
 	errInvalidSensorVersion  = errors.New("invalid sensor version")
 	errSensorVersionNotFound = errors.New("sensor version not found")
@@ -55,7 +62,7 @@ func (images ImageRepository) GetPreferredImage(ctx context.Context, sensorType
 		WithValues("architecture", images.getSystemArchitecture()).
 		WithValues("sensorType", sensorType)
 
-	version, err := images.getPreferredSensorVersion(versionSpec, updatePolicySpec, logger)
+	version, err := images.getPreferredSensorVersion(ctx, sensorType, versionSpec, updatePolicySpec, logger)
 	if err != nil {
 		return "", err
 	}
@@ -69,10 +76,101 @@ func (images ImageRepository) GetPreferredImage(ctx context.Context, sensorType
 	return tag, nil
 }
 
+// GetReleaseTrain returns the major.minor sensor release selected by the version or update policy.
+// The latest node sensor release is used when neither is requested.
+func (images ImageRepository) GetReleaseTrain(ctx context.Context, versionSpec *string, updatePolicySpec *string) (string, error) {
+	logger := log.FromContext(ctx).
+		WithValues("architecture", images.getSystemArchitecture())
+
+	version, err := images.getPreferredSensorVersion(ctx, falcon.NodeSensor, versionSpec, updatePolicySpec, logger)
+	if err != nil {
+		return "", err
+	}
+
+	if version == nil {
+		tag, err := images.getImageTagForSensorVersion(ctx, falcon.NodeSensor, nil)
+		if err != nil {
+			return "", err
+		}
+
+		version = &tag
+	}
+
+	train, err := releaseTrain(*version)
+	if err != nil {
+		return "", fmt.Errorf("unable to determine release train from sensor version %s: %w", *version, err)
+	}
+
+	logger.Info("selected sensor release train", "releaseTrain", train)
+	return train, nil
+}
+
 func (images *ImageRepository) SetOverrideImageUri(imageUri string) {
 	images.tags.SetCrowdstrikeRepoOverride(imageUri)
 }
 
+// GetUpdatePolicy returns the named Linux sensor update policy together with the sensor version it selects for the current system architecture.
+// Problems with the policy itself are reported as *UpdatePolicyError. Within a context from WithUpdatePolicyCache, the policy is only fetched once.
+func (images ImageRepository) GetUpdatePolicy(ctx context.Context, policyName string) (UpdatePolicy, error) {
+	if cache, ok := ctx.Value(updatePolicyCacheKey{}).(updatePolicyCache); ok {
+		if cached, found := cache[policyName]; found {
+			return cached.policy, cached.err
+		}
+
+		policy, err := images.fetchUpdatePolicy(policyName)
+		if policyErr := (*UpdatePolicyError)(nil); err == nil || errors.As(err, &policyErr) {
+			cache[policyName] = cachedUpdatePolicy{policy: policy, err: err}
+		}
+
+		return policy, err
+	}
+
+	return images.fetchUpdatePolicy(policyName)
+}
+
+func (images ImageRepository) fetchUpdatePolicy(policyName string) (UpdatePolicy, error) {
+	policyID, err := images.findPolicy(policyName)
+	if err != nil {
+		return UpdatePolicy{}, err
+	}
+
+	params := sensor_update_policies.NewGetSensorUpdatePoliciesV2Params().WithIds([]string{policyID})
+	response, err := images.api.GetSensorUpdatePoliciesV2(params)
+	if err != nil {
+		return UpdatePolicy{}, err
+	}
+
+	policies := getNonZeroValuesInSlice(response.Payload.Resources)
+	if len(policies) == 0 {
+		return UpdatePolicy{}, newUpdatePolicyError(falconv1alpha1.ReasonUpdatePolicyNotFound, "update-policy with ID %s not found", policyID)
+	}
+
+	policy := policies[0]
+	if policy.Enabled == nil || !*policy.Enabled {
+		return UpdatePolicy{}, newUpdatePolicyError(falconv1alpha1.ReasonUpdatePolicyDisabled, "update-policy with ID %s is disabled", policyID)
+	}
+
+	if policy.Settings == nil {
+		return UpdatePolicy{}, images.sensorVersionNotFound(policyID)
+	}
+
+	platform, sensorVersion, build, err := images.getSensorVersionForCurrentRuntimeArchitecture(policy)
+	if err == errInvalidSensorVersion {
+		return UpdatePolicy{}, newUpdatePolicyError(falconv1alpha1.ReasonInvalidSensorVersion, "update-policy with ID %s has an invalid sensor version", policyID)
+	} else if err != nil {
+		return UpdatePolicy{}, images.sensorVersionNotFound(policyID)
+	}
+
+	return UpdatePolicy{
+		ID:                  policyID,
+		Name:                policyName,
+		Platform:            platform,
+		SensorVersion:       sensorVersion,
+		Build:               pinnedBuild(build),
+		UninstallProtection: swag.StringValue(policy.Settings.UninstallProtection),
+	}, nil
+}
+
 func (images ImageRepository) findPolicy(policyName string) (string, error) {
 	filter := falconFilter{}.
 		addClause("platform_name", "Linux").
@@ -87,28 +185,14 @@ func (images ImageRepository) findPolicy(policyName string) (string, error) {
 
 	ids := getNonZeroValuesInSlice(response.Payload.Resources)
 	if len(ids) == 0 {
-		return "", fmt.Errorf("update-policy %s not found", policyName)
+		return "", newUpdatePolicyError(falconv1alpha1.ReasonUpdatePolicyNotFound, "update-policy %s not found", policyName)
 	}
 
 	return ids[0], nil
 }
 
-func (images ImageRepository) findSensorVersionByUpdatePolicy(updatePolicy string) (string, error) {
-	policyID, err := images.findPolicy(updatePolicy)
-	if err != nil {
-		return "", err
-	}
-
-	version, err := images.getSensorVersionForPolicy(policyID)
-	if err == errInvalidSensorVersion {
-		return "", fmt.Errorf("update-policy with ID %s has an invalid sensor version", policyID)
-	} else if err == errSensorVersionNotFound {
-		return "", fmt.Errorf("update-policy with ID %s contains no version for system architecture %s", policyID, images.getSystemArchitecture())
-	} else if err != nil {
-		return "", err
-	}
-
-	return version, nil
+func (images ImageRepository) sensorVersionNotFound(policyID string) error {
+	return newUpdatePolicyError(falconv1alpha1.ReasonSensorVersionNotFound, "update-policy with ID %s contains no version for system architecture %s", policyID, images.getSystemArchitecture())
 }
 
 func (images ImageRepository) getImageTagForSensorVersion(ctx context.Context, sensorType falcon.SensorType, version *string) (string, error) {
@@ -122,7 +206,7 @@ func (images ImageRepository) getImageTagForSensorVersion(ctx context.Context, s
 	return images.tags.LastContainerTag(ctx, sensorType, version)
 }
 
-func (images ImageRepository) getPreferredSensorVersion(versionSpec *string, updatePolicySpec *string, logger logr.Logger) (*string, error) {
+func (images ImageRepository) getPreferredSensorVersion(ctx context.Context, sensorType falcon.SensorType, versionSpec *string, updatePolicySpec *string, logger logr.Logger) (*string, error) {
 	if versionSpec != nil && *versionSpec != "" {
 		logger.Info("requested specific sensor version", "version", *versionSpec)
 		return versionSpec, nil
@@ -130,12 +214,13 @@ func (images ImageRepository) getPreferredSensorVersion(versionSpec *string, upd
 
 	if updatePolicySpec != nil && *updatePolicySpec != "" {
 		logger.Info("requested sensor update policy", "policyName", *updatePolicySpec)
-		version, err := images.findSensorVersionByUpdatePolicy(*updatePolicySpec)
+		policy, err := images.GetUpdatePolicy(ctx, *updatePolicySpec)
 		if err != nil {
 			return nil, err
 		}
 
-		logger.Info("version selected by sensor update policy", "policyName", *updatePolicySpec, "version", version)
+		version := policy.versionSpec(sensorType)
+		logger.Info("version selected by sensor update policy", "policyName", *updatePolicySpec, "platform", policy.Platform, "sensorVersion", policy.SensorVersion, "build", policy.Build, "version", version)
 		return &version, nil
 	}
 
@@ -143,45 +228,39 @@ func (images ImageRepository) getPreferredSensorVersion(versionSpec *string, upd
 	return nil, nil
 }
 
-func (images ImageRepository) getSensorVersionForCurrentRuntimeArchitecture(policy *models.SensorUpdatePolicyV2) (string, error) {
-	switch images.getSystemArchitecture() {
-	case amd64:
-		return trimVersion(policy.Settings.SensorVersion)
-	case arm64:
-		return getARM64Variant(policy)
+// getSensorVersionForCurrentRuntimeArchitecture returns the platform, sensor version and build configured for the system architecture.
+// The policy settings describe the x86_64 sensor, while every other Linux platform is listed as a variant.
+func (images ImageRepository) getSensorVersionForCurrentRuntimeArchitecture(policy *models.SensorUpdatePolicyV2) (string, string, string, error) {
+	architecture := images.getSystemArchitecture()
+	if architecture == amd64 {
+		version, err := parsePolicyVersion(policy.Settings.SensorVersion)
+		return amd64Platform, version, swag.StringValue(policy.Settings.Build), err
 	}
 
-	return "", errSensorVersionNotFound
-}
+	for _, variant := range policy.Settings.Variants {
+		if variant == nil || variant.Platform == nil || !isVariantForArchitecture(*variant.Platform, architecture) {
+			continue
+		}
 
-func (images ImageRepository) getSensorVersionForPolicy(policyID string) (string, error) {
-	params := sensor_update_policies.NewGetSensorUpdatePoliciesV2Params().WithIds([]string{policyID})
-	response, err := images.api.GetSensorUpdatePoliciesV2(params)
-	if err != nil {
-		return "", err
+		version, err := parsePolicyVersion(variant.SensorVersion)
+		return *variant.Platform, version, swag.StringValue(variant.Build), err
 	}
 
-	policies := getNonZeroValuesInSlice(response.Payload.Resources)
-	if len(policies) == 0 {
-		return "", fmt.Errorf("update-policy with ID %s not found", policyID)
-	}
+	return "", "", "", errSensorVersionNotFound
+}
 
-	policy := policies[0]
-	if !*policy.Enabled {
-		return "", fmt.Errorf("update-policy with ID %s is disabled", policyID)
+// isVariantForArchitecture matches policy variant platforms such as LinuxArm64 to a Go architecture name.
+func isVariantForArchitecture(platform string, architecture string) bool {
+	if architecture == "" {
+		return false
 	}
 
-	return images.getSensorVersionForCurrentRuntimeArchitecture(policy)
-}
-
-func getARM64Variant(policy *models.SensorUpdatePolicyV2) (string, error) {
-	for _, variant := range policy.Settings.Variants {
-		if *variant.Platform == arm64Platform {
-			return trimVersion(variant.SensorVersion)
-		}
+	if variantPlatform, ok := variantPlatforms[architecture]; ok {
+		return strings.EqualFold(platform, variantPlatform)
 	}
 
-	return "", errSensorVersionNotFound
+	platform = strings.ToLower(platform)
+	return strings.HasPrefix(platform, "linux") && strings.HasSuffix(platform, strings.ToLower(architecture))
 }
 
 func getNonZeroValuesInSlice[T any](input []T) []T {
@@ -196,7 +275,8 @@ func getNonZeroValuesInSlice[T any](input []T) []T {
 	return output
 }
 
-func trimVersion(version *string) (string, error) {
+// parsePolicyVersion validates a major.minor.build sensor version as reported by update policies.
+func parsePolicyVersion(version *string) (string, error) {
 	if version == nil {
 		return "", errSensorVersionNotFound
 	}
@@ -211,6 +291,44 @@ func trimVersion(version *string) (string, error) {
 		return "", errInvalidSensorVersion
 	}
 
+	for _, part := range parts {
+		if part == "" || strings.Trim(part, "0123456789") != "" {
+			return "", errInvalidSensorVersion
+		}
+	}
+
+	return trimmed, nil
+}
+
+// pinnedBuild returns the build number when a policy build setting pins a specific build.
+// Builds that follow a release stage carry a relative marker, e.g. 18410|n-1|tagged|16, and are not pinned.
+func pinnedBuild(build string) string {
+	fields := strings.Split(strings.TrimSpace(build), "|")
+	if fields[0] == "" || strings.Trim(fields[0], "0123456789") != "" {
+		return ""
+	}
+
+	for _, field := range fields[1:] {
+		if field == "n" || strings.HasPrefix(field, "n-") {
+			return ""
+		}
+	}
+
+	return fields[0]
+}
+
+// releaseTrain reduces a sensor version or image tag such as 7.31.0-18410-1 to its major.minor release.
+func releaseTrain(version string) (string, error) {
+	trimmed := strings.TrimSpace(version)
+	if trimmed == "" {
+		return "", errSensorVersionNotFound
+	}
+
+	parts := strings.Split(strings.SplitN(trimmed, "-", 2)[0], ".")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", errInvalidSensorVersion
+	}
+
 	return strings.Join(parts[0:2], "."), nil
 }
 
@@ -2,9 +2,10 @@ package sensor
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"testing"
 
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
 	"github.com/crowdstrike/falcon-operator/internal/apitest"
 	"github.com/crowdstrike/gofalcon/falcon"
 	"github.com/crowdstrike/gofalcon/falcon/client/sensor_update_policies"
@@ -69,7 +70,7 @@ func TestGetPreferredImage(t *testing.T) {
 		ExpectOutputs("imageByPolicy", noError).
 		WithMockCall(newQuerySensorUpdatePoliciesCall("somePolicyName", "somePolicyID", noError)).
 		WithMockCall(newGetSensorUpdatePoliciesCall("somePolicyID", policyExists, includeArmVersion, stringPointer("1.2.3"), policyEnabled, noError)).
-		WithMockCall(newLastContainerTagCall(ctx, falcon.SidecarSensor, stringPointer("1.2"), "imageByPolicy", noError)).
+		WithMockCall(newLastContainerTagCall(ctx, falcon.SidecarSensor, stringPointer("1.2."), "imageByPolicy", noError)).
 		Run(t, runner)
 
 	apitest.NewTest("armVersionByPolicy", arm64).
@@ -77,7 +78,7 @@ func TestGetPreferredImage(t *testing.T) {
 		ExpectOutputs("imageByPolicy", noError).
 		WithMockCall(newQuerySensorUpdatePoliciesCall("somePolicyName", "somePolicyID", noError)).
 		WithMockCall(newGetSensorUpdatePoliciesCall("somePolicyID", policyExists, includeArmVersion, stringPointer("1.2.3"), policyEnabled, noError)).
-		WithMockCall(newLastContainerTagCall(ctx, falcon.SidecarSensor, stringPointer("1.2"), "imageByPolicy", noError)).
+		WithMockCall(newLastContainerTagCall(ctx, falcon.SidecarSensor, stringPointer("1.2."), "imageByPolicy", noError)).
 		Run(t, runner)
 
 	apitest.NewTest("querySensorUpdatePoliciesFails", arm64).
@@ -95,59 +96,105 @@ func TestGetPreferredImage(t *testing.T) {
 
 	apitest.NewTest("policyNameNotFound", arm64).
 		WithInputs(falcon.SidecarSensor, noVersionRequested, stringPointer("somePolicyName")).
-		ExpectOutputs("", errors.New("update-policy somePolicyName not found")).
+		ExpectOutputs("", policyError(falconv1alpha1.ReasonUpdatePolicyNotFound, "update-policy somePolicyName not found")).
 		WithMockCall(newQuerySensorUpdatePoliciesCall("somePolicyName", "", noError)).
 		Run(t, runner)
 
 	apitest.NewTest("policyIDNotFound", arm64).
 		WithInputs(falcon.SidecarSensor, noVersionRequested, stringPointer("somePolicyName")).
-		ExpectOutputs("", errors.New("update-policy with ID somePolicyID not found")).
+		ExpectOutputs("", policyError(falconv1alpha1.ReasonUpdatePolicyNotFound, "update-policy with ID somePolicyID not found")).
 		WithMockCall(newQuerySensorUpdatePoliciesCall("somePolicyName", "somePolicyID", noError)).
 		WithMockCall(newGetSensorUpdatePoliciesCall("somePolicyID", policyDoesNotExist, includeArmVersion, nil, policyDisabled, noError)).
 		Run(t, runner)
 
 	apitest.NewTest("policyDisabled", arm64).
 		WithInputs(falcon.SidecarSensor, noVersionRequested, stringPointer("somePolicyName")).
-		ExpectOutputs("", errors.New("update-policy with ID somePolicyID is disabled")).
+		ExpectOutputs("", policyError(falconv1alpha1.ReasonUpdatePolicyDisabled, "update-policy with ID somePolicyID is disabled")).
 		WithMockCall(newQuerySensorUpdatePoliciesCall("somePolicyName", "somePolicyID", noError)).
 		WithMockCall(newGetSensorUpdatePoliciesCall("somePolicyID", policyExists, includeArmVersion, stringPointer("1.2.3"), policyDisabled, noError)).
 		Run(t, runner)
 
 	apitest.NewTest("nilSensorVersion", arm64).
 		WithInputs(falcon.SidecarSensor, noVersionRequested, stringPointer("somePolicyName")).
-		ExpectOutputs("", errors.New("update-policy with ID somePolicyID contains no version for system architecture arm64")).
+		ExpectOutputs("", policyError(falconv1alpha1.ReasonSensorVersionNotFound, "update-policy with ID somePolicyID contains no version for system architecture arm64")).
 		WithMockCall(newQuerySensorUpdatePoliciesCall("somePolicyName", "somePolicyID", noError)).
 		WithMockCall(newGetSensorUpdatePoliciesCall("somePolicyID", policyExists, includeArmVersion, nil, policyEnabled, noError)).
 		Run(t, runner)
 
 	apitest.NewTest("blankSensorVersion", arm64).
 		WithInputs(falcon.SidecarSensor, noVersionRequested, stringPointer("somePolicyName")).
-		ExpectOutputs("", errors.New("update-policy with ID somePolicyID contains no version for system architecture arm64")).
+		ExpectOutputs("", policyError(falconv1alpha1.ReasonSensorVersionNotFound, "update-policy with ID somePolicyID contains no version for system architecture arm64")).
 		WithMockCall(newQuerySensorUpdatePoliciesCall("somePolicyName", "somePolicyID", noError)).
 		WithMockCall(newGetSensorUpdatePoliciesCall("somePolicyID", policyExists, includeArmVersion, stringPointer(""), policyEnabled, noError)).
 		Run(t, runner)
 
 	apitest.NewTest("invalidSensorVersion", arm64).
 		WithInputs(falcon.SidecarSensor, noVersionRequested, stringPointer("somePolicyName")).
-		ExpectOutputs("", errors.New("update-policy with ID somePolicyID has an invalid sensor version")).
+		ExpectOutputs("", policyError(falconv1alpha1.ReasonInvalidSensorVersion, "update-policy with ID somePolicyID has an invalid sensor version")).
 		WithMockCall(newQuerySensorUpdatePoliciesCall("somePolicyName", "somePolicyID", noError)).
 		WithMockCall(newGetSensorUpdatePoliciesCall("somePolicyID", policyExists, includeArmVersion, stringPointer("1.2"), policyEnabled, noError)).
 		Run(t, runner)
 
 	apitest.NewTest("unconfiguredArmVariantNotFound", arm64).
 		WithInputs(falcon.SidecarSensor, noVersionRequested, stringPointer("somePolicyName")).
-		ExpectOutputs("", errors.New("update-policy with ID somePolicyID contains no version for system architecture arm64")).
+		ExpectOutputs("", policyError(falconv1alpha1.ReasonSensorVersionNotFound, "update-policy with ID somePolicyID contains no version for system architecture arm64")).
 		WithMockCall(newQuerySensorUpdatePoliciesCall("somePolicyName", "somePolicyID", noError)).
 		WithMockCall(newGetSensorUpdatePoliciesCall("somePolicyID", policyExists, excludeArmVersion, stringPointer("1.2.3"), policyEnabled, noError)).
 		Run(t, runner)
 
 	apitest.NewTest("unknownArchitectureVariantNotFound", "unknownArchitecture").
 		WithInputs(falcon.SidecarSensor, noVersionRequested, stringPointer("somePolicyName")).
-		ExpectOutputs("", errors.New("update-policy with ID somePolicyID contains no version for system architecture unknownArchitecture")).
+		ExpectOutputs("", policyError(falconv1alpha1.ReasonSensorVersionNotFound, "update-policy with ID somePolicyID contains no version for system architecture unknownArchitecture")).
 		WithMockCall(newQuerySensorUpdatePoliciesCall("somePolicyName", "somePolicyID", noError)).
 		WithMockCall(newGetSensorUpdatePoliciesCall("somePolicyID", policyExists, includeArmVersion, stringPointer("1.2.3"), policyEnabled, noError)).
 		Run(t, runner)
 
+	apitest.NewTest("pinnedNodeSensorBuildByPolicy", amd64).
+		WithInputs(falcon.NodeSensor, noVersionRequested, stringPointer("somePolicyName")).
+		ExpectOutputs("7.31.0-18410-1", noError).
+		WithMockCall(newQuerySensorUpdatePoliciesCall("somePolicyName", "somePolicyID", noError)).
+		WithMockCall(newGetSensorUpdatePolicyCall("somePolicyID", newPolicy("7.31.18410", "18410", nil), noError)).
+		WithMockCall(newLastNodeTagCall(ctx, stringPointer("7.31.0-18410-"), "7.31.0-18410-1", noError)).
+		Run(t, runner)
+
+	apitest.NewTest("pinnedBuildIgnoredForContainerSensors", amd64).
+		WithInputs(falcon.SidecarSensor, noVersionRequested, stringPointer("somePolicyName")).
+		ExpectOutputs("imageByPolicy", noError).
+		WithMockCall(newQuerySensorUpdatePoliciesCall("somePolicyName", "somePolicyID", noError)).
+		WithMockCall(newGetSensorUpdatePolicyCall("somePolicyID", newPolicy("7.31.18410", "18410", nil), noError)).
+		WithMockCall(newLastContainerTagCall(ctx, falcon.SidecarSensor, stringPointer("7.31."), "imageByPolicy", noError)).
+		Run(t, runner)
+
+	apitest.NewTest("releaseStageBuildByPolicy", amd64).
+		WithInputs(falcon.NodeSensor, noVersionRequested, stringPointer("somePolicyName")).
+		ExpectOutputs("7.30.0-18306-1", noError).
+		WithMockCall(newQuerySensorUpdatePoliciesCall("somePolicyName", "somePolicyID", noError)).
+		WithMockCall(newGetSensorUpdatePolicyCall("somePolicyID", newPolicy("7.30.18306", "18306|n-1|tagged|16", nil), noError)).
+		WithMockCall(newLastNodeTagCall(ctx, stringPointer("7.30."), "7.30.0-18306-1", noError)).
+		Run(t, runner)
+
+	apitest.NewTest("pinnedVariantBuildByPolicy", arm64).
+		WithInputs(falcon.NodeSensor, noVersionRequested, stringPointer("somePolicyName")).
+		ExpectOutputs("7.29.0-18202-1", noError).
+		WithMockCall(newQuerySensorUpdatePoliciesCall("somePolicyName", "somePolicyID", noError)).
+		WithMockCall(newGetSensorUpdatePolicyCall("somePolicyID", newPolicy("7.31.18410", "18410|n|tagged|17", []*models.SensorUpdateBuildRespV1{
+			{Platform: stringPointer("LinuxZLinux"), SensorVersion: stringPointer("7.28.18100"), Build: stringPointer("18100")},
+			{Platform: stringPointer(arm64Platform), SensorVersion: stringPointer("7.29.18202"), Build: stringPointer("18202")},
+		}), noError)).
+		WithMockCall(newLastNodeTagCall(ctx, stringPointer("7.29.0-18202-"), "7.29.0-18202-1", noError)).
+		Run(t, runner)
+
+	apitest.NewTest("otherPlatformVariantByPolicy", "s390x").
+		WithInputs(falcon.NodeSensor, noVersionRequested, stringPointer("somePolicyName")).
+		ExpectOutputs("7.28.0-18100-1", noError).
+		WithMockCall(newQuerySensorUpdatePoliciesCall("somePolicyName", "somePolicyID", noError)).
+		WithMockCall(newGetSensorUpdatePolicyCall("somePolicyID", newPolicy("7.31.18410", "", []*models.SensorUpdateBuildRespV1{
+			{Platform: stringPointer(arm64Platform), SensorVersion: stringPointer("7.29.18202")},
+			{Platform: stringPointer("LinuxS390x"), SensorVersion: stringPointer("7.28.18100")},
+		}), noError)).
+		WithMockCall(newLastNodeTagCall(ctx, stringPointer("7.28."), "7.28.0-18100-1", noError)).
+		Run(t, runner)
+
 	apitest.NewTest("lastContainerTagFails", arm64).
 		WithInputs(falcon.SidecarSensor, noVersionRequested, noUpdatePolicyRequested).
 		ExpectOutputs("", assert.AnError).
@@ -161,6 +208,60 @@ func TestGetPreferredImage(t *testing.T) {
 		Run(t, runner)
 }
 
+func TestGetReleaseTrain(t *testing.T) {
+	ctx := context.Background()
+
+	runner := func(t apitest.Test[string], architecture string) {
+		m := &mockFalcon{Mock: *t.GetMock()}
+		images := ImageRepository{
+			api:                   m,
+			getSystemArchitecture: func() string { return architecture },
+			tags:                  m,
+		}
+
+		train, err := images.GetReleaseTrain(ctx, t.GetStringPointerInput(0), t.GetStringPointerInput(1))
+		t.AssertExpectations(train, err)
+	}
+
+	noError := error(nil)
+	noUpdatePolicyRequested := (*string)(nil)
+	noVersionRequested := (*string)(nil)
+
+	apitest.NewTest("specificVersion", amd64).
+		WithInputs(stringPointer("7.31.0"), noUpdatePolicyRequested).
+		ExpectOutputs("7.31", noError).
+		Run(t, runner)
+
+	apitest.NewTest("releaseTrainVersion", amd64).
+		WithInputs(stringPointer("7.31"), noUpdatePolicyRequested).
+		ExpectOutputs("7.31", noError).
+		Run(t, runner)
+
+	apitest.NewTest("versionByPolicy", amd64).
+		WithInputs(noVersionRequested, stringPointer("somePolicyName")).
+		ExpectOutputs("1.2", noError).
+		WithMockCall(newQuerySensorUpdatePoliciesCall("somePolicyName", "somePolicyID", noError)).
+		WithMockCall(newGetSensorUpdatePoliciesCall("somePolicyID", true, true, stringPointer("1.2.3"), true, noError)).
+		Run(t, runner)
+
+	apitest.NewTest("latestNodeSensorVersion", amd64).
+		WithInputs(noVersionRequested, noUpdatePolicyRequested).
+		ExpectOutputs("7.31", noError).
+		WithMockCall(newLastNodeTagCall(ctx, noVersionRequested, "7.31.0-18410-1", noError)).
+		Run(t, runner)
+
+	apitest.NewTest("latestNodeSensorVersionFails", amd64).
+		WithInputs(noVersionRequested, noUpdatePolicyRequested).
+		ExpectOutputs("", assert.AnError).
+		WithMockCall(newLastNodeTagCall(ctx, noVersionRequested, "", assert.AnError)).
+		Run(t, runner)
+
+	apitest.NewTest("invalidVersion", amd64).
+		WithInputs(stringPointer("7"), noUpdatePolicyRequested).
+		ExpectOutputs("", fmt.Errorf("unable to determine release train from sensor version 7: %w", errInvalidSensorVersion)).
+		Run(t, runner)
+}
+
 type mockFalcon struct {
 	mock.Mock
 }
@@ -219,6 +320,28 @@ func newGetSensorUpdatePoliciesCall(policyID string, policyExists bool, includeA
 	return m
 }
 
+func newGetSensorUpdatePolicyCall(policyID string, policy *models.SensorUpdatePolicyV2, expectedError error) *mock.Mock {
+	params := sensor_update_policies.NewGetSensorUpdatePoliciesV2Params().WithIds([]string{policyID})
+	payload := &models.SensorUpdateRespV2{Resources: []*models.SensorUpdatePolicyV2{policy}}
+
+	m := &mock.Mock{}
+	m.On("GetSensorUpdatePoliciesV2", params, []sensor_update_policies.ClientOption(nil)).
+		Return(&sensor_update_policies.GetSensorUpdatePoliciesV2OK{Payload: payload}, expectedError)
+	return m
+}
+
+func newPolicy(sensorVersion string, build string, variants []*models.SensorUpdateBuildRespV1) *models.SensorUpdatePolicyV2 {
+	enabled := true
+	return &models.SensorUpdatePolicyV2{
+		Enabled: &enabled,
+		Settings: &models.SensorUpdateSettingsRespV2{
+			Build:         &build,
+			SensorVersion: &sensorVersion,
+			Variants:      variants,
+		},
+	}
+}
+
 func newLastContainerTagCall(ctx context.Context, sensorType falcon.SensorType, versionRequested *string, expectedImage string, expectedError error) *mock.Mock {
 	m := &mock.Mock{}
 	m.On("LastContainerTag", ctx, sensorType, versionRequested).Return(expectedImage, expectedError)
@@ -250,6 +373,10 @@ func newQuerySensorUpdatePoliciesCall(updatePolicyRequested string, expectedPoli
 	return m
 }
 
+func policyError(reason string, message string) error {
+	return &UpdatePolicyError{Reason: reason, Message: message}
+}
+
 func stringPointer(s string) *string {
 	return &s
 }
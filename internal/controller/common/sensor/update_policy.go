@@ -0,0 +1,140 @@
+package sensor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/gofalcon/falcon"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	uninstallProtectionEnabled         = "ENABLED"
+	uninstallProtectionMaintenanceMode = "MAINTENANCE_MODE"
+)
+
+// UpdatePolicy is a Linux sensor update policy resolved for the current system architecture.
+type UpdatePolicy struct {
+	ID       string
+	Name     string
+	Platform string
+
+	// SensorVersion is the major.minor.build version selected by the policy, e.g. 7.31.18410.
+	SensorVersion string
+
+	// Build is set when the policy pins a specific build rather than following a release stage.
+	Build string
+
+	UninstallProtection string
+}
+
+// versionSpec returns the image tag prefix matching the sensor version selected by the policy.
+// Only node sensor images carry the Linux sensor build number, so the other sensor types are matched on the release.
+func (policy UpdatePolicy) versionSpec(sensorType falcon.SensorType) string {
+	parts := strings.Split(policy.SensorVersion, ".")
+	release := strings.Join(parts[0:2], ".")
+
+	if sensorType == falcon.NodeSensor && policy.Build != "" {
+		return fmt.Sprintf("%s.0-%s-", release, policy.Build)
+	}
+
+	// Match on the trailing dot so that release 7.3 does not select 7.31 tags
+	return release + "."
+}
+
+type updatePolicyCacheKey struct{}
+
+type cachedUpdatePolicy struct {
+	policy UpdatePolicy
+	err    error
+}
+
+type updatePolicyCache map[string]cachedUpdatePolicy
+
+// WithUpdatePolicyCache returns a context in which each update policy is fetched from the Falcon API at most once.
+// Reconcilers use it so that the policy checked for the UpdatePolicyReady condition is reused when selecting the sensor image.
+// The cache is not safe for concurrent use, so the context must not outlive a single reconcile.
+func WithUpdatePolicyCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, updatePolicyCacheKey{}, updatePolicyCache{})
+}
+
+// UpdatePolicyError reports an update policy that cannot be used to select a sensor version.
+type UpdatePolicyError struct {
+	Reason  string
+	Message string
+}
+
+func (err *UpdatePolicyError) Error() string {
+	return err.Message
+}
+
+func newUpdatePolicyError(reason string, format string, args ...any) error {
+	return &UpdatePolicyError{
+		Reason:  reason,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// UpdatePolicyCondition resolves the named update policy and describes the outcome as an UpdatePolicyReady condition.
+// An error is returned only when the policy could not be queried, so callers can report misconfigured policies without failing the reconcile.
+func UpdatePolicyCondition(ctx context.Context, apiConfig *falcon.ApiConfig, sensorType falcon.SensorType, policyName string) (metav1.Condition, error) {
+	images, err := NewImageRepository(ctx, apiConfig)
+	if err != nil {
+		return metav1.Condition{}, err
+	}
+
+	policy, err := images.GetUpdatePolicy(ctx, policyName)
+	if err != nil {
+		return UpdatePolicyFailedCondition(err)
+	}
+
+	return policy.Condition(sensorType), nil
+}
+
+// UpdatePolicyFailedCondition converts an *UpdatePolicyError into a failed UpdatePolicyReady condition and returns any other error unchanged.
+func UpdatePolicyFailedCondition(err error) (metav1.Condition, error) {
+	var policyErr *UpdatePolicyError
+	if !errors.As(err, &policyErr) {
+		return metav1.Condition{}, err
+	}
+
+	return metav1.Condition{
+		Type:    falconv1alpha1.ConditionUpdatePolicyReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  policyErr.Reason,
+		Message: policyErr.Message,
+	}, nil
+}
+
+// Condition describes the sensor version selected by the policy as an UpdatePolicyReady condition.
+// Uninstall protection only applies to the node sensor; the other sensors ignore that policy setting.
+func (policy UpdatePolicy) Condition(sensorType falcon.SensorType) metav1.Condition {
+	condition := metav1.Condition{
+		Type:    falconv1alpha1.ConditionUpdatePolicyReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  falconv1alpha1.ReasonReqMet,
+		Message: fmt.Sprintf("update-policy %s selects sensor version %s", policy.Name, policy.SensorVersion),
+	}
+
+	if policy.Build != "" {
+		condition.Message = fmt.Sprintf("update-policy %s pins sensor version %s", policy.Name, policy.SensorVersion)
+	}
+
+	if sensorType != falcon.NodeSensor {
+		return condition
+	}
+
+	switch policy.UninstallProtection {
+	case uninstallProtectionEnabled:
+		condition.Reason = falconv1alpha1.ReasonUninstallProtectionEnabled
+		condition.Message += "; uninstall and maintenance protection is enabled, which blocks updates and uninstallation of DaemonSet sensors 7.33 and earlier"
+	case uninstallProtectionMaintenanceMode:
+		condition.Reason = falconv1alpha1.ReasonMaintenanceModeEnabled
+		condition.Message += "; uninstall and maintenance protection is in maintenance mode"
+	}
+
+	return condition
+}
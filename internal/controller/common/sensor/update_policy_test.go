@@ -0,0 +1,117 @@
+package sensor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/gofalcon/falcon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPinnedBuild(t *testing.T) {
+	tests := map[string]string{
+		"":                    "",
+		"18410":               "18410",
+		"18410|tagged":        "18410",
+		"18410|n|tagged|17":   "",
+		"18306|n-1|tagged|16": "",
+		"n-2":                 "",
+		"not-a-build":         "",
+	}
+
+	for build, expected := range tests {
+		assert.Equal(t, expected, pinnedBuild(build), "build %q", build)
+	}
+}
+
+func TestUpdatePolicyCondition(t *testing.T) {
+	policy := UpdatePolicy{
+		Name:                "somePolicy",
+		SensorVersion:       "7.31.18410",
+		UninstallProtection: uninstallProtectionEnabled,
+	}
+
+	t.Run("should report uninstall protection for the node sensor", func(t *testing.T) {
+		condition := policy.Condition(falcon.NodeSensor)
+		assert.Equal(t, falconv1alpha1.ConditionUpdatePolicyReady, condition.Type)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, falconv1alpha1.ReasonUninstallProtectionEnabled, condition.Reason)
+		assert.Contains(t, condition.Message, "uninstall and maintenance protection is enabled")
+	})
+
+	t.Run("should ignore uninstall protection for other sensors", func(t *testing.T) {
+		condition := policy.Condition(falcon.SidecarSensor)
+		assert.Equal(t, falconv1alpha1.ReasonReqMet, condition.Reason)
+		assert.Equal(t, "update-policy somePolicy selects sensor version 7.31.18410", condition.Message)
+	})
+
+	t.Run("should report pinned builds", func(t *testing.T) {
+		pinned := policy
+		pinned.Build = "18410"
+		pinned.UninstallProtection = uninstallProtectionMaintenanceMode
+
+		condition := pinned.Condition(falcon.NodeSensor)
+		assert.Equal(t, falconv1alpha1.ReasonMaintenanceModeEnabled, condition.Reason)
+		assert.Contains(t, condition.Message, "pins sensor version 7.31.18410")
+	})
+}
+
+func TestUpdatePolicyFailedCondition(t *testing.T) {
+	t.Run("should convert update policy errors", func(t *testing.T) {
+		err := fmt.Errorf("wrapped: %w", newUpdatePolicyError(falconv1alpha1.ReasonUpdatePolicyDisabled, "update-policy with ID %s is disabled", "someID"))
+
+		condition, err := UpdatePolicyFailedCondition(err)
+		require.NoError(t, err)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, falconv1alpha1.ReasonUpdatePolicyDisabled, condition.Reason)
+		assert.Equal(t, "update-policy with ID someID is disabled", condition.Message)
+	})
+
+	t.Run("should return other errors", func(t *testing.T) {
+		_, err := UpdatePolicyFailedCondition(assert.AnError)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestGetUpdatePolicy(t *testing.T) {
+	newImages := func() (ImageRepository, *mockFalcon) {
+		m := &mockFalcon{}
+		m.Mock.ExpectedCalls = append(m.Mock.ExpectedCalls, newQuerySensorUpdatePoliciesCall("somePolicyName", "somePolicyID", nil).ExpectedCalls...)
+		m.Mock.ExpectedCalls = append(m.Mock.ExpectedCalls, newGetSensorUpdatePoliciesCall("somePolicyID", true, false, stringPointer("7.31.18410"), true, nil).ExpectedCalls...)
+
+		return ImageRepository{
+			api:                   m,
+			getSystemArchitecture: func() string { return amd64 },
+			tags:                  m,
+		}, m
+	}
+
+	t.Run("should fetch the policy once within a cached context", func(t *testing.T) {
+		images, m := newImages()
+		ctx := WithUpdatePolicyCache(context.Background())
+
+		for range 2 {
+			policy, err := images.GetUpdatePolicy(ctx, "somePolicyName")
+			require.NoError(t, err)
+			assert.Equal(t, "7.31.18410", policy.SensorVersion)
+		}
+
+		m.AssertNumberOfCalls(t, "QuerySensorUpdatePolicies", 1)
+		m.AssertNumberOfCalls(t, "GetSensorUpdatePoliciesV2", 1)
+	})
+
+	t.Run("should fetch the policy every time without a cache", func(t *testing.T) {
+		images, m := newImages()
+
+		for range 2 {
+			_, err := images.GetUpdatePolicy(context.Background(), "somePolicyName")
+			require.NoError(t, err)
+		}
+
+		m.AssertNumberOfCalls(t, "GetSensorUpdatePoliciesV2", 2)
+	})
+}
@@ -0,0 +1,52 @@
+package sensorversion
+
+import (
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AvailableUpdate returns the pending update for a detected sensor version, or nil when that version is already deployed.
+// The detection time of an unchanged pending update is preserved.
+func AvailableUpdate(current *falconv1alpha1.FalconAvailableUpdate, deployed *string, version string, source string) *falconv1alpha1.FalconAvailableUpdate {
+	if version == "" || (deployed != nil && *deployed == version) {
+		return nil
+	}
+
+	if current != nil && current.Version == version && current.Source == source {
+		return current
+	}
+
+	return &falconv1alpha1.FalconAvailableUpdate{
+		Version:    version,
+		Source:     source,
+		DetectedAt: metav1.Now(),
+	}
+}
+
+// IsBlockedByUpdatePolicy reports whether the last reconcile stopped because the update policy could not select a sensor version.
+// Policies are fixed in the Falcon console, so the tracker reconciles such objects once the policy selects a version again.
+func IsBlockedByUpdatePolicy(conditions []metav1.Condition) bool {
+	return meta.IsStatusConditionFalse(conditions, falconv1alpha1.ConditionUpdatePolicyReady)
+}
+
+// IsDeployed reports whether a pending update no longer applies because its version is the deployed one.
+func IsDeployed(update *falconv1alpha1.FalconAvailableUpdate, deployed *string) bool {
+	return update != nil && deployed != nil && update.Version == *deployed
+}
+
+// ApprovedVersion returns the version of the pending update when it has been approved with the approve-version annotation.
+// Approving any other version has no effect, so a stale annotation cannot roll the sensor back or skip ahead.
+func ApprovedVersion(obj metav1.Object, update *falconv1alpha1.FalconAvailableUpdate) (string, bool) {
+	if update == nil {
+		return "", false
+	}
+
+	approved, ok := obj.GetAnnotations()[common.FalconApproveVersionKey]
+	if !ok || approved != update.Version {
+		return "", false
+	}
+
+	return approved, true
+}
@@ -0,0 +1,89 @@
+package sensorversion
+
+import (
+	"testing"
+	"time"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAvailableUpdate(t *testing.T) {
+	deployed := "7.30.0-18306-1"
+	detectedAt := metav1.NewTime(time.Now().Add(-time.Hour))
+	pending := &falconv1alpha1.FalconAvailableUpdate{
+		Version:    "7.31.0-18410-1",
+		Source:     falconv1alpha1.AvailableUpdateSourceLatest,
+		DetectedAt: detectedAt,
+	}
+
+	t.Run("deployed version", func(t *testing.T) {
+		assert.Nil(t, AvailableUpdate(pending, &deployed, deployed, falconv1alpha1.AvailableUpdateSourceLatest))
+	})
+
+	t.Run("nothing deployed yet", func(t *testing.T) {
+		update := AvailableUpdate(nil, nil, "7.31.0-18410-1", falconv1alpha1.AvailableUpdateSourcePolicy)
+		assert.Equal(t, "7.31.0-18410-1", update.Version)
+		assert.Equal(t, falconv1alpha1.AvailableUpdateSourcePolicy, update.Source)
+	})
+
+	t.Run("unchanged pending update keeps detection time", func(t *testing.T) {
+		update := AvailableUpdate(pending, &deployed, "7.31.0-18410-1", falconv1alpha1.AvailableUpdateSourceLatest)
+		assert.Equal(t, detectedAt, update.DetectedAt)
+	})
+
+	t.Run("newer pending update", func(t *testing.T) {
+		update := AvailableUpdate(pending, &deployed, "7.32.0-18504-1", falconv1alpha1.AvailableUpdateSourceLatest)
+		assert.Equal(t, "7.32.0-18504-1", update.Version)
+		assert.True(t, update.DetectedAt.After(detectedAt.Time))
+	})
+}
+
+func TestApprovedVersion(t *testing.T) {
+	pending := &falconv1alpha1.FalconAvailableUpdate{
+		Version: "7.31.0-18410-1",
+		Source:  falconv1alpha1.AvailableUpdateSourceLatest,
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		update      *falconv1alpha1.FalconAvailableUpdate
+		want        string
+		wantOk      bool
+	}{
+		{
+			name:        "approved pending version",
+			annotations: map[string]string{common.FalconApproveVersionKey: "7.31.0-18410-1"},
+			update:      pending,
+			want:        "7.31.0-18410-1",
+			wantOk:      true,
+		},
+		{
+			name:        "other version approved",
+			annotations: map[string]string{common.FalconApproveVersionKey: "7.30.0-18306-1"},
+			update:      pending,
+		},
+		{
+			name:   "not approved",
+			update: pending,
+		},
+		{
+			name:        "no pending update",
+			annotations: map[string]string{common.FalconApproveVersionKey: "7.31.0-18410-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &falconv1alpha1.FalconNodeSensor{}
+			obj.SetAnnotations(tt.annotations)
+
+			got, ok := ApprovedVersion(obj, tt.update)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantOk, ok)
+		})
+	}
+}
@@ -3,6 +3,8 @@ package sensorversion
 import (
 	"context"
 
+	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensor"
+	"github.com/crowdstrike/falcon-operator/pkg/falcon_api"
 	"github.com/crowdstrike/falcon-operator/pkg/registry/falcon_registry"
 	"github.com/crowdstrike/gofalcon/falcon"
 )
@@ -13,6 +15,35 @@ func NewFalconCloudQuery(sensorType falcon.SensorType, apiConfig *falcon.ApiConf
 	}
 }
 
+// NewPreferredImageQuery returns the image tag the operator would select for the given version and update policy specs.
+// The repoOverride, when set, is the CrowdStrike registry repository override of the node sensor.
+func NewPreferredImageQuery(sensorType falcon.SensorType, apiConfig *falcon.ApiConfig, versionSpec *string, updatePolicySpec *string, repoOverride *string) SensorVersionQuery {
+	versionSpec = copyString(versionSpec)
+	updatePolicySpec = copyString(updatePolicySpec)
+	repoOverride = copyString(repoOverride)
+
+	return func(ctx context.Context) (string, error) {
+		config := *apiConfig
+		config.Context = ctx
+
+		images, err := sensor.NewImageRepository(ctx, &config)
+		if err != nil {
+			return "", err
+		}
+
+		if repoOverride != nil {
+			cloud, err := falcon_api.FalconCloud(ctx, &config)
+			if err != nil {
+				return "", err
+			}
+
+			images.SetOverrideImageUri(falcon_registry.CrowdstrikeRepoOverride(cloud, *repoOverride))
+		}
+
+		return images.GetPreferredImage(ctx, sensorType, versionSpec, updatePolicySpec)
+	}
+}
+
 func getLatestSensorVersion(ctx context.Context, sensorType falcon.SensorType, apiConfig *falcon.ApiConfig) (string, error) {
 	if sensorType == falcon.NodeSensor {
 		return getLatestSensorNodeVersion(ctx, apiConfig)
@@ -34,3 +65,13 @@ func getLatestSensorNodeVersion(ctx context.Context, apiConfig *falcon.ApiConfig
 
 	return registry.LastNodeTag(ctx, nil)
 }
+
+// copyString detaches the query from the object it was built from, which may be reused by later reconciles.
+func copyString(value *string) *string {
+	if value == nil {
+		return nil
+	}
+
+	copied := *value
+	return &copied
+}
@@ -2,21 +2,42 @@ package sensorversion
 
 import (
 	"context"
+	"slices"
 	"time"
 
+	"github.com/crowdstrike/gofalcon/falcon"
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-type Handler func(context.Context, types.NamespacedName) error
+type Handler func(context.Context, types.NamespacedName, string) error
 type SensorVersionQuery func(context.Context) (string, error)
 
+// maxPendingRefreshes bounds the refresh requests queued while a polling cycle is running.
+const maxPendingRefreshes = 8
+
+// Key identifies a track by the kind and name of the tracked resource.
+// The Falcon custom resources are cluster-scoped, so resources of different kinds may share the same name.
+type Key struct {
+	GroupVersionKind schema.GroupVersionKind
+	Name             types.NamespacedName
+}
+
+func NewKey(gvk schema.GroupVersionKind, name types.NamespacedName) Key {
+	return Key{
+		GroupVersionKind: gvk,
+		Name:             name,
+	}
+}
+
 type Tracker struct {
-	activeTracks    map[types.NamespacedName]*track
+	activeTracks    map[Key]*track
 	ctx             context.Context
 	logger          logr.Logger
 	pollingInterval time.Duration
+	refreshRequests chan []falcon.SensorType
 	trackUpdates    chan track
 }
 
@@ -24,16 +45,19 @@ type track struct {
 	forceHandler     bool
 	getSensorVersion SensorVersionQuery
 	handler          Handler
-	name             types.NamespacedName
+	key              Key
+	notifyInitial    bool
 	priorVersion     string
+	sensorType       falcon.SensorType
 }
 
 func NewTracker(ctx context.Context, pollingInterval time.Duration) Tracker {
 	return Tracker{
-		activeTracks:    make(map[types.NamespacedName]*track),
+		activeTracks:    make(map[Key]*track),
 		ctx:             ctx,
 		logger:          log.FromContext(ctx).WithName("sensor-version-tracker"),
 		pollingInterval: pollingInterval,
+		refreshRequests: make(chan []falcon.SensorType, maxPendingRefreshes),
 		trackUpdates:    make(chan track),
 	}
 }
@@ -52,18 +76,43 @@ func (tracker Tracker) StartTracking() {
 	}
 }
 
-func (tracker Tracker) StopTracking(name types.NamespacedName) {
+func (tracker Tracker) StopTracking(key Key) {
+	tracker.trackUpdates <- track{
+		key: key,
+	}
+}
+
+func (tracker Tracker) Track(key Key, sensorType falcon.SensorType, getSensorVersion SensorVersionQuery, handler Handler, forceHandler bool) {
 	tracker.trackUpdates <- track{
-		name: name,
+		forceHandler:     forceHandler,
+		getSensorVersion: getSensorVersion,
+		handler:          handler,
+		key:              key,
+		sensorType:       sensorType,
 	}
 }
 
-func (tracker Tracker) Track(name types.NamespacedName, getSensorVersion SensorVersionQuery, handler Handler, forceHandler bool) {
+// TrackWithInitialVersion is like Track, but also calls the handler with the version found when the track is added,
+// so that the handler does not have to wait a full polling interval to learn about the current version.
+func (tracker Tracker) TrackWithInitialVersion(key Key, sensorType falcon.SensorType, getSensorVersion SensorVersionQuery, handler Handler, forceHandler bool) {
 	tracker.trackUpdates <- track{
 		forceHandler:     forceHandler,
 		getSensorVersion: getSensorVersion,
 		handler:          handler,
-		name:             name,
+		key:              key,
+		notifyInitial:    true,
+		sensorType:       sensorType,
+	}
+}
+
+// Refresh runs a polling cycle for the tracks of the given sensor types, or for all tracks when none are given, without waiting for the polling interval.
+// It returns false when too many refreshes are already pending.
+func (tracker Tracker) Refresh(sensorTypes ...falcon.SensorType) bool {
+	select {
+	case tracker.refreshRequests <- sensorTypes:
+		return true
+	default:
+		return false
 	}
 }
 
@@ -80,20 +129,20 @@ func (tracker Tracker) TrackChanges() error {
 
 		case update := <-tracker.trackUpdates:
 			if update.getSensorVersion != nil && update.handler != nil {
-				if err := tracker.updateTrack(update); err != nil {
-					return err
-				}
+				tracker.updateTrack(update)
 			} else {
-				if _, exists := tracker.activeTracks[update.name]; exists {
-					delete(tracker.activeTracks, update.name)
-					tracker.logDebug("deleted track", "namespace", update.name.Namespace, "name", update.name.Name)
+				if _, exists := tracker.activeTracks[update.key]; exists {
+					delete(tracker.activeTracks, update.key)
+					tracker.logDebug("deleted track", update.key.logValues()...)
 				}
 			}
 
+		case sensorTypes := <-tracker.refreshRequests:
+			tracker.logDebug("refresh requested", "sensorTypes", sensorTypes)
+			tracker.runPollingCycle(sensorTypes...)
+
 		case <-timer.C:
-			if err := tracker.runPollingCycle(); err != nil {
-				return err
-			}
+			tracker.runPollingCycle()
 
 			timer.Reset(tracker.pollingInterval)
 			tracker.logDebug("waiting for next polling cycle", "interval", tracker.pollingInterval.String())
@@ -108,61 +157,85 @@ func NewTestTracker() (Tracker, func()) {
 	return tracker, cancel
 }
 
+func (key Key) logValues() []any {
+	return []any{"kind", key.GroupVersionKind.Kind, "namespace", key.Name.Namespace, "name", key.Name.Name}
+}
+
 func (tracker Tracker) logDebug(msg string, keysAndValues ...any) {
 	tracker.logger.V(1).Info(msg, keysAndValues...)
 }
 
-func (tracker Tracker) runPollingCycle() error {
+// runPollingCycle checks the tracks for new sensor versions. A failing track is logged and retried on the next cycle,
+// so that it does not hold back the other tracks.
+func (tracker Tracker) runPollingCycle(sensorTypes ...falcon.SensorType) {
 	tracker.logDebug("started polling cycle")
 
-	for name, trk := range tracker.activeTracks {
+	for key, trk := range tracker.activeTracks {
+		if len(sensorTypes) > 0 && !slices.Contains(sensorTypes, trk.sensorType) {
+			continue
+		}
+
 		latestVersion, err := trk.getSensorVersion(tracker.ctx)
 		if err != nil {
-			return err
+			// Forget the prior version, so that the handler is called once the version can be found again
+			tracker.logger.Error(err, "unable to get the latest sensor version", key.logValues()...)
+			trk.priorVersion = ""
+			continue
 		}
-		tracker.logDebug("latest available sensor version", "namespace", name.Namespace, "name", name.Name, "version", latestVersion)
+		tracker.logDebug("latest available sensor version", append(key.logValues(), "version", latestVersion)...)
 
 		if latestVersion != trk.priorVersion || trk.forceHandler {
 			if latestVersion != trk.priorVersion {
-				tracker.logDebug("sensor version changed, calling handler", "namespace", name.Namespace, "name", name.Name, "priorVersion", trk.priorVersion, "newVersion", latestVersion)
+				tracker.logDebug("sensor version changed, calling handler", append(key.logValues(), "priorVersion", trk.priorVersion, "newVersion", latestVersion)...)
 			} else {
-				tracker.logDebug("sensor version unchanged, but calling handler anyway", "namespace", name.Namespace, "name", name.Name, "latestAvailableVersion", latestVersion)
+				tracker.logDebug("sensor version unchanged, but calling handler anyway", append(key.logValues(), "latestAvailableVersion", latestVersion)...)
 			}
 
-			if err := trk.handler(tracker.ctx, name); err != nil {
-				return err
+			// The prior version is kept when the handler fails, so that it is called again on the next cycle
+			if err := trk.handler(tracker.ctx, key.Name, latestVersion); err != nil {
+				tracker.logger.Error(err, "sensor version handler failed", append(key.logValues(), "version", latestVersion)...)
+				continue
 			}
 		}
 
 		trk.priorVersion = latestVersion
 	}
-
-	return nil
 }
 
-func (tracker Tracker) updateTrack(update track) error {
-	trk, exists := tracker.activeTracks[update.name]
+func (tracker Tracker) updateTrack(update track) {
+	trk, exists := tracker.activeTracks[update.key]
 	if exists {
 		trk.forceHandler = update.forceHandler
 		trk.getSensorVersion = update.getSensorVersion
 		trk.handler = update.handler
-		tracker.logDebug("updated track", "namespace", update.name.Namespace, "name", update.name.Name, "forceHandler", update.forceHandler)
-		return nil
+		trk.sensorType = update.sensorType
+		tracker.logDebug("updated track", append(update.key.logValues(), "forceHandler", update.forceHandler)...)
+		return
 	}
 
-	initialVersion, err := update.getSensorVersion(tracker.ctx)
-	if err != nil {
-		return err
-	}
-
-	tracker.activeTracks[update.name] = &track{
+	trk = &track{
 		forceHandler:     update.forceHandler,
 		getSensorVersion: update.getSensorVersion,
 		handler:          update.handler,
-		name:             update.name,
-		priorVersion:     initialVersion,
+		key:              update.key,
+		sensorType:       update.sensorType,
+	}
+	tracker.activeTracks[update.key] = trk
+
+	// Without an initial version, the track is still added and the next polling cycle calls the handler with the version it finds
+	initialVersion, err := update.getSensorVersion(tracker.ctx)
+	if err != nil {
+		tracker.logger.Error(err, "unable to get the initial sensor version", update.key.logValues()...)
+		return
 	}
 
-	tracker.logDebug("added track", "namespace", update.name.Namespace, "name", update.name.Name, "initialVersion", initialVersion, "forceHandler", update.forceHandler)
-	return nil
+	trk.priorVersion = initialVersion
+	tracker.logDebug("added track", append(update.key.logValues(), "initialVersion", initialVersion, "forceHandler", update.forceHandler)...)
+
+	if update.notifyInitial {
+		if err := update.handler(tracker.ctx, update.key.Name, initialVersion); err != nil {
+			tracker.logger.Error(err, "sensor version handler failed", append(update.key.logValues(), "version", initialVersion)...)
+			trk.priorVersion = ""
+		}
+	}
 }
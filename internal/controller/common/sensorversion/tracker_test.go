@@ -7,25 +7,38 @@ import (
 	"testing"
 	"time"
 
+	"github.com/crowdstrike/gofalcon/falcon"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 )
 
 const noPollingInterval = 0
 
-func TestTracker_WhenGettingSensorVersionFails_TrackChangesFailsWithSameError(t *testing.T) {
+const testSensorType = falcon.NodeSensor
+
+var testGVK = schema.GroupVersionKind{Group: "falcon.crowdstrike.com", Version: "v1alpha1", Kind: "FalconNodeSensor"}
+
+func TestTracker_WhenGettingSensorVersionFails_KeepsPollingOtherTracks(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	handler := func(_ context.Context, _ types.NamespacedName) error {
-		require.Fail(t, "handler unexpectedly called")
+	failingName := types.NamespacedName{Name: "failing"}
+	workingName := types.NamespacedName{Name: "working"}
+
+	called := make(chan types.NamespacedName, 1)
+	handler := func(_ context.Context, name types.NamespacedName, _ string) error {
+		assert.Equal(t, workingName, name, "handler called for a track without a sensor version")
+		select {
+		case called <- name:
+		default:
+		}
 		return nil
 	}
 
-	expectedError := errors.New("some error")
 	alwaysFails := func(_ context.Context) (string, error) {
-		return "", expectedError
+		return "", errors.New("some error")
 	}
 
 	tracker := NewTracker(ctx, noPollingInterval)
@@ -34,86 +47,205 @@ func TestTracker_WhenGettingSensorVersionFails_TrackChangesFailsWithSameError(t
 	go func() {
 		defer close(done)
 
-		actualError := tracker.TrackChanges()
-		assert.Equal(t, expectedError, actualError, "wrong error returned from TrackChanges()")
+		err := tracker.TrackChanges()
+		assert.NoError(t, err, "TrackChanges() unexpectedly failed")
 	}()
 
-	name := types.NamespacedName{
-		Namespace: "someNamespace",
-		Name:      "someName",
-	}
-	tracker.Track(name, alwaysFails, handler, false)
+	tracker.Track(NewKey(testGVK, failingName), testSensorType, alwaysFails, handler, false)
+	tracker.Track(NewKey(testGVK, workingName), testSensorType, newIncrementingSensorVersionGenerator(t, ctx), handler, false)
 
 	select {
+	case <-called:
+
 	case <-done:
-		return
+		require.Fail(t, "TrackChanges() returned after a failing track")
 
 	case <-time.After(time.Second):
-		require.Fail(t, "TrackChanges() never returned")
+		require.Fail(t, "handler never called")
 	}
 }
 
-func TestTracker_WhenHandlerFails_TrackChangesFailsWithSameError(t *testing.T) {
-	expectedContext, cancel := context.WithCancel(context.Background())
+func TestTracker_WhenHandlerFails_CallsHandlerAgainOnNextCycle(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	getSensorVersion := newIncrementingSensorVersionGenerator(t, expectedContext)
-
-	expectedName := types.NamespacedName{
+	name := types.NamespacedName{
 		Namespace: "someNamespace",
 		Name:      "someName",
 	}
 
-	expectedError := errors.New("some error")
-	handler := func(actualContext context.Context, actualName types.NamespacedName) error {
-		assert.Same(t, expectedContext, actualContext, "wrong context passed to handler")
-		assert.Equal(t, expectedName, actualName, "wrong name passed to handler")
-		return expectedError
+	// The initial version is followed by a single new version, so the handler only sees it again if the failure is retried
+	queried := false
+	getSensorVersion := func(_ context.Context) (string, error) {
+		if !queried {
+			queried = true
+			return "v1.1.1", nil
+		}
+		return "v2.2.2", nil
 	}
 
-	tracker := NewTracker(expectedContext, noPollingInterval)
+	calls := 0
+	versions := make(chan string, 2)
+	handler := func(_ context.Context, _ types.NamespacedName, version string) error {
+		calls++
+		versions <- version
+		if calls == 1 {
+			return errors.New("some error")
+		}
+		return nil
+	}
 
-	done := make(chan any)
+	tracker := NewTracker(ctx, time.Hour)
 	go func() {
-		defer close(done)
-
-		actualError := tracker.TrackChanges()
-		assert.Equal(t, expectedError, actualError, "wrong error returned from TrackChanges()")
+		err := tracker.TrackChanges()
+		assert.NoError(t, err, "TrackChanges() unexpectedly failed")
 	}()
 
-	tracker.Track(expectedName, getSensorVersion, handler, false)
+	// Let the initial polling cycle run before anything is tracked
+	time.Sleep(100 * time.Millisecond)
 
-	select {
-	case <-done:
-		return
+	tracker.Track(NewKey(testGVK, name), testSensorType, getSensorVersion, handler, false)
+	require.True(t, tracker.Refresh())
+	require.True(t, tracker.Refresh())
 
-	case <-time.After(time.Second):
-		require.Fail(t, "TrackChanges() never returned")
+	for range 2 {
+		select {
+		case version := <-versions:
+			assert.Equal(t, "v2.2.2", version, "wrong version passed to handler")
+
+		case <-time.After(time.Second):
+			require.Fail(t, "handler not called again after failing")
+		}
 	}
 }
 
 func TestTracker_WhenSensorVersionChanges_CallsHandler(t *testing.T) {
 	runHandlerTest(t, func(ctx context.Context, tracker Tracker, name types.NamespacedName, handler Handler) {
 		getSensorVersion := newIncrementingSensorVersionGenerator(t, ctx)
-		tracker.Track(name, getSensorVersion, handler, false)
+		tracker.Track(NewKey(testGVK, name), testSensorType, getSensorVersion, handler, false)
 	})
 }
 
 func TestTracker_WhenSensorVersionDoesNotChangeButIsForced_CallsHandler(t *testing.T) {
 	runHandlerTest(t, func(ctx context.Context, tracker Tracker, name types.NamespacedName, handler Handler) {
 		getSensorVersion := newConstantSensorVersionGenerator(t, ctx)
-		tracker.Track(name, getSensorVersion, handler, true)
+		tracker.Track(NewKey(testGVK, name), testSensorType, getSensorVersion, handler, true)
 	})
 }
 
 func TestTracker_WhenTrackUpdatedWithForcedHandler_CallsHandler(t *testing.T) {
 	runHandlerTest(t, func(ctx context.Context, tracker Tracker, name types.NamespacedName, handler Handler) {
 		getSensorVersion := newConstantSensorVersionGenerator(t, ctx)
-		tracker.Track(name, getSensorVersion, handler, false)
-		tracker.Track(name, getSensorVersion, handler, true)
+		tracker.Track(NewKey(testGVK, name), testSensorType, getSensorVersion, handler, false)
+		tracker.Track(NewKey(testGVK, name), testSensorType, getSensorVersion, handler, true)
 	})
 }
 
+func TestTracker_WhenTrackedWithInitialVersion_CallsHandlerWithInitialVersion(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	versions := make(chan string, 1)
+	handler := func(_ context.Context, _ types.NamespacedName, version string) error {
+		select {
+		case versions <- version:
+		default:
+		}
+		return nil
+	}
+
+	tracker := NewTracker(ctx, time.Hour)
+	go func() {
+		err := tracker.TrackChanges()
+		assert.NoError(t, err, "TrackChanges() unexpectedly failed")
+	}()
+
+	name := types.NamespacedName{
+		Namespace: "someNamespace",
+		Name:      "someName",
+	}
+	tracker.TrackWithInitialVersion(NewKey(testGVK, name), testSensorType, newConstantSensorVersionGenerator(t, ctx), handler, false)
+
+	select {
+	case version := <-versions:
+		assert.Equal(t, "v1.1.1", version, "wrong version passed to handler")
+
+	case <-time.After(time.Second):
+		require.Fail(t, "handler never called")
+	}
+}
+
+func TestTracker_WhenSensorVersionChanges_PassesLatestVersionToHandler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	versions := make(chan string, 1)
+	handler := func(_ context.Context, _ types.NamespacedName, version string) error {
+		select {
+		case versions <- version:
+		default:
+		}
+		return nil
+	}
+
+	tracker := NewTracker(ctx, noPollingInterval)
+	go func() {
+		err := tracker.TrackChanges()
+		assert.NoError(t, err, "TrackChanges() unexpectedly failed")
+	}()
+
+	name := types.NamespacedName{
+		Namespace: "someNamespace",
+		Name:      "someName",
+	}
+	tracker.Track(NewKey(testGVK, name), testSensorType, newIncrementingSensorVersionGenerator(t, ctx), handler, false)
+
+	select {
+	case version := <-versions:
+		assert.NotEqual(t, "v1.1.1", version, "initial version passed to handler")
+
+	case <-time.After(time.Second):
+		require.Fail(t, "handler never called")
+	}
+}
+
+func TestTracker_WhenResourcesOfDifferentKindsShareName_KeepsSeparateTracks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tracker := NewTracker(ctx, time.Hour)
+	go func() {
+		err := tracker.TrackChanges()
+		assert.NoError(t, err, "TrackChanges() unexpectedly failed")
+	}()
+
+	// Let the initial polling cycle run before anything is tracked
+	time.Sleep(100 * time.Millisecond)
+
+	name := types.NamespacedName{Name: "falcon"}
+	otherKind := schema.GroupVersionKind{Group: testGVK.Group, Version: testGVK.Version, Kind: "FalconDeployment"}
+
+	called := make(chan types.NamespacedName, 1)
+	handler := func(_ context.Context, name types.NamespacedName, _ string) error {
+		called <- name
+		return nil
+	}
+
+	tracker.Track(NewKey(testGVK, name), testSensorType, newIncrementingSensorVersionGenerator(t, ctx), handler, false)
+
+	// Stopping the track of another kind must not delete the track registered above
+	tracker.StopTracking(NewKey(otherKind, name))
+	require.True(t, tracker.Refresh())
+
+	select {
+	case actualName := <-called:
+		assert.Equal(t, name, actualName, "wrong name passed to handler")
+
+	case <-time.After(time.Second):
+		require.Fail(t, "track deleted when stopping the track of another kind")
+	}
+}
+
 func newConstantSensorVersionGenerator(t *testing.T, expectedContext context.Context) SensorVersionQuery {
 	const fixedVersion = "v1.1.1"
 
@@ -145,7 +277,7 @@ func runHandlerTest(t *testing.T, runner func(ctx context.Context, tracker Track
 
 	done := make(chan any)
 	channelOpen := true
-	handler := func(actualContext context.Context, actualName types.NamespacedName) error {
+	handler := func(actualContext context.Context, actualName types.NamespacedName, _ string) error {
 		assert.Same(t, expectedContext, actualContext, "wrong context passed to handler")
 		assert.Equal(t, expectedName, actualName, "wrong name passed to handler")
 
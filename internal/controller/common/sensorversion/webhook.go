@@ -0,0 +1,189 @@
+package sensorversion
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crowdstrike/gofalcon/falcon"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// RefreshPath is the path of the endpoint that triggers an immediate sensor version refresh.
+	RefreshPath = "/sensor-versions/refresh"
+
+	// SignatureHeader carries the HMAC-SHA256 signature of the request, formatted as "sha256=<hex>".
+	SignatureHeader = "X-Falcon-Signature-256"
+
+	// TimestampHeader carries the Unix time in seconds at which the request was signed.
+	TimestampHeader = "X-Falcon-Timestamp"
+
+	// MaxSignatureAge is how far the signing time may differ from the current time before a request is rejected as a replay.
+	MaxSignatureAge = 5 * time.Minute
+
+	maxRefreshRequestSize = 64 * 1024
+	signaturePrefix       = "sha256="
+)
+
+// refreshableSensorTypes are the sensor types that the operator tracks.
+var refreshableSensorTypes = []falcon.SensorType{
+	falcon.NodeSensor,
+	falcon.SidecarSensor,
+	falcon.KacSensor,
+	falcon.ImageSensor,
+}
+
+// RefreshRequest is the body of a sensor version refresh notification.
+// An empty list of sensor types refreshes all tracked sensors.
+type RefreshRequest struct {
+	SensorTypes []falcon.SensorType `json:"sensorTypes,omitempty"`
+}
+
+// Sign returns the signature header value for a refresh request body signed at the given Unix time.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+type refreshHandler struct {
+	logger  logr.Logger
+	now     func() time.Time
+	refresh func(...falcon.SensorType) bool
+	secret  []byte
+}
+
+// NewRefreshHandler returns an HTTP handler that verifies signed refresh notifications and runs the tracker's polling for the requested sensor types.
+func NewRefreshHandler(ctx context.Context, tracker Tracker, secret []byte) http.Handler {
+	return newRefreshHandler(ctx, tracker.Refresh, secret)
+}
+
+func newRefreshHandler(ctx context.Context, refresh func(...falcon.SensorType) bool, secret []byte) refreshHandler {
+	return refreshHandler{
+		logger:  log.FromContext(ctx).WithName("sensor-version-refresh"),
+		now:     time.Now,
+		refresh: refresh,
+		secret:  secret,
+	}
+}
+
+func (handler refreshHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRefreshRequestSize))
+	if err != nil {
+		http.Error(w, "unable to read request body", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if err := handler.verify(r.Header, body); err != nil {
+		handler.logger.Info("rejected sensor version refresh request", "remoteAddr", r.RemoteAddr, "reason", err.Error())
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	request := RefreshRequest{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &request); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	for _, sensorType := range request.SensorTypes {
+		if !slices.Contains(refreshableSensorTypes, sensorType) {
+			http.Error(w, fmt.Sprintf("unsupported sensor type %q", sensorType), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if !handler.refresh(request.SensorTypes...) {
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, "too many pending refresh requests", http.StatusTooManyRequests)
+		return
+	}
+
+	handler.logger.Info("sensor version refresh requested", "sensorTypes", request.SensorTypes)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (handler refreshHandler) verify(header http.Header, body []byte) error {
+	timestamp := header.Get(TimestampHeader)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("missing or invalid %s header", TimestampHeader)
+	}
+
+	age := handler.now().Sub(time.Unix(seconds, 0))
+	if age > MaxSignatureAge || age < -MaxSignatureAge {
+		return fmt.Errorf("request signed %s ago is outside the allowed window of %s", age.Round(time.Second), MaxSignatureAge)
+	}
+
+	signature := header.Get(SignatureHeader)
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return fmt.Errorf("missing or invalid %s header", SignatureHeader)
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(Sign(handler.secret, timestamp, body))) {
+		return errors.New("signature mismatch")
+	}
+
+	return nil
+}
+
+// RefreshServer serves the sensor version refresh endpoint until the manager stops.
+type RefreshServer struct {
+	Addr    string
+	Handler http.Handler
+}
+
+// Start implements manager.Runnable.
+func (server RefreshServer) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(RefreshPath, server.Handler)
+
+	srv := &http.Server{
+		Addr:              server.Addr,
+		Handler:           mux,
+		ReadHeaderTimeout: time.Second * 10,
+		ReadTimeout:       time.Second * 30,
+		WriteTimeout:      time.Second * 10,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+// Only the leader reconciles, so only the leader has tracks to refresh.
+func (server RefreshServer) NeedLeaderElection() bool {
+	return true
+}
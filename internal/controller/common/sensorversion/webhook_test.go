@@ -0,0 +1,205 @@
+package sensorversion
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/crowdstrike/gofalcon/falcon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var testSecret = []byte("some secret")
+
+func TestRefreshHandler_WithValidSignature_RefreshesRequestedSensorTypes(t *testing.T) {
+	var refreshed []falcon.SensorType
+	server := newTestRefreshServer(t, func(sensorTypes ...falcon.SensorType) bool {
+		refreshed = sensorTypes
+		return true
+	})
+
+	response := postRefresh(t, server, testSecret, time.Now(), []byte(`{"sensorTypes":["falcon-sensor","falcon-kac"]}`))
+	assert.Equal(t, http.StatusAccepted, response.StatusCode)
+	assert.Equal(t, []falcon.SensorType{falcon.NodeSensor, falcon.KacSensor}, refreshed)
+}
+
+func TestRefreshHandler_WithEmptyBody_RefreshesAllSensorTypes(t *testing.T) {
+	called := false
+	var refreshed []falcon.SensorType
+	server := newTestRefreshServer(t, func(sensorTypes ...falcon.SensorType) bool {
+		called = true
+		refreshed = sensorTypes
+		return true
+	})
+
+	response := postRefresh(t, server, testSecret, time.Now(), nil)
+	assert.Equal(t, http.StatusAccepted, response.StatusCode)
+	assert.True(t, called, "refresh not requested")
+	assert.Empty(t, refreshed)
+}
+
+func TestRefreshHandler_RejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name       string
+		secret     []byte
+		signedAt   time.Time
+		body       []byte
+		wantStatus int
+	}{
+		{
+			name:       "wrong secret",
+			secret:     []byte("other secret"),
+			signedAt:   time.Now(),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "expired signature",
+			secret:     testSecret,
+			signedAt:   time.Now().Add(-MaxSignatureAge - time.Minute),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "signature from the future",
+			secret:     testSecret,
+			signedAt:   time.Now().Add(MaxSignatureAge + time.Minute),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "unsupported sensor type",
+			secret:     testSecret,
+			signedAt:   time.Now(),
+			body:       []byte(`{"sensorTypes":["falcon-snapshot"]}`),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "malformed body",
+			secret:     testSecret,
+			signedAt:   time.Now(),
+			body:       []byte(`{"sensorTypes":`),
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestRefreshServer(t, func(_ ...falcon.SensorType) bool {
+				require.Fail(t, "refresh unexpectedly requested")
+				return true
+			})
+
+			response := postRefresh(t, server, tt.secret, tt.signedAt, tt.body)
+			assert.Equal(t, tt.wantStatus, response.StatusCode)
+		})
+	}
+}
+
+func TestRefreshHandler_WithoutSignature_IsUnauthorized(t *testing.T) {
+	server := newTestRefreshServer(t, func(_ ...falcon.SensorType) bool {
+		require.Fail(t, "refresh unexpectedly requested")
+		return true
+	})
+
+	response, err := server.Client().Post(server.URL+RefreshPath, "application/json", nil)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestRefreshHandler_WithGetRequest_IsNotAllowed(t *testing.T) {
+	server := newTestRefreshServer(t, func(_ ...falcon.SensorType) bool {
+		require.Fail(t, "refresh unexpectedly requested")
+		return true
+	})
+
+	response, err := server.Client().Get(server.URL + RefreshPath)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	assert.Equal(t, http.StatusMethodNotAllowed, response.StatusCode)
+}
+
+func TestRefreshHandler_WhenTooManyRefreshesPending_AsksToRetry(t *testing.T) {
+	server := newTestRefreshServer(t, func(_ ...falcon.SensorType) bool {
+		return false
+	})
+
+	response := postRefresh(t, server, testSecret, time.Now(), nil)
+	assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+	assert.NotEmpty(t, response.Header.Get("Retry-After"))
+}
+
+func TestTracker_WhenRefreshed_PollsOnlyMatchingTracks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tracker := NewTracker(ctx, time.Hour)
+	go func() {
+		err := tracker.TrackChanges()
+		assert.NoError(t, err, "TrackChanges() unexpectedly failed")
+	}()
+
+	// Let the initial polling cycle run before anything is tracked
+	time.Sleep(100 * time.Millisecond)
+
+	nodeName := types.NamespacedName{Name: "node"}
+	kacName := types.NamespacedName{Name: "kac"}
+
+	called := make(chan types.NamespacedName, 2)
+	handler := func(_ context.Context, name types.NamespacedName, _ string) error {
+		called <- name
+		return nil
+	}
+
+	tracker.Track(NewKey(testGVK, nodeName), falcon.NodeSensor, newIncrementingSensorVersionGenerator(t, ctx), handler, false)
+	tracker.Track(NewKey(testGVK, kacName), falcon.KacSensor, newIncrementingSensorVersionGenerator(t, ctx), handler, false)
+
+	server := httptest.NewServer(NewRefreshHandler(ctx, tracker, testSecret))
+	defer server.Close()
+
+	response := postRefresh(t, server, testSecret, time.Now(), []byte(`{"sensorTypes":["falcon-kac"]}`))
+	require.Equal(t, http.StatusAccepted, response.StatusCode)
+
+	select {
+	case name := <-called:
+		assert.Equal(t, kacName, name, "handler called for a track of another sensor type")
+
+	case <-time.After(time.Second):
+		require.Fail(t, "handler never called")
+	}
+
+	select {
+	case name := <-called:
+		require.Fail(t, "handler unexpectedly called", "name", name)
+
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func newTestRefreshServer(t *testing.T, refresh func(...falcon.SensorType) bool) *httptest.Server {
+	server := httptest.NewServer(newRefreshHandler(context.Background(), refresh, testSecret))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func postRefresh(t *testing.T, server *httptest.Server, secret []byte, signedAt time.Time, body []byte) *http.Response {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+
+	request, err := http.NewRequest(http.MethodPost, server.URL+RefreshPath, bytes.NewReader(body))
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(TimestampHeader, timestamp)
+	request.Header.Set(SignatureHeader, Sign(secret, timestamp, body))
+
+	response, err := server.Client().Do(request)
+	require.NoError(t, err)
+	t.Cleanup(func() { response.Body.Close() })
+
+	return response
+}
@@ -171,28 +171,46 @@ func Delete(r client.Client, ctx context.Context, req ctrl.Request, log logr.Log
 
 // ConditionsUpdate updates the Falcon Object CR conditions
 func ConditionsUpdate(r client.Client, ctx context.Context, req ctrl.Request, log logr.Logger, falconObject client.Object, falconStatus *falconv1alpha1.FalconCRStatus, falconCondition metav1.Condition) error {
-	if !meta.IsStatusConditionPresentAndEqual(falconStatus.Conditions, falconCondition.Type, falconCondition.Status) {
-		fgvk := falconObject.GetObjectKind().GroupVersionKind()
+	if meta.IsStatusConditionPresentAndEqual(falconStatus.Conditions, falconCondition.Type, falconCondition.Status) {
+		return nil
+	}
 
-		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-			// Re-fetch the Custom Resource before update the status
-			// so that we have the latest state of the resource on the cluster and we will avoid
-			// raise the issue "the object has been modified, please apply
-			// your changes to the latest version and try again" which would re-trigger the reconciliation
-			err := r.Get(ctx, req.NamespacedName, falconObject)
-			if err != nil {
-				log.Error(err, fmt.Sprintf("Failed to re-fetch %s for status update", fgvk.Kind))
-				return err
-			}
+	return conditionsUpdate(r, ctx, req, log, falconObject, falconStatus, falconCondition)
+}
 
-			// The following implementation will update the status
-			meta.SetStatusCondition(&falconStatus.Conditions, falconCondition)
-			return r.Status().Update(ctx, falconObject)
-		})
+// ConditionsUpdateWithReason is like ConditionsUpdate, but also updates the condition when only its reason or observed generation changes.
+// It is used for conditions whose reasons tell apart different causes of the same status.
+func ConditionsUpdateWithReason(r client.Client, ctx context.Context, req ctrl.Request, log logr.Logger, falconObject client.Object, falconStatus *falconv1alpha1.FalconCRStatus, falconCondition metav1.Condition) error {
+	if existing := meta.FindStatusCondition(falconStatus.Conditions, falconCondition.Type); existing != nil &&
+		existing.Status == falconCondition.Status && existing.Reason == falconCondition.Reason &&
+		existing.ObservedGeneration == falconCondition.ObservedGeneration {
+		return nil
+	}
+
+	return conditionsUpdate(r, ctx, req, log, falconObject, falconStatus, falconCondition)
+}
+
+func conditionsUpdate(r client.Client, ctx context.Context, req ctrl.Request, log logr.Logger, falconObject client.Object, falconStatus *falconv1alpha1.FalconCRStatus, falconCondition metav1.Condition) error {
+	fgvk := falconObject.GetObjectKind().GroupVersionKind()
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// Re-fetch the Custom Resource before update the status
+		// so that we have the latest state of the resource on the cluster and we will avoid
+		// raise the issue "the object has been modified, please apply
+		// your changes to the latest version and try again" which would re-trigger the reconciliation
+		err := r.Get(ctx, req.NamespacedName, falconObject)
 		if err != nil {
-			log.Error(err, fmt.Sprintf("Failed to update %s status", fgvk.Kind))
+			log.Error(err, fmt.Sprintf("Failed to re-fetch %s for status update", fgvk.Kind))
 			return err
 		}
+
+		// The following implementation will update the status
+		meta.SetStatusCondition(&falconStatus.Conditions, falconCondition)
+		return r.Status().Update(ctx, falconObject)
+	})
+	if err != nil {
+		log.Error(err, fmt.Sprintf("Failed to update %s status", fgvk.Kind))
+		return err
 	}
 
 	return nil
@@ -4,9 +4,12 @@ import (
 	"context"
 	"testing"
 
+	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	"github.com/google/go-cmp/cmp"
+	arv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,6 +24,15 @@ func getFakeClient(initObjs ...client.Object) (client.WithWatch, error) {
 	if err := appsv1.AddToScheme(scheme); err != nil {
 		return nil, err
 	}
+	if err := arv1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := certv1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := policyv1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
 	// ...
 	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build(), nil
 }
@@ -0,0 +1,283 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	watchdogDefaultPeriod           = 10 * time.Second
+	watchdogDefaultFailureThreshold = 3
+	watchdogDefaultSuccessThreshold = 2
+	watchdogProbeTimeout            = 5 * time.Second
+	watchdogTick                    = time.Second
+)
+
+// WebhookProbe describes the health endpoint of a webhook service, and when the webhook fails open
+type WebhookProbe struct {
+	// URL of the health endpoint, served with the certificate of the webhook
+	URL string
+	// CABundle verifies the certificate of the webhook. The system roots are used when it is empty.
+	CABundle         []byte
+	Period           time.Duration
+	FailureThreshold int
+	SuccessThreshold int
+}
+
+// NewWebhookProbe returns the probe of the health endpoint at url, with the settings of the watchdog spec
+func NewWebhookProbe(url string, caBundle []byte, watchdog *falconv1alpha1.FalconWebhookWatchdog) WebhookProbe {
+	probe := WebhookProbe{
+		URL:              url,
+		CABundle:         caBundle,
+		Period:           watchdogDefaultPeriod,
+		FailureThreshold: watchdogDefaultFailureThreshold,
+		SuccessThreshold: watchdogDefaultSuccessThreshold,
+	}
+
+	if watchdog.PeriodSeconds != nil {
+		probe.Period = time.Duration(*watchdog.PeriodSeconds) * time.Second
+	}
+	if watchdog.FailureThreshold != nil {
+		probe.FailureThreshold = int(*watchdog.FailureThreshold)
+	}
+	if watchdog.SuccessThreshold != nil {
+		probe.SuccessThreshold = int(*watchdog.SuccessThreshold)
+	}
+
+	return probe
+}
+
+// WebhookHealth is the result of the probes of a webhook
+type WebhookHealth struct {
+	// Probed is false until the first probe has completed
+	Probed bool
+	// FailingOpen reports that the webhook failed more than its failure threshold, and has not recovered since
+	FailingOpen bool
+	// LastError is the error of the last failed probe
+	LastError error
+}
+
+// Condition returns the WebhookHealthy condition reporting the health of the webhook
+func (h WebhookHealth) Condition(generation int64) metav1.Condition {
+	if h.FailingOpen {
+		return metav1.Condition{
+			Type:               falconv1alpha1.ConditionWebhookHealthy,
+			Status:             metav1.ConditionFalse,
+			Reason:             falconv1alpha1.ReasonWebhookFailedOpen,
+			Message:            fmt.Sprintf("The webhook failure policy is set to Ignore until its health checks pass: %v", h.LastError),
+			ObservedGeneration: generation,
+		}
+	}
+
+	return metav1.Condition{
+		Type:               falconv1alpha1.ConditionWebhookHealthy,
+		Status:             metav1.ConditionTrue,
+		Reason:             falconv1alpha1.ReasonWebhookHealthy,
+		Message:            "The webhook passes its health checks",
+		ObservedGeneration: generation,
+	}
+}
+
+// WebhookWatchdog periodically probes webhook services, and calls onChange with the name of the Falcon resource
+// whenever its webhook starts or stops failing open. It runs as a manager runnable, on the leader only.
+type WebhookWatchdog struct {
+	log      logr.Logger
+	onChange func(types.NamespacedName)
+	check    func(context.Context, *http.Client, string) error
+
+	mu      sync.Mutex
+	targets map[types.NamespacedName]*watchdogTarget
+}
+
+type watchdogTarget struct {
+	probe     WebhookProbe
+	client    *http.Client
+	nextProbe time.Time
+	failures  int
+	successes int
+	health    WebhookHealth
+}
+
+// NewWebhookWatchdog returns a watchdog that calls onChange when the health of a webhook changes
+func NewWebhookWatchdog(log logr.Logger, onChange func(types.NamespacedName)) *WebhookWatchdog {
+	return &WebhookWatchdog{
+		log:      log,
+		onChange: onChange,
+		check:    checkHealth,
+		targets:  map[types.NamespacedName]*watchdogTarget{},
+	}
+}
+
+// Watch starts probing the webhook of the Falcon resource, or updates its probe. The probe results are kept when only the thresholds change.
+func (w *WebhookWatchdog) Watch(name types.NamespacedName, probe WebhookProbe) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	target, ok := w.targets[name]
+	if ok && target.probe.URL == probe.URL && bytes.Equal(target.probe.CABundle, probe.CABundle) {
+		target.probe = probe
+		return nil
+	}
+
+	client, err := probeClient(probe.CABundle)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		target = &watchdogTarget{}
+		w.targets[name] = target
+	}
+	target.probe = probe
+	target.client = client
+	target.nextProbe = time.Time{}
+
+	return nil
+}
+
+// Unwatch stops probing the webhook of the Falcon resource. It does nothing on a nil watchdog.
+func (w *WebhookWatchdog) Unwatch(name types.NamespacedName) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.targets, name)
+}
+
+// Health returns the result of the probes of the webhook of the Falcon resource
+func (w *WebhookWatchdog) Health(name types.NamespacedName) WebhookHealth {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if target, ok := w.targets[name]; ok {
+		return target.health
+	}
+
+	return WebhookHealth{}
+}
+
+// Start probes the webhooks until the context is done
+func (w *WebhookWatchdog) Start(ctx context.Context) error {
+	ticker := time.NewTicker(watchdogTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			w.probeDue(ctx, now)
+		}
+	}
+}
+
+// NeedLeaderElection makes the watchdog run on the leader only, as it has the webhooks reconciled
+func (w *WebhookWatchdog) NeedLeaderElection() bool {
+	return true
+}
+
+// probeDue probes the webhooks whose period has elapsed. The lock is not held while probing, so a probe
+// result is dropped when its target has been updated meanwhile.
+func (w *WebhookWatchdog) probeDue(ctx context.Context, now time.Time) {
+	w.mu.Lock()
+	due := map[types.NamespacedName]*watchdogTarget{}
+	for name, target := range w.targets {
+		if !now.Before(target.nextProbe) {
+			target.nextProbe = now.Add(target.probe.Period)
+			due[name] = target
+		}
+	}
+	w.mu.Unlock()
+
+	for name, target := range due {
+		err := w.check(ctx, target.client, target.probe.URL)
+
+		w.mu.Lock()
+		changed := false
+		if w.targets[name] == target {
+			changed = target.record(err)
+		}
+		failingOpen := target.health.FailingOpen
+		w.mu.Unlock()
+
+		if changed {
+			w.log.Info("Webhook health changed", "name", name.String(), "url", target.probe.URL, "failingOpen", failingOpen, "probeError", err)
+			w.onChange(name)
+		}
+	}
+}
+
+// record counts the result of a probe, and reports whether the webhook started or stopped failing open
+func (t *watchdogTarget) record(err error) bool {
+	firstProbe := !t.health.Probed
+	t.health.Probed = true
+
+	if err != nil {
+		t.health.LastError = err
+		t.failures++
+		t.successes = 0
+		if !t.health.FailingOpen && t.failures >= t.probe.FailureThreshold {
+			t.health.FailingOpen = true
+			return true
+		}
+		return firstProbe
+	}
+
+	t.successes++
+	t.failures = 0
+	if t.health.FailingOpen && t.successes >= t.probe.SuccessThreshold {
+		t.health.FailingOpen = false
+		t.health.LastError = nil
+		return true
+	}
+	return firstProbe
+}
+
+func probeClient(caBundle []byte) (*http.Client, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(caBundle) > 0 {
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("no certificate found in the webhook CA bundle")
+		}
+		tlsConfig.RootCAs = roots
+	}
+
+	return &http.Client{
+		Timeout:   watchdogProbeTimeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}, nil
+}
+
+func checkHealth(ctx context.Context, client *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("health check returned HTTP %d", resp.StatusCode)
+	}
+
+	return nil
+}
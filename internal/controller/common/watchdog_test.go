@@ -0,0 +1,109 @@
+package common
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestWebhookWatchdog(t *testing.T) {
+	var healthy atomic.Bool
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/live" || !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	name := types.NamespacedName{Name: "falcon-sidecar-sensor"}
+	changes := 0
+	watchdog := NewWebhookWatchdog(logr.Discard(), func(changed types.NamespacedName) {
+		if changed != name {
+			t.Errorf("onChange() name = %s, want %s", changed, name)
+		}
+		changes++
+	})
+
+	if watchdog.Health(name).Probed {
+		t.Fatal("Health() should not report unwatched webhooks as probed")
+	}
+
+	err := watchdog.Watch(name, WebhookProbe{URL: server.URL + "/live", CABundle: caBundle, Period: time.Second, FailureThreshold: 2, SuccessThreshold: 2})
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+	probe := func(wantFailingOpen bool, wantChanges int) {
+		t.Helper()
+		now = now.Add(time.Second)
+		watchdog.probeDue(ctx, now)
+
+		health := watchdog.Health(name)
+		if !health.Probed {
+			t.Fatal("Health() should report the webhook as probed")
+		}
+		if health.FailingOpen != wantFailingOpen {
+			t.Errorf("Health() FailingOpen = %v, want %v", health.FailingOpen, wantFailingOpen)
+		}
+		if changes != wantChanges {
+			t.Errorf("onChange() called %d times, want %d", changes, wantChanges)
+		}
+	}
+
+	healthy.Store(true)
+	probe(false, 1)
+
+	healthy.Store(false)
+	probe(false, 1)
+	probe(true, 2)
+	probe(true, 2)
+	if watchdog.Health(name).LastError == nil {
+		t.Error("Health() should report the error of the failed probe")
+	}
+
+	healthy.Store(true)
+	probe(true, 2)
+	probe(false, 3)
+
+	// The probe is not due before its period has elapsed
+	healthy.Store(false)
+	watchdog.probeDue(ctx, now.Add(time.Millisecond))
+	probe(false, 3)
+
+	watchdog.Unwatch(name)
+	if watchdog.Health(name).Probed {
+		t.Error("Health() should forget unwatched webhooks")
+	}
+}
+
+func TestWebhookWatchdogCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	name := types.NamespacedName{Name: "falcon-kac"}
+	watchdog := NewWebhookWatchdog(logr.Discard(), func(types.NamespacedName) {})
+
+	// The server certificate is not signed by the system roots
+	if err := watchdog.Watch(name, WebhookProbe{URL: server.URL, Period: time.Second, FailureThreshold: 1, SuccessThreshold: 1}); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	watchdog.probeDue(context.Background(), time.Now())
+
+	if !watchdog.Health(name).FailingOpen {
+		t.Error("Health() should fail open when the webhook certificate cannot be verified")
+	}
+
+	if err := watchdog.Watch(name, WebhookProbe{URL: server.URL, CABundle: []byte("not a certificate")}); err == nil {
+		t.Error("Watch() should reject an invalid CA bundle")
+	}
+}
@@ -0,0 +1,118 @@
+package common
+
+import (
+	"context"
+
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/go-logr/logr"
+	arv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// RemoveWebhook deletes the given webhook configuration, and reports whether it is gone.
+// The configuration is read from the API server, since it is not necessarily cached.
+func RemoveWebhook(ctx context.Context, c client.Client, reader client.Reader, webhook client.Object) (bool, error) {
+	err := reader.Get(ctx, types.NamespacedName{Name: webhook.GetName()}, webhook)
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if webhook.GetDeletionTimestamp() == nil {
+		if err := c.Delete(ctx, webhook); err != nil && !apierrors.IsNotFound(err) {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+// CleanupOrphanedWebhooks deletes the webhook configurations created by the operator whose services no longer exist.
+// Such webhooks are left behind when the operator is removed before the Falcon resources, and may block the
+// creation of pods in the whole cluster.
+func CleanupOrphanedWebhooks(ctx context.Context, c client.Client, reader client.Reader, log logr.Logger) error {
+	operatorLabels := client.MatchingLabels{
+		common.FalconManagedByKey: common.FalconManagedByValue,
+		common.FalconProviderKey:  common.FalconProviderValue,
+	}
+
+	validatingWebhooks := &arv1.ValidatingWebhookConfigurationList{}
+	if err := reader.List(ctx, validatingWebhooks, operatorLabels); err != nil {
+		return err
+	}
+
+	for i := range validatingWebhooks.Items {
+		webhook := &validatingWebhooks.Items[i]
+		services := []*arv1.ServiceReference{}
+		for _, w := range webhook.Webhooks {
+			services = append(services, w.ClientConfig.Service)
+		}
+
+		if err := deleteOrphanedWebhook(ctx, c, reader, log, webhook, services); err != nil {
+			return err
+		}
+	}
+
+	mutatingWebhooks := &arv1.MutatingWebhookConfigurationList{}
+	if err := reader.List(ctx, mutatingWebhooks, operatorLabels); err != nil {
+		return err
+	}
+
+	for i := range mutatingWebhooks.Items {
+		webhook := &mutatingWebhooks.Items[i]
+		services := []*arv1.ServiceReference{}
+		for _, w := range webhook.Webhooks {
+			services = append(services, w.ClientConfig.Service)
+		}
+
+		if err := deleteOrphanedWebhook(ctx, c, reader, log, webhook, services); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// OrphanedWebhookCleanup returns a manager runnable that runs CleanupOrphanedWebhooks once the operator is the leader
+func OrphanedWebhookCleanup(c client.Client, reader client.Reader, log logr.Logger) manager.Runnable {
+	return manager.RunnableFunc(func(ctx context.Context) error {
+		if err := CleanupOrphanedWebhooks(ctx, c, reader, log); err != nil {
+			// The reconcilers still delete the webhooks of removed Falcon resources, so this is not fatal
+			log.Error(err, "Failed to clean up orphaned Falcon webhooks")
+		}
+
+		return nil
+	})
+}
+
+// deleteOrphanedWebhook deletes the webhook configuration when one of the services it calls does not exist
+func deleteOrphanedWebhook(ctx context.Context, c client.Client, reader client.Reader, log logr.Logger, webhook client.Object, services []*arv1.ServiceReference) error {
+	for _, service := range services {
+		if service == nil {
+			continue
+		}
+
+		err := reader.Get(ctx, types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, &corev1.Service{})
+		if err == nil {
+			continue
+		}
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		log.Info("Deleting orphaned Falcon webhook configuration", "name", webhook.GetName(), "service", service.Namespace+"/"+service.Name)
+		if err := c.Delete(ctx, webhook); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/go-logr/logr"
+	arv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func testMutatingWebhook(name string, labels map[string]string, service string) *arv1.MutatingWebhookConfiguration {
+	return &arv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Webhooks: []arv1.MutatingWebhook{
+			{
+				Name:         name,
+				ClientConfig: arv1.WebhookClientConfig{Service: &arv1.ServiceReference{Name: service, Namespace: "falcon-system"}},
+			},
+		},
+	}
+}
+
+func TestRemoveWebhook(t *testing.T) {
+	ctx := context.Background()
+	webhook := testMutatingWebhook("injector.falcon-sidecar", nil, "injector")
+
+	fakeClient, err := getFakeClient(webhook.DeepCopy())
+	if err != nil {
+		t.Fatalf("TestRemoveWebhook getFakeClient() error = %v", err)
+	}
+
+	gone, err := RemoveWebhook(ctx, fakeClient, fakeClient, &arv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: webhook.Name}})
+	if err != nil {
+		t.Fatalf("RemoveWebhook() error = %v", err)
+	}
+	if gone {
+		t.Error("RemoveWebhook() should wait for the deletion to be observed")
+	}
+
+	gone, err = RemoveWebhook(ctx, fakeClient, fakeClient, &arv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: webhook.Name}})
+	if err != nil {
+		t.Fatalf("RemoveWebhook() error = %v", err)
+	}
+	if !gone {
+		t.Error("RemoveWebhook() should report a deleted webhook as gone")
+	}
+}
+
+func TestCleanupOrphanedWebhooks(t *testing.T) {
+	ctx := context.Background()
+	labels := common.CRLabels("mutatingwebhook", "injector", common.FalconSidecarSensor)
+
+	fakeClient, err := getFakeClient(
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "injector", Namespace: "falcon-system"}},
+		testMutatingWebhook("served", labels, "injector"),
+		testMutatingWebhook("orphaned", labels, "missing"),
+		testMutatingWebhook("unmanaged", nil, "missing"),
+		&arv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "orphaned-validating", Labels: labels},
+			Webhooks: []arv1.ValidatingWebhook{
+				{
+					Name:         "orphaned-validating",
+					ClientConfig: arv1.WebhookClientConfig{Service: &arv1.ServiceReference{Name: "missing", Namespace: "falcon-kac"}},
+				},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("TestCleanupOrphanedWebhooks getFakeClient() error = %v", err)
+	}
+
+	if err := CleanupOrphanedWebhooks(ctx, fakeClient, fakeClient, logr.Discard()); err != nil {
+		t.Fatalf("CleanupOrphanedWebhooks() error = %v", err)
+	}
+
+	for name, wantDeleted := range map[string]bool{"served": false, "orphaned": true, "unmanaged": false} {
+		err := fakeClient.Get(ctx, types.NamespacedName{Name: name}, &arv1.MutatingWebhookConfiguration{})
+		if deleted := apierrors.IsNotFound(err); deleted != wantDeleted {
+			t.Errorf("CleanupOrphanedWebhooks() deleted %s = %v, want %v", name, deleted, wantDeleted)
+		}
+	}
+
+	err = fakeClient.Get(ctx, types.NamespacedName{Name: "orphaned-validating"}, &arv1.ValidatingWebhookConfiguration{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("CleanupOrphanedWebhooks() should delete the orphaned validating webhook, got %v", err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -54,6 +55,11 @@ var _ = Describe("FalconContainer AITap reconciliation", func() {
 			// Delete FalconContainer custom resource
 			falconContainerCR := &falconv1alpha1.FalconContainer{}
 			if err := k8sClient.Get(ctx, containerNamespacedName, falconContainerCR); err == nil {
+				// Remove finalizer for successful FalconContainer CR deletion
+				patch := client.MergeFrom(falconContainerCR.DeepCopy())
+				falconContainerCR.SetFinalizers(nil)
+				_ = k8sClient.Patch(ctx, falconContainerCR, patch)
+
 				Expect(k8sClient.Delete(ctx, falconContainerCR)).To(Succeed())
 
 				Eventually(func() bool {
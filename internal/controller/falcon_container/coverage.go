@@ -0,0 +1,457 @@
+package falcon
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/go-logr/logr"
+	arv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// injectedContainerName is the name of the sensor container that the injector adds to pods
+	injectedContainerName = "crowdstrike-falcon-container"
+
+	// restartedForWebhookAnnotation records the UID of the injector webhook a workload was restarted for, so that it is restarted once
+	restartedForWebhookAnnotation = "sensor.falcon-system.crowdstrike.com/restarted-for-webhook"
+
+	// restartedAtAnnotation is the pod template annotation set by kubectl rollout restart
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+	// mirrorPodAnnotation marks the API server copies of static pods, which the webhook does not mutate
+	mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+	// coverageCheckInterval is how often the pods are checked for the sensor once the injector is installed
+	coverageCheckInterval = 5 * time.Minute
+
+	// maxReportedNamespaces limits the number of namespaces listed in the coverage of the FalconContainer status
+	maxReportedNamespaces = 200
+
+	// maxRestartsPerCheck limits the workloads restarted by a coverage check, so that enabling restartUninjected on a large
+	// cluster spreads the restarts over several checks instead of restarting every workload at once
+	maxRestartsPerCheck = 5
+)
+
+// injectionExcludedNamespaces are never injected, whatever their labels
+var injectionExcludedNamespaces = []string{"kube-system", "kube-public"}
+
+// coverageReporter periodically checks the pods targeted by the injectors of the FalconContainers that enable coverage.
+// It runs apart from the reconcile loop, as a manager runnable on the leader only, so that listing the pods of large
+// clusters neither delays nor retriggers the installation of the injector.
+type coverageReporter struct {
+	reconciler *FalconContainerReconciler
+	interval   time.Duration
+}
+
+// Start checks the coverage every interval until the context is done
+func (c *coverageReporter) Start(ctx context.Context) error {
+	log := clog.FromContext(ctx).WithName("falconcontainer-coverage")
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.reconciler.reportCoverage(ctx, log)
+		}
+	}
+}
+
+// reportCoverage checks the coverage of every FalconContainer, and clears the coverage of the ones that no longer enable it
+func (r *FalconContainerReconciler) reportCoverage(ctx context.Context, log logr.Logger) {
+	falconContainers := &falconv1alpha1.FalconContainerList{}
+	if err := r.List(ctx, falconContainers); err != nil {
+		log.Error(err, "Unable to list FalconContainers")
+		return
+	}
+
+	for i := range falconContainers.Items {
+		falconContainer := &falconContainers.Items[i]
+		log := log.WithValues("FalconContainer", falconContainer.Name)
+
+		if !falconContainer.Spec.Injector.CoverageEnabled() {
+			if err := r.clearCoverage(ctx, falconContainer); err != nil {
+				log.Error(err, "Failed to clear the coverage of the Falcon Container sensor")
+			}
+			continue
+		}
+
+		// Coverage is reported on a best effort basis, it does not affect the installation
+		if err := r.reconcileCoverage(ctx, log, falconContainer); err != nil {
+			log.Error(err, "Failed to report the pods running without the Falcon Container sensor")
+		}
+	}
+}
+
+// clearCoverage removes the coverage metrics and status of a FalconContainer that no longer enables coverage
+func (r *FalconContainerReconciler) clearCoverage(ctx context.Context, falconContainer *falconv1alpha1.FalconContainer) error {
+	deleteCoverageMetrics(falconContainer.Name)
+	if falconContainer.Status.Coverage == nil {
+		return nil
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := r.Get(ctx, types.NamespacedName{Name: falconContainer.Name}, falconContainer); err != nil {
+			return err
+		}
+
+		falconContainer.Status.Coverage = nil
+		return r.Status().Update(ctx, falconContainer)
+	})
+}
+
+// reconcileCoverage reports the pods targeted by the injector that run without the sensor in the FalconContainer status and
+// metrics, and restarts their workloads when injector.restartUninjected is set
+func (r *FalconContainerReconciler) reconcileCoverage(ctx context.Context, log logr.Logger, falconContainer *falconv1alpha1.FalconContainer) error {
+	webhook := &arv1.MutatingWebhookConfiguration{}
+	if err := r.Reader.Get(ctx, types.NamespacedName{Name: webhookName}, webhook); err != nil {
+		// The injector is not installed yet
+		return client.IgnoreNotFound(err)
+	}
+
+	namespaces := &metav1.PartialObjectMetadataList{}
+	namespaces.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("NamespaceList"))
+	if err := r.coverageReader.List(ctx, namespaces); err != nil {
+		return fmt.Errorf("unable to list namespaces: %v", err)
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.coverageReader.List(ctx, pods); err != nil {
+		return fmt.Errorf("unable to list pods: %v", err)
+	}
+
+	coverage, uninjected := podsCoverage(falconContainer, namespaces.Items, pods.Items, webhook.CreationTimestamp)
+
+	deleteCoverageMetrics(falconContainer.Name)
+	for _, namespace := range coverage {
+		targetedPods.WithLabelValues(falconContainer.Name, namespace.Namespace).Set(float64(namespace.Pods))
+		uninjectedPods.WithLabelValues(falconContainer.Name, namespace.Namespace).Set(float64(namespace.Uninjected))
+	}
+
+	status := coverageStatus(coverage)
+	if !equality.Semantic.DeepEqual(falconContainer.Status.Coverage, status) {
+		if status.Uninjected > 0 {
+			log.Info("Pods targeted by the injector run without the Falcon Container sensor", "pods", status.Uninjected, "namespaces", len(status.Namespaces))
+		}
+
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			if err := r.Get(ctx, types.NamespacedName{Name: falconContainer.Name}, falconContainer); err != nil {
+				return err
+			}
+
+			falconContainer.Status.Coverage = status
+			return r.Status().Update(ctx, falconContainer)
+		})
+		if err != nil {
+			return fmt.Errorf("unable to update FalconContainer status for falconcontainer.Status.Coverage: %v", err)
+		}
+	}
+
+	if !falconContainer.Spec.Injector.RestartUninjected || len(uninjected) == 0 {
+		return nil
+	}
+
+	// Restarted pods would not be injected while the webhook fails open
+	if r.watchdog != nil && r.watchdog.Health(types.NamespacedName{Name: falconContainer.Name}).FailingOpen {
+		log.Info("Not restarting uninjected workloads while the injector webhook fails open")
+		return nil
+	}
+
+	return r.restartUninjectedWorkloads(ctx, log, falconContainer, webhook, uninjected)
+}
+
+// newCoverageCache returns the cache of the pods and namespaces checked for coverage. Its informers are only started by the
+// first coverage check, so clusters without coverage enabled do not watch their pods. Namespaces are cached as metadata only,
+// and pods are stripped down to the fields read by podsCoverage and podWorkload.
+func newCoverageCache(mgr ctrl.Manager) (cache.Cache, error) {
+	return cache.New(mgr.GetConfig(), cache.Options{
+		HTTPClient: mgr.GetHTTPClient(),
+		Scheme:     mgr.GetScheme(),
+		Mapper:     mgr.GetRESTMapper(),
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.Pod{}: {Transform: stripCoveragePod},
+		},
+	})
+}
+
+// stripCoveragePod keeps the fields of a pod used to report coverage. The injected sensor is only visible in the pod spec,
+// so the container names are kept in addition to the metadata.
+func stripCoveragePod(obj any) (any, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return obj, nil
+	}
+
+	stripped := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              pod.Name,
+			Namespace:         pod.Namespace,
+			UID:               pod.UID,
+			ResourceVersion:   pod.ResourceVersion,
+			CreationTimestamp: pod.CreationTimestamp,
+			DeletionTimestamp: pod.DeletionTimestamp,
+			OwnerReferences:   pod.OwnerReferences,
+		},
+		Status: corev1.PodStatus{Phase: pod.Status.Phase},
+	}
+
+	for _, annotation := range []string{common.FalconContainerInjection, mirrorPodAnnotation} {
+		if value, ok := pod.Annotations[annotation]; ok {
+			if stripped.Annotations == nil {
+				stripped.Annotations = map[string]string{}
+			}
+			stripped.Annotations[annotation] = value
+		}
+	}
+
+	for _, container := range pod.Spec.InitContainers {
+		stripped.Spec.InitContainers = append(stripped.Spec.InitContainers, corev1.Container{Name: container.Name})
+	}
+	for _, container := range pod.Spec.Containers {
+		stripped.Spec.Containers = append(stripped.Spec.Containers, corev1.Container{Name: container.Name})
+	}
+
+	return stripped, nil
+}
+
+// podsCoverage counts the running pods targeted by the injector per namespace, and returns the ones that run without the sensor.
+// Namespaces without targeted pods are not returned.
+func podsCoverage(falconContainer *falconv1alpha1.FalconContainer, namespaces []metav1.PartialObjectMetadata, pods []corev1.Pod, webhookCreated metav1.Time) ([]falconv1alpha1.FalconContainerNamespaceCoverage, []corev1.Pod) {
+	targetedNamespaces := map[string]bool{}
+	for i := range namespaces {
+		if namespaceTargeted(falconContainer, &namespaces[i]) {
+			targetedNamespaces[namespaces[i].Name] = true
+		}
+	}
+
+	perNamespace := map[string]*falconv1alpha1.FalconContainerNamespaceCoverage{}
+	uninjected := []corev1.Pod{}
+	for i := range pods {
+		pod := &pods[i]
+		if !targetedNamespaces[pod.Namespace] || !podTargeted(falconContainer, pod) {
+			continue
+		}
+
+		namespace, ok := perNamespace[pod.Namespace]
+		if !ok {
+			namespace = &falconv1alpha1.FalconContainerNamespaceCoverage{Namespace: pod.Namespace}
+			perNamespace[pod.Namespace] = namespace
+		}
+
+		namespace.Pods++
+		if podInjected(pod) {
+			continue
+		}
+
+		namespace.Uninjected++
+		if pod.CreationTimestamp.Before(&webhookCreated) {
+			namespace.CreatedBeforeWebhook++
+		}
+		uninjected = append(uninjected, *pod)
+	}
+
+	coverage := make([]falconv1alpha1.FalconContainerNamespaceCoverage, 0, len(perNamespace))
+	for _, namespace := range perNamespace {
+		coverage = append(coverage, *namespace)
+	}
+	sort.Slice(coverage, func(i, j int) bool {
+		return coverage[i].Namespace < coverage[j].Namespace
+	})
+
+	return coverage, uninjected
+}
+
+// coverageStatus totals the coverage of the namespaces, and lists the namespaces with uninjected pods
+func coverageStatus(coverage []falconv1alpha1.FalconContainerNamespaceCoverage) *falconv1alpha1.FalconContainerCoverage {
+	status := &falconv1alpha1.FalconContainerCoverage{}
+	for _, namespace := range coverage {
+		status.Pods += namespace.Pods
+		status.Uninjected += namespace.Uninjected
+
+		if namespace.Uninjected > 0 && len(status.Namespaces) < maxReportedNamespaces {
+			status.Namespaces = append(status.Namespaces, namespace)
+		}
+	}
+
+	return status
+}
+
+// namespaceTargeted mirrors the namespace selector of the injector webhook
+func namespaceTargeted(falconContainer *falconv1alpha1.FalconContainer, namespace *metav1.PartialObjectMetadata) bool {
+	if namespace.Name == falconContainer.Spec.InstallNamespace || slices.Contains(injectionExcludedNamespaces, namespace.Name) {
+		return false
+	}
+
+	if _, ok := namespace.Labels["control-plane"]; ok {
+		return false
+	}
+
+	if falconContainer.Spec.Injector.DisableDefaultNSInjection {
+		return namespace.Labels[common.FalconContainerInjection] == "enabled"
+	}
+
+	return namespace.Labels[common.FalconContainerInjection] != "disabled"
+}
+
+// podTargeted reports whether the pod is running and subject to injection according to its annotation
+func podTargeted(falconContainer *falconv1alpha1.FalconContainer, pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil || (pod.Status.Phase != corev1.PodPending && pod.Status.Phase != corev1.PodRunning) {
+		return false
+	}
+
+	if _, ok := pod.Annotations[mirrorPodAnnotation]; ok {
+		return false
+	}
+
+	if falconContainer.Spec.Injector.DisableDefaultPodInjection {
+		return pod.Annotations[common.FalconContainerInjection] == "enabled"
+	}
+
+	return pod.Annotations[common.FalconContainerInjection] != "disabled"
+}
+
+func podInjected(pod *corev1.Pod) bool {
+	hasSensor := func(container corev1.Container) bool {
+		return container.Name == injectedContainerName
+	}
+
+	return slices.ContainsFunc(pod.Spec.Containers, hasSensor) || slices.ContainsFunc(pod.Spec.InitContainers, hasSensor)
+}
+
+// restartUninjectedWorkloads restarts the Deployments, StatefulSets and DaemonSets of the uninjected pods, like kubectl rollout restart.
+// Each workload is restarted once for a given webhook, so that pods the injector keeps failing to inject do not restart in a loop,
+// and at most maxRestartsPerCheck workloads are restarted per call; the others are restarted by the next coverage checks.
+func (r *FalconContainerReconciler) restartUninjectedWorkloads(ctx context.Context, log logr.Logger, falconContainer *falconv1alpha1.FalconContainer, webhook *arv1.MutatingWebhookConfiguration, pods []corev1.Pod) error {
+	restarted := map[string]bool{}
+	restarts := 0
+	for i := range pods {
+		if restarts == maxRestartsPerCheck {
+			log.Info("Deferring the restart of the remaining uninjected workloads to the next coverage check", "restarted", restarts)
+			return nil
+		}
+
+		workload, err := r.podWorkload(ctx, &pods[i])
+		if err != nil {
+			return err
+		}
+		if workload == nil {
+			continue
+		}
+
+		kind := workloadKind(workload)
+		key := kind + "/" + workload.GetNamespace() + "/" + workload.GetName()
+		if restarted[key] {
+			continue
+		}
+		restarted[key] = true
+
+		if workload.GetAnnotations()[restartedForWebhookAnnotation] == string(webhook.UID) {
+			continue
+		}
+
+		patch := client.MergeFrom(workload.DeepCopyObject().(client.Object))
+		annotations := workload.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[restartedForWebhookAnnotation] = string(webhook.UID)
+		workload.SetAnnotations(annotations)
+
+		template := podTemplate(workload)
+		if template.Annotations == nil {
+			template.Annotations = map[string]string{}
+		}
+		template.Annotations[restartedAtAnnotation] = time.Now().Format(time.RFC3339)
+
+		log.Info("Restarting workload to inject the Falcon Container sensor", "kind", kind, "namespace", workload.GetNamespace(), "name", workload.GetName())
+		if err := r.Client.Patch(ctx, workload, patch); err != nil {
+			return fmt.Errorf("unable to restart %s %s/%s: %v", kind, workload.GetNamespace(), workload.GetName(), err)
+		}
+		restarts++
+
+		if r.recorder != nil {
+			r.recorder.Eventf(falconContainer, workload, corev1.EventTypeNormal, "WorkloadRestarted", "Restart",
+				"Restarted %s %s/%s whose pods run without the Falcon Container sensor", kind, workload.GetNamespace(), workload.GetName())
+		}
+	}
+
+	return nil
+}
+
+// podWorkload returns the Deployment, StatefulSet or DaemonSet controlling the pod, or nil for other pods
+func (r *FalconContainerReconciler) podWorkload(ctx context.Context, pod *corev1.Pod) (client.Object, error) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || owner.APIVersion != appsv1.SchemeGroupVersion.String() {
+		return nil, nil
+	}
+
+	var workload client.Object
+	switch owner.Kind {
+	case "ReplicaSet":
+		replicaSet := &appsv1.ReplicaSet{}
+		if err := r.Reader.Get(ctx, types.NamespacedName{Name: owner.Name, Namespace: pod.Namespace}, replicaSet); err != nil {
+			return nil, client.IgnoreNotFound(err)
+		}
+
+		owner = metav1.GetControllerOf(replicaSet)
+		if owner == nil || owner.APIVersion != appsv1.SchemeGroupVersion.String() || owner.Kind != "Deployment" {
+			return nil, nil
+		}
+		workload = &appsv1.Deployment{}
+	case "StatefulSet":
+		workload = &appsv1.StatefulSet{}
+	case "DaemonSet":
+		workload = &appsv1.DaemonSet{}
+	default:
+		return nil, nil
+	}
+
+	if err := r.Reader.Get(ctx, types.NamespacedName{Name: owner.Name, Namespace: pod.Namespace}, workload); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	return workload, nil
+}
+
+func podTemplate(workload client.Object) *corev1.PodTemplateSpec {
+	switch w := workload.(type) {
+	case *appsv1.Deployment:
+		return &w.Spec.Template
+	case *appsv1.StatefulSet:
+		return &w.Spec.Template
+	case *appsv1.DaemonSet:
+		return &w.Spec.Template
+	}
+
+	return &corev1.PodTemplateSpec{}
+}
+
+func workloadKind(workload client.Object) string {
+	switch workload.(type) {
+	case *appsv1.Deployment:
+		return "Deployment"
+	case *appsv1.StatefulSet:
+		return "StatefulSet"
+	case *appsv1.DaemonSet:
+		return "DaemonSet"
+	}
+
+	return "Workload"
+}
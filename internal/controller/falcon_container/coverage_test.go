@@ -0,0 +1,303 @@
+package falcon
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	arv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func newCoveragePod(namespace, name string, created time.Time, injected bool, annotations map[string]string) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			CreationTimestamp: metav1.NewTime(created),
+			Annotations:       annotations,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app"}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	if injected {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: injectedContainerName})
+	}
+
+	return pod
+}
+
+func TestPodsCoverage(t *testing.T) {
+	webhookCreated := time.Now().Add(-time.Hour)
+	before := webhookCreated.Add(-time.Hour)
+	after := webhookCreated.Add(time.Minute)
+
+	falconContainer := &falconv1alpha1.FalconContainer{
+		Spec: falconv1alpha1.FalconContainerSpec{InstallNamespace: "falcon-system"},
+	}
+
+	namespaces := []metav1.PartialObjectMetadata{
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "disabled", Labels: map[string]string{common.FalconContainerInjection: "disabled"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "falcon-system"}},
+	}
+
+	finished := newCoveragePod("team-a", "finished", before, false, nil)
+	finished.Status.Phase = corev1.PodSucceeded
+
+	pods := []corev1.Pod{
+		newCoveragePod("team-a", "injected", after, true, nil),
+		newCoveragePod("team-a", "old", before, false, nil),
+		newCoveragePod("team-a", "failed-open", after, false, nil),
+		newCoveragePod("team-a", "opted-out", before, false, map[string]string{common.FalconContainerInjection: "disabled"}),
+		newCoveragePod("team-a", "static", before, false, map[string]string{mirrorPodAnnotation: "hash"}),
+		finished,
+		newCoveragePod("team-b", "injected", after, true, nil),
+		newCoveragePod("disabled", "pod", before, false, nil),
+		newCoveragePod("kube-system", "pod", before, false, nil),
+		newCoveragePod("falcon-system", "injector", before, false, nil),
+	}
+
+	coverage, uninjected := podsCoverage(falconContainer, namespaces, pods, metav1.NewTime(webhookCreated))
+
+	assert.Equal(t, []falconv1alpha1.FalconContainerNamespaceCoverage{
+		{Namespace: "team-a", Pods: 3, Uninjected: 2, CreatedBeforeWebhook: 1},
+		{Namespace: "team-b", Pods: 1},
+	}, coverage)
+
+	names := []string{}
+	for _, pod := range uninjected {
+		names = append(names, pod.Name)
+	}
+	assert.ElementsMatch(t, []string{"old", "failed-open"}, names)
+
+	status := coverageStatus(coverage)
+	assert.Equal(t, int32(4), status.Pods)
+	assert.Equal(t, int32(2), status.Uninjected)
+	assert.Equal(t, []falconv1alpha1.FalconContainerNamespaceCoverage{coverage[0]}, status.Namespaces)
+
+	t.Run("opt-in injection", func(t *testing.T) {
+		optIn := falconContainer.DeepCopy()
+		optIn.Spec.Injector.DisableDefaultNSInjection = true
+		optIn.Spec.Injector.DisableDefaultPodInjection = true
+
+		namespaces := []metav1.PartialObjectMetadata{
+			{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{common.FalconContainerInjection: "enabled"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+		}
+		pods := []corev1.Pod{
+			newCoveragePod("team-a", "enabled", before, false, map[string]string{common.FalconContainerInjection: "enabled"}),
+			newCoveragePod("team-a", "default", before, false, nil),
+			newCoveragePod("team-b", "enabled", before, false, map[string]string{common.FalconContainerInjection: "enabled"}),
+		}
+
+		coverage, uninjected := podsCoverage(optIn, namespaces, pods, metav1.NewTime(webhookCreated))
+		assert.Equal(t, []falconv1alpha1.FalconContainerNamespaceCoverage{
+			{Namespace: "team-a", Pods: 1, Uninjected: 1, CreatedBeforeWebhook: 1},
+		}, coverage)
+		require.Len(t, uninjected, 1)
+		assert.Equal(t, "enabled", uninjected[0].Name)
+	})
+}
+
+func TestRestartUninjectedWorkloads(t *testing.T) {
+	ctx := context.Background()
+	log := zap.New(zap.UseDevMode(true))
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, falconv1alpha1.AddToScheme(scheme))
+
+	controller := true
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team-a"}}
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-5d4f",
+			Namespace: "team-a",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "Deployment", Name: "app", UID: "deployment-uid", Controller: &controller},
+			},
+		},
+	}
+	restartedStatefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "db",
+			Namespace:   "team-a",
+			Annotations: map[string]string{restartedForWebhookAnnotation: "webhook-uid"},
+		},
+	}
+
+	ownedPod := func(name, kind, owner string) corev1.Pod {
+		pod := newCoveragePod("team-a", name, time.Now(), false, nil)
+		pod.OwnerReferences = []metav1.OwnerReference{
+			{APIVersion: "apps/v1", Kind: kind, Name: owner, UID: types.UID(owner + "-uid"), Controller: &controller},
+		}
+		return pod
+	}
+
+	pods := []corev1.Pod{
+		ownedPod("app-5d4f-a", "ReplicaSet", "app-5d4f"),
+		ownedPod("app-5d4f-b", "ReplicaSet", "app-5d4f"),
+		ownedPod("db-0", "StatefulSet", "db"),
+		newCoveragePod("team-a", "bare", time.Now(), false, nil),
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(deployment, replicaSet, restartedStatefulSet).
+		Build()
+
+	reconciler := &FalconContainerReconciler{
+		Client: fakeClient,
+		Reader: fakeClient,
+		Scheme: scheme,
+	}
+
+	webhook := &arv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: webhookName, UID: "webhook-uid"}}
+	require.NoError(t, reconciler.restartUninjectedWorkloads(ctx, log, &falconv1alpha1.FalconContainer{}, webhook, pods))
+
+	restarted := &appsv1.Deployment{}
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "app", Namespace: "team-a"}, restarted))
+	assert.Equal(t, "webhook-uid", restarted.Annotations[restartedForWebhookAnnotation])
+	assert.NotEmpty(t, restarted.Spec.Template.Annotations[restartedAtAnnotation])
+
+	statefulSet := &appsv1.StatefulSet{}
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "db", Namespace: "team-a"}, statefulSet))
+	assert.NotContains(t, statefulSet.Spec.Template.Annotations, restartedAtAnnotation, "workloads are restarted once per webhook")
+}
+
+func TestRestartUninjectedWorkloads_LimitsRestartsPerCheck(t *testing.T) {
+	ctx := context.Background()
+	log := zap.New(zap.UseDevMode(true))
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, falconv1alpha1.AddToScheme(scheme))
+
+	controller := true
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	pods := []corev1.Pod{}
+	for i := range maxRestartsPerCheck + 2 {
+		name := fmt.Sprintf("db-%d", i)
+		builder.WithObjects(&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a"}})
+
+		pod := newCoveragePod("team-a", name+"-0", time.Now(), false, nil)
+		pod.OwnerReferences = []metav1.OwnerReference{
+			{APIVersion: "apps/v1", Kind: "StatefulSet", Name: name, UID: types.UID(name + "-uid"), Controller: &controller},
+		}
+		pods = append(pods, pod)
+	}
+	fakeClient := builder.Build()
+
+	reconciler := &FalconContainerReconciler{
+		Client: fakeClient,
+		Reader: fakeClient,
+		Scheme: scheme,
+	}
+
+	webhook := &arv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: webhookName, UID: "webhook-uid"}}
+	restartedCount := func() int {
+		statefulSets := &appsv1.StatefulSetList{}
+		require.NoError(t, fakeClient.List(ctx, statefulSets))
+
+		count := 0
+		for _, statefulSet := range statefulSets.Items {
+			if statefulSet.Annotations[restartedForWebhookAnnotation] == "webhook-uid" {
+				count++
+			}
+		}
+		return count
+	}
+
+	require.NoError(t, reconciler.restartUninjectedWorkloads(ctx, log, &falconv1alpha1.FalconContainer{}, webhook, pods))
+	assert.Equal(t, maxRestartsPerCheck, restartedCount())
+
+	require.NoError(t, reconciler.restartUninjectedWorkloads(ctx, log, &falconv1alpha1.FalconContainer{}, webhook, pods))
+	assert.Equal(t, maxRestartsPerCheck+2, restartedCount(), "the next check restarts the remaining workloads")
+}
+
+func TestReportCoverage(t *testing.T) {
+	ctx := context.Background()
+	log := zap.New(zap.UseDevMode(true))
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, arv1.AddToScheme(scheme))
+	require.NoError(t, falconv1alpha1.AddToScheme(scheme))
+
+	enabled := &falconv1alpha1.FalconContainer{
+		ObjectMeta: metav1.ObjectMeta{Name: "enabled"},
+		Spec: falconv1alpha1.FalconContainerSpec{
+			InstallNamespace: "falcon-system",
+			Injector:         falconv1alpha1.FalconContainerInjectorSpec{ReportCoverage: true},
+		},
+	}
+	disabled := &falconv1alpha1.FalconContainer{
+		ObjectMeta: metav1.ObjectMeta{Name: "disabled"},
+		Status: falconv1alpha1.FalconContainerStatus{
+			Coverage: &falconv1alpha1.FalconContainerCoverage{Pods: 1},
+		},
+	}
+	webhook := &arv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: webhookName, UID: "webhook-uid"}}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}
+	pod := newCoveragePod("team-a", "old", time.Now(), false, nil)
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(enabled, disabled, webhook, namespace, &pod).
+		WithStatusSubresource(enabled, disabled).
+		Build()
+
+	reconciler := &FalconContainerReconciler{
+		Client:         fakeClient,
+		Reader:         fakeClient,
+		Scheme:         scheme,
+		coverageReader: fakeClient,
+	}
+
+	reconciler.reportCoverage(ctx, log)
+
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "enabled"}, enabled))
+	require.NotNil(t, enabled.Status.Coverage)
+	assert.Equal(t, int32(1), enabled.Status.Coverage.Uninjected)
+
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "disabled"}, disabled))
+	assert.Nil(t, disabled.Status.Coverage, "coverage is cleared once disabled")
+}
+
+func TestStripCoveragePod(t *testing.T) {
+	pod := newCoveragePod("team-a", "app", time.Now(), true, map[string]string{
+		common.FalconContainerInjection:                    "enabled",
+		"kubectl.kubernetes.io/last-applied-configuration": "{}",
+	})
+	pod.Labels = map[string]string{"app": "app"}
+	pod.Spec.Containers[0].Image = "app:latest"
+
+	obj, err := stripCoveragePod(&pod)
+	require.NoError(t, err)
+
+	stripped := obj.(*corev1.Pod)
+	assert.Equal(t, map[string]string{common.FalconContainerInjection: "enabled"}, stripped.Annotations)
+	assert.Empty(t, stripped.Labels)
+	assert.Empty(t, stripped.Spec.Containers[0].Image)
+	assert.True(t, podInjected(stripped))
+	assert.Equal(t, corev1.PodRunning, stripped.Status.Phase)
+}
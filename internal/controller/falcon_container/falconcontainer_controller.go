@@ -2,21 +2,26 @@ package falcon
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
 	"os"
 	"time"
 
+	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
 	k8sutils "github.com/crowdstrike/falcon-operator/internal/controller/common"
+	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensor"
 	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensorversion"
 	"github.com/crowdstrike/falcon-operator/pkg/aws"
 	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/crowdstrike/falcon-operator/pkg/tls"
 	"github.com/crowdstrike/falcon-operator/version"
 	"github.com/crowdstrike/gofalcon/falcon"
 	"github.com/go-logr/logr"
 	arv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -24,10 +29,15 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
 // FalconContainerReconciler reconciles a FalconContainer object
@@ -38,15 +48,20 @@ type FalconContainerReconciler struct {
 	Scheme          *runtime.Scheme
 	RestConfig      *rest.Config
 	OpenShift       bool
+	CertManager     bool
 	reconcileObject func(client.Object)
 	tracker         sensorversion.Tracker
+	watchdog        *k8sutils.WebhookWatchdog
+	recorder        events.EventRecorder
+	coverageReader  client.Reader
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *FalconContainerReconciler) SetupWithManager(mgr ctrl.Manager, tracker sensorversion.Tracker) error {
-	containerController, err := ctrl.NewControllerManagedBy(mgr).
+	containerBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&falconv1alpha1.FalconContainer{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Owns(&corev1.Namespace{}).
 		Owns(&corev1.Secret{}).
 		Owns(&corev1.ConfigMap{}).
@@ -54,7 +69,13 @@ func (r *FalconContainerReconciler) SetupWithManager(mgr ctrl.Manager, tracker s
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&rbacv1.ClusterRoleBinding{}).
 		Owns(&arv1.MutatingWebhookConfiguration{}).
-		Build(r)
+		Watches(&falconv1alpha1.FalconContainerProfile{}, handler.EnqueueRequestsFromMapFunc(r.enqueueFalconContainers), builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+	// cert-manager updates the Certificate status when it issues or renews the injector certificate. The kind only exists when cert-manager is installed.
+	if r.CertManager {
+		containerBuilder = containerBuilder.Owns(&certv1.Certificate{})
+	}
+
+	containerController, err := containerBuilder.Build(r)
 	if err != nil {
 		return err
 	}
@@ -64,7 +85,27 @@ func (r *FalconContainerReconciler) SetupWithManager(mgr ctrl.Manager, tracker s
 		return err
 	}
 
+	r.watchdog = k8sutils.NewWebhookWatchdog(mgr.GetLogger().WithName("falconcontainer-watchdog"), func(name types.NamespacedName) {
+		r.reconcileObject(&falconv1alpha1.FalconContainer{ObjectMeta: metav1.ObjectMeta{Name: name.Name}})
+	})
+	if err := mgr.Add(r.watchdog); err != nil {
+		return err
+	}
+
+	coverageCache, err := newCoverageCache(mgr)
+	if err != nil {
+		return err
+	}
+	if err := mgr.Add(coverageCache); err != nil {
+		return err
+	}
+	r.coverageReader = coverageCache
+	if err := mgr.Add(&coverageReporter{reconciler: r, interval: coverageCheckInterval}); err != nil {
+		return err
+	}
+
 	r.tracker = tracker
+	r.recorder = mgr.GetEventRecorder("falconcontainer")
 	return nil
 }
 
@@ -79,6 +120,9 @@ func (r *FalconContainerReconciler) GetK8sReader() client.Reader {
 //+kubebuilder:rbac:groups=falcon.crowdstrike.com,resources=falconcontainers,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=falcon.crowdstrike.com,resources=falconcontainers/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=falcon.crowdstrike.com,resources=falconcontainers/finalizers,verbs=get;update;patch
+//+kubebuilder:rbac:groups=falcon.crowdstrike.com,resources=falconcontainerprofiles,verbs=get;list;watch
+//+kubebuilder:rbac:groups=falcon.crowdstrike.com,resources=falconcontainerprofiles/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 
 // +kubebuilder:rbac:groups=image.openshift.io,resources=imagestreams,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;delete
@@ -88,7 +132,11 @@ func (r *FalconContainerReconciler) GetK8sReader() client.Reader {
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update;delete
-// +kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="apps",resources=replicasets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="apps",resources=statefulsets;daemonsets,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups="policy",resources=poddisruptionbudgets,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="cert-manager.io",resources=certificates,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="admissionregistration.k8s.io",resources=mutatingwebhookconfigurations,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=clusterrolebindings,verbs=get;list;watch;create;update;delete
 
@@ -100,11 +148,17 @@ func (r *FalconContainerReconciler) GetK8sReader() client.Reader {
 func (r *FalconContainerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 	log.Info("reconciling FalconContainer")
+
+	// The update policy is checked for its condition and again when selecting the image, but only fetched once
+	ctx = sensor.WithUpdatePolicyCache(ctx)
+
 	falconContainer := &falconv1alpha1.FalconContainer{}
 
 	if err := r.Get(ctx, req.NamespacedName, falconContainer); err != nil {
 		if errors.IsNotFound(err) {
-			r.tracker.StopTracking(req.NamespacedName)
+			r.tracker.StopTracking(sensorVersionTrackKey(req.NamespacedName))
+			r.watchdog.Unwatch(req.NamespacedName)
+			deleteCoverageMetrics(req.Name)
 
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
@@ -115,6 +169,23 @@ func (r *FalconContainerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, err
 	}
 
+	// The mutating webhook is removed before the injector is garbage collected. Its failure policy is Fail,
+	// so pods could not be created in the cluster while it points at an injector without pods.
+	if falconContainer.GetDeletionTimestamp() != nil {
+		r.tracker.StopTracking(sensorVersionTrackKey(req.NamespacedName))
+		r.watchdog.Unwatch(req.NamespacedName)
+		return r.finalizeContainer(ctx, log, falconContainer)
+	}
+
+	if !controllerutil.ContainsFinalizer(falconContainer, common.FalconFinalizer) {
+		controllerutil.AddFinalizer(falconContainer, common.FalconFinalizer)
+		if err := r.Client.Update(ctx, falconContainer); err != nil {
+			log.Error(err, "Unable to update finalizer")
+			return ctrl.Result{}, err
+		}
+		log.Info("Adding finalizer")
+	}
+
 	if len(falconContainer.Status.Conditions) == 0 {
 		err := r.StatusUpdate(ctx, req, log, falconContainer, falconv1alpha1.ConditionPending,
 			metav1.ConditionFalse,
@@ -125,7 +196,10 @@ func (r *FalconContainerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		}
 	}
 
-	validate, err := k8sutils.CheckRunningPodLabels(r.Reader, ctx, falconContainer.Spec.InstallNamespace, common.CRLabels("deployment", injectorName, common.FalconSidecarSensor))
+	// The injectors of FalconContainerProfiles run next to the injector, with their own instance label
+	injectorPodLabels := common.CRLabels("deployment", injectorName, common.FalconSidecarSensor)
+	delete(injectorPodLabels, common.FalconInstanceKey)
+	validate, err := k8sutils.CheckRunningPodLabels(r.Reader, ctx, falconContainer.Spec.InstallNamespace, injectorPodLabels)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -169,10 +243,52 @@ func (r *FalconContainerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 			return ctrl.Result{}, apiConfigErr
 		}
 
-		getSensorVersion := sensorversion.NewFalconCloudQuery(falcon.SidecarSensor, falconApiConfig)
-		r.tracker.Track(req.NamespacedName, getSensorVersion, r.reconcileObjectWithName, falconContainer.Spec.Advanced.IsAutoUpdatingForced())
+		getSensorVersion := sensorversion.NewPreferredImageQuery(falcon.SidecarSensor, falconApiConfig, falconContainer.Spec.Version, falconContainer.Spec.Advanced.UpdatePolicy, nil)
+		r.tracker.TrackWithInitialVersion(sensorVersionTrackKey(req.NamespacedName), falcon.SidecarSensor, getSensorVersion, r.handleSensorVersion, falconContainer.Spec.Advanced.IsAutoUpdatingForced())
 	} else {
-		r.tracker.StopTracking(req.NamespacedName)
+		r.tracker.StopTracking(sensorVersionTrackKey(req.NamespacedName))
+	}
+
+	if falconContainer.Status.AvailableUpdate != nil &&
+		(!shouldTrackSensorVersions(falconContainer) || sensorversion.IsDeployed(falconContainer.Status.AvailableUpdate, falconContainer.Status.Sensor)) {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			err := r.Get(ctx, req.NamespacedName, falconContainer)
+			if err != nil {
+				return err
+			}
+
+			falconContainer.Status.AvailableUpdate = nil
+			return r.Status().Update(ctx, falconContainer)
+		})
+		if err != nil {
+			log.Error(err, "Failed to update FalconContainer status for falconcontainer.Status.AvailableUpdate")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if shouldCheckUpdatePolicy(falconContainer) {
+		falconApiConfig, apiConfigErr := r.falconApiConfig(ctx, falconContainer)
+		if apiConfigErr != nil {
+			return ctrl.Result{}, apiConfigErr
+		}
+
+		condition, err := sensor.UpdatePolicyCondition(ctx, falconApiConfig, falcon.SidecarSensor, falconContainer.Spec.Advanced.GetUpdatePolicy())
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if existing := meta.FindStatusCondition(falconContainer.Status.Conditions, condition.Type); existing == nil || existing.Status != condition.Status || existing.Reason != condition.Reason ||
+			existing.ObservedGeneration != falconContainer.GetGeneration() {
+			if err := r.StatusUpdate(ctx, req, log, falconContainer, condition.Type, condition.Status, condition.Reason, condition.Message); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		if condition.Status == metav1.ConditionFalse {
+			log.Info("sensor update policy cannot be applied", "reason", condition.Reason, "message", condition.Message)
+			// The sensor version tracker polls the policy and reconciles again once it selects a version
+			return ctrl.Result{}, nil
+		}
 	}
 
 	// Image being set will override other image based settings
@@ -279,21 +395,34 @@ func (r *FalconContainerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, fmt.Errorf("failed to reconcile Cluster Role Binding: %v", err)
 	}
 
-	injectorTLS, err := r.reconcileInjectorTLSSecret(ctx, log, falconContainer)
-	if err != nil {
+	injectorTLS, tlsRenewed, err := r.reconcileInjectorTLSSecret(ctx, log, falconContainer)
+	if goerrors.Is(err, k8sutils.ErrCertificateNotIssued) {
+		log.Info(err.Error())
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	} else if err != nil {
 		err = r.StatusUpdate(ctx, req, log, falconContainer, falconv1alpha1.ConditionFailed, metav1.ConditionFalse, "Reconciling", fmt.Sprintf("failed to reconcile injector TLS Secret: %v", err))
 		if err != nil {
 			return ctrl.Result{}, err
 		}
 		return ctrl.Result{}, fmt.Errorf("failed to reconcile injector TLS Secret: %v", err)
 	}
+	// Issuers of cert-manager do not always provide the CA, in which case the webhook and the watchdog trust the system roots
 	caBundle := injectorTLS.Data["ca.crt"]
-	if caBundle == nil {
-		err = r.StatusUpdate(ctx, req, log, falconContainer, falconv1alpha1.ConditionFailed, metav1.ConditionFalse, "Reconciling", "CA bundle not present in injector TLS Secret")
-		if err != nil {
-			return ctrl.Result{}, err
+
+	certificateRenewal, err := r.updateCertificateExpiry(ctx, req, falconContainer, injectorTLS)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Trust the renewed certificate before the injector pods restart to serve it
+	if tlsRenewed {
+		if _, err = r.reconcileWebhook(ctx, log, falconContainer, caBundle); err != nil {
+			err = r.StatusUpdate(ctx, req, log, falconContainer, falconv1alpha1.ConditionFailed, metav1.ConditionFalse, "Reconciling", fmt.Sprintf("failed to reconcile injector MutatingWebhookConfiguration: %v", err))
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, fmt.Errorf("failed to reconcile injector MutatingWebhookConfiguration: %v", err)
 		}
-		return ctrl.Result{}, fmt.Errorf("CA bundle not present in injector TLS Secret")
 	}
 
 	if falconContainer.Spec.FalconSecret.Enabled {
@@ -302,7 +431,8 @@ func (r *FalconContainerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		}
 	}
 
-	if _, err = r.reconcileConfigMap(ctx, log, falconContainer); err != nil {
+	injectorConfig, err := r.reconcileConfigMap(ctx, log, falconContainer)
+	if err != nil {
 		err = r.StatusUpdate(ctx, req, log, falconContainer, falconv1alpha1.ConditionFailed, metav1.ConditionFalse, "Reconciling", fmt.Sprintf("failed to reconcile injector ConfigMap: %v", err))
 		if err != nil {
 			return ctrl.Result{}, err
@@ -310,7 +440,16 @@ func (r *FalconContainerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, fmt.Errorf("failed to reconcile injector ConfigMap: %v", err)
 	}
 
-	if _, err = r.reconcileDeployment(ctx, log, falconContainer); err != nil {
+	if err = r.reconcileProfiles(ctx, log, falconContainer, injectorConfig, tls.Fingerprint(injectorTLS.Data["tls.crt"])); err != nil {
+		err = r.StatusUpdate(ctx, req, log, falconContainer, falconv1alpha1.ConditionFailed, metav1.ConditionFalse, "Reconciling", fmt.Sprintf("failed to reconcile FalconContainerProfiles: %v", err))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to reconcile FalconContainerProfiles: %v", err)
+	}
+
+	deployment, err := r.reconcileDeployment(ctx, log, falconContainer, injectorName, tls.Fingerprint(injectorTLS.Data["tls.crt"]))
+	if err != nil {
 		err = r.StatusUpdate(ctx, req, log, falconContainer, falconv1alpha1.ConditionFailed, metav1.ConditionFalse, "Reconciling", fmt.Sprintf("failed to reconcile injector Deployment: %v", err))
 		if err != nil {
 			return ctrl.Result{}, err
@@ -318,7 +457,15 @@ func (r *FalconContainerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, fmt.Errorf("failed to reconcile injector Deployment: %v", err)
 	}
 
-	if _, err = r.reconcileService(ctx, log, falconContainer); err != nil {
+	if err = r.reconcilePodDisruptionBudget(ctx, log, falconContainer, deployment); err != nil {
+		err = r.StatusUpdate(ctx, req, log, falconContainer, falconv1alpha1.ConditionFailed, metav1.ConditionFalse, "Reconciling", fmt.Sprintf("failed to reconcile injector PodDisruptionBudget: %v", err))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to reconcile injector PodDisruptionBudget: %v", err)
+	}
+
+	if _, err = r.reconcileService(ctx, log, falconContainer, injectorName); err != nil {
 		err = r.StatusUpdate(ctx, req, log, falconContainer, falconv1alpha1.ConditionFailed, metav1.ConditionFalse, "Reconciling", fmt.Sprintf("failed to reconcile injector Service: %v", err))
 		if err != nil {
 			return ctrl.Result{}, err
@@ -335,6 +482,11 @@ func (r *FalconContainerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, fmt.Errorf("failed to find Ready injector pod: %v", err)
 	}
 	if pod.Name == "" {
+		// The webhook is not created before an injector pod is ready, but an existing one fails open when the watchdog reports the injector as unhealthy
+		if err := r.reconcileExistingWebhook(ctx, log, falconContainer, caBundle); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to reconcile injector MutatingWebhookConfiguration: %v", err)
+		}
+
 		log.Info("Looking for a Ready injector pod", "namespace", falconContainer.Spec.InstallNamespace)
 		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 	}
@@ -351,7 +503,8 @@ func (r *FalconContainerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		metav1.ConditionTrue,
 		falconv1alpha1.ReasonInstallSucceeded,
 		"FalconContainer installation completed")
-	return ctrl.Result{}, err
+	useCertManager := falconContainer.Spec.Injector.TLS.CertManager != nil && r.CertManager
+	return ctrl.Result{RequeueAfter: k8sutils.RenewalRequeue(certificateRenewal, useCertManager)}, err
 }
 
 func (r *FalconContainerReconciler) StatusUpdate(ctx context.Context, req ctrl.Request, log logr.Logger, falconContainer *falconv1alpha1.FalconContainer, condType string, status metav1.ConditionStatus, reason string, message string) error {
@@ -379,24 +532,87 @@ func (r *FalconContainerReconciler) StatusUpdate(ctx context.Context, req ctrl.R
 	return nil
 }
 
-func (r *FalconContainerReconciler) reconcileObjectWithName(ctx context.Context, name types.NamespacedName) error {
+// handleSensorVersion records the sensor version selected by the tracker as a pending update, and reconciles the object to deploy it when automatic updates are enabled
+// or to retry an update policy that could not select a version.
+func (r *FalconContainerReconciler) handleSensorVersion(ctx context.Context, name types.NamespacedName, sensorVersion string) error {
 	obj := &falconv1alpha1.FalconContainer{}
-	err := r.Get(ctx, name, obj)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := r.Get(ctx, name, obj)
+		if err != nil {
+			return err
+		}
+
+		update := sensorversion.AvailableUpdate(obj.Status.AvailableUpdate, obj.Status.Sensor, sensorVersion, obj.Spec.Advanced.AvailableUpdateSource())
+		if update == obj.Status.AvailableUpdate {
+			return nil
+		}
+
+		obj.Status.AvailableUpdate = update
+		return r.Status().Update(ctx, obj)
+	})
+	if errors.IsNotFound(err) {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
-	log.FromContext(ctx).Info("reconciling FalconContainer object", "namespace", obj.Namespace, "name", obj.Name)
-	r.reconcileObject(obj)
+	if obj.Spec.Advanced.IsAutoUpdating() || sensorversion.IsBlockedByUpdatePolicy(obj.Status.Conditions) {
+		log.FromContext(ctx).Info("reconciling FalconContainer object", "namespace", obj.Namespace, "name", obj.Name)
+		r.reconcileObject(obj)
+	}
+
 	return nil
 }
 
+// finalizeContainer deletes the mutating webhook, and removes the finalizer once the webhook is gone
+func (r *FalconContainerReconciler) finalizeContainer(ctx context.Context, log logr.Logger, falconContainer *falconv1alpha1.FalconContainer) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(falconContainer, common.FalconFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	webhook := &arv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: webhookName}}
+	gone, err := k8sutils.RemoveWebhook(ctx, r.Client, r.Reader, webhook)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to delete mutating webhook configuration %s: %v", webhookName, err)
+	}
+	if !gone {
+		log.Info("Waiting for the mutating webhook configuration to be deleted", "name", webhookName)
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	}
+
+	// Once the finalizer is removed, the injector resources are garbage collected
+	controllerutil.RemoveFinalizer(falconContainer, common.FalconFinalizer)
+	if err := r.Client.Update(ctx, falconContainer); err != nil {
+		return ctrl.Result{}, err
+	}
+	log.Info("Removing finalizer")
+
+	return ctrl.Result{}, nil
+}
+
 func (r *FalconContainerReconciler) injectFalconSecretData(ctx context.Context, falconContainer *falconv1alpha1.FalconContainer, logger logr.Logger) error {
 	logger.Info("injecting Falcon secret data into Spec.Falcon and Spec.FalconAPI - sensitive manifest values will be overwritten with values in k8s secret")
 
-	return k8sutils.InjectFalconSecretData(ctx, r, falconContainer)
+	// The sidecar sensors run in the namespaces of the workloads and do not read the maintenance token
+	return k8sutils.InjectFalconSecretData(ctx, r, falconContainer, "")
+}
+
+// sensorVersionTrackKey identifies the sensor version track of a FalconContainer.
+func sensorVersionTrackKey(name types.NamespacedName) sensorversion.Key {
+	return sensorversion.NewKey(falconv1alpha1.GroupVersion.WithKind("FalconContainer"), name)
 }
 
+// shouldTrackSensorVersions reports whether new sensor versions are tracked, either to deploy them automatically or to report them as pending updates.
+// Tracking is opt-in, so resources that only use the Falcon API to pick the initial image do not poll it.
 func shouldTrackSensorVersions(obj *falconv1alpha1.FalconContainer) bool {
-	return obj.Spec.FalconAPI != nil && obj.Spec.Advanced.IsAutoUpdating()
+	return obj.Spec.FalconAPI != nil && (obj.Spec.Image == nil || *obj.Spec.Image == "") &&
+		obj.Spec.Advanced.IsTrackingUpdates()
+}
+
+// shouldCheckUpdatePolicy reports whether the sensor version is selected by an update policy rather than an explicit image or version.
+func shouldCheckUpdatePolicy(obj *falconv1alpha1.FalconContainer) bool {
+	return obj.Spec.FalconAPI != nil && (obj.Spec.Image == nil || *obj.Spec.Image == "") &&
+		(obj.Spec.Version == nil || *obj.Spec.Version == "") &&
+		obj.Spec.Advanced.HasUpdatePolicy()
 }
@@ -75,6 +75,11 @@ var _ = Describe("FalconContainer controller", func() {
 			// Delete FalconContainer custom resource
 			falconContainerCR := &falconv1alpha1.FalconContainer{}
 			Expect(k8sClient.Get(ctx, containerNamespacedName, falconContainerCR)).To(Succeed())
+			// Remove finalizer for successful FalconContainer CR deletion
+			patch := client.MergeFrom(falconContainerCR.DeepCopy())
+			falconContainerCR.SetFinalizers(nil)
+			_ = k8sClient.Patch(ctx, falconContainerCR, patch)
+
 			Expect(k8sClient.Delete(ctx, falconContainerCR)).To(Succeed())
 
 			Eventually(func() bool {
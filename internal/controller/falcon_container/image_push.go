@@ -11,6 +11,7 @@ import (
 
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
 	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensor"
+	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensorversion"
 	"github.com/crowdstrike/falcon-operator/internal/controller/image"
 	"github.com/crowdstrike/falcon-operator/pkg/aws"
 	"github.com/crowdstrike/falcon-operator/pkg/common"
@@ -50,6 +51,9 @@ func (r *FalconContainerReconciler) PushImage(ctx context.Context, log logr.Logg
 
 	image := image.NewImageRefresher(ctx, log, falconApiConfig, pushAuth, falconContainer.Spec.Registry.TLS.InsecureSkipVerify)
 	version := falconContainer.Spec.Version
+	if approved, ok := sensorversion.ApprovedVersion(falconContainer, falconContainer.Status.AvailableUpdate); ok {
+		version = &approved
+	}
 
 	tag, err := image.Refresh(registryUri, falcon.SidecarSensor, version)
 	if err != nil {
@@ -219,6 +223,11 @@ func (r *FalconContainerReconciler) setImageTag(ctx context.Context, falconConta
 		return *falconContainer.Status.Sensor, r.Client.Status().Update(ctx, falconContainer)
 	}
 
+	if approved, ok := sensorversion.ApprovedVersion(falconContainer, falconContainer.Status.AvailableUpdate); ok {
+		falconContainer.Status.Sensor = &approved
+		return approved, nil
+	}
+
 	falconApiConfig, apiConfigErr := r.falconApiConfig(ctx, falconContainer)
 	if apiConfigErr != nil {
 		return "", apiConfigErr
@@ -266,7 +275,7 @@ func (r *FalconContainerReconciler) imageMirroringEnabled(falconContainer *falco
 }
 
 func (r *FalconContainerReconciler) versionLock(falconContainer *falconv1alpha1.FalconContainer) bool {
-	if falconContainer.Status.Sensor == nil || falconContainer.Spec.Advanced.HasUpdatePolicy() || falconContainer.Spec.Advanced.IsAutoUpdating() {
+	if _, approved := sensorversion.ApprovedVersion(falconContainer, falconContainer.Status.AvailableUpdate); approved || falconContainer.Status.Sensor == nil || falconContainer.Spec.Advanced.HasUpdatePolicy() || falconContainer.Spec.Advanced.IsAutoUpdating() {
 		return false
 	}
 
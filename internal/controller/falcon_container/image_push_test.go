@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -76,6 +77,24 @@ func TestVersionLock_WithUpdatePolicy(t *testing.T) {
 	assert.False(t, reconciler.versionLock(container))
 }
 
+func TestVersionLock_WithApprovedUpdate(t *testing.T) {
+	reconciler := &FalconContainerReconciler{}
+	container := &falconv1alpha1.FalconContainer{}
+	container.Status.Sensor = stringPointer("some sensor")
+	container.Status.AvailableUpdate = &falconv1alpha1.FalconAvailableUpdate{Version: "newer sensor"}
+	container.SetAnnotations(map[string]string{common.FalconApproveVersionKey: "newer sensor"})
+	assert.False(t, reconciler.versionLock(container))
+}
+
+func TestVersionLock_WithUnapprovedUpdate(t *testing.T) {
+	reconciler := &FalconContainerReconciler{}
+	container := &falconv1alpha1.FalconContainer{}
+	container.Status.Sensor = stringPointer("some sensor")
+	container.Status.AvailableUpdate = &falconv1alpha1.FalconAvailableUpdate{Version: "newer sensor"}
+	container.SetAnnotations(map[string]string{common.FalconApproveVersionKey: "other sensor"})
+	assert.True(t, reconciler.versionLock(container))
+}
+
 func stringPointer(s string) *string {
 	return &s
 }
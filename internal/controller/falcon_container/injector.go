@@ -4,12 +4,16 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
+	"time"
 
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
 	"github.com/crowdstrike/falcon-operator/internal/controller/assets"
+	k8sutils "github.com/crowdstrike/falcon-operator/internal/controller/common"
 	"github.com/go-logr/logr"
 	"github.com/operator-framework/operator-lib/proxy"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crowdstrike/falcon-operator/pkg/common"
 	"github.com/crowdstrike/falcon-operator/pkg/tls"
@@ -17,7 +21,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 )
 
 const (
@@ -27,44 +33,123 @@ const (
 	registryCABundleConfigMapName = "falcon-sidecar-registry-certs"
 )
 
-func (r *FalconContainerReconciler) reconcileInjectorTLSSecret(ctx context.Context, log logr.Logger, falconContainer *falconv1alpha1.FalconContainer) (*corev1.Secret, error) {
+// injectorCertInfo returns the names of the injector certificate. The wildcard names cover the services of the injectors
+// of FalconContainerProfiles.
+func injectorCertInfo(namespace string) tls.CertInfo {
+	return tls.CertInfo{
+		CommonName: fmt.Sprintf("%s.%s.svc", injectorName, namespace),
+		DNSNames: []string{
+			fmt.Sprintf("%s.%s.svc", injectorName, namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", injectorName, namespace),
+			fmt.Sprintf("*.%s.svc", namespace),
+			fmt.Sprintf("*.%s.svc.cluster.local", namespace),
+		},
+	}
+}
+
+// reconcileInjectorTLSSecret creates the TLS Secret of the injector webhook and renews its certificate when it is due for renewal.
+// It reports whether the certificate was renewed. When cert-manager issues the certificate, it returns the Secret written by cert-manager.
+func (r *FalconContainerReconciler) reconcileInjectorTLSSecret(ctx context.Context, log logr.Logger, falconContainer *falconv1alpha1.FalconContainer) (*corev1.Secret, bool, error) {
 	existingInjectorTLSSecret := &corev1.Secret{}
 
+	validity := 3650
+	if falconContainer.Spec.Injector.TLS.Validity != nil {
+		validity = max(*falconContainer.Spec.Injector.TLS.Validity, tls.MinValidityDays)
+	}
+
+	certInfo := injectorCertInfo(falconContainer.Spec.InstallNamespace)
+
+	if k8sutils.UseCertManager(log, falconContainer.Spec.Injector.TLS.CertManager, r.CertManager) {
+		certificate := assets.Certificate(injectorTLSSecretName, falconContainer.Spec.InstallNamespace, common.FalconSidecarSensor, certInfo.CommonName, certInfo.DNSNames, validity,
+			falconContainer.Spec.Injector.TLS.CertManager.IssuerRef, common.CRLabels("secret", injectorTLSSecretName, common.FalconSidecarSensor))
+		secret, err := k8sutils.ReconcileCertificate(ctx, r, certificate,
+			func(obj client.Object) error {
+				if err := ctrl.SetControllerReference(falconContainer, obj, r.Scheme); err != nil {
+					return fmt.Errorf("unable to set controller reference on injector Certificate %s: %v", obj.GetName(), err)
+				}
+				return r.Create(ctx, log, falconContainer, obj)
+			},
+			func(obj client.Object) error {
+				return r.Update(ctx, log, falconContainer, obj)
+			})
+		return secret, false, err
+	}
+
+	genSecretData := func() (map[string][]byte, error) {
+		bundle, err := tls.NewBundle(falconContainer.Spec.InstallNamespace, validity, certInfo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate Falcon Container PKI: %v", err)
+		}
+		return bundle.SecretData(), nil
+	}
+
 	err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: injectorTLSSecretName, Namespace: falconContainer.Spec.InstallNamespace}, existingInjectorTLSSecret)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			validity := 3650
-			if falconContainer.Spec.Injector.TLS.Validity != nil {
-				validity = *falconContainer.Spec.Injector.TLS.Validity
-			}
-
-			certInfo := tls.CertInfo{
-				CommonName: fmt.Sprintf("%s.%s.svc", injectorName, falconContainer.Spec.InstallNamespace),
-				DNSNames:   []string{fmt.Sprintf("%s.%s.svc", injectorName, falconContainer.Spec.InstallNamespace), fmt.Sprintf("%s.%s.svc.cluster.local", injectorName, falconContainer.Spec.InstallNamespace)},
-			}
-
-			c, k, b, err := tls.CertSetup(falconContainer.Spec.InstallNamespace, validity, certInfo)
+			secretData, err := genSecretData()
 			if err != nil {
-				return &corev1.Secret{}, fmt.Errorf("failed to generate Falcon Container PKI: %v", err)
-			}
-			secretData := map[string][]byte{
-				"tls.crt": c,
-				"tls.key": k,
-				"ca.crt":  b,
+				return &corev1.Secret{}, false, err
 			}
 			injectorTLSSecret := assets.Secret(injectorTLSSecretName, falconContainer.Spec.InstallNamespace, common.FalconSidecarSensor, secretData, corev1.SecretTypeTLS)
 			if err = ctrl.SetControllerReference(falconContainer, injectorTLSSecret, r.Scheme); err != nil {
-				return &corev1.Secret{}, fmt.Errorf("unable to set controller reference on injector TLS Secret%s: %v", injectorTLSSecret.ObjectMeta.Name, err)
+				return &corev1.Secret{}, false, fmt.Errorf("unable to set controller reference on injector TLS Secret%s: %v", injectorTLSSecret.ObjectMeta.Name, err)
+			}
+			return injectorTLSSecret, false, r.Create(ctx, log, falconContainer, injectorTLSSecret)
+		}
+		return &corev1.Secret{}, false, fmt.Errorf("unable to query existing injector TLS secret %s: %v", injectorTLSSecretName, err)
+	}
+
+	cert, err := tls.ParseCertificate(existingInjectorTLSSecret.Data["tls.crt"])
+	if err == nil && time.Now().Before(tls.RenewalTime(cert)) && slices.Equal(cert.DNSNames, certInfo.DNSNames) {
+		return existingInjectorTLSSecret, false, nil
+	}
+
+	if err != nil {
+		log.Info("Unable to parse injector TLS certificate, generating a new one", "error", err.Error())
+	} else if !slices.Equal(cert.DNSNames, certInfo.DNSNames) {
+		log.Info("Injector TLS certificate does not cover the injector services, generating a new one", "dnsNames", cert.DNSNames)
+	} else {
+		log.Info("Renewing injector TLS certificate", "expiry", cert.NotAfter)
+	}
+
+	secretData, err := genSecretData()
+	if err != nil {
+		return &corev1.Secret{}, false, err
+	}
+
+	// Keep trusting the previous CA until the injector pods serve the new certificate
+	secretData["ca.crt"] = tls.CABundle(time.Now(), secretData["ca.crt"], existingInjectorTLSSecret.Data["ca.crt"])
+	existingInjectorTLSSecret.Data = secretData
+	return existingInjectorTLSSecret, true, r.Update(ctx, log, falconContainer, existingInjectorTLSSecret)
+}
+
+// updateCertificateExpiry records the expiry of the injector certificate in the status, and returns when the certificate is due for renewal
+func (r *FalconContainerReconciler) updateCertificateExpiry(ctx context.Context, req ctrl.Request, falconContainer *falconv1alpha1.FalconContainer, injectorTLS *corev1.Secret) (time.Time, error) {
+	cert, err := tls.ParseCertificate(injectorTLS.Data["tls.crt"])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to parse injector TLS certificate: %v", err)
+	}
+
+	expiry := metav1.NewTime(cert.NotAfter)
+	if falconContainer.Status.CertificateExpiry == nil || !falconContainer.Status.CertificateExpiry.Equal(&expiry) {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			err := r.Get(ctx, req.NamespacedName, falconContainer)
+			if err != nil {
+				return err
 			}
-			return injectorTLSSecret, r.Create(ctx, log, falconContainer, injectorTLSSecret)
+			falconContainer.Status.CertificateExpiry = &expiry
+			return r.Status().Update(ctx, falconContainer)
+		})
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unable to update certificate expiry in status: %v", err)
 		}
-		return &corev1.Secret{}, fmt.Errorf("unable to query existing injector TLS secret %s: %v", injectorTLSSecretName, err)
 	}
-	return existingInjectorTLSSecret, nil
 
+	return tls.RenewalTime(cert), nil
 }
 
-func (r *FalconContainerReconciler) reconcileDeployment(ctx context.Context, log logr.Logger, falconContainer *falconv1alpha1.FalconContainer) (*appsv1.Deployment, error) {
+// reconcileDeployment creates and updates the injector Deployment with the given name, which is configured from the <name>-config ConfigMap
+func (r *FalconContainerReconciler) reconcileDeployment(ctx context.Context, log logr.Logger, falconContainer *falconv1alpha1.FalconContainer, name string, certFingerprint string) (*appsv1.Deployment, error) {
 	update := false
 
 	imageUri, err := r.imageUri(ctx, falconContainer)
@@ -72,7 +157,9 @@ func (r *FalconContainerReconciler) reconcileDeployment(ctx context.Context, log
 		return &appsv1.Deployment{}, fmt.Errorf("unable to determine falcon container image URI: %v", err)
 	}
 
-	deployment := assets.SideCarDeployment(injectorName, falconContainer.Spec.InstallNamespace, common.FalconSidecarSensor, imageUri, falconContainer)
+	deployment := assets.SideCarDeployment(name, falconContainer.Spec.InstallNamespace, common.FalconSidecarSensor, imageUri, falconContainer)
+	// Restart the injector when its serving certificate is renewed
+	deployment.Spec.Template.Annotations[common.FalconTLSCertificateKey] = certFingerprint
 
 	if !r.OpenShift {
 		var runAsUser int64 = 1234
@@ -91,7 +178,7 @@ func (r *FalconContainerReconciler) reconcileDeployment(ctx context.Context, log
 		}
 	}
 
-	err = common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: injectorName, Namespace: falconContainer.Spec.InstallNamespace}, existingDeployment)
+	err = common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: name, Namespace: falconContainer.Spec.InstallNamespace}, existingDeployment)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			if err = ctrl.SetControllerReference(falconContainer, deployment, r.Scheme); err != nil {
@@ -99,7 +186,7 @@ func (r *FalconContainerReconciler) reconcileDeployment(ctx context.Context, log
 			}
 			return deployment, r.Create(ctx, log, falconContainer, deployment)
 		}
-		return &appsv1.Deployment{}, fmt.Errorf("unable to query existing injector Deployment %s: %v", injectorName, err)
+		return &appsv1.Deployment{}, fmt.Errorf("unable to query existing injector Deployment %s: %v", name, err)
 	}
 
 	// Selectors are immutable
@@ -127,6 +214,14 @@ func (r *FalconContainerReconciler) reconcileDeployment(ctx context.Context, log
 
 	}
 
+	if existingDeployment.Spec.Template.Annotations[common.FalconTLSCertificateKey] != certFingerprint {
+		if existingDeployment.Spec.Template.Annotations == nil {
+			existingDeployment.Spec.Template.Annotations = map[string]string{}
+		}
+		existingDeployment.Spec.Template.Annotations[common.FalconTLSCertificateKey] = certFingerprint
+		update = true
+	}
+
 	if !reflect.DeepEqual(deployment.Spec.Template.Spec.Containers[0].Image, existingDeployment.Spec.Template.Spec.Containers[0].Image) {
 		existingDeployment.Spec.Template.Spec.Containers[0].Image = deployment.Spec.Template.Spec.Containers[0].Image
 		update = true
@@ -162,8 +257,8 @@ func (r *FalconContainerReconciler) reconcileDeployment(ctx context.Context, log
 		update = true
 	}
 
-	if !reflect.DeepEqual(deployment.Spec.Template.Spec.Affinity.NodeAffinity, existingDeployment.Spec.Template.Spec.Affinity.NodeAffinity) {
-		existingDeployment.Spec.Template.Spec.Affinity.NodeAffinity = deployment.Spec.Template.Spec.Affinity.NodeAffinity
+	if !reflect.DeepEqual(deployment.Spec.Template.Spec.Affinity, existingDeployment.Spec.Template.Spec.Affinity) {
+		existingDeployment.Spec.Template.Spec.Affinity = deployment.Spec.Template.Spec.Affinity
 		update = true
 	}
 
@@ -174,3 +269,20 @@ func (r *FalconContainerReconciler) reconcileDeployment(ctx context.Context, log
 	return existingDeployment, nil
 
 }
+
+// reconcilePodDisruptionBudget creates, updates or removes the PodDisruptionBudget of an injector Deployment
+func (r *FalconContainerReconciler) reconcilePodDisruptionBudget(ctx context.Context, log logr.Logger, falconContainer *falconv1alpha1.FalconContainer, deployment *appsv1.Deployment) error {
+	return k8sutils.ReconcilePodDisruptionBudget(ctx, r, falconContainer, deployment, common.FalconSidecarSensor, falconContainer.Spec.Injector.Availability,
+		func(obj client.Object) error {
+			if err := ctrl.SetControllerReference(falconContainer, obj, r.Scheme); err != nil {
+				return fmt.Errorf("unable to set controller reference on injector PodDisruptionBudget %s: %v", obj.GetName(), err)
+			}
+			return r.Create(ctx, log, falconContainer, obj)
+		},
+		func(obj client.Object) error {
+			return r.Update(ctx, log, falconContainer, obj)
+		},
+		func(obj client.Object) error {
+			return r.Delete(ctx, log, falconContainer, obj)
+		})
+}
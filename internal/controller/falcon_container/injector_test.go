@@ -2,11 +2,21 @@ package falcon
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"testing"
+	"time"
 
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/pkg/tls"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -25,6 +35,33 @@ func (e *erroringClient) Get(ctx context.Context, key client.ObjectKey, obj clie
 	return errors.NewBadRequest("test error")
 }
 
+// newExpiringBundle returns a self-signed certificate issued 10 days ago that expires tomorrow, so it is due for renewal
+func newExpiringBundle(t *testing.T) *tls.Bundle {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().AddDate(0, 0, -10),
+		NotAfter:     time.Now().AddDate(0, 0, 1),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return &tls.Bundle{
+		Cert: certPEM,
+		Key:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		CA:   certPEM,
+	}
+}
+
 func TestReconcileInjectorTLSSecretLogic(t *testing.T) {
 	ctx := context.Background()
 	log := zap.New(zap.UseDevMode(true))
@@ -35,6 +72,9 @@ func TestReconcileInjectorTLSSecretLogic(t *testing.T) {
 	require.NoError(t, falconv1alpha1.AddToScheme(scheme))
 
 	t.Run("should return existing secret when found", func(t *testing.T) {
+		existing, err := tls.NewBundle("test-namespace", 365, injectorCertInfo("test-namespace"))
+		require.NoError(t, err)
+
 		existingSecret := &corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      injectorTLSSecretName,
@@ -42,9 +82,9 @@ func TestReconcileInjectorTLSSecretLogic(t *testing.T) {
 			},
 			Type: corev1.SecretTypeTLS,
 			Data: map[string][]byte{
-				"tls.crt": []byte("existing-cert"),
-				"tls.key": []byte("existing-key"),
-				"ca.crt":  []byte("existing-ca"),
+				"tls.crt": existing.Cert,
+				"tls.key": existing.Key,
+				"ca.crt":  existing.CA,
 			},
 		}
 
@@ -72,15 +112,65 @@ func TestReconcileInjectorTLSSecretLogic(t *testing.T) {
 			Scheme: scheme,
 		}
 
-		secret, err := reconciler.reconcileInjectorTLSSecret(ctx, log, falconContainer)
+		secret, renewed, err := reconciler.reconcileInjectorTLSSecret(ctx, log, falconContainer)
 
 		require.NoError(t, err)
+		assert.False(t, renewed)
 		assert.NotNil(t, secret)
 		assert.Equal(t, injectorTLSSecretName, secret.Name)
 		assert.Equal(t, "test-namespace", secret.Namespace)
-		assert.Equal(t, []byte("existing-cert"), secret.Data["tls.crt"])
-		assert.Equal(t, []byte("existing-key"), secret.Data["tls.key"])
-		assert.Equal(t, []byte("existing-ca"), secret.Data["ca.crt"])
+		assert.Equal(t, existing.Cert, secret.Data["tls.crt"])
+		assert.Equal(t, existing.Key, secret.Data["tls.key"])
+		assert.Equal(t, existing.CA, secret.Data["ca.crt"])
+	})
+
+	t.Run("should renew a certificate that is due for renewal", func(t *testing.T) {
+		expiring := newExpiringBundle(t)
+
+		existingSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      injectorTLSSecretName,
+				Namespace: "test-namespace",
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: expiring.SecretData(),
+		}
+
+		falconContainer := &falconv1alpha1.FalconContainer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-falcon-container",
+				Namespace: "test-namespace",
+			},
+			Spec: falconv1alpha1.FalconContainerSpec{
+				InstallNamespace: "test-namespace",
+			},
+		}
+
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(existingSecret, falconContainer).
+			WithStatusSubresource(falconContainer).
+			Build()
+
+		reconciler := &FalconContainerReconciler{
+			Client: fakeClient,
+			Reader: fakeClient,
+			Scheme: scheme,
+		}
+
+		secret, renewed, err := reconciler.reconcileInjectorTLSSecret(ctx, log, falconContainer)
+
+		require.NoError(t, err)
+		assert.True(t, renewed)
+		assert.NotEqual(t, expiring.Cert, secret.Data["tls.crt"])
+
+		cert, err := tls.ParseCertificate(secret.Data["tls.crt"])
+		require.NoError(t, err)
+		assert.True(t, cert.NotAfter.After(time.Now().AddDate(0, 0, 3649)))
+
+		stored := &corev1.Secret{}
+		require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(existingSecret), stored))
+		assert.Equal(t, secret.Data["tls.crt"], stored.Data["tls.crt"])
 	})
 
 	t.Run("should handle default validity configuration", func(t *testing.T) {
@@ -155,7 +245,7 @@ func TestReconcileInjectorTLSSecretLogic(t *testing.T) {
 			Scheme: scheme,
 		}
 
-		secret, err := reconciler.reconcileInjectorTLSSecret(ctx, log, falconContainer)
+		secret, _, err := reconciler.reconcileInjectorTLSSecret(ctx, log, falconContainer)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "unable to query existing injector TLS secret")
@@ -174,14 +264,14 @@ func TestReconcileInjectorTLSSecretLogic(t *testing.T) {
 				expectedValidity: 3650,
 			},
 			{
-				name:             "minimum validity (0)",
-				validity:         &[]int{0}[0],
-				expectedValidity: 0,
+				name:             "minimum validity (7)",
+				validity:         &[]int{7}[0],
+				expectedValidity: 7,
 			},
 			{
-				name:             "single digit validity",
+				name:             "validity below the minimum",
 				validity:         &[]int{1}[0],
-				expectedValidity: 1,
+				expectedValidity: 7,
 			},
 			{
 				name:             "maximum validity (9999)",
@@ -204,7 +294,7 @@ func TestReconcileInjectorTLSSecretLogic(t *testing.T) {
 
 				validity := 3650
 				if falconContainer.Spec.Injector.TLS.Validity != nil {
-					validity = *falconContainer.Spec.Injector.TLS.Validity
+					validity = max(*falconContainer.Spec.Injector.TLS.Validity, tls.MinValidityDays)
 				}
 
 				assert.Equal(t, tc.expectedValidity, validity)
@@ -252,6 +342,7 @@ func TestReconcileInjectorTLSSecretIntegrationScenarios(t *testing.T) {
 		fakeClient := fake.NewClientBuilder().
 			WithScheme(scheme).
 			WithObjects(existingSecret, falconContainer).
+			WithStatusSubresource(falconContainer).
 			Build()
 
 		reconciler := &FalconContainerReconciler{
@@ -260,7 +351,7 @@ func TestReconcileInjectorTLSSecretIntegrationScenarios(t *testing.T) {
 			Scheme: scheme,
 		}
 
-		secret, err := reconciler.reconcileInjectorTLSSecret(ctx, log, falconContainer)
+		secret, _, err := reconciler.reconcileInjectorTLSSecret(ctx, log, falconContainer)
 
 		require.NoError(t, err)
 		assert.Equal(t, corev1.SecretTypeTLS, secret.Type)
@@ -282,3 +373,41 @@ func TestReconcileInjectorTLSSecretIntegrationScenarios(t *testing.T) {
 		assert.Contains(t, caCert, "CERTIFICATE", "ca.crt should contain certificate markers")
 	})
 }
+
+func TestReconcileDeploymentRestoresRemovedAffinity(t *testing.T) {
+	ctx := context.Background()
+	log := zap.New(zap.UseDevMode(true))
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, falconv1alpha1.AddToScheme(scheme))
+
+	image := "falcon-sensor:test"
+	listenPort := int32(4433)
+	falconContainer := &falconv1alpha1.FalconContainer{
+		ObjectMeta: metav1.ObjectMeta{Name: "falcon-container", UID: "falcon-container-uid"},
+		Spec: falconv1alpha1.FalconContainerSpec{
+			InstallNamespace: "falcon-system",
+			Image:            &image,
+			Injector:         falconv1alpha1.FalconContainerInjectorSpec{ListenPort: &listenPort},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(falconContainer).WithStatusSubresource(falconContainer).Build()
+	reconciler := &FalconContainerReconciler{Client: fakeClient, Reader: fakeClient, Scheme: scheme}
+
+	deployment, err := reconciler.reconcileDeployment(ctx, log, falconContainer, injectorName, "fingerprint")
+	require.NoError(t, err)
+	require.NotNil(t, deployment.Spec.Template.Spec.Affinity)
+	affinity := deployment.Spec.Template.Spec.Affinity.DeepCopy()
+
+	deployment.Spec.Template.Spec.Affinity = nil
+	require.NoError(t, fakeClient.Update(ctx, deployment))
+
+	require.NotPanics(t, func() {
+		deployment, err = reconciler.reconcileDeployment(ctx, log, falconContainer, injectorName, "fingerprint")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, affinity, deployment.Spec.Template.Spec.Affinity)
+}
@@ -0,0 +1,36 @@
+package falcon
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// targetedPods is the number of running pods of a namespace that the injector of a FalconContainer targets
+	targetedPods = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "falcon_operator_container_targeted_pods",
+			Help: "Number of running pods in the namespace targeted by the Falcon Container injector",
+		},
+		[]string{"falconcontainer", "namespace"},
+	)
+
+	// uninjectedPods is the number of targeted pods of a namespace that run without the Falcon Container sensor
+	uninjectedPods = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "falcon_operator_container_uninjected_pods",
+			Help: "Number of running pods in the namespace targeted by the Falcon Container injector that run without the sensor",
+		},
+		[]string{"falconcontainer", "namespace"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(targetedPods, uninjectedPods)
+}
+
+// deleteCoverageMetrics removes the coverage metrics of the FalconContainer, before they are reported again or once it is deleted
+func deleteCoverageMetrics(falconContainer string) {
+	targetedPods.DeletePartialMatch(prometheus.Labels{"falconcontainer": falconContainer})
+	uninjectedPods.DeletePartialMatch(prometheus.Labels{"falconcontainer": falconContainer})
+}
@@ -0,0 +1,318 @@
+package falcon
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/internal/controller/assets"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// FalconContainerProfiles override the sidecar injection settings per namespace. The injector reads its settings from its
+// environment only, so each applied profile gets an injector of its own in the install namespace, configured with the
+// injector settings of the FalconContainer overridden by the fields the profile sets. The settings of a pod are resolved
+// in this order:
+//
+//  1. The injection labels and annotations of the namespace and pod decide whether the pod is injected at all.
+//  2. The mutating webhook sends the pods of a namespace with an applied FalconContainerProfile to the injector of the
+//     profile. Only the oldest profile of a namespace is applied, the others are reported as conflicting. Profiles are
+//     only applied in the namespaces listed in injector.profileNamespaces, and up to injector.maxProfiles of them, since
+//     each one runs an injector in the install namespace.
+//  3. The pods of the other namespaces are sent to the injector of the FalconContainer.
+//
+// A namespace keeps using the injector of the FalconContainer until the injector of its profile is available. As the
+// injectors of the profiles run in the install namespace, the CID and provisioning token are never copied to the
+// namespaces of the workloads.
+
+// profileInjectorName returns the name of the injector Deployment, Service and PodDisruptionBudget of the profile of the
+// namespace. Namespace names are as long as Service names can be, so they are hashed.
+func profileInjectorName(namespace string) string {
+	sum := sha256.Sum256([]byte(namespace))
+	return fmt.Sprintf("%s-%x", injectorName, sum[:5])
+}
+
+// defaultMaxProfiles is the number of FalconContainerProfiles applied when injector.maxProfiles is not set
+const defaultMaxProfiles = 10
+
+// reconcileProfiles runs an injector for each valid FalconContainerProfile, removes the injectors of the profiles that
+// are no longer applied, and reports the outcome in the status of each profile
+func (r *FalconContainerReconciler) reconcileProfiles(ctx context.Context, log logr.Logger, falconContainer *falconv1alpha1.FalconContainer, injectorConfig *corev1.ConfigMap, certFingerprint string) error {
+	profiles := &falconv1alpha1.FalconContainerProfileList{}
+	if err := r.List(ctx, profiles); err != nil {
+		return fmt.Errorf("unable to list FalconContainerProfiles: %v", err)
+	}
+
+	sort.Slice(profiles.Items, func(i, j int) bool {
+		a, b := profiles.Items[i], profiles.Items[j]
+		if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+			return a.CreationTimestamp.Before(&b.CreationTimestamp)
+		}
+		return a.Name < b.Name
+	})
+
+	maxProfiles := defaultMaxProfiles
+	if falconContainer.Spec.Injector.MaxProfiles != nil {
+		maxProfiles = int(*falconContainer.Spec.Injector.MaxProfiles)
+	}
+
+	applied := map[string]string{}
+	injectors := map[string]bool{}
+	for i := range profiles.Items {
+		profile := &profiles.Items[i]
+		injector := ""
+		condition := metav1.Condition{
+			Type:               falconv1alpha1.ConditionProfileApplied,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: profile.Generation,
+		}
+
+		if active, ok := applied[profile.Namespace]; ok {
+			condition.Reason = falconv1alpha1.ReasonProfileConflict
+			condition.Message = fmt.Sprintf("FalconContainerProfile %s takes precedence in the namespace, as it is older", active)
+		} else if profile.Namespace == falconContainer.Spec.InstallNamespace {
+			condition.Reason = falconv1alpha1.ReasonProfileNotApplied
+			condition.Message = "Pods are not injected in the install namespace of the FalconContainer"
+		} else if !slices.Contains(falconContainer.Spec.Injector.ProfileNamespaces, profile.Namespace) {
+			condition.Reason = falconv1alpha1.ReasonProfileNotApplied
+			condition.Message = fmt.Sprintf("The namespace is not listed in injector.profileNamespaces of FalconContainer %s", falconContainer.Name)
+		} else if err := profile.Spec.Validate(); err != nil {
+			condition.Reason = falconv1alpha1.ReasonInvalidProfile
+			condition.Message = err.Error()
+		} else if len(injectors) >= maxProfiles {
+			condition.Reason = falconv1alpha1.ReasonProfileNotApplied
+			condition.Message = fmt.Sprintf("FalconContainer %s already applies the maximum of %d FalconContainerProfiles set in injector.maxProfiles", falconContainer.Name, maxProfiles)
+		} else {
+			name := profileInjectorName(profile.Namespace)
+			injectors[name] = true
+
+			available, err := r.reconcileProfileInjector(ctx, log, falconContainer, profile, name, injectorConfig, certFingerprint)
+			if err != nil {
+				return fmt.Errorf("unable to reconcile the injector of FalconContainerProfile %s/%s: %v", profile.Namespace, profile.Name, err)
+			}
+
+			if available {
+				injector = name
+				condition.Status = metav1.ConditionTrue
+				condition.Reason = falconv1alpha1.ReasonProfileApplied
+				condition.Message = fmt.Sprintf("Pods of the namespace are injected by injector %s", name)
+			} else {
+				condition.Reason = falconv1alpha1.ReasonInjectorPending
+				condition.Message = fmt.Sprintf("Waiting for injector %s to become available, pods of the namespace are injected with the settings of the FalconContainer until then", name)
+			}
+		}
+
+		if _, ok := applied[profile.Namespace]; !ok {
+			applied[profile.Namespace] = profile.Name
+		}
+
+		if condition.Status == metav1.ConditionFalse && condition.Reason != falconv1alpha1.ReasonProfileConflict {
+			log.Info("FalconContainerProfile is not applied", "namespace", profile.Namespace, "name", profile.Name, "reason", condition.Reason, "message", condition.Message)
+		}
+
+		if err := r.updateProfileStatus(ctx, profile, injector, condition); err != nil {
+			return fmt.Errorf("unable to update status of FalconContainerProfile %s/%s: %v", profile.Namespace, profile.Name, err)
+		}
+	}
+
+	return r.deleteStaleProfileInjectors(ctx, log, falconContainer, injectors)
+}
+
+// reconcileProfileInjector runs the injector of the profile, and reports whether it is available
+func (r *FalconContainerReconciler) reconcileProfileInjector(ctx context.Context, log logr.Logger, falconContainer *falconv1alpha1.FalconContainer, profile *falconv1alpha1.FalconContainerProfile, name string, injectorConfig *corev1.ConfigMap, certFingerprint string) (bool, error) {
+	data, err := profileConfigData(profile.Spec)
+	if err != nil {
+		return false, err
+	}
+
+	config := maps.Clone(injectorConfig.Data)
+	maps.Copy(config, data)
+	if err := r.reconcileProfileConfigMap(ctx, log, falconContainer, name+"-config", config); err != nil {
+		return false, err
+	}
+
+	deployment, err := r.reconcileDeployment(ctx, log, falconContainer, name, certFingerprint)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.reconcilePodDisruptionBudget(ctx, log, falconContainer, deployment); err != nil {
+		return false, err
+	}
+
+	if _, err := r.reconcileService(ctx, log, falconContainer, name); err != nil {
+		return false, err
+	}
+
+	return deployment.Status.AvailableReplicas > 0, nil
+}
+
+// profileConfigData returns the injector settings the profile overrides, with the keys and encoding of the injector ConfigMap
+func profileConfigData(spec falconv1alpha1.FalconContainerProfileSpec) (map[string]string, error) {
+	data := map[string]string{}
+
+	for k, v := range spec.AdditionalEnvironmentVariables {
+		data[strings.ToUpper(k)] = v
+	}
+
+	if spec.ImagePullPolicy != "" {
+		data["FALCON_IMAGE_PULL_POLICY"] = string(spec.ImagePullPolicy)
+	}
+
+	if spec.LogVolume != nil {
+		vol, err := common.EncodeBase64Interface(spec.LogVolume.Volume())
+		if err != nil {
+			return nil, fmt.Errorf("unable to base64 encode log volume: %v", err)
+		}
+		data["FALCON_LOG_VOLUME"] = vol
+	}
+
+	if spec.SensorResources != nil {
+		resources, err := common.EncodeBase64Interface(*spec.SensorResources)
+		if err != nil {
+			return nil, fmt.Errorf("unable to base64 encode falcon resources: %v", err)
+		}
+		data["FALCON_RESOURCES"] = resources
+	}
+
+	if len(spec.Tags) > 0 {
+		data["FALCONCTL_OPT_TAGS"] = strings.Join(spec.Tags, ",")
+	}
+
+	return data, nil
+}
+
+func (r *FalconContainerReconciler) reconcileProfileConfigMap(ctx context.Context, log logr.Logger, falconContainer *falconv1alpha1.FalconContainer, name string, data map[string]string) error {
+	configMap := assets.SensorConfigMap(name, falconContainer.Spec.InstallNamespace, common.FalconSidecarSensor, data)
+	existingConfigMap := &corev1.ConfigMap{}
+
+	err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: name, Namespace: falconContainer.Spec.InstallNamespace}, existingConfigMap)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			if err = ctrl.SetControllerReference(falconContainer, configMap, r.Scheme); err != nil {
+				return fmt.Errorf("unable to set controller reference on config map %s: %v", name, err)
+			}
+			return r.Create(ctx, log, falconContainer, configMap)
+		}
+		return fmt.Errorf("unable to query existing config map %s: %v", name, err)
+	}
+
+	if reflect.DeepEqual(configMap.Data, existingConfigMap.Data) {
+		return nil
+	}
+
+	existingConfigMap.Data = configMap.Data
+	return r.Update(ctx, log, falconContainer, existingConfigMap)
+}
+
+// deleteStaleProfileInjectors deletes the injector objects of the profiles that are no longer applied
+func (r *FalconContainerReconciler) deleteStaleProfileInjectors(ctx context.Context, log logr.Logger, falconContainer *falconv1alpha1.FalconContainer, injectors map[string]bool) error {
+	lists := []client.ObjectList{
+		&appsv1.DeploymentList{},
+		&policyv1.PodDisruptionBudgetList{},
+		&corev1.ServiceList{},
+		&corev1.ConfigMapList{},
+	}
+
+	for _, list := range lists {
+		if err := r.List(ctx, list, client.InNamespace(falconContainer.Spec.InstallNamespace), client.MatchingLabels{common.FalconComponentKey: common.FalconSidecarSensor}); err != nil {
+			return fmt.Errorf("unable to list injector objects: %v", err)
+		}
+
+		err := meta.EachListItem(list, func(o runtime.Object) error {
+			obj, ok := o.(client.Object)
+			if !ok || !metav1.IsControlledBy(obj, falconContainer) {
+				return nil
+			}
+
+			injector := strings.TrimSuffix(obj.GetName(), "-config")
+			if injector == injectorName || !strings.HasPrefix(injector, injectorName+"-") || injectors[injector] {
+				return nil
+			}
+
+			if err := r.Delete(ctx, log, falconContainer, obj); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// profileInjectors returns the injectors of the applied FalconContainerProfiles, by namespace
+func (r *FalconContainerReconciler) profileInjectors(ctx context.Context) (map[string]string, error) {
+	// Read the statuses the reconcile just updated
+	profiles := &falconv1alpha1.FalconContainerProfileList{}
+	if err := r.Reader.List(ctx, profiles); err != nil {
+		return nil, fmt.Errorf("unable to list FalconContainerProfiles: %v", err)
+	}
+
+	injectors := map[string]string{}
+	for _, profile := range profiles.Items {
+		if profile.Status.Injector != "" {
+			injectors[profile.Namespace] = profile.Status.Injector
+		}
+	}
+
+	return injectors, nil
+}
+
+func (r *FalconContainerReconciler) updateProfileStatus(ctx context.Context, profile *falconv1alpha1.FalconContainerProfile, injector string, condition metav1.Condition) error {
+	existing := meta.FindStatusCondition(profile.Status.Conditions, condition.Type)
+	if profile.Status.Injector == injector && existing != nil &&
+		existing.Status == condition.Status &&
+		existing.Reason == condition.Reason &&
+		existing.Message == condition.Message &&
+		existing.ObservedGeneration == condition.ObservedGeneration {
+		return nil
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := r.Get(ctx, client.ObjectKeyFromObject(profile), profile); err != nil {
+			return err
+		}
+
+		profile.Status.Injector = injector
+		meta.SetStatusCondition(&profile.Status.Conditions, condition)
+		return r.Status().Update(ctx, profile)
+	})
+}
+
+// enqueueFalconContainers reconciles every FalconContainer when a profile changes, since profiles are not owned by one
+func (r *FalconContainerReconciler) enqueueFalconContainers(ctx context.Context, obj client.Object) []reconcile.Request {
+	falconContainers := &falconv1alpha1.FalconContainerList{}
+	if err := r.List(ctx, falconContainers); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "Failed to list FalconContainers")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(falconContainers.Items))
+	for _, falconContainer := range falconContainers.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: falconContainer.Name}})
+	}
+
+	return requests
+}
@@ -0,0 +1,330 @@
+package falcon
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/internal/controller/assets"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func newTestProfile(namespace, name string, created time.Time, spec falconv1alpha1.FalconContainerProfileSpec) *falconv1alpha1.FalconContainerProfile {
+	return &falconv1alpha1.FalconContainerProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: spec,
+	}
+}
+
+func TestReconcileProfiles(t *testing.T) {
+	ctx := context.Background()
+	log := zap.New(zap.UseDevMode(true))
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, policyv1.AddToScheme(scheme))
+	require.NoError(t, falconv1alpha1.AddToScheme(scheme))
+
+	image := "falcon-sensor:test"
+	listenPort := int32(4433)
+	maxProfiles := int32(2)
+	falconContainer := &falconv1alpha1.FalconContainer{
+		ObjectMeta: metav1.ObjectMeta{Name: "falcon-container", UID: "falcon-container-uid"},
+		Spec: falconv1alpha1.FalconContainerSpec{
+			InstallNamespace: "falcon-system",
+			Image:            &image,
+			Injector: falconv1alpha1.FalconContainerInjectorSpec{
+				ListenPort:        &listenPort,
+				ProfileNamespaces: []string{"team-a", "team-b", "team-c", "team-d", "falcon-system"},
+				MaxProfiles:       &maxProfiles,
+			},
+		},
+	}
+	injectorConfig := &corev1.ConfigMap{Data: map[string]string{
+		"FALCONCTL_OPT_CID":        "cid",
+		"FALCONCTL_OPT_TAGS":       "cluster",
+		"FALCON_IMAGE_PULL_POLICY": "Always",
+	}}
+
+	now := time.Now().Truncate(time.Second)
+	resources := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+	}
+
+	teamA := newTestProfile("team-a", "sensor", now, falconv1alpha1.FalconContainerProfileSpec{
+		SensorResources: &resources,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Tags:            []string{"team-a", "prod"},
+		AdditionalEnvironmentVariables: map[string]string{
+			"http_proxy": "http://proxy.team-a:3128",
+		},
+	})
+	teamANewer := newTestProfile("team-a", "another", now.Add(time.Minute), falconv1alpha1.FalconContainerProfileSpec{
+		Tags: []string{"other"},
+	})
+	teamB := newTestProfile("team-b", "sensor", now, falconv1alpha1.FalconContainerProfileSpec{
+		AdditionalEnvironmentVariables: map[string]string{"falconctl_opt_cid": "other-cid"},
+	})
+	installNamespace := newTestProfile("falcon-system", "sensor", now, falconv1alpha1.FalconContainerProfileSpec{
+		Tags: []string{"system"},
+	})
+	teamC := newTestProfile("team-c", "sensor", now.Add(time.Minute), falconv1alpha1.FalconContainerProfileSpec{
+		Tags: []string{"team-c"},
+	})
+	teamD := newTestProfile("team-d", "sensor", now.Add(2*time.Minute), falconv1alpha1.FalconContainerProfileSpec{
+		Tags: []string{"team-d"},
+	})
+	unlisted := newTestProfile("team-e", "sensor", now, falconv1alpha1.FalconContainerProfileSpec{
+		Tags: []string{"team-e"},
+	})
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(falconContainer, teamA, teamANewer, teamB, installNamespace, teamC, teamD, unlisted).
+		WithStatusSubresource(falconContainer, &falconv1alpha1.FalconContainerProfile{}, &appsv1.Deployment{}).
+		Build()
+
+	reconciler := &FalconContainerReconciler{
+		Client: fakeClient,
+		Reader: fakeClient,
+		Scheme: scheme,
+	}
+
+	reconcileProfiles := func() {
+		require.NoError(t, reconciler.reconcileProfiles(ctx, log, falconContainer, injectorConfig, "fingerprint"))
+	}
+	reconcileProfiles()
+
+	profileCondition := func(namespace, name string) (*falconv1alpha1.FalconContainerProfile, *metav1.Condition) {
+		profile := &falconv1alpha1.FalconContainerProfile{}
+		require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, profile))
+		condition := meta.FindStatusCondition(profile.Status.Conditions, falconv1alpha1.ConditionProfileApplied)
+		require.NotNil(t, condition)
+		return profile, condition
+	}
+
+	teamAInjector := types.NamespacedName{Namespace: "falcon-system", Name: profileInjectorName("team-a")}
+
+	t.Run("runs an injector for the oldest profile of a namespace", func(t *testing.T) {
+		profile, condition := profileCondition("team-a", "sensor")
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, falconv1alpha1.ReasonInjectorPending, condition.Reason)
+		assert.Empty(t, profile.Status.Injector)
+
+		configMap := &corev1.ConfigMap{}
+		require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Namespace: "falcon-system", Name: teamAInjector.Name + "-config"}, configMap))
+		assert.True(t, metav1.IsControlledBy(configMap, falconContainer))
+		assert.Equal(t, "cid", configMap.Data["FALCONCTL_OPT_CID"])
+		assert.Equal(t, "IfNotPresent", configMap.Data["FALCON_IMAGE_PULL_POLICY"])
+		assert.Equal(t, "team-a,prod", configMap.Data["FALCONCTL_OPT_TAGS"])
+		assert.Equal(t, "http://proxy.team-a:3128", configMap.Data["HTTP_PROXY"])
+		assert.NotContains(t, configMap.Data, "FALCON_LOG_VOLUME")
+
+		decoded, err := base64.StdEncoding.DecodeString(configMap.Data["FALCON_RESOURCES"])
+		require.NoError(t, err)
+		assert.Contains(t, string(decoded), "256Mi")
+
+		deployment := &appsv1.Deployment{}
+		require.NoError(t, fakeClient.Get(ctx, teamAInjector, deployment))
+		assert.Equal(t, teamAInjector.Name+"-config", deployment.Spec.Template.Spec.Containers[0].EnvFrom[0].ConfigMapRef.Name)
+
+		service := &corev1.Service{}
+		require.NoError(t, fakeClient.Get(ctx, teamAInjector, service))
+		assert.Equal(t, deployment.Spec.Selector.MatchLabels, service.Spec.Selector)
+	})
+
+	t.Run("applies the profile once its injector is available", func(t *testing.T) {
+		deployment := &appsv1.Deployment{}
+		require.NoError(t, fakeClient.Get(ctx, teamAInjector, deployment))
+		deployment.Status.AvailableReplicas = 1
+		require.NoError(t, fakeClient.Status().Update(ctx, deployment))
+
+		reconcileProfiles()
+
+		profile, condition := profileCondition("team-a", "sensor")
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, falconv1alpha1.ReasonProfileApplied, condition.Reason)
+		assert.Equal(t, teamAInjector.Name, profile.Status.Injector)
+
+		injectors, err := reconciler.profileInjectors(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"team-a": teamAInjector.Name}, injectors)
+	})
+
+	t.Run("reports newer profiles of the namespace as conflicting", func(t *testing.T) {
+		profile, condition := profileCondition("team-a", "another")
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, falconv1alpha1.ReasonProfileConflict, condition.Reason)
+		assert.Contains(t, condition.Message, "sensor")
+		assert.Empty(t, profile.Status.Injector)
+	})
+
+	t.Run("rejects profiles overriding operator settings", func(t *testing.T) {
+		_, condition := profileCondition("team-b", "sensor")
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, falconv1alpha1.ReasonInvalidProfile, condition.Reason)
+
+		err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "falcon-system", Name: profileInjectorName("team-b")}, &appsv1.Deployment{})
+		assert.True(t, errors.IsNotFound(err))
+	})
+
+	t.Run("ignores profiles in the install namespace", func(t *testing.T) {
+		_, condition := profileCondition("falcon-system", "sensor")
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, falconv1alpha1.ReasonProfileNotApplied, condition.Reason)
+	})
+
+	t.Run("ignores profiles of namespaces that are not listed", func(t *testing.T) {
+		_, condition := profileCondition("team-e", "sensor")
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, falconv1alpha1.ReasonProfileNotApplied, condition.Reason)
+		assert.Contains(t, condition.Message, "injector.profileNamespaces")
+
+		err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "falcon-system", Name: profileInjectorName("team-e")}, &appsv1.Deployment{})
+		assert.True(t, errors.IsNotFound(err))
+	})
+
+	t.Run("does not run injectors for profiles over the maximum", func(t *testing.T) {
+		_, condition := profileCondition("team-c", "sensor")
+		assert.Equal(t, falconv1alpha1.ReasonInjectorPending, condition.Reason)
+
+		_, condition = profileCondition("team-d", "sensor")
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, falconv1alpha1.ReasonProfileNotApplied, condition.Reason)
+		assert.Contains(t, condition.Message, "injector.maxProfiles")
+
+		err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "falcon-system", Name: profileInjectorName("team-d")}, &appsv1.Deployment{})
+		assert.True(t, errors.IsNotFound(err))
+	})
+
+	t.Run("deletes the injector of a removed profile", func(t *testing.T) {
+		injectorConfigMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      injectorConfigMapName,
+				Namespace: "falcon-system",
+				Labels:    common.CRLabels("configmap", injectorConfigMapName, common.FalconSidecarSensor),
+			},
+		}
+		require.NoError(t, controllerutil.SetControllerReference(falconContainer, injectorConfigMap, scheme))
+		require.NoError(t, fakeClient.Create(ctx, injectorConfigMap))
+
+		require.NoError(t, fakeClient.Delete(ctx, teamA))
+		require.NoError(t, fakeClient.Delete(ctx, teamANewer))
+
+		reconcileProfiles()
+
+		for _, obj := range []client.Object{&appsv1.Deployment{}, &corev1.Service{}} {
+			err := fakeClient.Get(ctx, teamAInjector, obj)
+			assert.True(t, errors.IsNotFound(err))
+		}
+		err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "falcon-system", Name: teamAInjector.Name + "-config"}, &corev1.ConfigMap{})
+		assert.True(t, errors.IsNotFound(err))
+		require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(injectorConfigMap), &corev1.ConfigMap{}), "the ConfigMap of the injector is kept")
+	})
+}
+
+func TestAddProfileWebhooks(t *testing.T) {
+	listenPort := int32(4433)
+	falconContainer := &falconv1alpha1.FalconContainer{
+		Spec: falconv1alpha1.FalconContainerSpec{
+			InstallNamespace: "falcon-system",
+			Injector:         falconv1alpha1.FalconContainerInjectorSpec{ListenPort: &listenPort},
+		},
+	}
+	webhook := assets.MutatingWebhook(injectorName, "falcon-system", webhookName, []byte("ca"), false, falconContainer)
+
+	addProfileWebhooks(webhook, map[string]string{"team-b": "injector-b", "team-a": "injector-a"})
+	require.Len(t, webhook.Webhooks, 3)
+
+	notIn := webhook.Webhooks[0].NamespaceSelector.MatchExpressions[2]
+	assert.Equal(t, corev1.LabelMetadataName, notIn.Key)
+	assert.Equal(t, metav1.LabelSelectorOpNotIn, notIn.Operator)
+	assert.Equal(t, []string{"team-a", "team-b"}, notIn.Values)
+
+	profileWebhook := webhook.Webhooks[1]
+	assert.Equal(t, "injector-a."+webhookName, profileWebhook.Name)
+	assert.Equal(t, "injector-a", profileWebhook.ClientConfig.Service.Name)
+	assert.Len(t, profileWebhook.NamespaceSelector.MatchExpressions, 3)
+	assert.Equal(t, []string{"team-a"}, profileWebhook.NamespaceSelector.MatchExpressions[2].Values)
+	assert.Equal(t, webhook.Webhooks[0].NamespaceSelector.MatchExpressions[0], profileWebhook.NamespaceSelector.MatchExpressions[0])
+}
+
+func TestFalconContainerProfileValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    falconv1alpha1.FalconContainerProfileSpec
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			spec: falconv1alpha1.FalconContainerProfileSpec{
+				Tags:                           []string{"team-a", "env/prod", "tier_1"},
+				AdditionalEnvironmentVariables: map[string]string{"https_proxy": "http://proxy:3128"},
+			},
+		},
+		{
+			name: "log volume",
+			spec: falconv1alpha1.FalconContainerProfileSpec{
+				LogVolume: &falconv1alpha1.FalconContainerProfileLogVolume{Name: "logs", EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			},
+		},
+		{
+			name:    "log volume without a source",
+			spec:    falconv1alpha1.FalconContainerProfileSpec{LogVolume: &falconv1alpha1.FalconContainerProfileLogVolume{Name: "logs"}},
+			wantErr: true,
+		},
+		{
+			name:    "tag with a comma",
+			spec:    falconv1alpha1.FalconContainerProfileSpec{Tags: []string{"a,b"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid variable name",
+			spec:    falconv1alpha1.FalconContainerProfileSpec{AdditionalEnvironmentVariables: map[string]string{"1-proxy": "x"}},
+			wantErr: true,
+		},
+		{
+			name:    "reserved variable",
+			spec:    falconv1alpha1.FalconContainerProfileSpec{AdditionalEnvironmentVariables: map[string]string{"falcon_image": "x"}},
+			wantErr: true,
+		},
+		{
+			name:    "falconctl option",
+			spec:    falconv1alpha1.FalconContainerProfileSpec{AdditionalEnvironmentVariables: map[string]string{"FALCONCTL_OPT_TAGS": "x"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
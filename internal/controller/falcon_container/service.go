@@ -15,13 +15,15 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
-func (r *FalconContainerReconciler) reconcileService(ctx context.Context, log logr.Logger, falconContainer *falconv1alpha1.FalconContainer) (*corev1.Service, error) {
-	selector := map[string]string{common.FalconComponentKey: common.FalconSidecarSensor}
-	service := assets.Service(injectorName, falconContainer.Spec.InstallNamespace, common.FalconSidecarSensor, selector, common.FalconServiceHTTPSName, *falconContainer.Spec.Injector.ListenPort)
+// reconcileService creates and updates the Service of the injector Deployment with the given name
+func (r *FalconContainerReconciler) reconcileService(ctx context.Context, log logr.Logger, falconContainer *falconv1alpha1.FalconContainer, name string) (*corev1.Service, error) {
+	// Select the pods of this injector only, as the injectors of FalconContainerProfiles run in the same namespace
+	selector := common.CRLabels("deployment", name, common.FalconSidecarSensor)
+	service := assets.Service(name, falconContainer.Spec.InstallNamespace, common.FalconSidecarSensor, selector, common.FalconServiceHTTPSName, *falconContainer.Spec.Injector.ListenPort)
 	updated := false
 	existingService := &corev1.Service{}
 
-	err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: injectorName, Namespace: falconContainer.Spec.InstallNamespace}, existingService)
+	err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: name, Namespace: falconContainer.Spec.InstallNamespace}, existingService)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			if err = ctrl.SetControllerReference(falconContainer, service, r.Scheme); err != nil {
@@ -31,7 +33,7 @@ func (r *FalconContainerReconciler) reconcileService(ctx context.Context, log lo
 			return service, r.Create(ctx, log, falconContainer, service)
 		}
 
-		return &corev1.Service{}, fmt.Errorf("unable to query existing service %s: %v", injectorName, err)
+		return &corev1.Service{}, fmt.Errorf("unable to query existing service %s: %v", name, err)
 	}
 
 	if !reflect.DeepEqual(service.Spec.Selector, existingService.Spec.Selector) {
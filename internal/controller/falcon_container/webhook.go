@@ -3,15 +3,22 @@ package falcon
 import (
 	"context"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
 	"github.com/crowdstrike/falcon-operator/internal/controller/assets"
+	k8sutils "github.com/crowdstrike/falcon-operator/internal/controller/common"
 	"github.com/crowdstrike/falcon-operator/pkg/common"
 	"github.com/go-logr/logr"
 	arv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -27,9 +34,33 @@ func (r *FalconContainerReconciler) reconcileWebhook(ctx context.Context, log lo
 	}
 
 	webhook := assets.MutatingWebhook(injectorName, falconContainer.Spec.InstallNamespace, webhookName, caBundle, disableDefaultNSInjection, falconContainer)
+	profileInjectors, err := r.profileInjectors(ctx)
+	if err != nil {
+		return &arv1.MutatingWebhookConfiguration{}, err
+	}
+	addProfileWebhooks(webhook, profileInjectors)
+
+	failingOpen, err := r.reconcileWatchdog(ctx, log, falconContainer, caBundle)
+	if err != nil {
+		return &arv1.MutatingWebhookConfiguration{}, err
+	}
+	if failingOpen {
+		failurePolicy := arv1.Ignore
+		for i := range webhook.Webhooks {
+			webhook.Webhooks[i].FailurePolicy = &failurePolicy
+		}
+	}
+
+	// The cert-manager CA injector owns the caBundle when cert-manager issues the injector certificate
+	injectCAFrom := ""
+	if falconContainer.Spec.Injector.TLS.CertManager != nil && r.CertManager {
+		injectCAFrom = falconContainer.Spec.InstallNamespace + "/" + injectorTLSSecretName
+		setCABundle(webhook, nil)
+	}
+	k8sutils.SetInjectCAFrom(webhook, injectCAFrom)
 	existingWebhook := &arv1.MutatingWebhookConfiguration{}
 
-	err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: webhookName}, existingWebhook)
+	err = common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: webhookName}, existingWebhook)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			if err = ctrl.SetControllerReference(falconContainer, webhook, r.Scheme); err != nil {
@@ -42,8 +73,14 @@ func (r *FalconContainerReconciler) reconcileWebhook(ctx context.Context, log lo
 		return &arv1.MutatingWebhookConfiguration{}, fmt.Errorf("unable to query existing mutating webhook configuration %s: %v", webhookName, err)
 	}
 
-	if !reflect.DeepEqual(webhook.Webhooks[0], existingWebhook.Webhooks[0]) {
-		existingWebhook.Webhooks[0] = webhook.Webhooks[0]
+	if injectCAFrom != "" && len(existingWebhook.Webhooks) > 0 {
+		// The CA injector sets the same caBundle on every webhook of the configuration, including those of new profiles
+		setCABundle(webhook, existingWebhook.Webhooks[0].ClientConfig.CABundle)
+	}
+
+	annotationsUpdated := k8sutils.SetInjectCAFrom(existingWebhook, injectCAFrom)
+	if annotationsUpdated || !reflect.DeepEqual(webhook.Webhooks, existingWebhook.Webhooks) {
+		existingWebhook.Webhooks = webhook.Webhooks
 
 		return webhook, r.Update(ctx, log, falconContainer, existingWebhook)
 	}
@@ -51,3 +88,98 @@ func (r *FalconContainerReconciler) reconcileWebhook(ctx context.Context, log lo
 	return existingWebhook, nil
 
 }
+
+// setCABundle sets the caBundle of every webhook of the configuration
+func setCABundle(webhook *arv1.MutatingWebhookConfiguration, caBundle []byte) {
+	for i := range webhook.Webhooks {
+		webhook.Webhooks[i].ClientConfig.CABundle = caBundle
+	}
+}
+
+// addProfileWebhooks sends the pods of the namespaces with an applied FalconContainerProfile to the injector of the profile,
+// instead of the injector of the FalconContainer
+func addProfileWebhooks(webhook *arv1.MutatingWebhookConfiguration, profileInjectors map[string]string) {
+	if len(profileInjectors) == 0 {
+		return
+	}
+
+	namespaces := slices.Sorted(maps.Keys(profileInjectors))
+	injectorWebhook := webhook.Webhooks[0].DeepCopy()
+	webhook.Webhooks[0].NamespaceSelector.MatchExpressions = append(webhook.Webhooks[0].NamespaceSelector.MatchExpressions, metav1.LabelSelectorRequirement{
+		Key:      corev1.LabelMetadataName,
+		Operator: metav1.LabelSelectorOpNotIn,
+		Values:   namespaces,
+	})
+
+	for _, namespace := range namespaces {
+		profileWebhook := injectorWebhook.DeepCopy()
+		profileWebhook.Name = profileInjectors[namespace] + "." + webhookName
+		profileWebhook.ClientConfig.Service.Name = profileInjectors[namespace]
+		profileWebhook.NamespaceSelector.MatchExpressions = append(profileWebhook.NamespaceSelector.MatchExpressions, metav1.LabelSelectorRequirement{
+			Key:      corev1.LabelMetadataName,
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   []string{namespace},
+		})
+		webhook.Webhooks = append(webhook.Webhooks, *profileWebhook)
+	}
+}
+
+// reconcileExistingWebhook reconciles the webhook when it exists, and does not create it otherwise
+func (r *FalconContainerReconciler) reconcileExistingWebhook(ctx context.Context, log logr.Logger, falconContainer *falconv1alpha1.FalconContainer, caBundle []byte) error {
+	err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: webhookName}, &arv1.MutatingWebhookConfiguration{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = r.reconcileWebhook(ctx, log, falconContainer, caBundle)
+	return err
+}
+
+// reconcileWatchdog has the watchdog probe the injector when it is enabled, reports its health in the status,
+// and returns whether the webhook should fail open
+func (r *FalconContainerReconciler) reconcileWatchdog(ctx context.Context, log logr.Logger, falconContainer *falconv1alpha1.FalconContainer, caBundle []byte) (bool, error) {
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: falconContainer.Name}}
+	watchdog := falconContainer.Spec.Injector.Watchdog
+	if r.watchdog == nil || !watchdog.IsEnabled() {
+		r.watchdog.Unwatch(req.NamespacedName)
+
+		if meta.FindStatusCondition(falconContainer.Status.Conditions, falconv1alpha1.ConditionWebhookHealthy) == nil {
+			return false, nil
+		}
+
+		return false, retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			if err := r.Get(ctx, req.NamespacedName, falconContainer); err != nil {
+				return err
+			}
+			meta.RemoveStatusCondition(&falconContainer.Status.Conditions, falconv1alpha1.ConditionWebhookHealthy)
+			return r.Status().Update(ctx, falconContainer)
+		})
+	}
+
+	url := fmt.Sprintf("https://%s.%s.svc:%d%s", injectorName, falconContainer.Spec.InstallNamespace, *falconContainer.Spec.Injector.ListenPort, common.FalconContainerProbePath)
+	if err := r.watchdog.Watch(req.NamespacedName, k8sutils.NewWebhookProbe(url, caBundle, watchdog)); err != nil {
+		return false, fmt.Errorf("unable to watch injector health: %v", err)
+	}
+
+	health := r.watchdog.Health(req.NamespacedName)
+	if !health.Probed {
+		return false, nil
+	}
+
+	condition := health.Condition(falconContainer.GetGeneration())
+	previous := meta.FindStatusCondition(falconContainer.Status.Conditions, falconv1alpha1.ConditionWebhookHealthy)
+	if previous != nil && previous.Status == condition.Status {
+		return health.FailingOpen, nil
+	}
+
+	if health.FailingOpen {
+		r.recorder.Eventf(falconContainer, nil, corev1.EventTypeWarning, condition.Reason, "Watchdog", "%s", condition.Message)
+	} else if previous != nil {
+		r.recorder.Eventf(falconContainer, nil, corev1.EventTypeNormal, condition.Reason, "Watchdog", "The injector webhook failure policy is restored")
+	}
+
+	return health.FailingOpen, r.StatusUpdate(ctx, req, log, falconContainer, condition.Type, condition.Status, condition.Reason, condition.Message)
+}
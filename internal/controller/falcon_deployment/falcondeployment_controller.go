@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -19,6 +20,8 @@ import (
 
 	"dario.cat/mergo"
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	k8sutils "github.com/crowdstrike/falcon-operator/internal/controller/common"
+	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensorversion"
 	"github.com/crowdstrike/falcon-operator/version"
 	"github.com/go-logr/logr"
 )
@@ -26,9 +29,11 @@ import (
 // FalconDeploymentReconciler reconciles a FalconDeployment object
 type FalconDeploymentReconciler struct {
 	client.Client
-	Reader    client.Reader
-	Scheme    *runtime.Scheme
-	OpenShift bool
+	Reader          client.Reader
+	Scheme          *runtime.Scheme
+	OpenShift       bool
+	reconcileObject func(client.Object)
+	tracker         sensorversion.Tracker
 }
 
 //+kubebuilder:rbac:groups=falcon.crowdstrike.com,resources=falcondeployments,verbs=get;list;watch;create;update;patch;delete
@@ -69,6 +74,8 @@ func (r *FalconDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	err := r.Get(ctx, req.NamespacedName, falconDeployment)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
+			r.tracker.StopTracking(releaseTrainTrackKey(req.NamespacedName))
+
 			// If the custom resource is not found then, it usually means that it was deleted or not created
 			// In this way, we will stop the reconciliation
 			log.Info("FalconDeployment resource not found. Ignoring since object must be deleted")
@@ -105,6 +112,10 @@ func (r *FalconDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		}
 	}
 
+	if err = r.reconcileReleaseTrain(ctx, req, log, falconDeployment); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	if falconDeployment.Spec.FalconAPI != nil {
 		cloud, err := falconDeployment.Spec.FalconAPI.FalconCloudWithSecret(ctx, r.Reader, falconDeployment.Spec.FalconSecret)
 		if err != nil {
@@ -140,14 +151,25 @@ func (r *FalconDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Req
 }
 
 // SetupWithManager sets up the controller with the Manager.
-func (r *FalconDeploymentReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+func (r *FalconDeploymentReconciler) SetupWithManager(mgr ctrl.Manager, tracker sensorversion.Tracker) error {
+	deploymentController, err := ctrl.NewControllerManagedBy(mgr).
 		For(&falconv1alpha1.FalconDeployment{}).
 		Owns(&falconv1alpha1.FalconAdmission{}).
 		Owns(&falconv1alpha1.FalconContainer{}).
 		Owns(&falconv1alpha1.FalconImageAnalyzer{}).
 		Owns(&falconv1alpha1.FalconNodeSensor{}).
-		Complete(r)
+		Build(r)
+	if err != nil {
+		return err
+	}
+
+	r.reconcileObject, err = k8sutils.NewReconcileTrigger(deploymentController)
+	if err != nil {
+		return err
+	}
+
+	r.tracker = tracker
+	return nil
 }
 
 func (r *FalconDeploymentReconciler) reconcileAdmissionController(ctx context.Context, log logr.Logger, falconDeployment *falconv1alpha1.FalconDeployment) error {
@@ -180,6 +202,13 @@ func (r *FalconDeploymentReconciler) reconcileAdmissionController(ctx context.Co
 			return fmt.Errorf("unable to merge specs for FalconAdmission: %v", err)
 		}
 
+		if train := falconDeployment.Status.ReleaseTrain; train != nil {
+			if version := coordinatedVersion(falconDeployment.Spec.FalconAdmission.Version, train.Admission); version != nil {
+				newFalconAdmission.Spec.Version = version
+				newFalconAdmission.Spec.Advanced = falconv1alpha1.FalconAdvanced{}
+			}
+		}
+
 		if len(admissionList.Items) == 0 {
 			if err := ctrl.SetControllerReference(falconDeployment, newFalconAdmission, r.Scheme); err != nil {
 				return fmt.Errorf("unable to set controller reference for %s: %v", newFalconAdmission.Name, err)
@@ -218,64 +247,72 @@ func (r *FalconDeploymentReconciler) reconcileAdmissionController(ctx context.Co
 
 func (r *FalconDeploymentReconciler) reconcileNodeSensor(ctx context.Context, log logr.Logger, falconDeployment *falconv1alpha1.FalconDeployment) error {
 	var nodeSensorList falconv1alpha1.FalconNodeSensorList
-	existingNodeSensor := &falconv1alpha1.FalconNodeSensor{}
-	updated := false
+	var newNodeSensors []*falconv1alpha1.FalconNodeSensor
 
 	if err := r.Client.List(ctx, &nodeSensorList); err != nil {
 		return fmt.Errorf("unable to get FalconNodeSensorList: %s", err)
 	}
 
-	if len(nodeSensorList.Items) != 0 {
-		existingNodeSensor.ObjectMeta = metav1.ObjectMeta{
-			Name:      "falcon-node-sensor",
-			Namespace: nodeSensorList.Items[0].GetNamespace(),
+	if *falconDeployment.Spec.DeployNodeSensor {
+		var err error
+		if newNodeSensors, err = desiredNodeSensors(falconDeployment); err != nil {
+			return err
 		}
 	}
 
-	if *falconDeployment.Spec.DeployNodeSensor {
-		newNodeSensor := &falconv1alpha1.FalconNodeSensor{}
-		newNodeSensor.Spec.FalconAPI = falconDeployment.Spec.FalconAPI
-		newNodeSensor.Spec.FalconSecret = falconDeployment.Spec.FalconSecret
-		newNodeSensor.ObjectMeta = metav1.ObjectMeta{
-			Name:      "falcon-node-sensor",
-			Namespace: falconDeployment.Spec.FalconNodeSensor.InstallNamespace,
+	// Remove the FalconNodeSensors that are no longer wanted before deploying their replacements,
+	// so that their node cleanup does not remove the sensors that the replacements install on the same nodes.
+	removing := false
+	for i := range nodeSensorList.Items {
+		existingNodeSensor := &nodeSensorList.Items[i]
+		if slices.ContainsFunc(newNodeSensors, func(nodeSensor *falconv1alpha1.FalconNodeSensor) bool {
+			return nodeSensor.Name == existingNodeSensor.Name
+		}) {
+			continue
+		}
+
+		if existingNodeSensor.Name != nodeSensorName && !metav1.IsControlledBy(existingNodeSensor, falconDeployment) {
+			continue
 		}
 
-		if err := mergo.Merge(&newNodeSensor.Spec, falconDeployment.Spec.FalconNodeSensor, mergo.WithOverride); err != nil {
-			return fmt.Errorf("unable to merge specs for FalconNodeSensor: %v", err)
+		removing = true
+		if existingNodeSensor.GetDeletionTimestamp() != nil {
+			continue
 		}
 
-		if len(nodeSensorList.Items) == 0 {
+		if err := r.delete(ctx, log, falconDeployment, existingNodeSensor); err != nil {
+			return err
+		}
+	}
+
+	if removing {
+		log.Info("Waiting for removed FalconNodeSensors to be deleted")
+		return nil
+	}
+
+	for _, newNodeSensor := range newNodeSensors {
+		existingNodeSensor := &falconv1alpha1.FalconNodeSensor{}
+		err := r.Client.Get(ctx, types.NamespacedName{Name: newNodeSensor.Name, Namespace: newNodeSensor.Namespace}, existingNodeSensor)
+		if apierrors.IsNotFound(err) {
 			if err := ctrl.SetControllerReference(falconDeployment, newNodeSensor, r.Scheme); err != nil {
 				return fmt.Errorf("unable to set controller reference for %s: %v", newNodeSensor.Name, err)
 			}
-			return r.create(ctx, log, falconDeployment, newNodeSensor)
-		}
 
-		err := r.Client.Get(ctx, types.NamespacedName{Name: existingNodeSensor.Name, Namespace: existingNodeSensor.Namespace}, existingNodeSensor)
-
-		if err != nil {
+			if err := r.create(ctx, log, falconDeployment, newNodeSensor); err != nil {
+				return err
+			}
+			continue
+		} else if err != nil {
 			log.Error(err, "Failed to get FalconNodeSensor resource")
 			return err
 		}
 
 		if !reflect.DeepEqual(newNodeSensor.Spec, existingNodeSensor.Spec) {
 			existingNodeSensor.Spec = newNodeSensor.Spec
-			updated = true
-		}
-
-		if updated {
 			if err := r.update(ctx, log, falconDeployment, existingNodeSensor); err != nil {
 				return err
 			}
 		}
-	} else if len(nodeSensorList.Items) != 0 {
-		err := r.Client.Get(ctx, types.NamespacedName{Name: existingNodeSensor.Name, Namespace: existingNodeSensor.Namespace}, existingNodeSensor)
-		if err != nil {
-			log.Error(err, "Failed to get FalconNodeSensor resource")
-			return err
-		}
-		return r.delete(ctx, log, falconDeployment, existingNodeSensor)
 	}
 
 	return nil
@@ -310,6 +347,13 @@ func (r *FalconDeploymentReconciler) reconcileImageAnalyzer(ctx context.Context,
 			return fmt.Errorf("unable to merge specs for FalconImageAnalyzer: %v", err)
 		}
 
+		if train := falconDeployment.Status.ReleaseTrain; train != nil {
+			if version := coordinatedVersion(falconDeployment.Spec.FalconImageAnalyzer.Version, train.ImageAnalyzer); version != nil {
+				newImageAnalyzer.Spec.Version = version
+				newImageAnalyzer.Spec.Advanced = falconv1alpha1.FalconAdvanced{}
+			}
+		}
+
 		if len(imageAnalyzerList.Items) == 0 {
 			if err := ctrl.SetControllerReference(falconDeployment, newImageAnalyzer, r.Scheme); err != nil {
 				return fmt.Errorf("unable to set controller reference for %s: %v", newImageAnalyzer.Name, err)
@@ -376,6 +420,13 @@ func (r *FalconDeploymentReconciler) reconcileContainerSensor(ctx context.Contex
 			return fmt.Errorf("unable to merge specs for FalconContainerSensor: %v", err)
 		}
 
+		if train := falconDeployment.Status.ReleaseTrain; train != nil {
+			if version := coordinatedVersion(falconDeployment.Spec.FalconContainerSensor.Version, train.ContainerSensor); version != nil {
+				newContainerSensor.Spec.Version = version
+				newContainerSensor.Spec.Advanced = falconv1alpha1.FalconAdvanced{}
+			}
+		}
+
 		if len(containerSensorList.Items) == 0 {
 			if err := ctrl.SetControllerReference(falconDeployment, newContainerSensor, r.Scheme); err != nil {
 				return fmt.Errorf("unable to set controller reference for %s: %v", newContainerSensor.Name, err)
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensorversion"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -174,9 +175,13 @@ var _ = Describe("FalconDeployment Controller", func() {
 			}, time.Minute, time.Second).Should(Succeed())
 
 			By("Reconciling the FalconDeployment custom resource created")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconDeploymentReconciler := &FalconDeploymentReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			_, err = falconDeploymentReconciler.Reconcile(ctx, reconcile.Request{
@@ -0,0 +1,77 @@
+package falcon
+
+import (
+	"fmt"
+
+	"dario.cat/mergo"
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// nodeSensorName is the name of the FalconNodeSensor deployed to all nodes, and the prefix of the FalconNodeSensors deployed for node sensor profiles.
+const nodeSensorName = "falcon-node-sensor"
+
+// desiredNodeSensors returns the FalconNodeSensors to deploy: one per node sensor profile, or a single FalconNodeSensor for all nodes when no profile is configured.
+func desiredNodeSensors(falconDeployment *falconv1alpha1.FalconDeployment) ([]*falconv1alpha1.FalconNodeSensor, error) {
+	nodeSensor := &falconv1alpha1.FalconNodeSensor{}
+	nodeSensor.Spec.FalconAPI = falconDeployment.Spec.FalconAPI
+	nodeSensor.Spec.FalconSecret = falconDeployment.Spec.FalconSecret
+	nodeSensor.ObjectMeta = metav1.ObjectMeta{
+		Name:      nodeSensorName,
+		Namespace: falconDeployment.Spec.FalconNodeSensor.InstallNamespace,
+	}
+
+	if err := mergo.Merge(&nodeSensor.Spec, falconDeployment.Spec.FalconNodeSensor, mergo.WithOverride); err != nil {
+		return nil, fmt.Errorf("unable to merge specs for FalconNodeSensor: %v", err)
+	}
+
+	if train := falconDeployment.Status.ReleaseTrain; train != nil {
+		if version := coordinatedVersion(falconDeployment.Spec.FalconNodeSensor.Node.Version, train.NodeSensor); version != nil {
+			nodeSensor.Spec.Node.Version = version
+			nodeSensor.Spec.Node.Advanced = falconv1alpha1.FalconAdvanced{}
+		}
+	}
+
+	if len(falconDeployment.Spec.NodeSensorProfiles) == 0 {
+		return []*falconv1alpha1.FalconNodeSensor{nodeSensor}, nil
+	}
+
+	nodeSensors := make([]*falconv1alpha1.FalconNodeSensor, 0, len(falconDeployment.Spec.NodeSensorProfiles))
+	for _, profile := range falconDeployment.Spec.NodeSensorProfiles {
+		profileNodeSensor := nodeSensor.DeepCopy()
+		profileNodeSensor.Name = fmt.Sprintf("%s-%s", nodeSensorName, profile.Name)
+		applyNodeSensorProfile(&profileNodeSensor.Spec, profile)
+		nodeSensors = append(nodeSensors, profileNodeSensor)
+	}
+
+	return nodeSensors, nil
+}
+
+// applyNodeSensorProfile restricts the FalconNodeSensor to the nodes of the profile and replaces the settings configured by the profile.
+func applyNodeSensorProfile(spec *falconv1alpha1.FalconNodeSensorSpec, profile falconv1alpha1.FalconNodeSensorProfile) {
+	spec.Node.NodeSelector = profile.NodeSelector
+
+	if profile.Tolerations != nil {
+		spec.Node.Tolerations = profile.Tolerations
+	}
+
+	if profile.Image != "" {
+		spec.Node.Image = profile.Image
+	}
+
+	if profile.Resources != nil {
+		spec.Node.SensorResources = *profile.Resources
+	}
+
+	if profile.Tags != nil {
+		spec.Falcon.Tags = profile.Tags
+	}
+
+	if profile.TagTemplates != nil {
+		spec.Node.TagTemplates = profile.TagTemplates
+	}
+
+	if profile.Trace != "" {
+		spec.Falcon.Trace = profile.Trace
+	}
+}
@@ -0,0 +1,94 @@
+package falcon
+
+import (
+	"testing"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDesiredNodeSensors(t *testing.T) {
+	baseTolerations := []corev1.Toleration{{Key: "node-role.kubernetes.io/control-plane", Operator: corev1.TolerationOpExists}}
+	newFalconDeployment := func() *falconv1alpha1.FalconDeployment {
+		falconDeployment := &falconv1alpha1.FalconDeployment{}
+		falconDeployment.Spec.FalconAPI = &falconv1alpha1.FalconAPI{ClientId: "clientId"}
+		falconDeployment.Spec.FalconNodeSensor.InstallNamespace = "falcon-system"
+		falconDeployment.Spec.FalconNodeSensor.Node.Tolerations = &baseTolerations
+		falconDeployment.Spec.FalconNodeSensor.Falcon.Tags = []string{"base"}
+		falconDeployment.Spec.FalconNodeSensor.Falcon.Trace = "none"
+		return falconDeployment
+	}
+
+	t.Run("should deploy a single node sensor without profiles", func(t *testing.T) {
+		nodeSensors, err := desiredNodeSensors(newFalconDeployment())
+		require.NoError(t, err)
+		require.Len(t, nodeSensors, 1)
+
+		assert.Equal(t, "falcon-node-sensor", nodeSensors[0].Name)
+		assert.Equal(t, "clientId", nodeSensors[0].Spec.FalconAPI.ClientId)
+		assert.Empty(t, nodeSensors[0].Spec.Node.NodeSelector)
+		assert.Equal(t, []string{"base"}, nodeSensors[0].Spec.Falcon.Tags)
+	})
+
+	t.Run("should deploy one node sensor per profile", func(t *testing.T) {
+		gpuTolerations := []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}}
+		falconDeployment := newFalconDeployment()
+		falconDeployment.Spec.NodeSensorProfiles = []falconv1alpha1.FalconNodeSensorProfile{
+			{
+				Name:         "gpu",
+				NodeSelector: map[string]string{"pool": "gpu"},
+				Tolerations:  &gpuTolerations,
+				Resources:    &falconv1alpha1.Resources{Requests: falconv1alpha1.ResourceList{CPU: "500m"}},
+				Tags:         []string{"gpu"},
+				TagTemplates: []string{"zone/{{ .Zone }}"},
+				Trace:        "debug",
+			},
+			{
+				Name:         "general",
+				NodeSelector: map[string]string{"pool": "general"},
+				Image:        "example.com/falcon-sensor:7.31.0-18410-1",
+			},
+		}
+
+		nodeSensors, err := desiredNodeSensors(falconDeployment)
+		require.NoError(t, err)
+		require.Len(t, nodeSensors, 2)
+
+		gpu := nodeSensors[0]
+		assert.Equal(t, "falcon-node-sensor-gpu", gpu.Name)
+		assert.Equal(t, "clientId", gpu.Spec.FalconAPI.ClientId)
+		assert.Equal(t, map[string]string{"pool": "gpu"}, gpu.Spec.Node.NodeSelector)
+		assert.Equal(t, &gpuTolerations, gpu.Spec.Node.Tolerations)
+		assert.Equal(t, "500m", gpu.Spec.Node.SensorResources.Requests.CPU)
+		assert.Equal(t, []string{"gpu"}, gpu.Spec.Falcon.Tags)
+		assert.Equal(t, []string{"zone/{{ .Zone }}"}, gpu.Spec.Node.TagTemplates)
+		assert.Equal(t, "debug", gpu.Spec.Falcon.Trace)
+		assert.Empty(t, gpu.Spec.Node.Image)
+
+		general := nodeSensors[1]
+		assert.Equal(t, "falcon-node-sensor-general", general.Name)
+		assert.Equal(t, map[string]string{"pool": "general"}, general.Spec.Node.NodeSelector)
+		assert.Equal(t, baseTolerations, *general.Spec.Node.Tolerations)
+		assert.Equal(t, "example.com/falcon-sensor:7.31.0-18410-1", general.Spec.Node.Image)
+		assert.Equal(t, []string{"base"}, general.Spec.Falcon.Tags)
+		assert.Equal(t, "none", general.Spec.Falcon.Trace)
+
+		assert.Equal(t, []string{"base"}, falconDeployment.Spec.FalconNodeSensor.Falcon.Tags, "profiles must not modify the FalconDeployment spec")
+	})
+
+	t.Run("should pin the release train in every profile", func(t *testing.T) {
+		falconDeployment := newFalconDeployment()
+		falconDeployment.Status.ReleaseTrain = &falconv1alpha1.FalconReleaseTrain{Version: "7.31", NodeSensor: "7.31.0-18410-1"}
+		falconDeployment.Spec.NodeSensorProfiles = []falconv1alpha1.FalconNodeSensorProfile{
+			{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+		}
+
+		nodeSensors, err := desiredNodeSensors(falconDeployment)
+		require.NoError(t, err)
+		require.Len(t, nodeSensors, 1)
+		require.NotNil(t, nodeSensors[0].Spec.Node.Version)
+		assert.Equal(t, "7.31.0-18410-1", *nodeSensors[0].Spec.Node.Version)
+	})
+}
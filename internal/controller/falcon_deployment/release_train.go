@@ -0,0 +1,225 @@
+package falcon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensor"
+	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensorversion"
+	"github.com/crowdstrike/gofalcon/falcon"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// sensorImages resolves sensor versions and image tags from the CrowdStrike registry and update policies.
+type sensorImages interface {
+	GetReleaseTrain(ctx context.Context, versionSpec *string, updatePolicySpec *string) (string, error)
+	GetPreferredImage(ctx context.Context, sensorType falcon.SensorType, versionSpec *string, updatePolicySpec *string) (string, error)
+}
+
+// reconcileReleaseTrain resolves the sensor release train once per FalconDeployment generation and records it in the status.
+// Child specs are pinned to the recorded versions, so all components upgrade together.
+func (r *FalconDeploymentReconciler) reconcileReleaseTrain(ctx context.Context, req ctrl.Request, log logr.Logger, falconDeployment *falconv1alpha1.FalconDeployment) error {
+	if !falconDeployment.Spec.HasCoordinatedVersions() {
+		r.tracker.StopTracking(releaseTrainTrackKey(req.NamespacedName))
+
+		if falconDeployment.Status.ReleaseTrain == nil {
+			return nil
+		}
+
+		return r.setReleaseTrain(ctx, req.NamespacedName, falconDeployment, nil)
+	}
+
+	apiConfig, err := falconDeployment.Spec.FalconAPI.ApiConfigWithSecret(ctx, r.Reader, falconDeployment.Spec.FalconSecret)
+	if err != nil {
+		return err
+	}
+
+	if falconDeployment.Spec.Advanced.IsAutoUpdating() {
+		getSensorVersion := sensorversion.NewFalconCloudQuery(falcon.NodeSensor, apiConfig)
+		r.tracker.Track(releaseTrainTrackKey(req.NamespacedName), falcon.NodeSensor, getSensorVersion, r.refreshReleaseTrain, falconDeployment.Spec.Advanced.IsAutoUpdatingForced())
+	} else {
+		r.tracker.StopTracking(releaseTrainTrackKey(req.NamespacedName))
+	}
+
+	current := falconDeployment.Status.ReleaseTrain
+	if current != nil && current.ObservedGeneration == falconDeployment.GetGeneration() {
+		return nil
+	}
+
+	images, err := sensor.NewImageRepository(ctx, apiConfig)
+	if err != nil {
+		return err
+	}
+
+	train, warnings, err := resolveReleaseTrain(ctx, images, falconDeployment.Spec, current)
+	if err != nil {
+		reason := falconv1alpha1.ReasonReqNotMet
+		if policyErr := (*sensor.UpdatePolicyError)(nil); errors.As(err, &policyErr) {
+			reason = policyErr.Reason
+		}
+
+		if statusErr := r.statusUpdate(ctx, req, log, falconDeployment, falconv1alpha1.ConditionReleaseTrainReady,
+			metav1.ConditionFalse,
+			reason,
+			fmt.Sprintf("Unable to resolve sensor release train: %v", err)); statusErr != nil {
+			return statusErr
+		}
+
+		return err
+	}
+
+	train.ObservedGeneration = falconDeployment.GetGeneration()
+	train.ResolvedAt = metav1.Now()
+
+	if current == nil || current.Version != train.Version {
+		log.Info("selected sensor release train", "releaseTrain", train.Version, "source", train.Source)
+	}
+
+	if err := r.setReleaseTrain(ctx, req.NamespacedName, falconDeployment, train); err != nil {
+		return err
+	}
+
+	if len(warnings) > 0 {
+		log.Info("some components were kept on their current version", "releaseTrain", train.Version, "components", warnings)
+		return r.statusUpdate(ctx, req, log, falconDeployment, falconv1alpha1.ConditionReleaseTrainReady,
+			metav1.ConditionFalse,
+			falconv1alpha1.ReasonReleaseTrainDegraded,
+			fmt.Sprintf("Sensor release train %s selected from %s, but %s", train.Version, train.Source, strings.Join(warnings, "; ")))
+	}
+
+	return r.statusUpdate(ctx, req, log, falconDeployment, falconv1alpha1.ConditionReleaseTrainReady,
+		metav1.ConditionTrue,
+		falconv1alpha1.ReasonReqMet,
+		fmt.Sprintf("Sensor release train %s selected from %s", train.Version, train.Source))
+}
+
+// resolveReleaseTrain selects a single release train from the node sensor and a matching version of every deployed component.
+// The admission controller, container sensor and image analyzer have their own version lines, so a component that cannot be resolved
+// keeps its version from the current release train and is reported in the returned warnings rather than failing the other components.
+func resolveReleaseTrain(ctx context.Context, images sensorImages, spec falconv1alpha1.FalconDeploymentSpec, current *falconv1alpha1.FalconReleaseTrain) (*falconv1alpha1.FalconReleaseTrain, []string, error) {
+	version, err := images.GetReleaseTrain(ctx, spec.Version, spec.Advanced.UpdatePolicy)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	train := &falconv1alpha1.FalconReleaseTrain{
+		Version: version,
+		Source:  releaseTrainSource(spec),
+	}
+	if current == nil {
+		current = &falconv1alpha1.FalconReleaseTrain{}
+	}
+
+	// Match on the trailing dot so that release 7.3 does not select 7.31 tags
+	versionPrefix := version + "."
+
+	if isEnabled(spec.DeployNodeSensor) {
+		if train.NodeSensor, err = images.GetPreferredImage(ctx, falcon.NodeSensor, &versionPrefix, nil); err != nil {
+			return nil, nil, fmt.Errorf("unable to resolve FalconNodeSensor version for release train %s: %w", version, err)
+		}
+	}
+
+	var warnings []string
+	resolve := func(kind string, deployed *bool, currentVersion string, resolveVersion func() (string, error)) string {
+		if !isEnabled(deployed) {
+			return ""
+		}
+
+		resolved, err := resolveVersion()
+		if err == nil {
+			return resolved
+		}
+
+		kept := currentVersion
+		if kept == "" {
+			kept = "its configured version"
+		}
+		warnings = append(warnings, fmt.Sprintf("%s kept on %s: %v", kind, kept, err))
+		return currentVersion
+	}
+
+	train.Admission = resolve("FalconAdmission", spec.DeployAdmissionController, current.Admission, func() (string, error) {
+		return resolveComponentVersion(ctx, images, falcon.KacSensor, versionPrefix)
+	})
+	train.ContainerSensor = resolve("FalconContainer", spec.DeployContainerSensor, current.ContainerSensor, func() (string, error) {
+		return resolveComponentVersion(ctx, images, falcon.SidecarSensor, versionPrefix)
+	})
+
+	// The image analyzer does not follow the sensor release numbering, so the latest version is pinned instead
+	train.ImageAnalyzer = resolve("FalconImageAnalyzer", spec.DeployImageAnalyzer, current.ImageAnalyzer, func() (string, error) {
+		return images.GetPreferredImage(ctx, falcon.ImageSensor, nil, nil)
+	})
+
+	return train, warnings, nil
+}
+
+// resolveComponentVersion selects the version of the admission controller or container sensor for the release train.
+// The release of the node sensor is preferred, and the latest version of the component is used when it has no image for that release.
+func resolveComponentVersion(ctx context.Context, images sensorImages, sensorType falcon.SensorType, versionPrefix string) (string, error) {
+	if tag, err := images.GetPreferredImage(ctx, sensorType, &versionPrefix, nil); err == nil {
+		return tag, nil
+	}
+
+	return images.GetPreferredImage(ctx, sensorType, nil, nil)
+}
+
+func releaseTrainSource(spec falconv1alpha1.FalconDeploymentSpec) string {
+	switch {
+	case spec.Version != nil && *spec.Version != "":
+		return falconv1alpha1.ReleaseTrainSourceVersion
+	case spec.Advanced.HasUpdatePolicy():
+		return falconv1alpha1.ReleaseTrainSourceUpdatePolicy
+	default:
+		return falconv1alpha1.ReleaseTrainSourceLatest
+	}
+}
+
+// coordinatedVersion returns the release train version for a component, or nil when the component has its own version configured.
+func coordinatedVersion(configured *string, pinned string) *string {
+	if pinned == "" || (configured != nil && *configured != "") {
+		return nil
+	}
+
+	return &pinned
+}
+
+func (r *FalconDeploymentReconciler) setReleaseTrain(ctx context.Context, name types.NamespacedName, falconDeployment *falconv1alpha1.FalconDeployment, train *falconv1alpha1.FalconReleaseTrain) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := r.Get(ctx, name, falconDeployment); err != nil {
+			return err
+		}
+
+		falconDeployment.Status.ReleaseTrain = train
+		return r.Status().Update(ctx, falconDeployment)
+	})
+}
+
+// refreshReleaseTrain is called by the sensor version tracker when a new sensor version becomes available.
+func (r *FalconDeploymentReconciler) refreshReleaseTrain(ctx context.Context, name types.NamespacedName, _ string) error {
+	falconDeployment := &falconv1alpha1.FalconDeployment{}
+	if err := r.setReleaseTrain(ctx, name, falconDeployment, nil); err != nil {
+		return err
+	}
+
+	clog.FromContext(ctx).Info("refreshing FalconDeployment release train", "name", falconDeployment.Name)
+	r.reconcileObject(falconDeployment)
+	return nil
+}
+
+// releaseTrainTrackKey identifies the sensor version track registered for the release train of a FalconDeployment.
+// It never matches the tracks of the child resources, which may share the FalconDeployment name.
+func releaseTrainTrackKey(name types.NamespacedName) sensorversion.Key {
+	return sensorversion.NewKey(falconv1alpha1.GroupVersion.WithKind("FalconDeployment"), name)
+}
+
+func isEnabled(value *bool) bool {
+	return value != nil && *value
+}
@@ -0,0 +1,188 @@
+package falcon
+
+import (
+	"context"
+	"testing"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/gofalcon/falcon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSensorImages struct {
+	train        string
+	tags         map[falcon.SensorType]string
+	err          error
+	requested    map[falcon.SensorType]*string
+	trainRequest []*string
+	// latestOnly lists the components that have no image for the release train
+	latestOnly map[falcon.SensorType]bool
+	// unavailable lists the components whose version cannot be resolved
+	unavailable map[falcon.SensorType]bool
+}
+
+func (f *fakeSensorImages) GetReleaseTrain(_ context.Context, versionSpec *string, updatePolicySpec *string) (string, error) {
+	f.trainRequest = []*string{versionSpec, updatePolicySpec}
+	return f.train, f.err
+}
+
+func (f *fakeSensorImages) GetPreferredImage(_ context.Context, sensorType falcon.SensorType, versionSpec *string, _ *string) (string, error) {
+	if f.requested == nil {
+		f.requested = make(map[falcon.SensorType]*string)
+	}
+	f.requested[sensorType] = versionSpec
+
+	if f.unavailable[sensorType] || (versionSpec != nil && f.latestOnly[sensorType]) {
+		return "", assert.AnError
+	}
+	return f.tags[sensorType], nil
+}
+
+func TestResolveReleaseTrain(t *testing.T) {
+	ctx := context.Background()
+	enabled := true
+	disabled := false
+
+	t.Run("should pin all deployed components to one release train", func(t *testing.T) {
+		images := &fakeSensorImages{
+			train: "7.31",
+			tags: map[falcon.SensorType]string{
+				falcon.NodeSensor:    "7.31.0-18410-1",
+				falcon.KacSensor:     "7.31.0-2003",
+				falcon.SidecarSensor: "7.31.0-6001.container.x86_64.Release.US-1",
+				falcon.ImageSensor:   "1.0.24",
+			},
+		}
+		policy := "somePolicy"
+		spec := falconv1alpha1.FalconDeploymentSpec{
+			DeployNodeSensor:          &enabled,
+			DeployAdmissionController: &enabled,
+			DeployContainerSensor:     &enabled,
+			DeployImageAnalyzer:       &enabled,
+			Advanced:                  falconv1alpha1.FalconAdvanced{UpdatePolicy: &policy},
+		}
+
+		train, warnings, err := resolveReleaseTrain(ctx, images, spec, nil)
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+
+		assert.Equal(t, "7.31", train.Version)
+		assert.Equal(t, falconv1alpha1.ReleaseTrainSourceUpdatePolicy, train.Source)
+		assert.Equal(t, "7.31.0-18410-1", train.NodeSensor)
+		assert.Equal(t, "7.31.0-2003", train.Admission)
+		assert.Equal(t, "7.31.0-6001.container.x86_64.Release.US-1", train.ContainerSensor)
+		assert.Equal(t, "1.0.24", train.ImageAnalyzer)
+
+		assert.Equal(t, &policy, images.trainRequest[1])
+		assert.Equal(t, "7.31.", *images.requested[falcon.NodeSensor])
+		assert.Equal(t, "7.31.", *images.requested[falcon.KacSensor])
+		assert.Equal(t, "7.31.", *images.requested[falcon.SidecarSensor])
+		assert.Nil(t, images.requested[falcon.ImageSensor])
+	})
+
+	t.Run("should skip components that are not deployed", func(t *testing.T) {
+		images := &fakeSensorImages{
+			train: "7.30",
+			tags:  map[falcon.SensorType]string{falcon.NodeSensor: "7.30.0-18306-1"},
+		}
+		version := "7.30"
+		spec := falconv1alpha1.FalconDeploymentSpec{
+			Version:                   &version,
+			DeployNodeSensor:          &enabled,
+			DeployAdmissionController: &disabled,
+			DeployContainerSensor:     &disabled,
+			DeployImageAnalyzer:       nil,
+		}
+
+		train, warnings, err := resolveReleaseTrain(ctx, images, spec, nil)
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+
+		assert.Equal(t, falconv1alpha1.ReleaseTrainSourceVersion, train.Source)
+		assert.Equal(t, "7.30.0-18306-1", train.NodeSensor)
+		assert.Empty(t, train.Admission)
+		assert.Empty(t, train.ContainerSensor)
+		assert.Empty(t, train.ImageAnalyzer)
+		assert.Len(t, images.requested, 1)
+	})
+
+	t.Run("should return release train errors", func(t *testing.T) {
+		images := &fakeSensorImages{err: assert.AnError}
+
+		_, _, err := resolveReleaseTrain(ctx, images, falconv1alpha1.FalconDeploymentSpec{}, nil)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("should use the latest version of components without an image for the release train", func(t *testing.T) {
+		images := &fakeSensorImages{
+			train: "7.31",
+			tags: map[falcon.SensorType]string{
+				falcon.NodeSensor: "7.31.0-18410-1",
+				falcon.KacSensor:  "7.29.0-1903",
+			},
+			latestOnly: map[falcon.SensorType]bool{falcon.KacSensor: true},
+		}
+		spec := falconv1alpha1.FalconDeploymentSpec{
+			DeployNodeSensor:          &enabled,
+			DeployAdmissionController: &enabled,
+		}
+
+		train, warnings, err := resolveReleaseTrain(ctx, images, spec, nil)
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+		assert.Equal(t, "7.31.0-18410-1", train.NodeSensor)
+		assert.Equal(t, "7.29.0-1903", train.Admission)
+		assert.Nil(t, images.requested[falcon.KacSensor])
+	})
+
+	t.Run("should keep components that cannot be resolved on their current version", func(t *testing.T) {
+		images := &fakeSensorImages{
+			train: "7.31",
+			tags: map[falcon.SensorType]string{
+				falcon.NodeSensor:  "7.31.0-18410-1",
+				falcon.ImageSensor: "1.0.24",
+			},
+			unavailable: map[falcon.SensorType]bool{falcon.KacSensor: true, falcon.SidecarSensor: true},
+		}
+		spec := falconv1alpha1.FalconDeploymentSpec{
+			DeployNodeSensor:          &enabled,
+			DeployAdmissionController: &enabled,
+			DeployContainerSensor:     &enabled,
+			DeployImageAnalyzer:       &enabled,
+		}
+		current := &falconv1alpha1.FalconReleaseTrain{Version: "7.30", Admission: "7.30.0-2001"}
+
+		train, warnings, err := resolveReleaseTrain(ctx, images, spec, current)
+		require.NoError(t, err)
+		assert.Equal(t, "7.31.0-18410-1", train.NodeSensor)
+		assert.Equal(t, "7.30.0-2001", train.Admission)
+		assert.Empty(t, train.ContainerSensor)
+		assert.Equal(t, "1.0.24", train.ImageAnalyzer)
+		if assert.Len(t, warnings, 2) {
+			assert.Contains(t, warnings[0], "FalconAdmission kept on 7.30.0-2001")
+			assert.Contains(t, warnings[1], "FalconContainer kept on its configured version")
+		}
+	})
+
+	t.Run("should fail when the node sensor cannot be resolved", func(t *testing.T) {
+		images := &fakeSensorImages{
+			train:       "7.31",
+			unavailable: map[falcon.SensorType]bool{falcon.NodeSensor: true},
+		}
+		spec := falconv1alpha1.FalconDeploymentSpec{DeployNodeSensor: &enabled}
+
+		_, _, err := resolveReleaseTrain(ctx, images, spec, nil)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestCoordinatedVersion(t *testing.T) {
+	configured := "7.29.0"
+	empty := ""
+
+	assert.Equal(t, "7.31.0-18410-1", *coordinatedVersion(nil, "7.31.0-18410-1"))
+	assert.Equal(t, "7.31.0-18410-1", *coordinatedVersion(&empty, "7.31.0-18410-1"))
+	assert.Nil(t, coordinatedVersion(&configured, "7.31.0-18410-1"))
+	assert.Nil(t, coordinatedVersion(nil, ""))
+}
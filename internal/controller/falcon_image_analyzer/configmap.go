@@ -26,8 +26,11 @@ const (
 	agentMaxConsumerThreads = "1"
 )
 
-func (r *FalconImageAnalyzerReconciler) reconcileConfigMap(ctx context.Context, req ctrl.Request, log logr.Logger, falconImageAnalyzer *falconv1alpha1.FalconImageAnalyzer) (bool, error) {
-	return r.reconcileGenericConfigMap(falconImageAnalyzer.Name+"-config", r.newConfigMap, ctx, req, log, falconImageAnalyzer)
+func (r *FalconImageAnalyzerReconciler) reconcileConfigMap(ctx context.Context, req ctrl.Request, log logr.Logger, falconImageAnalyzer *falconv1alpha1.FalconImageAnalyzer, clusterName string) (bool, error) {
+	newConfigMap := func(ctx context.Context, name string, falconImageAnalyzer *falconv1alpha1.FalconImageAnalyzer) (*corev1.ConfigMap, error) {
+		return r.newConfigMap(ctx, name, falconImageAnalyzer, clusterName)
+	}
+	return r.reconcileGenericConfigMap(falconImageAnalyzer.Name+"-config", newConfigMap, ctx, req, log, falconImageAnalyzer)
 }
 
 func (r *FalconImageAnalyzerReconciler) reconcileGenericConfigMap(name string, genFunc func(context.Context, string, *falconv1alpha1.FalconImageAnalyzer) (*corev1.ConfigMap, error), ctx context.Context, req ctrl.Request, log logr.Logger, falconImageAnalyzer *falconv1alpha1.FalconImageAnalyzer) (bool, error) {
@@ -62,7 +65,7 @@ func (r *FalconImageAnalyzerReconciler) reconcileGenericConfigMap(name string, g
 
 }
 
-func (r *FalconImageAnalyzerReconciler) newConfigMap(ctx context.Context, name string, falconImageAnalyzer *falconv1alpha1.FalconImageAnalyzer) (*corev1.ConfigMap, error) {
+func (r *FalconImageAnalyzerReconciler) newConfigMap(ctx context.Context, name string, falconImageAnalyzer *falconv1alpha1.FalconImageAnalyzer, clusterName string) (*corev1.ConfigMap, error) {
 	var err error
 	data := map[string]string{}
 	cid := ""
@@ -88,8 +91,8 @@ func (r *FalconImageAnalyzerReconciler) newConfigMap(ctx context.Context, name s
 	// cloud region is required
 	data["AGENT_REGION"] = falcon.Cloud(falconImageAnalyzer.Spec.FalconAPI.CloudRegion).String()
 
-	if falconImageAnalyzer.Spec.ImageAnalyzerConfig.ClusterName != "" {
-		data["AGENT_CLUSTER_NAME"] = falconImageAnalyzer.Spec.ImageAnalyzerConfig.ClusterName
+	if clusterName != "" {
+		data["AGENT_CLUSTER_NAME"] = clusterName
 	}
 
 	if len(falconImageAnalyzer.Spec.ImageAnalyzerConfig.RegistryConfig.Credentials) > 0 {
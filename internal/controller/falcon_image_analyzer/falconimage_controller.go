@@ -2,25 +2,30 @@ package falcon
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
 	"os"
 	"reflect"
 	"strconv"
 	"time"
 
+	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
 	"github.com/crowdstrike/falcon-operator/internal/controller/assets"
 	k8sutils "github.com/crowdstrike/falcon-operator/internal/controller/common"
+	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensorversion"
 	"github.com/crowdstrike/falcon-operator/pkg/aws"
 	"github.com/crowdstrike/falcon-operator/pkg/common"
 	"github.com/crowdstrike/falcon-operator/pkg/registry/pulltoken"
 	"github.com/crowdstrike/falcon-operator/pkg/tls"
 	"github.com/crowdstrike/falcon-operator/version"
+	"github.com/crowdstrike/gofalcon/falcon"
 	"github.com/go-logr/logr"
 	imagev1 "github.com/openshift/api/image/v1"
 	"github.com/operator-framework/operator-lib/proxy"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -38,23 +43,43 @@ import (
 // FalconImageAnalyzerReconciler reconciles a FalconImageAnalyzer object
 type FalconImageAnalyzerReconciler struct {
 	client.Client
-	Reader    client.Reader
-	Scheme    *runtime.Scheme
-	OpenShift bool
+	Reader          client.Reader
+	Scheme          *runtime.Scheme
+	OpenShift       bool
+	CertManager     bool
+	reconcileObject func(client.Object)
+	tracker         sensorversion.Tracker
 }
 
 // SetupWithManager sets up the controller with the Manager.
-func (r *FalconImageAnalyzerReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+func (r *FalconImageAnalyzerReconciler) SetupWithManager(mgr ctrl.Manager, tracker sensorversion.Tracker) error {
+	imageAnalyzerBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&falconv1alpha1.FalconImageAnalyzer{}).
 		Owns(&corev1.Namespace{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.Secret{}).
 		Owns(&corev1.Service{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Owns(&corev1.ServiceAccount{}).
-		Owns(&rbacv1.ClusterRoleBinding{}).
-		Complete(r)
+		Owns(&rbacv1.ClusterRoleBinding{})
+	// Reconcile when cert-manager issues or renews the IAR Agent certificate
+	if r.CertManager {
+		imageAnalyzerBuilder = imageAnalyzerBuilder.Owns(&certv1.Certificate{})
+	}
+
+	imageAnalyzerController, err := imageAnalyzerBuilder.Build(r)
+	if err != nil {
+		return err
+	}
+
+	r.reconcileObject, err = k8sutils.NewReconcileTrigger(imageAnalyzerController)
+	if err != nil {
+		return err
+	}
+
+	r.tracker = tracker
+	return nil
 }
 
 func (r *FalconImageAnalyzerReconciler) GetK8sClient() client.Client {
@@ -75,7 +100,10 @@ func (r *FalconImageAnalyzerReconciler) GetK8sReader() client.Reader {
 //+kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update;delete
 //+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;update
 //+kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups="policy",resources=poddisruptionbudgets,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups="cert-manager.io",resources=certificates,verbs=get;list;watch;create;update;delete
 //+kubebuilder:rbac:groups="security.openshift.io",resources=securitycontextconstraints,resourceNames=privileged,verbs=use
+//+kubebuilder:rbac:groups="config.openshift.io",resources=infrastructures,verbs=get
 //+kubebuilder:rbac:groups="image.openshift.io",resources=imagestreams,verbs=get;list;watch;create;update;delete
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=create;get;list;update;watch;delete
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=create;get;list;update;watch;delete
@@ -99,6 +127,8 @@ func (r *FalconImageAnalyzerReconciler) Reconcile(ctx context.Context, req ctrl.
 	err := r.Get(ctx, req.NamespacedName, falconImageAnalyzer)
 	if err != nil {
 		if errors.IsNotFound(err) {
+			r.tracker.StopTracking(sensorVersionTrackKey(req.NamespacedName))
+
 			// If the custom resource is not found then, it usually means that it was deleted or not created
 			// In this way, we will stop the reconciliation
 			log.Info("FalconImageAnalyzer resource not found. Ignoring since object must be deleted")
@@ -169,6 +199,49 @@ func (r *FalconImageAnalyzerReconciler) Reconcile(ctx context.Context, req ctrl.
 		return ctrl.Result{}, err
 	}
 
+	if shouldTrackSensorVersions(falconImageAnalyzer) {
+		falconApiConfig, err := r.falconApiConfig(ctx, falconImageAnalyzer)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		getSensorVersion := sensorversion.NewPreferredImageQuery(falcon.ImageSensor, falconApiConfig, falconImageAnalyzer.Spec.Version, nil, nil)
+		r.tracker.TrackWithInitialVersion(sensorVersionTrackKey(req.NamespacedName), falcon.ImageSensor, getSensorVersion, r.handleSensorVersion, falconImageAnalyzer.Spec.Advanced.IsAutoUpdatingForced())
+	} else {
+		r.tracker.StopTracking(sensorVersionTrackKey(req.NamespacedName))
+	}
+
+	if falconImageAnalyzer.Status.AvailableUpdate != nil &&
+		(!shouldTrackSensorVersions(falconImageAnalyzer) || sensorversion.IsDeployed(falconImageAnalyzer.Status.AvailableUpdate, falconImageAnalyzer.Status.Sensor)) {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			err := r.Get(ctx, req.NamespacedName, falconImageAnalyzer)
+			if err != nil {
+				return err
+			}
+
+			falconImageAnalyzer.Status.AvailableUpdate = nil
+			return r.Status().Update(ctx, falconImageAnalyzer)
+		})
+		if err != nil {
+			log.Error(err, "Failed to update FalconImageAnalyzer status for falconImageAnalyzer.Status.AvailableUpdate")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Sensor update policies select Falcon sensor versions, which never match the image analyzer release numbering
+	if falconImageAnalyzer.Spec.Advanced.HasUpdatePolicy() {
+		condition := metav1.Condition{
+			Type:               falconv1alpha1.ConditionUpdatePolicyReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             falconv1alpha1.ReasonUpdatePolicyUnsupported,
+			Message:            "Sensor update policies do not apply to the Falcon Image Analyzer, advanced.updatePolicy is ignored",
+			ObservedGeneration: falconImageAnalyzer.GetGeneration(),
+		}
+		if err := k8sutils.ConditionsUpdateWithReason(r.Client, ctx, req, log, falconImageAnalyzer, &falconImageAnalyzer.Status, condition); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Image being set will override other image based settings
 	if falconImageAnalyzer.Spec.Image != "" {
 		if _, err := r.setImageTag(ctx, falconImageAnalyzer); err != nil {
@@ -224,19 +297,27 @@ func (r *FalconImageAnalyzerReconciler) Reconcile(ctx context.Context, req ctrl.
 		return ctrl.Result{}, err
 	}
 
+	clusterName, err := r.resolveClusterName(ctx, req, log, falconImageAnalyzer)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	if falconImageAnalyzer.Spec.FalconSecret.Enabled {
 		if err = r.injectFalconSecretData(ctx, falconImageAnalyzer, log); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
 
-	configUpdated, err := r.reconcileConfigMap(ctx, req, log, falconImageAnalyzer)
+	configUpdated, err := r.reconcileConfigMap(ctx, req, log, falconImageAnalyzer, clusterName)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	_, err = r.reconcileIARTLSSecret(ctx, req, log, falconImageAnalyzer)
-	if err != nil {
+	iarTLSSecret, err := r.reconcileIARTLSSecret(ctx, req, log, falconImageAnalyzer)
+	if goerrors.Is(err, k8sutils.ErrCertificateNotIssued) {
+		log.Info(err.Error())
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	} else if err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -244,11 +325,15 @@ func (r *FalconImageAnalyzerReconciler) Reconcile(ctx context.Context, req ctrl.
 		return ctrl.Result{}, err
 	}
 
-	err = r.reconcileImageAnalyzerDeployment(ctx, req, log, falconImageAnalyzer)
+	err = r.reconcileImageAnalyzerDeployment(ctx, req, log, falconImageAnalyzer, tls.Fingerprint(iarTLSSecret.Data["tls.crt"]))
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcilePodDisruptionBudget(ctx, req, log, falconImageAnalyzer); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	if configUpdated || serviceAccountUpdateRequiresRestart {
 		err = r.imageAnalyzerDeploymentUpdate(ctx, req, log, falconImageAnalyzer)
 		if err != nil {
@@ -271,7 +356,7 @@ func (r *FalconImageAnalyzerReconciler) Reconcile(ctx context.Context, req ctrl.
 	return ctrl.Result{}, nil
 }
 
-func (r *FalconImageAnalyzerReconciler) reconcileImageAnalyzerDeployment(ctx context.Context, req ctrl.Request, log logr.Logger, falconImageAnalyzer *falconv1alpha1.FalconImageAnalyzer) error {
+func (r *FalconImageAnalyzerReconciler) reconcileImageAnalyzerDeployment(ctx context.Context, req ctrl.Request, log logr.Logger, falconImageAnalyzer *falconv1alpha1.FalconImageAnalyzer, certFingerprint string) error {
 	imageUri, err := r.imageUri(ctx, falconImageAnalyzer)
 	if err != nil {
 		return fmt.Errorf("unable to determine falcon container image URI: %v", err)
@@ -279,6 +364,8 @@ func (r *FalconImageAnalyzerReconciler) reconcileImageAnalyzerDeployment(ctx con
 
 	existingDeployment := &appsv1.Deployment{}
 	dep := assets.ImageAnalyzerDeployment(falconImageAnalyzer.Name, falconImageAnalyzer.Spec.InstallNamespace, common.FalconImageAnalyzer, imageUri, falconImageAnalyzer)
+	// Restart the pods when the IAR Agent Service certificate changes, such as when cert-manager renews it
+	dep.Spec.Template.Annotations[common.FalconTLSCertificateKey] = certFingerprint
 	updated := false
 
 	if len(proxy.ReadProxyVarsFromEnv()) > 0 {
@@ -320,6 +407,15 @@ func (r *FalconImageAnalyzerReconciler) reconcileImageAnalyzerDeployment(ctx con
 		}
 	}
 
+	if existingDeployment.Spec.Template.Annotations[common.FalconTLSCertificateKey] != certFingerprint {
+		log.V(1).Info("Updating FalconImageAnalyzer Deployment: TLS certificate changed")
+		if existingDeployment.Spec.Template.Annotations == nil {
+			existingDeployment.Spec.Template.Annotations = map[string]string{}
+		}
+		existingDeployment.Spec.Template.Annotations[common.FalconTLSCertificateKey] = certFingerprint
+		updated = true
+	}
+
 	if !reflect.DeepEqual(dep.Spec.Template.Spec.Containers[0].Image, existingDeployment.Spec.Template.Spec.Containers[0].Image) {
 		log.V(1).Info("Updating FalconImageAnalyzer Deployment: Container Image changed",
 			"old", existingDeployment.Spec.Template.Spec.Containers[0].Image,
@@ -360,11 +456,19 @@ func (r *FalconImageAnalyzerReconciler) reconcileImageAnalyzerDeployment(ctx con
 		updated = true
 	}
 
-	if !reflect.DeepEqual(existingDeployment.Spec.Template.Spec.Affinity.NodeAffinity, dep.Spec.Template.Spec.Affinity.NodeAffinity) {
-		log.V(1).Info("Updating FalconImageAnalyzer Deployment: NodeAffinity changed",
-			"old", existingDeployment.Spec.Template.Spec.Affinity.NodeAffinity,
-			"new", dep.Spec.Template.Spec.Affinity.NodeAffinity)
-		existingDeployment.Spec.Template.Spec.Affinity.NodeAffinity = dep.Spec.Template.Spec.Affinity.NodeAffinity
+	if !reflect.DeepEqual(existingDeployment.Spec.Template.Spec.Affinity, dep.Spec.Template.Spec.Affinity) {
+		log.V(1).Info("Updating FalconImageAnalyzer Deployment: Affinity changed",
+			"old", existingDeployment.Spec.Template.Spec.Affinity,
+			"new", dep.Spec.Template.Spec.Affinity)
+		existingDeployment.Spec.Template.Spec.Affinity = dep.Spec.Template.Spec.Affinity
+		updated = true
+	}
+
+	if !reflect.DeepEqual(existingDeployment.Spec.Template.Spec.TopologySpreadConstraints, dep.Spec.Template.Spec.TopologySpreadConstraints) {
+		log.V(1).Info("Updating FalconImageAnalyzer Deployment: TopologySpreadConstraints changed",
+			"old", existingDeployment.Spec.Template.Spec.TopologySpreadConstraints,
+			"new", dep.Spec.Template.Spec.TopologySpreadConstraints)
+		existingDeployment.Spec.Template.Spec.TopologySpreadConstraints = dep.Spec.Template.Spec.TopologySpreadConstraints
 		updated = true
 	}
 
@@ -400,6 +504,27 @@ func (r *FalconImageAnalyzerReconciler) reconcileImageAnalyzerDeployment(ctx con
 	return nil
 }
 
+// reconcilePodDisruptionBudget creates, updates or removes the PodDisruptionBudget of the image analyzer Deployment
+func (r *FalconImageAnalyzerReconciler) reconcilePodDisruptionBudget(ctx context.Context, req ctrl.Request, log logr.Logger, falconImageAnalyzer *falconv1alpha1.FalconImageAnalyzer) error {
+	dep := &appsv1.Deployment{}
+	err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: falconImageAnalyzer.Name, Namespace: falconImageAnalyzer.Spec.InstallNamespace}, dep)
+	if err != nil {
+		log.Error(err, "Failed to get FalconImageAnalyzer Deployment")
+		return err
+	}
+
+	return k8sutils.ReconcilePodDisruptionBudget(ctx, r, falconImageAnalyzer, dep, common.FalconImageAnalyzer, falconImageAnalyzer.Spec.Availability,
+		func(obj client.Object) error {
+			return k8sutils.Create(r.Client, r.Scheme, ctx, req, log, falconImageAnalyzer, &falconImageAnalyzer.Status, obj)
+		},
+		func(obj client.Object) error {
+			return k8sutils.Update(r.Client, ctx, req, log, falconImageAnalyzer, &falconImageAnalyzer.Status, obj)
+		},
+		func(obj client.Object) error {
+			return k8sutils.Delete(r.Client, ctx, req, log, falconImageAnalyzer, &falconImageAnalyzer.Status, obj)
+		})
+}
+
 func (r *FalconImageAnalyzerReconciler) reconcileRegistrySecret(ctx context.Context, req ctrl.Request, log logr.Logger, falconImageAnalyzer *falconv1alpha1.FalconImageAnalyzer) error {
 	falconApiConfig, err := r.falconApiConfig(ctx, falconImageAnalyzer)
 	if err != nil {
@@ -520,6 +645,15 @@ func (r *FalconImageAnalyzerReconciler) imageAnalyzerDeploymentUpdate(ctx contex
 	return nil
 }
 
+// resolveClusterName returns the cluster name reported by the IAR, detecting it when it is not configured and detection is enabled,
+// and reports it with the ClusterNameReady condition
+func (r *FalconImageAnalyzerReconciler) resolveClusterName(ctx context.Context, req ctrl.Request, log logr.Logger, falconImageAnalyzer *falconv1alpha1.FalconImageAnalyzer) (string, error) {
+	config := falconImageAnalyzer.Spec.ImageAnalyzerConfig
+	clusterName, condition := k8sutils.ResolveClusterName(ctx, r.Reader, config.ClusterName, config.DetectClusterName)
+
+	return clusterName, k8sutils.ConditionsUpdateWithReason(r.Client, ctx, req, log, falconImageAnalyzer, &falconImageAnalyzer.Status, condition)
+}
+
 func (r *FalconImageAnalyzerReconciler) injectFalconSecretData(
 	ctx context.Context,
 	falconImageAnalyzer *falconv1alpha1.FalconImageAnalyzer,
@@ -527,7 +661,7 @@ func (r *FalconImageAnalyzerReconciler) injectFalconSecretData(
 ) error {
 	logger.Info("injecting Falcon secret data into Spec.Falcon and Spec.FalconAPI - sensitive manifest values will be overwritten with values in k8s secret")
 
-	return k8sutils.InjectFalconSecretData(ctx, r, falconImageAnalyzer)
+	return k8sutils.InjectFalconSecretData(ctx, r, falconImageAnalyzer, "")
 }
 
 func (r *FalconImageAnalyzerReconciler) reconcileIARAgentService(ctx context.Context, req ctrl.Request, log logr.Logger, falconImageAnalyzer *falconv1alpha1.FalconImageAnalyzer) error {
@@ -597,52 +731,59 @@ func (r *FalconImageAnalyzerReconciler) reconcileIARAgentService(ctx context.Con
 	return nil
 }
 
+// reconcileIARTLSSecret creates the TLS Secret of the IAR Agent Service. When cert-manager issues the certificate,
+// it reconciles the Certificate instead and returns the Secret written by cert-manager.
 func (r *FalconImageAnalyzerReconciler) reconcileIARTLSSecret(ctx context.Context, req ctrl.Request, log logr.Logger, falconImageAnalyzer *falconv1alpha1.FalconImageAnalyzer) (*corev1.Secret, error) {
 	existingTLSSecret := &corev1.Secret{}
 	name := falconImageAnalyzer.Name + "-tls"
 
-	err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: name, Namespace: falconImageAnalyzer.Spec.InstallNamespace}, existingTLSSecret)
+	validity := falconImageAnalyzer.Spec.ImageAnalyzerConfig.IARAgentService.CertExpiration
+	namespace := falconImageAnalyzer.Spec.InstallNamespace
+	domainName := falconImageAnalyzer.Spec.ImageAnalyzerConfig.IARAgentService.DomainName
 
-	if err != nil && apierrors.IsNotFound(err) {
-		validity := falconImageAnalyzer.Spec.ImageAnalyzerConfig.IARAgentService.CertExpiration
-		namespace := falconImageAnalyzer.Spec.InstallNamespace
-		domainName := falconImageAnalyzer.Spec.ImageAnalyzerConfig.IARAgentService.DomainName
+	fullName := fmt.Sprintf("%s.%s.svc", common.FalconImageAnalyzerAgentService, namespace)
+	if domainName != "" {
+		fullName = fmt.Sprintf("%s.%s.svc.%s", common.FalconImageAnalyzerAgentService, namespace, domainName)
+	}
 
-		fullName := fmt.Sprintf("%s.%s.svc", common.FalconImageAnalyzerAgentService, namespace)
-		if domainName != "" {
-			fullName = fmt.Sprintf("%s.%s.svc.%s", common.FalconImageAnalyzerAgentService, namespace, domainName)
-		}
+	altDNSNames := []string{
+		fullName,
+		fmt.Sprintf("%s.cluster.local", fullName),
+		fmt.Sprintf("%s.%s", fullName, namespace),
+	}
 
-		altDNSNames := []string{
-			fullName,
-			fmt.Sprintf("%s.cluster.local", fullName),
-			fmt.Sprintf("%s.%s", fullName, namespace),
-		}
+	// Add labels required for KAC -> IAR communication
+	labels := common.CRLabels("secret", name, common.FalconImageAnalyzer)
+	labels[common.AppLabelKey] = common.FalconImageAnalyzerAgentServiceApp
+	labels[common.KubernetesComponentKey] = common.FalconImageAnalyzerComponentName
+	labels[common.KubernetesNameKey] = falconImageAnalyzer.Name
+
+	if certManager := falconImageAnalyzer.Spec.ImageAnalyzerConfig.IARAgentService.CertManager; k8sutils.UseCertManager(log, certManager, r.CertManager) {
+		certificate := assets.Certificate(name, namespace, common.FalconImageAnalyzer, fullName, altDNSNames, validity, certManager.IssuerRef, labels)
+		return k8sutils.ReconcileCertificate(ctx, r, certificate,
+			func(obj client.Object) error {
+				return k8sutils.Create(r.Client, r.Scheme, ctx, req, log, falconImageAnalyzer, &falconImageAnalyzer.Status, obj)
+			},
+			func(obj client.Object) error {
+				return k8sutils.Update(r.Client, ctx, req, log, falconImageAnalyzer, &falconImageAnalyzer.Status, obj)
+			})
+	}
+
+	err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: name, Namespace: namespace}, existingTLSSecret)
 
+	if err != nil && apierrors.IsNotFound(err) {
 		certInfo := tls.CertInfo{
 			CommonName: fullName,
 			DNSNames:   altDNSNames,
 		}
 
-		c, k, b, err := tls.CertSetup(falconImageAnalyzer.Spec.InstallNamespace, validity, certInfo)
+		bundle, err := tls.NewBundle(namespace, validity, certInfo)
 		if err != nil {
 			log.Error(err, "Failed to generate IAR Agent TLS certificates")
 			return &corev1.Secret{}, err
 		}
 
-		secretData := map[string][]byte{
-			"tls.crt": c,
-			"tls.key": k,
-			"ca.crt":  b,
-		}
-
-		// Add labels required for KAC -> IAR communication
-		labels := common.CRLabels("secret", name, common.FalconImageAnalyzer)
-		labels[common.AppLabelKey] = common.FalconImageAnalyzerAgentServiceApp
-		labels[common.KubernetesComponentKey] = common.FalconImageAnalyzerComponentName
-		labels[common.KubernetesNameKey] = falconImageAnalyzer.Name
-
-		iarTLSSecret := assets.SecretWithCustomLabels(name, falconImageAnalyzer.Spec.InstallNamespace, secretData, corev1.SecretTypeTLS, labels)
+		iarTLSSecret := assets.SecretWithCustomLabels(name, namespace, bundle.SecretData(), corev1.SecretTypeTLS, labels)
 		err = k8sutils.Create(r.Client, r.Scheme, ctx, req, log, falconImageAnalyzer, &falconImageAnalyzer.Status, iarTLSSecret)
 		if err != nil {
 			return &corev1.Secret{}, err
@@ -655,3 +796,48 @@ func (r *FalconImageAnalyzerReconciler) reconcileIARTLSSecret(ctx context.Contex
 
 	return existingTLSSecret, nil
 }
+
+// handleSensorVersion records the sensor version selected by the tracker as a pending update, and reconciles the object to deploy it when automatic updates are enabled.
+func (r *FalconImageAnalyzerReconciler) handleSensorVersion(ctx context.Context, name types.NamespacedName, sensorVersion string) error {
+	obj := &falconv1alpha1.FalconImageAnalyzer{}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := r.Get(ctx, name, obj)
+		if err != nil {
+			return err
+		}
+
+		update := sensorversion.AvailableUpdate(obj.Status.AvailableUpdate, obj.Status.Sensor, sensorVersion, falconv1alpha1.AvailableUpdateSourceLatest)
+		if update == obj.Status.AvailableUpdate {
+			return nil
+		}
+
+		obj.Status.AvailableUpdate = update
+		return r.Status().Update(ctx, obj)
+	})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if obj.Spec.Advanced.IsAutoUpdating() {
+		log.FromContext(ctx).Info("reconciling FalconImageAnalyzer object", "namespace", obj.Namespace, "name", obj.Name)
+		r.reconcileObject(obj)
+	}
+
+	return nil
+}
+
+// sensorVersionTrackKey identifies the sensor version track of a FalconImageAnalyzer.
+func sensorVersionTrackKey(name types.NamespacedName) sensorversion.Key {
+	return sensorversion.NewKey(falconv1alpha1.GroupVersion.WithKind("FalconImageAnalyzer"), name)
+}
+
+// shouldTrackSensorVersions reports whether new sensor versions are tracked, either to deploy them automatically or to report them as pending updates.
+// Tracking is opt-in, so resources that only use the Falcon API to pick the initial image do not poll it.
+func shouldTrackSensorVersions(obj *falconv1alpha1.FalconImageAnalyzer) bool {
+	// The update policy is ignored for the image analyzer, so only AutoUpdate opts in
+	return obj.Spec.FalconAPI != nil && obj.Spec.Image == "" &&
+		obj.Spec.Advanced.AutoUpdate != nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensorversion"
 	"github.com/crowdstrike/falcon-operator/pkg/common"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -120,10 +121,14 @@ var _ = Describe("FalconImageAnalyzer controller", func() {
 			}, 6*time.Second, time.Second).Should(Succeed())
 
 			By("Reconciling the custom resource created")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconImageAnalyzerReconciler := &FalconImageAnalyzerReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			_, err = falconImageAnalyzerReconciler.Reconcile(ctx, reconcile.Request{
@@ -238,10 +243,14 @@ var _ = Describe("FalconImageAnalyzer controller", func() {
 			}, 6*time.Second, time.Second).Should(Succeed())
 
 			By("Reconciling the custom resource created")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconImageAnalyzerReconciler := &FalconImageAnalyzerReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			_, err = falconImageAnalyzerReconciler.Reconcile(ctx, reconcile.Request{
@@ -332,10 +341,14 @@ var _ = Describe("FalconImageAnalyzer controller", func() {
 			}, 20*time.Second, time.Second).Should(Succeed())
 
 			By("Creating the reconciler")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconImageAnalyzerReconciler := &FalconImageAnalyzerReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			By("Calling reconcileServiceAccount when no service account exists")
@@ -392,10 +405,14 @@ var _ = Describe("FalconImageAnalyzer controller", func() {
 			}, 20*time.Second, time.Second).Should(Succeed())
 
 			By("Creating the reconciler")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconImageAnalyzerReconciler := &FalconImageAnalyzerReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			By("Creating initial service account")
@@ -465,10 +482,14 @@ var _ = Describe("FalconImageAnalyzer controller", func() {
 			}, 20*time.Second, time.Second).Should(Succeed())
 
 			By("Creating the reconciler")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconImageAnalyzerReconciler := &FalconImageAnalyzerReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			By("Creating initial service account with default imagePullSecrets")
@@ -553,10 +574,14 @@ var _ = Describe("FalconImageAnalyzer controller", func() {
 			}, 20*time.Second, time.Second).Should(Succeed())
 
 			By("Creating the reconciler")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconImageAnalyzerReconciler := &FalconImageAnalyzerReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			By("Creating initial service account")
@@ -616,10 +641,14 @@ var _ = Describe("FalconImageAnalyzer controller", func() {
 			}, 20*time.Second, time.Second).Should(Succeed())
 
 			By("Creating the reconciler")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconImageAnalyzerReconciler := &FalconImageAnalyzerReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			By("Creating initial service account with multiple imagePullSecrets")
@@ -706,10 +735,14 @@ var _ = Describe("FalconImageAnalyzer controller", func() {
 			}, 20*time.Second, time.Second).Should(Succeed())
 
 			By("Creating the reconciler")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconImageAnalyzerReconciler := &FalconImageAnalyzerReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			By("Creating initial service account with operator-managed annotations")
@@ -805,10 +838,14 @@ var _ = Describe("FalconImageAnalyzer controller", func() {
 			Expect(err).To(Not(HaveOccurred()))
 
 			By("Reconciling the custom resource")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconImageAnalyzerReconciler := &FalconImageAnalyzerReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			_, err = falconImageAnalyzerReconciler.Reconcile(ctx, reconcile.Request{
@@ -863,10 +900,14 @@ var _ = Describe("FalconImageAnalyzer controller", func() {
 			Expect(err).To(Not(HaveOccurred()))
 
 			By("Reconciling to create initial deployment")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconImageAnalyzerReconciler := &FalconImageAnalyzerReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			_, err = falconImageAnalyzerReconciler.Reconcile(ctx, reconcile.Request{
@@ -956,10 +997,14 @@ var _ = Describe("FalconImageAnalyzer controller", func() {
 			Expect(err).To(Not(HaveOccurred()))
 
 			By("Reconciling to create initial deployment")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconImageAnalyzerReconciler := &FalconImageAnalyzerReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			_, err = falconImageAnalyzerReconciler.Reconcile(ctx, reconcile.Request{
@@ -1056,10 +1101,14 @@ var _ = Describe("FalconImageAnalyzer controller", func() {
 			Expect(err).To(Not(HaveOccurred()))
 
 			By("Reconciling to create initial deployment")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconImageAnalyzerReconciler := &FalconImageAnalyzerReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			_, err = falconImageAnalyzerReconciler.Reconcile(ctx, reconcile.Request{
@@ -1179,10 +1228,14 @@ var _ = Describe("FalconImageAnalyzer controller", func() {
 			Expect(err).To(Not(HaveOccurred()))
 
 			By("Reconciling to create deployment")
+			tracker, cancel := sensorversion.NewTestTracker()
+			defer cancel()
+
 			falconImageAnalyzerReconciler := &FalconImageAnalyzerReconciler{
-				Client: k8sClient,
-				Reader: k8sReader,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Reader:  k8sReader,
+				Scheme:  k8sClient.Scheme(),
+				tracker: tracker,
 			}
 
 			_, err = falconImageAnalyzerReconciler.Reconcile(ctx, reconcile.Request{
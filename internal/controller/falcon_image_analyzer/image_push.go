@@ -10,6 +10,8 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensor"
+	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensorversion"
 	"github.com/crowdstrike/falcon-operator/internal/controller/image"
 	"github.com/crowdstrike/falcon-operator/pkg/aws"
 	"github.com/crowdstrike/falcon-operator/pkg/common"
@@ -48,6 +50,9 @@ func (r *FalconImageAnalyzerReconciler) PushImage(ctx context.Context, log logr.
 
 	image := image.NewImageRefresher(ctx, log, falconApiConfig, pushAuth, falconImageAnalyzer.Spec.Registry.TLS.InsecureSkipVerify)
 	version := falconImageAnalyzer.Spec.Version
+	if approved, ok := sensorversion.ApprovedVersion(falconImageAnalyzer, falconImageAnalyzer.Status.AvailableUpdate); ok {
+		version = &approved
+	}
 
 	tag, err := image.Refresh(registryUri, falcon.ImageSensor, version)
 	if err != nil {
@@ -208,18 +213,23 @@ func (r *FalconImageAnalyzerReconciler) setImageTag(ctx context.Context, falconI
 		return *falconImageAnalyzer.Status.Sensor, r.Client.Status().Update(ctx, falconImageAnalyzer)
 	}
 
+	if approved, ok := sensorversion.ApprovedVersion(falconImageAnalyzer, falconImageAnalyzer.Status.AvailableUpdate); ok {
+		falconImageAnalyzer.Status.Sensor = &approved
+		return approved, nil
+	}
+
 	// Otherwise, get the newest version matching the requested version string
 	falconApiConfig, err := r.falconApiConfig(ctx, falconImageAnalyzer)
 	if err != nil {
 		return "", err
 	}
 
-	registry, err := falcon_registry.NewFalconRegistry(ctx, falconApiConfig)
+	imageRepo, err := sensor.NewImageRepository(ctx, falconApiConfig)
 	if err != nil {
 		return "", err
 	}
 
-	tag, err := registry.LastContainerTag(ctx, falcon.ImageSensor, falconImageAnalyzer.Spec.Version)
+	tag, err := imageRepo.GetPreferredImage(ctx, falcon.ImageSensor, falconImageAnalyzer.Spec.Version, nil)
 	if err == nil {
 		falconImageAnalyzer.Status.Sensor = common.ImageVersion(tag)
 	}
@@ -256,5 +266,9 @@ func (r *FalconImageAnalyzerReconciler) imageMirroringEnabled(falconImageAnalyze
 }
 
 func (r *FalconImageAnalyzerReconciler) versionLock(falconImageAnalyzer *falconv1alpha1.FalconImageAnalyzer) bool {
+	if _, approved := sensorversion.ApprovedVersion(falconImageAnalyzer, falconImageAnalyzer.Status.AvailableUpdate); approved || falconImageAnalyzer.Spec.Advanced.IsAutoUpdating() {
+		return false
+	}
+
 	return (falconImageAnalyzer.Spec.Version != nil && falconImageAnalyzer.Status.Sensor != nil && strings.Contains(*falconImageAnalyzer.Status.Sensor, *falconImageAnalyzer.Spec.Version)) || (falconImageAnalyzer.Spec.Version == nil && falconImageAnalyzer.Status.Sensor != nil)
 }
@@ -0,0 +1,241 @@
+package falcon
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/internal/controller/assets"
+	k8sutils "github.com/crowdstrike/falcon-operator/internal/controller/common"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// cleanupTimeout is how long the cleanup waits for the nodes before giving up on the remaining ones
+	cleanupTimeout = 10 * time.Minute
+
+	// cleanupRequeueInterval is how often the cleanup progress is checked
+	cleanupRequeueInterval = 5 * time.Second
+)
+
+// finalizeDaemonset deletes the DaemonSet running the Falcon Sensor and then runs a DaemonSet to cleanup the /opt/CrowdStrike directory
+// on the nodes that ran the sensor. The progress is tracked per node in the status, and finalizeDaemonset returns false until every
+// node has been cleaned up, could not be cleaned up or timed out, so that the deletion is requeued instead of blocking the worker.
+func (r *FalconNodeSensorReconciler) finalizeDaemonset(ctx context.Context, nsType types.NamespacedName, image string, serviceAccount string, nodesensor *falconv1alpha1.FalconNodeSensor, logger logr.Logger) (bool, error) {
+	dsCleanupName := nodesensor.Name + "-cleanup"
+
+	if nodesensor.Status.Cleanup == nil {
+		return false, r.startCleanup(ctx, nsType, nodesensor, logger)
+	}
+
+	cleanupPods := &corev1.PodList{}
+	cleanupListOptions := []client.ListOption{
+		client.InNamespace(nodesensor.Spec.InstallNamespace),
+		client.MatchingLabels{common.FalconInstanceNameKey: "cleanup", common.FalconInstanceKey: dsCleanupName},
+	}
+	if err := r.List(ctx, cleanupPods, cleanupListOptions...); err != nil {
+		logger.Error(err, "Failed to list cleanup pods")
+		return false, err
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		logger.Error(err, "Failed to list nodes")
+		return false, err
+	}
+
+	cleanup := nodesensor.Status.Cleanup.DeepCopy()
+	done := updateCleanupNodes(cleanup, cleanupPods.Items, nodes.Items, time.Now())
+
+	if !equality.Semantic.DeepEqual(nodesensor.Status.Cleanup, cleanup) {
+		if err := r.updateCleanupStatus(ctx, nsType, nodesensor, cleanup); err != nil {
+			logger.Error(err, "Failed to update FalconNodeSensor status for nodesensor.Status.Cleanup")
+			return false, err
+		}
+	}
+
+	if !done {
+		err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: dsCleanupName, Namespace: nodesensor.Spec.InstallNamespace}, &appsv1.DaemonSet{})
+		if err != nil && errors.IsNotFound(err) {
+			ds := assets.RemoveNodeDirDaemonset(dsCleanupName, image, serviceAccount, nodesensor)
+			if err := r.Create(ctx, ds); err != nil {
+				logger.Error(err, "Failed to delete node directory with cleanup DaemonSet", "Path", common.FalconHostInstallDir)
+				return false, err
+			}
+			logger.Info("Created cleanup DaemonSet", "Number of nodes", cleanup.Nodes)
+		} else if err != nil {
+			logger.Error(err, "error getting the cleanup DaemonSet")
+			return false, err
+		}
+
+		logger.Info("Waiting for cleanup pods to complete", "Completed", cleanup.Completed, "Number of nodes", cleanup.Nodes)
+		return false, nil
+	}
+
+	// The cleanup is done so delete the cleanup DS
+	if err := r.Delete(ctx,
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: dsCleanupName, Namespace: nodesensor.Spec.InstallNamespace,
+			},
+		}); err != nil && !errors.IsNotFound(err) {
+		logger.Error(err, "Failed to cleanup Falcon sensor DaemonSet pods")
+		return false, err
+	}
+
+	if len(cleanup.Remaining) > 0 {
+		logger.Info(fmt.Sprintf("%s may have not been removed on some nodes. See the troubleshooting section of the node sensor documentation for more information.", common.FalconHostInstallDir),
+			"Nodes", cleanupNodeNames(cleanup.Remaining))
+		r.recorder.Eventf(nodesensor, nil, corev1.EventTypeWarning, falconv1alpha1.ReasonCleanupIncomplete, "Cleanup",
+			"%s may have not been removed on %d of %d nodes: %s", common.FalconHostInstallDir, len(cleanup.Remaining), cleanup.Nodes, cleanupNodeNames(cleanup.Remaining))
+		return true, nil
+	}
+
+	logger.Info("Successfully deleted node directory", "Path", common.FalconDataDir, "Number of nodes", cleanup.Nodes)
+	return true, nil
+}
+
+// startCleanup records the nodes running the sensor in the status and deletes the sensor DaemonSet
+func (r *FalconNodeSensorReconciler) startCleanup(ctx context.Context, nsType types.NamespacedName, nodesensor *falconv1alpha1.FalconNodeSensor, logger logr.Logger) error {
+	sensorPods := &corev1.PodList{}
+	if err := r.List(ctx, sensorPods,
+		client.InNamespace(nodesensor.Spec.InstallNamespace),
+		client.MatchingLabels(common.CRLabels("daemonset", nodesensor.Name, common.FalconKernelSensor))); err != nil {
+		logger.Error(err, "Failed to list FalconNodeSensor pods")
+		return err
+	}
+
+	cleanup := &falconv1alpha1.FalconNodeSensorCleanup{StartedAt: metav1.Now()}
+	for _, pod := range sensorPods.Items {
+		if pod.Spec.NodeName == "" || slices.ContainsFunc(cleanup.Remaining, func(node falconv1alpha1.FalconNodeSensorCleanupNode) bool {
+			return node.Name == pod.Spec.NodeName
+		}) {
+			continue
+		}
+
+		cleanup.Remaining = append(cleanup.Remaining, falconv1alpha1.FalconNodeSensorCleanupNode{Name: pod.Spec.NodeName, State: falconv1alpha1.CleanupStatePending})
+	}
+	cleanup.Nodes = int32(len(cleanup.Remaining))
+	slices.SortFunc(cleanup.Remaining, func(a, b falconv1alpha1.FalconNodeSensorCleanupNode) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	// Record the nodes before deleting the DaemonSet, so that they are still known if the operator restarts
+	if err := r.updateCleanupStatus(ctx, nsType, nodesensor, cleanup); err != nil {
+		logger.Error(err, "Failed to update FalconNodeSensor status for nodesensor.Status.Cleanup")
+		return err
+	}
+
+	// Delete the Daemonset containing the sensor
+	if err := r.Delete(ctx,
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: nodesensor.Name, Namespace: nodesensor.Spec.InstallNamespace,
+			},
+		}); err != nil && !errors.IsNotFound(err) {
+		logger.Error(err, "Failed to cleanup Falcon sensor DaemonSet pods")
+		return err
+	}
+
+	logger.Info("Deleted FalconNodeSensor DaemonSet, cleaning up nodes", "Number of nodes", cleanup.Nodes)
+	return nil
+}
+
+func (r *FalconNodeSensorReconciler) updateCleanupStatus(ctx context.Context, nsType types.NamespacedName, nodesensor *falconv1alpha1.FalconNodeSensor, cleanup *falconv1alpha1.FalconNodeSensorCleanup) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := r.Get(ctx, nsType, nodesensor)
+		if err != nil {
+			return err
+		}
+
+		nodesensor.Status.Cleanup = cleanup
+		return r.Status().Update(ctx, nodesensor)
+	})
+}
+
+// updateCleanupNodes updates the state of the nodes that are pending cleanup and reports whether the cleanup is done.
+// Nodes that have been cleaned up are removed from the remaining nodes. Nodes that were removed from the cluster or are not
+// ready are skipped, because the cleanup pod cannot run on them. Cordoned nodes are still cleaned up, since DaemonSet pods
+// tolerate the unschedulable taint.
+func updateCleanupNodes(cleanup *falconv1alpha1.FalconNodeSensorCleanup, pods []corev1.Pod, nodes []corev1.Node, now time.Time) bool {
+	podsByNode := map[string]*corev1.Pod{}
+	for i := range pods {
+		podsByNode[pods[i].Spec.NodeName] = &pods[i]
+	}
+
+	nodesByName := map[string]*corev1.Node{}
+	for i := range nodes {
+		nodesByName[nodes[i].Name] = &nodes[i]
+	}
+
+	timedOut := now.After(cleanup.StartedAt.Add(cleanupTimeout))
+	remaining := make([]falconv1alpha1.FalconNodeSensorCleanupNode, 0, len(cleanup.Remaining))
+	done := true
+
+	for _, cleanupNode := range cleanup.Remaining {
+		if cleanupNode.State != falconv1alpha1.CleanupStatePending {
+			remaining = append(remaining, cleanupNode)
+			continue
+		}
+
+		pod := podsByNode[cleanupNode.Name]
+		node := nodesByName[cleanupNode.Name]
+
+		switch {
+		// The cleanup pods run a sleep container once the init container has removed the sensor files
+		case pod != nil && (pod.Status.Phase == corev1.PodRunning || pod.Status.Phase == corev1.PodSucceeded):
+			cleanup.Completed++
+			continue
+		case pod != nil && k8sutils.IsInitPodCrashLooping(pod):
+			cleanupNode.State = falconv1alpha1.CleanupStateFailed
+			cleanupNode.Message = "the cleanup pod is crashlooping"
+		case node == nil || node.GetDeletionTimestamp() != nil:
+			cleanupNode.State = falconv1alpha1.CleanupStateSkipped
+			cleanupNode.Message = "the node was removed from the cluster"
+		case !isNodeReady(node):
+			cleanupNode.State = falconv1alpha1.CleanupStateSkipped
+			cleanupNode.Message = "the node is not ready"
+		case timedOut:
+			cleanupNode.State = falconv1alpha1.CleanupStateTimedOut
+			cleanupNode.Message = fmt.Sprintf("the cleanup did not complete within %s", cleanupTimeout)
+		default:
+			done = false
+		}
+
+		remaining = append(remaining, cleanupNode)
+	}
+
+	cleanup.Remaining = remaining
+	return done
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func cleanupNodeNames(nodes []falconv1alpha1.FalconNodeSensorCleanupNode) string {
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		names = append(names, fmt.Sprintf("%s (%s)", node.Name, node.Message))
+	}
+
+	return strings.Join(names, ", ")
+}
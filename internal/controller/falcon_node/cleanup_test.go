@@ -0,0 +1,107 @@
+package falcon
+
+import (
+	"testing"
+	"time"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUpdateCleanupNodes(t *testing.T) {
+	startedAt := time.Now()
+	newCleanup := func() *falconv1alpha1.FalconNodeSensorCleanup {
+		return &falconv1alpha1.FalconNodeSensorCleanup{
+			StartedAt: metav1.Time{Time: startedAt},
+			Nodes:     5,
+			Remaining: []falconv1alpha1.FalconNodeSensorCleanupNode{
+				{Name: "cleaned", State: falconv1alpha1.CleanupStatePending},
+				{Name: "crashlooping", State: falconv1alpha1.CleanupStatePending},
+				{Name: "removed", State: falconv1alpha1.CleanupStatePending},
+				{Name: "not-ready", State: falconv1alpha1.CleanupStatePending},
+				{Name: "in-progress", State: falconv1alpha1.CleanupStatePending},
+			},
+		}
+	}
+
+	pods := []corev1.Pod{
+		newTestCleanupPod("cleaned", corev1.PodRunning, nil),
+		newTestCleanupPod("crashlooping", corev1.PodPending, &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}),
+		newTestCleanupPod("in-progress", corev1.PodPending, &corev1.ContainerStateWaiting{Reason: "PodInitializing"}),
+		newTestCleanupPod("scaled-up", corev1.PodPending, nil),
+	}
+
+	nodes := []corev1.Node{
+		newTestNode("cleaned", corev1.ConditionTrue),
+		newTestNode("crashlooping", corev1.ConditionTrue),
+		newTestNode("not-ready", corev1.ConditionUnknown),
+		newTestNode("in-progress", corev1.ConditionTrue),
+		newTestNode("scaled-up", corev1.ConditionTrue),
+	}
+
+	t.Run("should wait for nodes in progress", func(t *testing.T) {
+		cleanup := newCleanup()
+		done := updateCleanupNodes(cleanup, pods, nodes, startedAt.Add(time.Minute))
+		assert.False(t, done)
+
+		want := &falconv1alpha1.FalconNodeSensorCleanup{
+			StartedAt: metav1.Time{Time: startedAt},
+			Nodes:     5,
+			Completed: 1,
+			Remaining: []falconv1alpha1.FalconNodeSensorCleanupNode{
+				{Name: "crashlooping", State: falconv1alpha1.CleanupStateFailed, Message: "the cleanup pod is crashlooping"},
+				{Name: "removed", State: falconv1alpha1.CleanupStateSkipped, Message: "the node was removed from the cluster"},
+				{Name: "not-ready", State: falconv1alpha1.CleanupStateSkipped, Message: "the node is not ready"},
+				{Name: "in-progress", State: falconv1alpha1.CleanupStatePending},
+			},
+		}
+		if diff := cmp.Diff(want, cleanup); diff != "" {
+			t.Errorf("updateCleanupNodes() mismatch (-want +got): %s", diff)
+		}
+
+		// Completed nodes are not counted twice
+		assert.False(t, updateCleanupNodes(cleanup, pods, nodes, startedAt.Add(time.Minute)))
+		assert.Equal(t, int32(1), cleanup.Completed)
+	})
+
+	t.Run("should time out remaining nodes", func(t *testing.T) {
+		cleanup := newCleanup()
+		done := updateCleanupNodes(cleanup, pods, nodes, startedAt.Add(cleanupTimeout+time.Second))
+		assert.True(t, done)
+		assert.Len(t, cleanup.Remaining, 4)
+		assert.Equal(t, falconv1alpha1.CleanupStateTimedOut, cleanup.Remaining[3].State)
+	})
+
+	t.Run("should be done without nodes", func(t *testing.T) {
+		cleanup := &falconv1alpha1.FalconNodeSensorCleanup{StartedAt: metav1.Time{Time: startedAt}}
+		assert.True(t, updateCleanupNodes(cleanup, pods, nodes, startedAt))
+	})
+}
+
+func newTestCleanupPod(nodeName string, phase corev1.PodPhase, initWaiting *corev1.ContainerStateWaiting) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "falcon-node-sensor-cleanup-" + nodeName},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+
+	if initWaiting != nil {
+		pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+			{Name: "cleanup-opt-crowdstrike", State: corev1.ContainerState{Waiting: initWaiting}},
+		}
+	}
+
+	return pod
+}
+
+func newTestNode(name string, ready corev1.ConditionStatus) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+		},
+	}
+}
@@ -0,0 +1,125 @@
+package falcon
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/crowdstrike/falcon-operator/pkg/node"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// maxReportedIncompatibleNodes limits the number of nodes listed in the NodesCompatible condition message
+const maxReportedIncompatibleNodes = 10
+
+// checkNodeCompatibility evaluates the Linux nodes targeted by the DaemonSet with the pod spec against the compatibility matrix,
+// and reports the nodes that the sensor image does not support in the NodesCompatible condition. It runs before the DaemonSet
+// is created or updated, so that unsupported nodes are reported before a new sensor version rolls out.
+func (r *FalconNodeSensorReconciler) checkNodeCompatibility(ctx context.Context, nsType types.NamespacedName, nodesensor *falconv1alpha1.FalconNodeSensor, podSpec *corev1.PodSpec, image string, logger logr.Logger) error {
+	matrix, err := r.compatibilityMatrix(ctx, nodesensor)
+	if err != nil {
+		logger.Error(err, "Invalid node compatibility matrix", "ConfigMap", nodesensor.Spec.Node.CompatibilityConfigMap)
+		return r.conditionsUpdate(falconv1alpha1.ConditionNodesCompatible,
+			metav1.ConditionUnknown,
+			falconv1alpha1.ReasonInvalidCompatibilityMatrix,
+			fmt.Sprintf("Invalid node compatibility matrix in ConfigMap %s: %v", nodesensor.Spec.Node.CompatibilityConfigMap, err),
+			ctx, nsType, nodesensor, logger)
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, client.MatchingLabels(nodesensor.Spec.Node.NodeSelector)); err != nil {
+		logger.Error(err, "Failed to list nodes")
+		return err
+	}
+
+	sensorVersion := *common.ImageVersion(image)
+	targeted := 0
+	incompatible := []string{}
+	for _, n := range nodes.Items {
+		if !isLinuxNode(&n) || nodeExclusionReason(&n, podSpec, logger) != "" {
+			continue
+		}
+		targeted++
+
+		if reason := matrix.Incompatibility(&n, sensorVersion); reason != "" {
+			incompatible = append(incompatible, fmt.Sprintf("%s (%s)", n.Name, reason))
+		}
+	}
+
+	if len(incompatible) == 0 {
+		message := "The sensor supports all target nodes"
+		if len(matrix.Rules) == 0 {
+			message = "The sensor supports the architecture of all target nodes. OS images and kernels are only checked by the rules of a matrix referenced by node.compatibilityConfigMap"
+		}
+
+		return r.conditionsUpdate(falconv1alpha1.ConditionNodesCompatible,
+			metav1.ConditionTrue,
+			falconv1alpha1.ReasonReqMet,
+			message,
+			ctx, nsType, nodesensor, logger)
+	}
+
+	slices.Sort(incompatible)
+	logger.Info("The sensor does not support some target nodes", "Nodes", incompatible)
+
+	message := fmt.Sprintf("The sensor %s does not support %d of %d target nodes: %s", sensorVersion, len(incompatible), targeted,
+		strings.Join(incompatible[:min(len(incompatible), maxReportedIncompatibleNodes)], ", "))
+	if len(incompatible) > maxReportedIncompatibleNodes {
+		message += fmt.Sprintf(" and %d more", len(incompatible)-maxReportedIncompatibleNodes)
+	}
+
+	return r.conditionsUpdate(falconv1alpha1.ConditionNodesCompatible,
+		metav1.ConditionFalse,
+		falconv1alpha1.ReasonUnsupportedNodes,
+		message,
+		ctx, nsType, nodesensor, logger)
+}
+
+// enqueueNodeSensorsForCompatibilityConfigMap requests the reconciliation of the FalconNodeSensors referencing the ConfigMap
+// in node.compatibilityConfigMap, so that changes to the matrix are checked without waiting for another reconciliation
+func (r *FalconNodeSensorReconciler) enqueueNodeSensorsForCompatibilityConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	nodesensors := &falconv1alpha1.FalconNodeSensorList{}
+	if err := r.List(ctx, nodesensors); err != nil {
+		clog.FromContext(ctx).Error(err, "unable to list FalconNodeSensors")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, nodesensor := range nodesensors.Items {
+		if nodesensor.Spec.Node.CompatibilityConfigMap != obj.GetName() || nodesensor.Spec.InstallNamespace != obj.GetNamespace() {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: nodesensor.Name}})
+	}
+
+	return requests
+}
+
+// compatibilityMatrix returns the compatibility matrix of the ConfigMap referenced by the FalconNodeSensor, or the default one
+func (r *FalconNodeSensorReconciler) compatibilityMatrix(ctx context.Context, nodesensor *falconv1alpha1.FalconNodeSensor) (*node.CompatibilityMatrix, error) {
+	if nodesensor.Spec.Node.CompatibilityConfigMap == "" {
+		return node.DefaultCompatibilityMatrix(), nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: nodesensor.Spec.Node.CompatibilityConfigMap, Namespace: nodesensor.Spec.InstallNamespace}, configMap); err != nil {
+		return nil, err
+	}
+
+	data, ok := configMap.Data[node.CompatibilityMatrixKey]
+	if !ok {
+		return nil, fmt.Errorf("missing key %s", node.CompatibilityMatrixKey)
+	}
+
+	return node.ParseCompatibilityMatrix([]byte(data))
+}
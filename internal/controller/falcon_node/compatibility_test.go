@@ -0,0 +1,104 @@
+package falcon
+
+import (
+	"context"
+	"testing"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/pkg/node"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestCheckNodeCompatibility(t *testing.T) {
+	ctx := context.Background()
+	image := "registry/falcon-sensor:7.31.0-18410-1.falcon-linux.Release.US-1"
+
+	nodesensor := &falconv1alpha1.FalconNodeSensor{ObjectMeta: metav1.ObjectMeta{Name: "general"}}
+	nodesensor.Spec.InstallNamespace = "falcon-system"
+	nodesensor.Spec.Node.NodeSelector = map[string]string{"pool": "general"}
+	nodesensor.Spec.Node.CompatibilityConfigMap = "compatibility"
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "compatibility", Namespace: "falcon-system"},
+		Data: map[string]string{node.CompatibilityMatrixKey: `
+rules:
+- osImage: ^Ubuntu
+  minKernelVersion: "5.4"
+`},
+	}
+
+	newNode := func(name, pool, osImage, kernel string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"pool": pool}},
+			Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{
+				OperatingSystem: "linux",
+				OSImage:         osImage,
+				KernelVersion:   kernel,
+			}},
+		}
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, falconv1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(nodesensor).WithObjects(
+		nodesensor,
+		configMap,
+		newNode("supported", "general", "Ubuntu 22.04.4 LTS", "5.15.0-1051-aws"),
+		newNode("old-kernel", "general", "Ubuntu 18.04.6 LTS", "4.15.0-213-generic"),
+		newNode("other-pool", "gpu", "Ubuntu 18.04.6 LTS", "4.15.0-213-generic"),
+	).Build()
+
+	r := &FalconNodeSensorReconciler{Client: fakeClient, Reader: fakeClient, Scheme: scheme}
+	podSpec := &corev1.PodSpec{NodeSelector: nodesensor.Spec.Node.NodeSelector}
+	require.NoError(t, r.checkNodeCompatibility(ctx, client.ObjectKeyFromObject(nodesensor), nodesensor, podSpec, image, logr.Discard()))
+
+	condition := meta.FindStatusCondition(nodesensor.Status.Conditions, falconv1alpha1.ConditionNodesCompatible)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, falconv1alpha1.ReasonUnsupportedNodes, condition.Reason)
+	assert.Equal(t, "The sensor 7.31.0-18410-1.falcon-linux.Release.US-1 does not support 1 of 2 target nodes: "+
+		"old-kernel (kernel 4.15.0-213-generic is older than 5.4, the oldest supported on Ubuntu 18.04.6 LTS)", condition.Message)
+
+	configMap.Data[node.CompatibilityMatrixKey] = "rules: [{osImage: '(Ubuntu'}]"
+	require.NoError(t, fakeClient.Update(ctx, configMap))
+	require.NoError(t, r.checkNodeCompatibility(ctx, client.ObjectKeyFromObject(nodesensor), nodesensor, podSpec, image, logr.Discard()))
+
+	condition = meta.FindStatusCondition(nodesensor.Status.Conditions, falconv1alpha1.ConditionNodesCompatible)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionUnknown, condition.Status)
+	assert.Equal(t, falconv1alpha1.ReasonInvalidCompatibilityMatrix, condition.Reason)
+}
+
+func TestEnqueueNodeSensorsForCompatibilityConfigMap(t *testing.T) {
+	newNodeSensor := func(name, namespace, configMap string) *falconv1alpha1.FalconNodeSensor {
+		nodesensor := &falconv1alpha1.FalconNodeSensor{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		nodesensor.Spec.InstallNamespace = namespace
+		nodesensor.Spec.Node.CompatibilityConfigMap = configMap
+		return nodesensor
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, falconv1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newNodeSensor("referencing", "falcon-system", "compatibility"),
+		newNodeSensor("other-namespace", "falcon-other", "compatibility"),
+		newNodeSensor("other-configmap", "falcon-system", "other"),
+		newNodeSensor("default-matrix", "falcon-system", ""),
+	).Build()
+
+	r := &FalconNodeSensorReconciler{Client: fakeClient, Reader: fakeClient, Scheme: scheme}
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "compatibility", Namespace: "falcon-system"}}
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "referencing"}}},
+		r.enqueueNodeSensorsForCompatibilityConfigMap(context.Background(), configMap))
+}
@@ -2,13 +2,14 @@ package falcon
 
 import (
 	"context"
-	"fmt"
+	goerrors "errors"
 	"reflect"
 	"slices"
 
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
 	"github.com/crowdstrike/falcon-operator/internal/controller/assets"
 	k8sutils "github.com/crowdstrike/falcon-operator/internal/controller/common"
+	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensor"
 	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensorversion"
 	"github.com/crowdstrike/falcon-operator/pkg/common"
 	"github.com/crowdstrike/falcon-operator/pkg/k8s_utils"
@@ -25,14 +26,17 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	clog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
 // FalconNodeSensorReconciler reconciles a FalconNodeSensor object
@@ -43,6 +47,7 @@ type FalconNodeSensorReconciler struct {
 	Scheme          *runtime.Scheme
 	reconcileObject func(client.Object)
 	tracker         sensorversion.Tracker
+	recorder        events.EventRecorder
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -52,6 +57,11 @@ func (r *FalconNodeSensorReconciler) SetupWithManager(mgr ctrl.Manager, tracker
 		Owns(&corev1.ConfigMap{}).
 		Owns(&appsv1.DaemonSet{}).
 		Owns(&corev1.Secret{}).
+		Watches(&falconv1alpha1.FalconNodeSensor{}, handler.EnqueueRequestsFromMapFunc(r.enqueueOtherNodeSensors)).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(enqueueNodeSensorOfPod),
+			builder.WithPredicates(predicate.NewPredicateFuncs(isNodeCleanupPod), podPhaseChanged)).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.enqueueNodeSensorsForNode), builder.WithPredicates(nodeSchedulingChanged)).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.enqueueNodeSensorsForCompatibilityConfigMap)).
 		Build(r)
 	if err != nil {
 		return err
@@ -63,6 +73,7 @@ func (r *FalconNodeSensorReconciler) SetupWithManager(mgr ctrl.Manager, tracker
 	}
 
 	r.tracker = tracker
+	r.recorder = mgr.GetEventRecorder("falconnodesensor")
 	return nil
 }
 
@@ -74,7 +85,7 @@ func (r *FalconNodeSensorReconciler) GetK8sReader() client.Reader {
 	return r.Reader
 }
 
-// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;delete;deletecollection
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;delete;deletecollection
 
 //+kubebuilder:rbac:groups=falcon.crowdstrike.com,resources=falconnodesensors,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=falcon.crowdstrike.com,resources=falconnodesensors/status,verbs=get;update;patch
@@ -83,9 +94,11 @@ func (r *FalconNodeSensorReconciler) GetK8sReader() client.Reader {
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;create;update
 //+kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;create;update
-//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
-//+kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=clusterrolebindings,verbs=get;list;watch;create
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=clusterrolebindings,verbs=get;list;watch;create;update
 //+kubebuilder:rbac:groups="security.openshift.io",resources=securitycontextconstraints,resourceNames=privileged,verbs=use
+//+kubebuilder:rbac:groups="config.openshift.io",resources=infrastructures,verbs=get
 //+kubebuilder:rbac:groups="scheduling.k8s.io",resources=priorityclasses,verbs=get;list;watch;create;delete;update
 //+kubebuilder:rbac:groups="",resources=pods;services;nodes;daemonsets;replicasets;deployments;jobs;ingresses;cronjobs;persistentvolumes,verbs=get;watch;list
 
@@ -99,13 +112,17 @@ func (r *FalconNodeSensorReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	logger := log.WithValues("DaemonSet", req.NamespacedName)
 	logger.Info("reconciling FalconNodeSensor")
 
+	// The update policy is checked for its condition and again when selecting the image, but only fetched once
+	ctx = sensor.WithUpdatePolicyCache(ctx)
+
 	// Fetch the FalconNodeSensor instance.
 	nodesensor := &falconv1alpha1.FalconNodeSensor{}
 
 	err := r.Get(ctx, req.NamespacedName, nodesensor)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			r.tracker.StopTracking(req.NamespacedName)
+			r.tracker.StopTracking(sensorVersionTrackKey(req.NamespacedName))
+			uncoveredNodes.DeleteLabelValues(req.Name)
 
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
@@ -122,7 +139,56 @@ func (r *FalconNodeSensorReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		logger.V(1).Info("FalconNodeSensor backend field is deprecated and will be ignored; It may be removed in a future sensor release.", "backend", nodesensor.Spec.Node.Backend)
 	}
 
-	validate, err := k8sutils.CheckRunningPodLabels(r.Reader, ctx, nodesensor.Spec.InstallNamespace, common.CRLabels("daemonset", nodesensor.Name, common.FalconKernelSensor))
+	if nodesensor.GetDeletionTimestamp() == nil {
+		nodesensors := &falconv1alpha1.FalconNodeSensorList{}
+		if err := r.List(ctx, nodesensors); err != nil {
+			logger.Error(err, "Failed to list FalconNodeSensors")
+			return ctrl.Result{}, err
+		}
+
+		if other := overlappingNodeSensor(nodesensor, nodesensors.Items); other != nil {
+			err = r.conditionsUpdate(falconv1alpha1.ConditionFailed,
+				metav1.ConditionFalse,
+				falconv1alpha1.ReasonNodeSelectorOverlap,
+				nodeSelectorOverlapMessage(other),
+				ctx, req.NamespacedName, nodesensor, logger)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			logger.Error(nil, "FalconNodeSensor targets nodes of another FalconNodeSensor. Please update the nodeSelector in the CR configuration.", "FalconNodeSensor", other.Name)
+
+			if err := r.removeOverlappingDaemonSet(ctx, nodesensor, other, logger); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, nil
+		}
+
+		if err := node.ValidateTagTemplates(nodesensor.Spec.Node.TagTemplates); err != nil {
+			err = r.conditionsUpdate(falconv1alpha1.ConditionFailed,
+				metav1.ConditionFalse,
+				falconv1alpha1.ReasonInvalidTagTemplate,
+				err.Error(),
+				ctx, req.NamespacedName, nodesensor, logger)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			logger.Error(nil, "FalconNodeSensor has an invalid tag template. Please update the tagTemplates in the CR configuration.")
+			return ctrl.Result{}, nil
+		}
+
+		for _, reason := range []string{falconv1alpha1.ReasonNodeSelectorOverlap, falconv1alpha1.ReasonInvalidTagTemplate} {
+			if err := r.clearFailedCondition(ctx, req.NamespacedName, nodesensor, reason, logger); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	// Pods of other FalconNodeSensors and the node cleanup pods may run in the same namespace
+	podLabels := common.CRLabels("daemonset", nodesensor.Name, common.FalconKernelSensor)
+	delete(podLabels, common.FalconInstanceKey)
+	delete(podLabels, common.FalconInstanceNameKey)
+
+	validate, err := k8sutils.CheckRunningPodLabels(r.Reader, ctx, nodesensor.Spec.InstallNamespace, podLabels)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -206,15 +272,79 @@ func (r *FalconNodeSensorReconciler) Reconcile(ctx context.Context, req ctrl.Req
 			return ctrl.Result{}, apiConfigErr
 		}
 
-		getSensorVersion := sensorversion.NewFalconCloudQuery(falcon.NodeSensor, apiConfig)
-		r.tracker.Track(req.NamespacedName, getSensorVersion, r.reconcileObjectWithName, nodesensor.Spec.Node.Advanced.IsAutoUpdatingForced())
+		getSensorVersion := sensorversion.NewPreferredImageQuery(falcon.NodeSensor, apiConfig, nodesensor.Spec.Node.Version, nodesensor.Spec.Node.Advanced.UpdatePolicy, nodesensor.Spec.Internal.CrowdstrikeRegistryRepoOverride)
+		r.tracker.TrackWithInitialVersion(sensorVersionTrackKey(req.NamespacedName), falcon.NodeSensor, getSensorVersion, r.handleSensorVersion, nodesensor.Spec.Node.Advanced.IsAutoUpdatingForced())
 	} else {
-		r.tracker.StopTracking(req.NamespacedName)
+		r.tracker.StopTracking(sensorVersionTrackKey(req.NamespacedName))
+	}
+
+	if nodesensor.Status.AvailableUpdate != nil &&
+		(!shouldTrackSensorVersions(nodesensor) || sensorversion.IsDeployed(nodesensor.Status.AvailableUpdate, nodesensor.Status.Sensor)) {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			err := r.Get(ctx, req.NamespacedName, nodesensor)
+			if err != nil {
+				return err
+			}
+
+			nodesensor.Status.AvailableUpdate = nil
+			return r.Status().Update(ctx, nodesensor)
+		})
+		if err != nil {
+			log.Error(err, "Failed to update FalconNodeSensor status for nodesensor.Status.AvailableUpdate")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if shouldCheckUpdatePolicy(nodesensor) {
+		apiConfig, apiConfigErr := nodesensor.Spec.FalconAPI.ApiConfigWithSecret(ctx, r.Reader, nodesensor.Spec.FalconSecret)
+		if apiConfigErr != nil {
+			return ctrl.Result{}, apiConfigErr
+		}
+
+		condition, err := sensor.UpdatePolicyCondition(ctx, apiConfig, falcon.NodeSensor, nodesensor.Spec.Node.Advanced.GetUpdatePolicy())
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		err = r.conditionsUpdate(condition.Type, condition.Status, condition.Reason, condition.Message, ctx, req.NamespacedName, nodesensor, logger)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if condition.Status == metav1.ConditionFalse {
+			logger.Info("sensor update policy cannot be applied", "reason", condition.Reason, "message", condition.Message)
+			// The sensor version tracker polls the policy and reconciles again once it selects a version
+			return ctrl.Result{}, nil
+		}
+	}
+
+	clusterName, err := r.resolveClusterName(ctx, req.NamespacedName, nodesensor, logger)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 
 	// Inject Falcon secrets before handling config map updates
 	if nodesensor.Spec.FalconSecret.Enabled {
-		if err = r.injectFalconSecretData(ctx, nodesensor, logger); err != nil {
+		err = r.injectFalconSecretData(ctx, nodesensor, logger)
+	}
+
+	// Status updates fetch the FalconNodeSensor again, so they are made on a copy to keep the injected secret data
+	switch {
+	case goerrors.Is(err, k8sutils.ErrMaintenanceTokenNamespace):
+		// The sensor is deployed without the maintenance token rather than failing to start
+		logger.Error(err, "FalconNodeSensor maintenance token is not passed to the sensor. Please move the FalconSecret to the install namespace.")
+		err = r.conditionsUpdate(falconv1alpha1.ConditionFailed,
+			metav1.ConditionFalse,
+			falconv1alpha1.ReasonInvalidMaintenanceToken,
+			err.Error(),
+			ctx, req.NamespacedName, nodesensor.DeepCopy(), logger)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	case err != nil:
+		return ctrl.Result{}, err
+	default:
+		if err := r.clearFailedCondition(ctx, req.NamespacedName, nodesensor.DeepCopy(), falconv1alpha1.ReasonInvalidMaintenanceToken, logger); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
@@ -223,6 +353,7 @@ func (r *FalconNodeSensorReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	config.SetClusterName(clusterName)
 
 	sensorConf, updated, err := r.handleConfigMaps(ctx, config, nodesensor, logger)
 	if err != nil {
@@ -278,8 +409,51 @@ func (r *FalconNodeSensorReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, err
 	}
 
+	// Check if the FalconNodeSensor instance is marked to be deleted, which is
+	// indicated by the deletion timestamp being set.
+	// This happens before the DaemonSet is reconciled so that the sensor DaemonSet is not created again while the nodes are cleaned up.
+	isDSMarkedToBeDeleted := nodesensor.GetDeletionTimestamp() != nil
+	if isDSMarkedToBeDeleted {
+		if controllerutil.ContainsFinalizer(nodesensor, common.FalconFinalizer) {
+			// Allows the cleanup to be disabled by disableCleanup option
+			if !*nodesensor.Spec.Node.NodeCleanup {
+				// Run finalization logic for common.FalconFinalizer. The cleanup runs across
+				// several reconciliations, so keep the finalizer until it is done.
+				done, err := r.finalizeDaemonset(ctx, req.NamespacedName, image, serviceAccount, nodesensor, logger)
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+				if !done {
+					return ctrl.Result{RequeueAfter: cleanupRequeueInterval}, nil
+				}
+				logger.Info("Successfully finalized daemonset")
+			} else {
+				logger.Info("Skipping cleanup because it is disabled", "disableCleanup", *nodesensor.Spec.Node.NodeCleanup)
+			}
+
+			// Remove common.FalconFinalizer. Once all finalizers have been
+			// removed, the object will be deleted.
+			controllerutil.RemoveFinalizer(nodesensor, common.FalconFinalizer)
+			err := r.Update(ctx, nodesensor)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			log.Info("Removing finalizer")
+
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Report the nodes that the sensor does not support before it is rolled out
+	dsSpec := assets.Daemonset(nodesensor.Name, image, serviceAccount, nodesensor)
+	if err := r.checkNodeCompatibility(ctx, req.NamespacedName, nodesensor, &dsSpec.Spec.Template.Spec, image, logger); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Check if the daemonset already exists, if not create a new one
 	daemonset := &appsv1.DaemonSet{}
+	var podSpec *corev1.PodSpec
+	dsCreated := false
 
 	err = common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: nodesensor.Name, Namespace: nodesensor.Spec.InstallNamespace}, daemonset)
 	if err != nil && errors.IsNotFound(err) {
@@ -323,9 +497,8 @@ func (r *FalconNodeSensorReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		}
 
 		logger.Info("Created a new DaemonSet", "DaemonSet.Namespace", ds.Namespace, "DaemonSet.Name", ds.Name)
-		// Daemonset created successfully - return and requeue
-		return ctrl.Result{Requeue: true}, nil
-
+		podSpec = &ds.Spec.Template.Spec
+		dsCreated = true
 	} else if err != nil {
 		logger.Error(err, "error getting DaemonSet")
 		return ctrl.Result{}, err
@@ -338,6 +511,7 @@ func (r *FalconNodeSensorReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		containerUpdates := reconcileDaemonSetContainers(dsUpdate, dsTarget, image, logger)
 		containerEnvUpdates := reconcileDaemonSetContainerEnvs(dsUpdate, dsTarget, logger)
 		affUpdate := updateDaemonSetAffinity(dsUpdate, nodesensor, logger)
+		nodeSelectorUpdate := updateDaemonSetNodeSelector(dsUpdate, dsTarget, logger)
 		volumeUpdates := updateDaemonSetVolumes(dsUpdate, dsTarget, logger)
 		pc := updateDaemonSetPriorityClass(dsUpdate, dsTarget, logger)
 		tolsUpdate, err := r.updateDaemonSetTolerations(ctx, dsUpdate, nodesensor, logger)
@@ -345,9 +519,10 @@ func (r *FalconNodeSensorReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		if err != nil {
 			return ctrl.Result{}, err
 		}
+		podSpec = &dsUpdate.Spec.Template.Spec
 
 		// Update the daemonset and re-spin pods with changes
-		if containerUpdates || containerEnvUpdates || tolsUpdate || affUpdate ||
+		if containerUpdates || containerEnvUpdates || tolsUpdate || affUpdate || nodeSelectorUpdate ||
 			volumeUpdates || pc || pullSecretUpdate || updated {
 			err = r.Update(ctx, dsUpdate)
 			if err != nil {
@@ -378,6 +553,17 @@ func (r *FalconNodeSensorReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		}
 	}
 
+	// Nodes leaving the DaemonSet are detected against the pod template it was created or updated with
+	removedNodesPending, err := r.handleRemovedNodes(ctx, req.NamespacedName, nodesensor, podSpec, image, serviceAccount, logger)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if dsCreated {
+		// Daemonset created successfully - return and requeue
+		return ctrl.Result{Requeue: true}, nil
+	}
+
 	imgVer := common.ImageVersion(image)
 	if nodesensor.Status.Sensor != imgVer {
 		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
@@ -395,6 +581,10 @@ func (r *FalconNodeSensorReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		}
 	}
 
+	if err := updateNodesStatus(ctx, r.Client, r.recorder, req.NamespacedName, nodesensor, daemonset, logger); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	err = r.conditionsUpdate(falconv1alpha1.ConditionSuccess,
 		metav1.ConditionTrue,
 		falconv1alpha1.ReasonInstallSucceeded,
@@ -405,37 +595,6 @@ func (r *FalconNodeSensorReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{Requeue: true}, err
 	}
 
-	// Check if the FalconNodeSensor instance is marked to be deleted, which is
-	// indicated by the deletion timestamp being set.
-	isDSMarkedToBeDeleted := nodesensor.GetDeletionTimestamp() != nil
-	if isDSMarkedToBeDeleted {
-		if controllerutil.ContainsFinalizer(nodesensor, common.FalconFinalizer) {
-			logger.Info("Successfully finalized daemonset")
-			// Allows the cleanup to be disabled by disableCleanup option
-			if !*nodesensor.Spec.Node.NodeCleanup {
-				// Run finalization logic for common.FalconFinalizer. If the
-				// finalization logic fails, don't remove the finalizer so
-				// that we can retry during the next reconciliation.
-				if err := r.finalizeDaemonset(ctx, image, serviceAccount, nodesensor, logger); err != nil {
-					return ctrl.Result{}, err
-				}
-			} else {
-				logger.Info("Skipping cleanup because it is disabled", "disableCleanup", *nodesensor.Spec.Node.NodeCleanup)
-			}
-
-			// Remove common.FalconFinalizer. Once all finalizers have been
-			// removed, the object will be deleted.
-			controllerutil.RemoveFinalizer(nodesensor, common.FalconFinalizer)
-			err := r.Update(ctx, nodesensor)
-			if err != nil {
-				return ctrl.Result{}, err
-			}
-			log.Info("Removing finalizer")
-
-		}
-		return ctrl.Result{}, nil
-	}
-
 	// Add finalizer for this CR
 	if !controllerutil.ContainsFinalizer(nodesensor, common.FalconFinalizer) {
 		controllerutil.AddFinalizer(nodesensor, common.FalconFinalizer)
@@ -448,6 +607,10 @@ func (r *FalconNodeSensorReconciler) Reconcile(ctx context.Context, req ctrl.Req
 
 	}
 
+	if removedNodesPending {
+		return ctrl.Result{RequeueAfter: cleanupRequeueInterval}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -483,7 +646,7 @@ func (r *FalconNodeSensorReconciler) handleNamespace(ctx context.Context, nodese
 		return false, err
 	}
 
-	return false, nil
+	return false, r.shareOwnership(ctx, nodesensor, &ns, logger)
 }
 
 // handlePriorityClass creates and updates the priority class
@@ -619,7 +782,9 @@ func (r *FalconNodeSensorReconciler) handleCrowdStrikeSecrets(ctx context.Contex
 	secret := corev1.Secret{}
 
 	err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: common.FalconPullSecretName, Namespace: nodesensor.Spec.InstallNamespace}, &secret)
-	if err == nil || !errors.IsNotFound(err) {
+	if err == nil {
+		return r.shareOwnership(ctx, nodesensor, &secret, logger)
+	} else if !errors.IsNotFound(err) {
 		return err
 	}
 
@@ -658,11 +823,28 @@ func (r *FalconNodeSensorReconciler) updateImagePullSecrets(existingDs *appsv1.D
 // If an update is needed, this will update the tolerations from the given DaemonSet
 func (r *FalconNodeSensorReconciler) updateDaemonSetTolerations(ctx context.Context, ds *appsv1.DaemonSet, nodesensor *falconv1alpha1.FalconNodeSensor, logger logr.Logger) (bool, error) {
 	tolerations := &ds.Spec.Template.Spec.Tolerations
+
+	// The DaemonSet tolerations are replaced rather than merged while all taints are tolerated
+	if nodesensor.Spec.Node.TolerateAllTaints {
+		allTaints := *nodesensor.GetTolerations()
+		if equality.Semantic.DeepEqual(*tolerations, allTaints) {
+			return false, nil
+		}
+
+		logger.Info("Updating FalconNodeSensor DaemonSet Tolerations to tolerate all taints")
+		*tolerations = allTaints
+		return true, nil
+	}
+
 	origTolerations := nodesensor.Spec.Node.Tolerations
 	tolerationsUpdate := !equality.Semantic.DeepEqual(*tolerations, *origTolerations)
 	if tolerationsUpdate {
 		logger.Info("Updating FalconNodeSensor DaemonSet Tolerations")
-		mergedTolerations := k8s_utils.MergeTolerations(*tolerations, *origTolerations)
+		// Drop the toleration of all taints left behind by tolerateAllTaints, unless it is configured explicitly
+		existingTolerations := slices.DeleteFunc(slices.Clone(*tolerations), func(toleration corev1.Toleration) bool {
+			return equality.Semantic.DeepEqual(toleration, falconv1alpha1.AllTaintsToleration)
+		})
+		mergedTolerations := k8s_utils.MergeTolerations(existingTolerations, *origTolerations)
 		*tolerations = mergedTolerations
 		nodesensor.Spec.Node.Tolerations = &mergedTolerations
 
@@ -686,6 +868,18 @@ func updateDaemonSetAffinity(ds *appsv1.DaemonSet, nodesensor *falconv1alpha1.Fa
 	return affinityUpdate
 }
 
+// If an update is needed, this will update the node selector from the given DaemonSet
+func updateDaemonSetNodeSelector(ds, origDS *appsv1.DaemonSet, logger logr.Logger) bool {
+	nodeSelector := &ds.Spec.Template.Spec.NodeSelector
+	nodeSelectorUpdate := !equality.Semantic.DeepEqual(*nodeSelector, origDS.Spec.Template.Spec.NodeSelector)
+	if nodeSelectorUpdate {
+		logger.Info("Updating FalconNodeSensor DaemonSet NodeSelector")
+		*nodeSelector = origDS.Spec.Template.Spec.NodeSelector
+	}
+
+	return nodeSelectorUpdate
+}
+
 // If an update is needed, this will update the volumes from the given DaemonSet
 func updateDaemonSetVolumes(ds, origDS *appsv1.DaemonSet, logger logr.Logger) bool {
 	volumeMounts := &ds.Spec.Template.Spec.Volumes
@@ -894,7 +1088,23 @@ func (r *FalconNodeSensorReconciler) handleClusterRoleBinding(ctx context.Contex
 		return false, err
 	}
 
-	return false, nil
+	// FalconNodeSensors installed in other namespaces share the ClusterRoleBinding
+	subject := rbacv1.Subject{
+		Kind:      "ServiceAccount",
+		Name:      common.NodeServiceAccountName,
+		Namespace: nodesensor.Spec.InstallNamespace,
+	}
+	if !slices.Contains(binding.Subjects, subject) {
+		binding.Subjects = append(binding.Subjects, subject)
+
+		logger.Info("Updating FalconNodeSensor ClusterRoleBinding subjects")
+		if err := r.Update(ctx, &binding); err != nil {
+			logger.Error(err, "Failed to update ClusterRoleBinding", "ClusteRoleBinding.Name", common.NodeClusterRoleBindingName)
+			return false, err
+		}
+	}
+
+	return false, r.shareOwnership(ctx, nodesensor, &binding, logger)
 }
 
 // handleServiceAccount creates and updates the service account and grants necessary permissions to it
@@ -933,7 +1143,7 @@ func (r *FalconNodeSensorReconciler) handleServiceAccount(ctx context.Context, n
 		return false, err
 	}
 
-	return false, nil
+	return false, r.shareOwnership(ctx, nodesensor, &sa, logger)
 }
 
 // handleServiceAccount creates and updates the service account and grants necessary permissions to it
@@ -967,9 +1177,44 @@ func (r *FalconNodeSensorReconciler) handleSAAnnotations(ctx context.Context, no
 	return nil
 }
 
+// shareOwnership adds the FalconNodeSensor as an owner of an object created for another FalconNodeSensor.
+// The object is then only garbage collected once every FalconNodeSensor that uses it has been deleted.
+// Objects that were not created by the operator, such as an existing namespace, are left untouched.
+func (r *FalconNodeSensorReconciler) shareOwnership(ctx context.Context, nodesensor *falconv1alpha1.FalconNodeSensor, obj client.Object, logger logr.Logger) error {
+	ownedByNodeSensor := false
+	for _, owner := range obj.GetOwnerReferences() {
+		if owner.Kind != "FalconNodeSensor" {
+			continue
+		}
+
+		if owner.UID == nodesensor.GetUID() {
+			return nil
+		}
+		ownedByNodeSensor = true
+	}
+
+	if !ownedByNodeSensor {
+		return nil
+	}
+
+	if err := controllerutil.SetOwnerReference(nodesensor, obj, r.Scheme); err != nil {
+		logger.Error(err, "Unable to assign Owner Reference", "Name", obj.GetName())
+		return err
+	}
+
+	if err := r.Update(ctx, obj); err != nil {
+		logger.Error(err, "Failed to update Owner References", "Name", obj.GetName())
+		return err
+	}
+
+	logger.Info("Sharing object with another FalconNodeSensor", "Name", obj.GetName())
+	return nil
+}
+
 // statusUpdate updates the FalconNodeSensor CR conditions
 func (r *FalconNodeSensorReconciler) conditionsUpdate(condType string, status metav1.ConditionStatus, reason string, message string, ctx context.Context, nsType types.NamespacedName, nodesensor *falconv1alpha1.FalconNodeSensor, logger logr.Logger) error {
-	if !meta.IsStatusConditionPresentAndEqual(nodesensor.Status.Conditions, condType, status) {
+	if existing := meta.FindStatusCondition(nodesensor.Status.Conditions, condType); existing == nil || existing.Status != status || existing.Reason != reason || existing.Message != message ||
+		existing.ObservedGeneration != nodesensor.GetGeneration() {
 		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 			err := r.Get(ctx, nsType, nodesensor)
 			if err != nil {
@@ -995,152 +1240,97 @@ func (r *FalconNodeSensorReconciler) conditionsUpdate(condType string, status me
 	return nil
 }
 
-// finalizeDaemonset deletes the Daemonset running the Falcon Sensor and then runs a Daemonset to cleanup the /opt/CrowdStrike directory
-func (r *FalconNodeSensorReconciler) finalizeDaemonset(ctx context.Context, image string, serviceAccount string, nodesensor *falconv1alpha1.FalconNodeSensor, logger logr.Logger) error {
-	dsCleanupName := nodesensor.Name + "-cleanup"
-	daemonset := &appsv1.DaemonSet{}
-	pods := corev1.PodList{}
-	dsList := &appsv1.DaemonSetList{}
-	var nodeCount int32 = 0
+// resolveClusterName returns the cluster name reported by the sensor, detecting it when it is not configured and detection is enabled,
+// and reports it with the ClusterNameReady condition
+func (r *FalconNodeSensorReconciler) resolveClusterName(ctx context.Context, nsType types.NamespacedName, nodesensor *falconv1alpha1.FalconNodeSensor, logger logr.Logger) (string, error) {
+	configured := ""
+	if nodesensor.Spec.Node.ClusterName != nil {
+		configured = *nodesensor.Spec.Node.ClusterName
+	}
+
+	clusterName, condition := k8sutils.ResolveClusterName(ctx, r.Reader, configured, nodesensor.Spec.Node.DetectClusterName)
 
-	// Get a list of DS and return the DS within the correct NS
-	listOptions := &client.ListOptions{
-		LabelSelector: labels.SelectorFromSet(labels.Set{common.FalconComponentKey: common.FalconKernelSensor}),
-		Namespace:     nodesensor.Spec.InstallNamespace,
+	return clusterName, r.conditionsUpdate(condition.Type, condition.Status, condition.Reason, condition.Message, ctx, nsType, nodesensor, logger)
+}
+
+// clearFailedCondition removes the Failed condition once the configuration issue reported with the given reason is resolved
+func (r *FalconNodeSensorReconciler) clearFailedCondition(ctx context.Context, nsType types.NamespacedName, nodesensor *falconv1alpha1.FalconNodeSensor, reason string, logger logr.Logger) error {
+	if existing := meta.FindStatusCondition(nodesensor.Status.Conditions, falconv1alpha1.ConditionFailed); existing == nil || existing.Reason != reason {
+		return nil
 	}
 
-	if err := r.List(ctx, dsList, listOptions); err != nil {
-		if err = r.Reader.List(ctx, dsList, listOptions); err != nil {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := r.Get(ctx, nsType, nodesensor)
+		if err != nil {
 			return err
 		}
-	}
 
-	// Delete the Daemonset containing the sensor
-	if err := r.Delete(ctx,
-		&appsv1.DaemonSet{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: nodesensor.Name, Namespace: nodesensor.Spec.InstallNamespace,
-			},
-		}); err != nil && !errors.IsNotFound(err) {
-		logger.Error(err, "Failed to cleanup Falcon sensor DaemonSet pods")
+		meta.RemoveStatusCondition(&nodesensor.Status.Conditions, falconv1alpha1.ConditionFailed)
+		return r.Status().Update(ctx, nodesensor)
+	})
+	if err != nil {
+		logger.Error(err, "Failed to update FalconNodeSensor status", "Failed to remove the Condition at Reasoning", reason)
 		return err
 	}
 
-	// Check if the cleanup DS is created. If not, create it.
-	err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: dsCleanupName, Namespace: nodesensor.Spec.InstallNamespace}, daemonset)
-	if err != nil && errors.IsNotFound(err) {
-		// Define a new DS for cleanup
-		ds := assets.RemoveNodeDirDaemonset(dsCleanupName, image, serviceAccount, nodesensor)
+	logger.Info("FalconNodeSensor configuration issue resolved", "Reason", reason)
+	return nil
+}
 
-		// Create the cleanup DS
-		err = r.Create(ctx, ds)
+// handleSensorVersion records the sensor version selected by the tracker as a pending update, and reconciles the object to deploy it when automatic updates are enabled
+// or to retry an update policy that could not select a version.
+func (r *FalconNodeSensorReconciler) handleSensorVersion(ctx context.Context, name types.NamespacedName, sensorVersion string) error {
+	obj := &falconv1alpha1.FalconNodeSensor{}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := r.Get(ctx, name, obj)
 		if err != nil {
-			logger.Error(err, "Failed to delete node directory with cleanup DaemonSet", "Path", common.FalconHostInstallDir)
 			return err
 		}
 
-		var lastCompletedCount int32
-		var lastNodeCount int32
-		var crashloopingPodNodes []string
-		// Start inifite loop to check that all pods have either completed or are running in the DS
-		for {
-			// List all pods with the "cleanup" label in the appropriate NS
-			cleanupListOptions := &client.ListOptions{
-				LabelSelector: labels.SelectorFromSet(labels.Set{common.FalconInstanceNameKey: "cleanup"}),
-				Namespace:     nodesensor.Spec.InstallNamespace,
-			}
-			if err := r.List(ctx, &pods, cleanupListOptions); err != nil {
-				if err = r.Reader.List(ctx, &pods, cleanupListOptions); err != nil {
-					return err
-				}
-			}
-
-			// Reset completedCount each loop, to ensure we don't count the same node(s) multiple times
-			var completedCount int32 = 0
-			// Reset the nodeCount to the desired number of pods to be scheduled for cleanup each loop, in case the cluster has scaled down
-			for _, dSet := range dsList.Items {
-				nodeCount = dSet.Status.DesiredNumberScheduled
-				if lastNodeCount != nodeCount {
-					logger.Info("Setting DaemonSet node count", "Number of nodes", nodeCount)
-				}
-				lastNodeCount = nodeCount
-			}
-
-			// When the pods have a status of completed or running, increment the count.
-			// The reason running is an acceptable value is because the pods should be running the sleep command and have already cleaned up /opt/CrowdStrike
-			for _, pod := range pods.Items {
-				switch pod.Status.Phase {
-				case "Running", "Succeeded":
-					completedCount++
-				case "Pending":
-					if k8sutils.IsInitPodCrashLooping(&pod) {
-						if !slices.Contains(crashloopingPodNodes, pod.Spec.NodeName) {
-							logger.Info(fmt.Sprintf("/opt/CrowdStrike may have not been removed on node %s due to the cleanup pod crashlooping. See the troubleshooting section of the node sensor documentation for more information.", pod.Spec.NodeName))
-							_ = append(crashloopingPodNodes, pod.Spec.NodeName)
-						}
-						completedCount++
-					}
-				}
-			}
-
-			// Break out of the infinite loop for cleanup when the completed or running DS count reaches the desired node count
-			if completedCount == nodeCount {
-				logger.Info("Clean up pods should be done. Continuing deleting.")
-				break
-			} else if completedCount < nodeCount && completedCount > 0 {
-				if completedCount != lastCompletedCount {
-					logger.Info("Waiting for cleanup pods to complete. Retrying....", "Number of pods still processing task", completedCount)
-				}
-				lastCompletedCount = completedCount
-			}
-
-			err = common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: dsCleanupName, Namespace: nodesensor.Spec.InstallNamespace}, daemonset)
-			if err != nil && errors.IsNotFound(err) {
-				logger.Info("Clean-up daemonset has been removed")
-				break
-			}
-		}
-
-		// The cleanup DS should be completed so delete the cleanup DS
-		if err := r.Delete(ctx,
-			&appsv1.DaemonSet{
-				ObjectMeta: metav1.ObjectMeta{
-					Name: dsCleanupName, Namespace: nodesensor.Spec.InstallNamespace,
-				},
-			}); err != nil && !errors.IsNotFound(err) {
-			logger.Error(err, "Failed to cleanup Falcon sensor DaemonSet pods")
-			return err
+		update := sensorversion.AvailableUpdate(obj.Status.AvailableUpdate, obj.Status.Sensor, sensorVersion, obj.Spec.Node.Advanced.AvailableUpdateSource())
+		if update == obj.Status.AvailableUpdate {
+			return nil
 		}
 
-		// If we have gotten here, the cleanup should be successful
-		logger.Info("Successfully deleted node directory", "Path", common.FalconDataDir)
-	} else if err != nil {
-		logger.Error(err, "error getting the cleanup DaemonSet")
-		return err
+		obj.Status.AvailableUpdate = update
+		return r.Status().Update(ctx, obj)
+	})
+	if errors.IsNotFound(err) {
+		return nil
 	}
-
-	logger.Info("Successfully finalized daemonset")
-	return nil
-}
-
-func (r *FalconNodeSensorReconciler) reconcileObjectWithName(ctx context.Context, name types.NamespacedName) error {
-	obj := &falconv1alpha1.FalconNodeSensor{}
-	err := r.Get(ctx, name, obj)
 	if err != nil {
 		return err
 	}
 
-	clog.FromContext(ctx).Info("reconciling FalconNodeSensor object", "namespace", obj.Namespace, "name", obj.Name)
-	r.reconcileObject(obj)
+	if obj.Spec.Node.Advanced.IsAutoUpdating() || sensorversion.IsBlockedByUpdatePolicy(obj.Status.Conditions) {
+		clog.FromContext(ctx).Info("reconciling FalconNodeSensor object", "namespace", obj.Namespace, "name", obj.Name)
+		r.reconcileObject(obj)
+	}
+
 	return nil
 }
 
+// sensorVersionTrackKey identifies the sensor version track of a FalconNodeSensor.
+func sensorVersionTrackKey(name types.NamespacedName) sensorversion.Key {
+	return sensorversion.NewKey(falconv1alpha1.GroupVersion.WithKind("FalconNodeSensor"), name)
+}
+
+// shouldTrackSensorVersions reports whether new sensor versions are tracked, either to deploy them automatically or to report them as pending updates.
+// Tracking is opt-in, so resources that only use the Falcon API to pick the initial image do not poll it.
 func shouldTrackSensorVersions(obj *falconv1alpha1.FalconNodeSensor) bool {
-	return obj.Spec.FalconAPI != nil && obj.Spec.Node.Advanced.IsAutoUpdating()
+	return obj.Spec.FalconAPI != nil && obj.Spec.Node.Image == "" &&
+		obj.Spec.Node.Advanced.IsTrackingUpdates()
+}
+
+// shouldCheckUpdatePolicy reports whether the sensor version is selected by an update policy rather than an explicit image or version.
+func shouldCheckUpdatePolicy(obj *falconv1alpha1.FalconNodeSensor) bool {
+	return obj.Spec.FalconAPI != nil && obj.Spec.Node.Image == "" &&
+		(obj.Spec.Node.Version == nil || *obj.Spec.Node.Version == "") &&
+		obj.Spec.Node.Advanced.HasUpdatePolicy()
 }
 
 func (r *FalconNodeSensorReconciler) injectFalconSecretData(ctx context.Context, nodeSensor *falconv1alpha1.FalconNodeSensor, logger logr.Logger) error {
 	logger.Info("injecting Falcon secret data into Spec.Falcon and Spec.FalconAPI - sensitive manifest values will be overwritten with values in k8s secret")
 
-	return k8sutils.InjectFalconSecretData(ctx, r, nodeSensor)
+	return k8sutils.InjectFalconSecretData(ctx, r, nodeSensor, nodeSensor.Spec.InstallNamespace)
 }
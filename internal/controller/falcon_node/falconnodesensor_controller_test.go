@@ -17,6 +17,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -134,10 +135,11 @@ var _ = Describe("FalconNodeSensor controller", func() {
 			defer cancel()
 
 			falconNodeReconciler := &FalconNodeSensorReconciler{
-				Client:  k8sClient,
-				Reader:  k8sReader,
-				Scheme:  k8sClient.Scheme(),
-				tracker: tracker,
+				Client:   k8sClient,
+				Reader:   k8sReader,
+				Scheme:   k8sClient.Scheme(),
+				tracker:  tracker,
+				recorder: &events.FakeRecorder{},
 			}
 
 			_, err = falconNodeReconciler.Reconcile(ctx, reconcile.Request{
@@ -304,10 +306,11 @@ var _ = Describe("FalconNodeSensor controller", func() {
 			defer cancel()
 
 			falconNodeSensorReconciler := &FalconNodeSensorReconciler{
-				Client:  k8sClient,
-				Reader:  k8sReader,
-				Scheme:  k8sClient.Scheme(),
-				tracker: tracker,
+				Client:   k8sClient,
+				Reader:   k8sReader,
+				Scheme:   k8sClient.Scheme(),
+				tracker:  tracker,
+				recorder: &events.FakeRecorder{},
 			}
 
 			// FalconNodeSensor needs to reconcile 5 times to complete all steps of the reconciler
@@ -399,10 +402,11 @@ var _ = Describe("FalconNodeSensor controller", func() {
 			defer cancel()
 
 			reconciler := &FalconNodeSensorReconciler{
-				Client:  k8sClient,
-				Reader:  k8sReader,
-				Scheme:  k8sClient.Scheme(),
-				tracker: tracker,
+				Client:   k8sClient,
+				Reader:   k8sReader,
+				Scheme:   k8sClient.Scheme(),
+				tracker:  tracker,
+				recorder: &events.FakeRecorder{},
 			}
 
 			// FalconNodeSensor needs to reconcile multiple times
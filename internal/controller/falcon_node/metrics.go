@@ -0,0 +1,19 @@
+package falcon
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// uncoveredNodes is the number of Linux nodes selected by a FalconNodeSensor that its DaemonSet cannot be scheduled on
+var uncoveredNodes = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "falcon_operator_nodesensor_uncovered_nodes",
+		Help: "Number of Linux nodes selected by a FalconNodeSensor that cannot run the sensor because of taints, node affinity or node labels",
+	},
+	[]string{"falconnodesensor"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(uncoveredNodes)
+}
@@ -0,0 +1,114 @@
+package falcon
+
+import (
+	"context"
+	"fmt"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// nodeSelectorsOverlap reports whether a node can match both node selectors.
+// Two selectors only select disjoint sets of nodes when they require different values for the same label.
+func nodeSelectorsOverlap(a, b map[string]string) bool {
+	for key, value := range a {
+		if other, ok := b[key]; ok && other != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// precedes reports whether nodesensor keeps the nodes it shares with other.
+// The oldest FalconNodeSensor wins so that a new FalconNodeSensor never disrupts sensors that are already running.
+func precedes(nodesensor, other *falconv1alpha1.FalconNodeSensor) bool {
+	if !nodesensor.CreationTimestamp.Equal(&other.CreationTimestamp) {
+		return nodesensor.CreationTimestamp.Before(&other.CreationTimestamp)
+	}
+
+	return nodesensor.Name < other.Name
+}
+
+// overlappingNodeSensor returns the FalconNodeSensor that takes precedence over nodesensor on some of its nodes, or nil when nodesensor does not overlap with any other FalconNodeSensor.
+func overlappingNodeSensor(nodesensor *falconv1alpha1.FalconNodeSensor, nodesensors []falconv1alpha1.FalconNodeSensor) *falconv1alpha1.FalconNodeSensor {
+	for i := range nodesensors {
+		other := &nodesensors[i]
+		if other.UID == nodesensor.UID || other.GetDeletionTimestamp() != nil || !precedes(other, nodesensor) {
+			continue
+		}
+
+		if nodeSelectorsOverlap(nodesensor.Spec.Node.NodeSelector, other.Spec.Node.NodeSelector) {
+			return other
+		}
+	}
+
+	return nil
+}
+
+// nodeSelectorOverlapMessage describes why nodesensor is not deployed.
+func nodeSelectorOverlapMessage(other *falconv1alpha1.FalconNodeSensor) string {
+	if len(other.Spec.Node.NodeSelector) == 0 {
+		return fmt.Sprintf("FalconNodeSensor nodeSelector overlaps with FalconNodeSensor %s, which targets all nodes. "+
+			"Set a nodeSelector on both FalconNodeSensors that requires a different value for the same label.", other.Name)
+	}
+
+	return fmt.Sprintf("FalconNodeSensor nodeSelector overlaps with FalconNodeSensor %s, which already targets some of the same nodes. "+
+		"Set a nodeSelector that requires a different value for a label used in the nodeSelector of %s.", other.Name, other.Name)
+}
+
+// removeOverlappingDaemonSet deletes the DaemonSet of a FalconNodeSensor that overlaps with another FalconNodeSensor,
+// so that only the sensor of the FalconNodeSensor that takes precedence runs on the shared nodes.
+// The DaemonSet is created again once the overlap is resolved.
+func (r *FalconNodeSensorReconciler) removeOverlappingDaemonSet(ctx context.Context, nodesensor, other *falconv1alpha1.FalconNodeSensor, logger logr.Logger) error {
+	daemonset := &appsv1.DaemonSet{}
+	if err := r.Get(ctx, types.NamespacedName{Name: nodesensor.Name, Namespace: nodesensor.Spec.InstallNamespace}, daemonset); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if !metav1.IsControlledBy(daemonset, nodesensor) || daemonset.GetDeletionTimestamp() != nil {
+		return nil
+	}
+
+	if err := r.Delete(ctx, daemonset, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		logger.Error(err, "Failed to delete DaemonSet of overlapping FalconNodeSensor", "DaemonSet.Namespace", daemonset.Namespace, "DaemonSet.Name", daemonset.Name)
+		return err
+	}
+
+	logger.Info("Deleted DaemonSet of overlapping FalconNodeSensor", "DaemonSet.Namespace", daemonset.Namespace, "DaemonSet.Name", daemonset.Name, "FalconNodeSensor", other.Name)
+	r.recorder.Eventf(nodesensor, daemonset, corev1.EventTypeWarning, falconv1alpha1.ReasonNodeSelectorOverlap, "Delete",
+		"Deleted DaemonSet %s while the nodeSelector overlaps with FalconNodeSensor %s", daemonset.Name, other.Name)
+
+	return nil
+}
+
+// enqueueOtherNodeSensors requeues every other FalconNodeSensor when one changes, so that overlapping node selectors are re-evaluated.
+func (r *FalconNodeSensorReconciler) enqueueOtherNodeSensors(ctx context.Context, obj client.Object) []reconcile.Request {
+	nodesensors := &falconv1alpha1.FalconNodeSensorList{}
+	if err := r.List(ctx, nodesensors); err != nil {
+		clog.FromContext(ctx).Error(err, "unable to list FalconNodeSensors")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, nodesensor := range nodesensors.Items {
+		if nodesensor.Name == obj.GetName() {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: nodesensor.Name}})
+	}
+
+	return requests
+}
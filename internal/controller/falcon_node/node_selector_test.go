@@ -0,0 +1,172 @@
+package falcon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNodeSelectorsOverlap(t *testing.T) {
+	tests := []struct {
+		name string
+		a    map[string]string
+		b    map[string]string
+		want bool
+	}{
+		{
+			name: "both select all nodes",
+			want: true,
+		},
+		{
+			name: "one selects all nodes",
+			a:    map[string]string{"pool": "gpu"},
+			want: true,
+		},
+		{
+			name: "same label with different values",
+			a:    map[string]string{"pool": "gpu"},
+			b:    map[string]string{"pool": "general"},
+			want: false,
+		},
+		{
+			name: "same label with same value",
+			a:    map[string]string{"pool": "gpu"},
+			b:    map[string]string{"pool": "gpu"},
+			want: true,
+		},
+		{
+			name: "different labels",
+			a:    map[string]string{"pool": "gpu"},
+			b:    map[string]string{"os": "bottlerocket"},
+			want: true,
+		},
+		{
+			name: "one conflicting label among matching labels",
+			a:    map[string]string{"pool": "gpu", "zone": "a"},
+			b:    map[string]string{"pool": "gpu", "zone": "b"},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nodeSelectorsOverlap(tt.a, tt.b))
+			assert.Equal(t, tt.want, nodeSelectorsOverlap(tt.b, tt.a))
+		})
+	}
+}
+
+func TestOverlappingNodeSensor(t *testing.T) {
+	now := time.Now()
+	general := newTestNodeSensor("general", now, map[string]string{"pool": "general"})
+	gpu := newTestNodeSensor("gpu", now.Add(time.Minute), map[string]string{"pool": "gpu"})
+	all := newTestNodeSensor("all", now.Add(2*time.Minute), nil)
+	nodesensors := []falconv1alpha1.FalconNodeSensor{*general, *gpu, *all}
+
+	assert.Nil(t, overlappingNodeSensor(general, nodesensors))
+	assert.Nil(t, overlappingNodeSensor(gpu, nodesensors))
+
+	other := overlappingNodeSensor(all, nodesensors)
+	if assert.NotNil(t, other) {
+		assert.Equal(t, "general", other.Name)
+	}
+}
+
+func TestOverlappingNodeSensor_WithSameCreationTime_OrdersByName(t *testing.T) {
+	now := time.Now()
+	a := newTestNodeSensor("a", now, nil)
+	b := newTestNodeSensor("b", now, nil)
+	nodesensors := []falconv1alpha1.FalconNodeSensor{*b, *a}
+
+	assert.Nil(t, overlappingNodeSensor(a, nodesensors))
+
+	other := overlappingNodeSensor(b, nodesensors)
+	if assert.NotNil(t, other) {
+		assert.Equal(t, "a", other.Name)
+	}
+}
+
+func TestOverlappingNodeSensor_IgnoresDeletedNodeSensors(t *testing.T) {
+	now := time.Now()
+	deleted := newTestNodeSensor("deleted", now, nil)
+	deleted.DeletionTimestamp = &metav1.Time{Time: now.Add(time.Hour)}
+	replacement := newTestNodeSensor("replacement", now.Add(time.Minute), nil)
+
+	assert.Nil(t, overlappingNodeSensor(replacement, []falconv1alpha1.FalconNodeSensor{*deleted, *replacement}))
+}
+
+func TestNodeSelectorOverlapMessage(t *testing.T) {
+	all := newTestNodeSensor("all", time.Now(), nil)
+	assert.Contains(t, nodeSelectorOverlapMessage(all), "FalconNodeSensor all, which targets all nodes")
+
+	gpu := newTestNodeSensor("gpu", time.Now(), map[string]string{"pool": "gpu"})
+	assert.Contains(t, nodeSelectorOverlapMessage(gpu), "FalconNodeSensor gpu, which already targets some of the same nodes")
+}
+
+func TestRemoveOverlappingDaemonSet(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	general := newTestNodeSensor("general", now, nil)
+	all := newTestNodeSensor("all", now.Add(time.Minute), nil)
+	all.Spec.InstallNamespace = "falcon-system"
+
+	isController := true
+	daemonset := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      all.Name,
+			Namespace: all.Spec.InstallNamespace,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: falconv1alpha1.GroupVersion.String(),
+				Kind:       "FalconNodeSensor",
+				Name:       all.Name,
+				UID:        all.UID,
+				Controller: &isController,
+			}},
+		},
+	}
+	unowned := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: general.Name, Namespace: all.Spec.InstallNamespace}}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, falconv1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(general, all, daemonset, unowned).Build()
+
+	r := &FalconNodeSensorReconciler{Client: fakeClient, Scheme: scheme, recorder: &events.FakeRecorder{}}
+	require.NoError(t, r.removeOverlappingDaemonSet(ctx, all, general, logr.Discard()))
+
+	err := fakeClient.Get(ctx, client.ObjectKeyFromObject(daemonset), &appsv1.DaemonSet{})
+	assert.True(t, errors.IsNotFound(err), "DaemonSet of the overlapping FalconNodeSensor should be deleted")
+
+	// A DaemonSet with the same name that the FalconNodeSensor does not own is left alone
+	general.Spec.InstallNamespace = all.Spec.InstallNamespace
+	require.NoError(t, r.removeOverlappingDaemonSet(ctx, general, all, logr.Discard()))
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(unowned), &appsv1.DaemonSet{}))
+
+	// Nothing to delete once the DaemonSet is gone
+	require.NoError(t, r.removeOverlappingDaemonSet(ctx, all, general, logr.Discard()))
+}
+
+func newTestNodeSensor(name string, created time.Time, nodeSelector map[string]string) *falconv1alpha1.FalconNodeSensor {
+	nodesensor := &falconv1alpha1.FalconNodeSensor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			UID:               types.UID(name),
+			CreationTimestamp: metav1.Time{Time: created},
+		},
+	}
+	nodesensor.Spec.Node.NodeSelector = nodeSelector
+	return nodesensor
+}
@@ -0,0 +1,415 @@
+package falcon
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/internal/controller/assets"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// maxReportedNodes limits the number of nodes listed in each node list of the FalconNodeSensor status
+const maxReportedNodes = 500
+
+// daemonSetTolerations are the tolerations that the DaemonSet controller adds to every DaemonSet pod
+var daemonSetTolerations = []corev1.Toleration{
+	{Key: corev1.TaintNodeNotReady, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+	{Key: corev1.TaintNodeUnreachable, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+	{Key: corev1.TaintNodeDiskPressure, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: corev1.TaintNodeMemoryPressure, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: corev1.TaintNodePIDPressure, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: corev1.TaintNodeUnschedulable, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: corev1.TaintNodeNetworkUnavailable, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+}
+
+// nodeSelectorOperators maps the node selector operators to label selector operators
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+// NodeStatusReconciler keeps the node list of the FalconNodeSensor status up to date when sensor pods change state.
+// It only refreshes the status, so sensor pod transitions do not run the full FalconNodeSensor reconciliation.
+type NodeStatusReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	recorder events.EventRecorder
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NodeStatusReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.recorder = mgr.GetEventRecorder("falconnodesensor")
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("falconnodesensor-nodes").
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(enqueueNodeSensorOfPod),
+			builder.WithPredicates(predicate.NewPredicateFuncs(isSensorPod), podStateChanged)).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=falcon.crowdstrike.com,resources=falconnodesensors/status,verbs=get;update;patch
+
+// Reconcile refreshes the node list of the FalconNodeSensor status.
+func (r *NodeStatusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := clog.FromContext(ctx).WithValues("FalconNodeSensor", req.Name)
+
+	nodesensor := &falconv1alpha1.FalconNodeSensor{}
+	if err := r.Get(ctx, req.NamespacedName, nodesensor); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if nodesensor.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, nil
+	}
+
+	// The DaemonSet is created by the FalconNodeSensor reconciler, which also reports the node status
+	daemonset := &appsv1.DaemonSet{}
+	if err := r.Get(ctx, types.NamespacedName{Name: nodesensor.Name, Namespace: nodesensor.Spec.InstallNamespace}, daemonset); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	return ctrl.Result{}, updateNodesStatus(ctx, r.Client, r.recorder, req.NamespacedName, nodesensor, daemonset, logger)
+}
+
+// updateNodesStatus reports the state of the sensor on the nodes targeted by the DaemonSet in the FalconNodeSensor status.
+// Linux nodes that the DaemonSet cannot be scheduled on are also reported with an Event and the uncovered nodes metric.
+func updateNodesStatus(ctx context.Context, c client.Client, recorder events.EventRecorder, nsType types.NamespacedName, nodesensor *falconv1alpha1.FalconNodeSensor, daemonset *appsv1.DaemonSet, logger logr.Logger) error {
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(daemonset.Namespace), client.MatchingLabels(daemonset.Spec.Selector.MatchLabels)); err != nil {
+		logger.Error(err, "Failed to list FalconNodeSensor pods")
+		return err
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := c.List(ctx, nodes, client.MatchingLabels(nodesensor.Spec.Node.NodeSelector)); err != nil {
+		logger.Error(err, "Failed to list nodes")
+		return err
+	}
+
+	status := nodesStatus(daemonset, pods.Items, nodes.Items, logger)
+	uncoveredNodes.WithLabelValues(nodesensor.Name).Set(float64(status.ExcludedCount))
+
+	if equality.Semantic.DeepEqual(nodesensor.Status.Nodes, status) {
+		return nil
+	}
+
+	previous := map[string]string{}
+	if nodesensor.Status.Nodes != nil {
+		for _, excluded := range nodesensor.Status.Nodes.Excluded {
+			previous[excluded.Name] = excluded.Reason
+		}
+	}
+
+	for _, excluded := range status.Excluded {
+		if previous[excluded.Name] == excluded.Reason {
+			continue
+		}
+
+		excludedNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: excluded.Name}}
+		recorder.Eventf(nodesensor, excludedNode, corev1.EventTypeWarning, falconv1alpha1.ReasonNodeNotCovered, "Schedule",
+			"The sensor cannot run on node %s: %s", excluded.Name, excluded.Reason)
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := c.Get(ctx, nsType, nodesensor)
+		if err != nil {
+			return err
+		}
+
+		nodesensor.Status.Nodes = status
+		return c.Status().Update(ctx, nodesensor)
+	})
+	if err != nil {
+		logger.Error(err, "Failed to update FalconNodeSensor status for nodesensor.Status.Nodes")
+		return err
+	}
+
+	return nil
+}
+
+// nodesStatus summarizes the DaemonSet status, its pods and the Linux nodes that it cannot be scheduled on.
+// The nodes are expected to match the nodeSelector of the FalconNodeSensor.
+func nodesStatus(daemonset *appsv1.DaemonSet, pods []corev1.Pod, nodes []corev1.Node, logger logr.Logger) *falconv1alpha1.FalconNodeSensorNodes {
+	status := &falconv1alpha1.FalconNodeSensorNodes{
+		Desired:     daemonset.Status.DesiredNumberScheduled,
+		Ready:       daemonset.Status.NumberReady,
+		Updated:     daemonset.Status.UpdatedNumberScheduled,
+		Unavailable: daemonset.Status.NumberUnavailable,
+	}
+
+	scheduled := map[string]bool{}
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.GetDeletionTimestamp() != nil {
+			continue
+		}
+		scheduled[pod.Spec.NodeName] = true
+
+		if isCrashLooping(pod) {
+			status.CrashLooping = append(status.CrashLooping, pod.Spec.NodeName)
+		}
+
+		if len(pod.Spec.Containers) > 0 && pod.Status.Phase == corev1.PodRunning {
+			status.Sensors = append(status.Sensors, falconv1alpha1.FalconNodeSensorNodeVersion{
+				Name:    pod.Spec.NodeName,
+				Version: *common.ImageVersion(pod.Spec.Containers[0].Image),
+			})
+		}
+	}
+
+	for _, node := range nodes {
+		if scheduled[node.Name] || !isLinuxNode(&node) {
+			continue
+		}
+
+		if reason := nodeExclusionReason(&node, &daemonset.Spec.Template.Spec, logger); reason != "" {
+			status.Excluded = append(status.Excluded, falconv1alpha1.FalconNodeSensorExcludedNode{Name: node.Name, Reason: reason})
+		}
+	}
+	status.ExcludedCount = int32(len(status.Excluded))
+
+	slices.Sort(status.CrashLooping)
+	slices.SortFunc(status.Excluded, func(a, b falconv1alpha1.FalconNodeSensorExcludedNode) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	slices.SortFunc(status.Sensors, func(a, b falconv1alpha1.FalconNodeSensorNodeVersion) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	status.CrashLooping = status.CrashLooping[:min(len(status.CrashLooping), maxReportedNodes)]
+	status.Excluded = status.Excluded[:min(len(status.Excluded), maxReportedNodes)]
+	status.Sensors = status.Sensors[:min(len(status.Sensors), maxReportedNodes)]
+
+	return status
+}
+
+// isLinuxNode reports whether the node runs Linux, based on the operating system reported by the kubelet or the kubernetes.io/os label
+func isLinuxNode(node *corev1.Node) bool {
+	if os := node.Status.NodeInfo.OperatingSystem; os != "" {
+		return os == "linux"
+	}
+
+	return node.Labels[corev1.LabelOSStable] == "linux"
+}
+
+// isCrashLooping reports whether a container or init container of the pod is in CrashLoopBackOff
+func isCrashLooping(pod corev1.Pod) bool {
+	for _, containerStatus := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		if containerStatus.State.Waiting != nil && containerStatus.State.Waiting.Reason == "CrashLoopBackOff" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nodeExclusionReason returns why pods with the pod spec cannot be scheduled on the node, or an empty string when they can.
+func nodeExclusionReason(node *corev1.Node, podSpec *corev1.PodSpec, logger logr.Logger) string {
+	return schedulingConflict(node, podSpec, logger, corev1.TaintEffectNoSchedule, corev1.TaintEffectNoExecute)
+}
+
+// evictionReason returns why the DaemonSet controller removes a running pod with the pod spec from the node, or an empty string when the pod keeps running.
+// Unlike new pods, running pods are not removed because of NoSchedule taints.
+func evictionReason(node *corev1.Node, podSpec *corev1.PodSpec, logger logr.Logger) string {
+	return schedulingConflict(node, podSpec, logger, corev1.TaintEffectNoExecute)
+}
+
+// schedulingConflict returns why pods with the pod spec do not fit the node, considering only taints with the given effects
+func schedulingConflict(node *corev1.Node, podSpec *corev1.PodSpec, logger logr.Logger, taintEffects ...corev1.TaintEffect) string {
+	if !labels.SelectorFromSet(podSpec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return fmt.Sprintf("node labels do not match the node selector %s", labels.Set(podSpec.NodeSelector))
+	}
+
+	tolerations := slices.Concat(podSpec.Tolerations, daemonSetTolerations)
+	for _, taint := range node.Spec.Taints {
+		if !slices.Contains(taintEffects, taint.Effect) {
+			continue
+		}
+
+		tolerated := slices.ContainsFunc(tolerations, func(toleration corev1.Toleration) bool {
+			return toleration.ToleratesTaint(logger, &taint, false)
+		})
+		if !tolerated {
+			return fmt.Sprintf("untolerated taint %s", taint.ToString())
+		}
+	}
+
+	if podSpec.Affinity != nil && podSpec.Affinity.NodeAffinity != nil {
+		if required := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution; required != nil && !matchesNodeSelectorTerms(node, required.NodeSelectorTerms) {
+			return "node affinity does not match"
+		}
+	}
+
+	return ""
+}
+
+// matchesNodeSelectorTerms reports whether the node matches any of the node selector terms
+func matchesNodeSelectorTerms(node *corev1.Node, terms []corev1.NodeSelectorTerm) bool {
+	for _, term := range terms {
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			continue
+		}
+
+		if matchesNodeSelectorRequirements(labels.Set(node.Labels), term.MatchExpressions) &&
+			matchesNodeSelectorRequirements(labels.Set{"metadata.name": node.Name}, term.MatchFields) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesNodeSelectorRequirements(values labels.Set, requirements []corev1.NodeSelectorRequirement) bool {
+	for _, requirement := range requirements {
+		operator, ok := nodeSelectorOperators[requirement.Operator]
+		if !ok {
+			return false
+		}
+
+		selector, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+		if err != nil || !selector.Matches(values) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// enqueueNodeSensorOfPod reconciles the FalconNodeSensor that runs a sensor pod or a node cleanup pod
+func enqueueNodeSensorOfPod(ctx context.Context, obj client.Object) []reconcile.Request {
+	if !isSensorPod(obj) && !isNodeCleanupPod(obj) {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: obj.GetLabels()[common.FalconInstanceKey]}}}
+}
+
+// podStateChanged ignores pod updates that do not change the reported node status, such as probe results that leave the readiness unchanged
+var podStateChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldPod, ok := e.ObjectOld.(*corev1.Pod)
+		if !ok {
+			return false
+		}
+		newPod, ok := e.ObjectNew.(*corev1.Pod)
+		if !ok {
+			return false
+		}
+
+		return oldPod.Spec.NodeName != newPod.Spec.NodeName ||
+			oldPod.Status.Phase != newPod.Status.Phase ||
+			isPodReady(oldPod) != isPodReady(newPod) ||
+			isCrashLooping(*oldPod) != isCrashLooping(*newPod) ||
+			(oldPod.GetDeletionTimestamp() == nil) != (newPod.GetDeletionTimestamp() == nil)
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+// podPhaseChanged ignores pod updates that do not change the pod phase
+var podPhaseChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldPod, ok := e.ObjectOld.(*corev1.Pod)
+		if !ok {
+			return false
+		}
+		newPod, ok := e.ObjectNew.(*corev1.Pod)
+		if !ok {
+			return false
+		}
+
+		return oldPod.Status.Phase != newPod.Status.Phase
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+// isPodReady reports whether the Ready condition of the pod is true
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+// isNodeCleanupPod reports whether the pod removes the sensor from a node that left the DaemonSet
+func isNodeCleanupPod(obj client.Object) bool {
+	podLabels := obj.GetLabels()
+	return podLabels[common.FalconComponentKey] == common.FalconKernelSensor &&
+		podLabels[common.FalconInstanceNameKey] == assets.NodeCleanupInstanceName &&
+		podLabels[common.FalconInstanceKey] != ""
+}
+
+// enqueueNodeSensorsForNode reconciles all FalconNodeSensors when a node is added, removed or its scheduling constraints change
+func (r *FalconNodeSensorReconciler) enqueueNodeSensorsForNode(ctx context.Context, obj client.Object) []reconcile.Request {
+	nodesensors := &falconv1alpha1.FalconNodeSensorList{}
+	if err := r.List(ctx, nodesensors); err != nil {
+		clog.FromContext(ctx).Error(err, "Failed to list FalconNodeSensors")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(nodesensors.Items))
+	for _, nodesensor := range nodesensors.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: nodesensor.Name}})
+	}
+
+	return requests
+}
+
+// nodeSchedulingChanged ignores node updates that do not change whether the sensor can be scheduled on or supports the node, such as status heartbeats
+var nodeSchedulingChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldNode, ok := e.ObjectOld.(*corev1.Node)
+		if !ok {
+			return false
+		}
+		newNode, ok := e.ObjectNew.(*corev1.Node)
+		if !ok {
+			return false
+		}
+
+		return !maps.Equal(oldNode.Labels, newNode.Labels) ||
+			!equality.Semantic.DeepEqual(oldNode.Spec.Taints, newNode.Spec.Taints) ||
+			oldNode.Status.NodeInfo.OperatingSystem != newNode.Status.NodeInfo.OperatingSystem ||
+			oldNode.Status.NodeInfo.Architecture != newNode.Status.NodeInfo.Architecture ||
+			oldNode.Status.NodeInfo.OSImage != newNode.Status.NodeInfo.OSImage ||
+			oldNode.Status.NodeInfo.KernelVersion != newNode.Status.NodeInfo.KernelVersion ||
+			oldNode.Status.NodeInfo.ContainerRuntimeVersion != newNode.Status.NodeInfo.ContainerRuntimeVersion
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
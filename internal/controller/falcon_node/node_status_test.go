@@ -0,0 +1,258 @@
+package falcon
+
+import (
+	"context"
+	"testing"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestNodesStatus(t *testing.T) {
+	daemonset := &appsv1.DaemonSet{
+		Status: appsv1.DaemonSetStatus{
+			DesiredNumberScheduled: 3,
+			NumberReady:            1,
+			UpdatedNumberScheduled: 2,
+			NumberUnavailable:      2,
+		},
+	}
+	daemonset.Spec.Template.Spec.NodeSelector = map[string]string{corev1.LabelOSStable: "linux"}
+	daemonset.Spec.Template.Spec.Tolerations = []corev1.Toleration{{Key: "pool", Operator: corev1.TolerationOpEqual, Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+
+	pods := []corev1.Pod{
+		newTestSensorPod("node-b", "registry/falcon-sensor:7.31.0-18410-1.falcon-linux.Release.US-1", corev1.PodRunning, nil),
+		newTestSensorPod("node-a", "registry/falcon-sensor:7.30.0-18306-1.falcon-linux.Release.US-1", corev1.PodRunning, nil),
+		newTestSensorPod("node-c", "registry/falcon-sensor:7.31.0-18410-1.falcon-linux.Release.US-1", corev1.PodPending, &corev1.ContainerStatus{
+			Name:  "init-falconstore",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		}),
+	}
+
+	linux := map[string]string{corev1.LabelOSStable: "linux"}
+	nodes := []corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: linux}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-b", Labels: linux}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-c", Labels: linux}},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-d", Labels: linux},
+			Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: "dedicated", Value: "db", Effect: corev1.TaintEffectNoSchedule}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-e", Labels: linux},
+			Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: "pool", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-f"},
+			Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{OperatingSystem: "linux"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-g", Labels: map[string]string{corev1.LabelOSStable: "windows"}},
+			Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: "os", Value: "windows", Effect: corev1.TaintEffectNoSchedule}}},
+		},
+	}
+
+	want := &falconv1alpha1.FalconNodeSensorNodes{
+		Desired:       3,
+		Ready:         1,
+		Updated:       2,
+		Unavailable:   2,
+		CrashLooping:  []string{"node-c"},
+		ExcludedCount: 2,
+		Excluded: []falconv1alpha1.FalconNodeSensorExcludedNode{
+			{Name: "node-d", Reason: "untolerated taint dedicated=db:NoSchedule"},
+			{Name: "node-f", Reason: "node labels do not match the node selector kubernetes.io/os=linux"},
+		},
+		Sensors: []falconv1alpha1.FalconNodeSensorNodeVersion{
+			{Name: "node-a", Version: "7.30.0-18306-1.falcon-linux.Release.US-1"},
+			{Name: "node-b", Version: "7.31.0-18410-1.falcon-linux.Release.US-1"},
+		},
+	}
+
+	got := nodesStatus(daemonset, pods, nodes, logr.Discard())
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("nodesStatus() mismatch (-want +got): %s", diff)
+	}
+}
+
+func TestNodeExclusionReason(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{"pool": "gpu", "cpus": "8"}},
+		Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+			{Key: corev1.TaintNodeUnschedulable, Effect: corev1.TaintEffectNoSchedule},
+			{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule},
+		}},
+	}
+
+	affinity := func(terms ...corev1.NodeSelectorTerm) *corev1.Affinity {
+		return &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: terms},
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		affinity *corev1.Affinity
+		want     string
+	}{
+		{
+			name: "without affinity",
+		},
+		{
+			name: "matching label expression",
+			affinity: affinity(corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+				{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"gpu", "general"}},
+				{Key: "cpus", Operator: corev1.NodeSelectorOpGt, Values: []string{"4"}},
+			}}),
+		},
+		{
+			name: "one matching term",
+			affinity: affinity(
+				corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "pool", Operator: corev1.NodeSelectorOpDoesNotExist}}},
+				corev1.NodeSelectorTerm{MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-a"}}}},
+			),
+		},
+		{
+			name: "no matching term",
+			affinity: affinity(corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+				{Key: "pool", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"gpu"}},
+			}}),
+			want: "node affinity does not match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nodeExclusionReason(node, &corev1.PodSpec{Affinity: tt.affinity}, logr.Discard()))
+		})
+	}
+}
+
+func TestNodeExclusionReason_TolerateAllTaints(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: "dedicated", Value: "db", Effect: corev1.TaintEffectNoExecute}}},
+	}
+
+	nodesensor := &falconv1alpha1.FalconNodeSensor{}
+	assert.Equal(t, "untolerated taint dedicated=db:NoExecute", nodeExclusionReason(node, &corev1.PodSpec{Tolerations: *nodesensor.GetTolerations()}, logr.Discard()))
+
+	nodesensor.Spec.Node.TolerateAllTaints = true
+	assert.Empty(t, nodeExclusionReason(node, &corev1.PodSpec{Tolerations: *nodesensor.GetTolerations()}, logr.Discard()))
+}
+
+func TestNodeStatusReconcile(t *testing.T) {
+	ctx := context.Background()
+
+	nodesensor := &falconv1alpha1.FalconNodeSensor{ObjectMeta: metav1.ObjectMeta{Name: "falcon-node-sensor"}}
+	nodesensor.Spec.InstallNamespace = "falcon-system"
+
+	daemonset := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: nodesensor.Name, Namespace: nodesensor.Spec.InstallNamespace},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: common.CRLabels("daemonset", nodesensor.Name, common.FalconKernelSensor)},
+		},
+		Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 1, NumberReady: 1, UpdatedNumberScheduled: 1},
+	}
+
+	pod := newTestSensorPod("node-a", "registry/falcon-sensor:7.31.0-18410-1.falcon-linux.Release.US-1", corev1.PodRunning, nil)
+	pod.Namespace = nodesensor.Spec.InstallNamespace
+	pod.Labels = daemonset.Spec.Selector.MatchLabels
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, falconv1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(nodesensor, daemonset, &pod).
+		WithStatusSubresource(nodesensor).
+		Build()
+
+	r := &NodeStatusReconciler{Client: fakeClient, Scheme: scheme, recorder: &events.FakeRecorder{}}
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: nodesensor.Name}})
+	require.NoError(t, err)
+
+	got := &falconv1alpha1.FalconNodeSensor{}
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: nodesensor.Name}, got))
+	require.NotNil(t, got.Status.Nodes)
+	assert.Equal(t, int32(1), got.Status.Nodes.Ready)
+	assert.Equal(t, []falconv1alpha1.FalconNodeSensorNodeVersion{
+		{Name: "node-a", Version: "7.31.0-18410-1.falcon-linux.Release.US-1"},
+	}, got.Status.Nodes.Sensors)
+
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "missing"}})
+	assert.NoError(t, err)
+}
+
+func TestPodStateChanged(t *testing.T) {
+	running := newTestSensorPod("node-a", "registry/falcon-sensor:7.31.0", corev1.PodRunning, nil)
+
+	ready := running.DeepCopy()
+	ready.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+
+	probed := ready.DeepCopy()
+	probed.Status.Conditions = append(probed.Status.Conditions, corev1.PodCondition{Type: corev1.ContainersReady, Status: corev1.ConditionTrue})
+	probed.ResourceVersion = "2"
+
+	crashLooping := running.DeepCopy()
+	crashLooping.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "falcon-node-sensor",
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+	}}
+
+	terminating := running.DeepCopy()
+	terminating.DeletionTimestamp = &metav1.Time{}
+
+	pending := newTestSensorPod("node-a", "registry/falcon-sensor:7.31.0", corev1.PodPending, nil)
+
+	tests := []struct {
+		name     string
+		old, new *corev1.Pod
+		want     bool
+	}{
+		{name: "phase", old: &pending, new: &running, want: true},
+		{name: "readiness", old: &running, new: ready, want: true},
+		{name: "crash loop", old: &running, new: crashLooping, want: true},
+		{name: "deletion", old: &running, new: terminating, want: true},
+		{name: "unchanged readiness", old: ready, new: probed, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, podStateChanged.Update(event.UpdateEvent{ObjectOld: tt.old, ObjectNew: tt.new}))
+		})
+	}
+
+	assert.True(t, podPhaseChanged.Update(event.UpdateEvent{ObjectOld: &pending, ObjectNew: &running}))
+	assert.False(t, podPhaseChanged.Update(event.UpdateEvent{ObjectOld: &running, ObjectNew: ready}))
+}
+
+func newTestSensorPod(nodeName, image string, phase corev1.PodPhase, initStatus *corev1.ContainerStatus) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "falcon-node-sensor-" + nodeName},
+		Spec: corev1.PodSpec{
+			NodeName:   nodeName,
+			Containers: []corev1.Container{{Name: "falcon-node-sensor", Image: image}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+
+	if initStatus != nil {
+		pod.Status.InitContainerStatuses = []corev1.ContainerStatus{*initStatus}
+	}
+
+	return pod
+}
@@ -0,0 +1,322 @@
+package falcon
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/internal/controller/assets"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// targetedNodesKey is the key of the ConfigMap that records the nodes targeted by the DaemonSet at the last reconciliation
+const targetedNodesKey = "nodes"
+
+// handleRemovedNodes removes the sensor from the nodes that are no longer targeted by the DaemonSet with the pod spec.
+// The nodes are detected from their running sensor pod or from the nodes targeted at the last reconciliation,
+// since the DaemonSet controller may already have deleted the sensor pods of these nodes.
+// Once the sensor pod is gone, a one-shot cleanup pod removes the sensor files from the node,
+// unless the node is now targeted by the DaemonSet of another FalconNodeSensor.
+// It reports whether nodes are still waiting to be cleaned up.
+func (r *FalconNodeSensorReconciler) handleRemovedNodes(ctx context.Context, nsType types.NamespacedName, nodesensor *falconv1alpha1.FalconNodeSensor, podSpec *corev1.PodSpec, image string, serviceAccount string, logger logr.Logger) (bool, error) {
+	if !nodesensor.Spec.Node.CleanupRemovedNodes || *nodesensor.Spec.Node.GKE.Enabled {
+		if err := r.deleteTargetedNodes(ctx, nodesensor, logger); err != nil {
+			return false, err
+		}
+		if len(nodesensor.Status.RemovedNodes) > 0 {
+			return false, r.updateRemovedNodesStatus(ctx, nsType, nodesensor, nil, logger)
+		}
+		return false, nil
+	}
+
+	previouslyTargeted, err := r.getTargetedNodes(ctx, nodesensor)
+	if err != nil {
+		logger.Error(err, "Failed to get the nodes targeted at the last reconciliation")
+		return false, err
+	}
+
+	sensorPods := &corev1.PodList{}
+	if err := r.List(ctx, sensorPods, client.InNamespace(nodesensor.Spec.InstallNamespace),
+		client.MatchingLabels(common.CRLabels("daemonset", nodesensor.Name, common.FalconKernelSensor))); err != nil {
+		logger.Error(err, "Failed to list FalconNodeSensor pods")
+		return false, err
+	}
+
+	cleanupPods := &corev1.PodList{}
+	if err := r.List(ctx, cleanupPods, client.InNamespace(nodesensor.Spec.InstallNamespace),
+		client.MatchingLabels(common.CRLabels(assets.NodeCleanupInstanceName, nodesensor.Name, common.FalconKernelSensor))); err != nil {
+		logger.Error(err, "Failed to list node cleanup pods")
+		return false, err
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := r.List(ctx, nodeList); err != nil {
+		logger.Error(err, "Failed to list nodes")
+		return false, err
+	}
+
+	nodes := map[string]*corev1.Node{}
+	for i := range nodeList.Items {
+		nodes[nodeList.Items[i].Name] = &nodeList.Items[i]
+	}
+
+	now := time.Now()
+	removedNodes := trackRemovedNodes(nodesensor.Status.RemovedNodes, previouslyTargeted, sensorPods.Items, nodes, podSpec, now, logger)
+	remaining := make([]falconv1alpha1.FalconNodeSensorRemovedNode, 0, len(removedNodes))
+
+	var targetedByOthers map[string]string
+	if len(removedNodes) > 0 {
+		targetedByOthers, err = r.nodesTargetedByOthers(ctx, nodesensor, nodes, logger)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	for _, removedNode := range removedNodes {
+		node := nodes[removedNode.Name]
+		cleanupPod := podOnNode(cleanupPods.Items, removedNode.Name)
+
+		switch {
+		case node == nil || node.GetDeletionTimestamp() != nil:
+			logger.Info("Node was removed from the cluster before the sensor was removed", "Node", removedNode.Name)
+		case nodeExclusionReason(node, podSpec, logger) == "":
+			logger.Info("Node is targeted by the DaemonSet again, cancelling the sensor removal", "Node", removedNode.Name)
+		case targetedByOthers[removedNode.Name] != "":
+			logger.Info("Node is targeted by another FalconNodeSensor, cancelling the sensor removal", "Node", removedNode.Name, "FalconNodeSensor", targetedByOthers[removedNode.Name])
+		case cleanupPod != nil && cleanupPod.Status.Phase == corev1.PodSucceeded:
+			r.recorder.Eventf(nodesensor, node, corev1.EventTypeNormal, falconv1alpha1.ReasonNodeCleanedUp, "Cleanup",
+				"Removed the sensor from node %s, which is no longer targeted by the DaemonSet", removedNode.Name)
+		case cleanupPod != nil && cleanupPod.Status.Phase == corev1.PodFailed:
+			r.recorder.Eventf(nodesensor, node, corev1.EventTypeWarning, falconv1alpha1.ReasonNodeCleanupFailed, "Cleanup",
+				"Failed to remove %s from node %s, see the logs of pod %s", common.FalconHostInstallDir, removedNode.Name, cleanupPod.Name)
+		case now.After(removedNode.RemovedAt.Add(cleanupTimeout)):
+			r.recorder.Eventf(nodesensor, node, corev1.EventTypeWarning, falconv1alpha1.ReasonNodeCleanupFailed, "Cleanup",
+				"Timed out removing %s from node %s after %s", common.FalconHostInstallDir, removedNode.Name, cleanupTimeout)
+		case podOnNode(sensorPods.Items, removedNode.Name) != nil:
+			// Wait for the DaemonSet controller to delete the sensor pod
+			remaining = append(remaining, removedNode)
+			continue
+		case cleanupPod == nil:
+			pod := assets.NodeCleanupPod(removedNode.Name, image, serviceAccount, nodesensor)
+			if err := controllerutil.SetControllerReference(nodesensor, pod, r.Scheme); err != nil {
+				logger.Error(err, "Unable to assign Controller Reference to the node cleanup pod")
+			}
+
+			if err := r.Create(ctx, pod); err != nil {
+				logger.Error(err, "Failed to create node cleanup pod", "Node", removedNode.Name)
+				return false, err
+			}
+			logger.Info("Removing the sensor from node that is no longer targeted by the DaemonSet", "Node", removedNode.Name)
+			remaining = append(remaining, removedNode)
+			continue
+		default:
+			// Wait for the cleanup pod to complete
+			remaining = append(remaining, removedNode)
+			continue
+		}
+
+		if cleanupPod != nil {
+			if err := r.Delete(ctx, cleanupPod); err != nil && !errors.IsNotFound(err) {
+				logger.Error(err, "Failed to delete node cleanup pod", "Pod", cleanupPod.Name)
+				return false, err
+			}
+		}
+	}
+
+	if targeted := targetedNodes(nodes, podSpec, logger); !slices.Equal(previouslyTargeted, targeted) {
+		if err := r.updateTargetedNodes(ctx, nodesensor, targeted, logger); err != nil {
+			return false, err
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(nodesensor.Status.RemovedNodes, remaining) {
+		if err := r.updateRemovedNodesStatus(ctx, nsType, nodesensor, remaining, logger); err != nil {
+			return false, err
+		}
+	}
+
+	return len(remaining) > 0, nil
+}
+
+func (r *FalconNodeSensorReconciler) updateRemovedNodesStatus(ctx context.Context, nsType types.NamespacedName, nodesensor *falconv1alpha1.FalconNodeSensor, removedNodes []falconv1alpha1.FalconNodeSensorRemovedNode, logger logr.Logger) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := r.Get(ctx, nsType, nodesensor)
+		if err != nil {
+			return err
+		}
+
+		nodesensor.Status.RemovedNodes = removedNodes
+		return r.Status().Update(ctx, nodesensor)
+	})
+	if err != nil {
+		logger.Error(err, "Failed to update FalconNodeSensor status for nodesensor.Status.RemovedNodes")
+		return err
+	}
+
+	return nil
+}
+
+// targetedNodesConfigMapName returns the name of the ConfigMap that records the nodes targeted by the DaemonSet.
+// The list grows with the cluster, so it is kept out of the FalconNodeSensor status.
+func targetedNodesConfigMapName(nodesensor *falconv1alpha1.FalconNodeSensor) string {
+	return nodesensor.Name + "-targeted-nodes"
+}
+
+// getTargetedNodes returns the sorted names of the nodes targeted by the DaemonSet at the last reconciliation
+func (r *FalconNodeSensorReconciler) getTargetedNodes(ctx context.Context, nodesensor *falconv1alpha1.FalconNodeSensor) ([]string, error) {
+	configMap := &corev1.ConfigMap{}
+	err := common.GetNamespacedObject(ctx, r.Client, r.Reader, types.NamespacedName{Name: targetedNodesConfigMapName(nodesensor), Namespace: nodesensor.Spec.InstallNamespace}, configMap)
+	if err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	if configMap.Data[targetedNodesKey] == "" {
+		return nil, nil
+	}
+
+	return strings.Split(configMap.Data[targetedNodesKey], "\n"), nil
+}
+
+// updateTargetedNodes records the nodes targeted by the DaemonSet in a ConfigMap owned by the FalconNodeSensor
+func (r *FalconNodeSensorReconciler) updateTargetedNodes(ctx context.Context, nodesensor *falconv1alpha1.FalconNodeSensor, names []string, logger logr.Logger) error {
+	name := targetedNodesConfigMapName(nodesensor)
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: nodesensor.Spec.InstallNamespace,
+			Labels:    common.CRLabels("configmap", name, common.FalconKernelSensor),
+		},
+		Data: map[string]string{targetedNodesKey: strings.Join(names, "\n")},
+	}
+	if err := controllerutil.SetControllerReference(nodesensor, configMap, r.Scheme); err != nil {
+		logger.Error(err, "Unable to assign Controller Reference to the targeted nodes ConfigMap")
+	}
+
+	err := r.Update(ctx, configMap)
+	if errors.IsNotFound(err) {
+		err = r.Create(ctx, configMap)
+	}
+	if err != nil {
+		logger.Error(err, "Failed to update the targeted nodes ConfigMap", "ConfigMap.Namespace", configMap.Namespace, "ConfigMap.Name", configMap.Name)
+		return err
+	}
+
+	return nil
+}
+
+// deleteTargetedNodes deletes the ConfigMap of the targeted nodes once removed nodes are no longer cleaned up
+func (r *FalconNodeSensorReconciler) deleteTargetedNodes(ctx context.Context, nodesensor *falconv1alpha1.FalconNodeSensor, logger logr.Logger) error {
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: targetedNodesConfigMapName(nodesensor), Namespace: nodesensor.Spec.InstallNamespace}}
+	if err := r.Delete(ctx, configMap); err != nil && !errors.IsNotFound(err) {
+		logger.Error(err, "Failed to delete the targeted nodes ConfigMap", "ConfigMap.Namespace", configMap.Namespace, "ConfigMap.Name", configMap.Name)
+		return err
+	}
+
+	return nil
+}
+
+// nodesTargetedByOthers maps the nodes that the DaemonSet of another FalconNodeSensor targets to the name of that FalconNodeSensor.
+// The sensor of these nodes is managed by the other FalconNodeSensor and must not be removed.
+// FalconNodeSensors whose DaemonSet does not exist yet are matched by their nodeSelector.
+func (r *FalconNodeSensorReconciler) nodesTargetedByOthers(ctx context.Context, nodesensor *falconv1alpha1.FalconNodeSensor, nodes map[string]*corev1.Node, logger logr.Logger) (map[string]string, error) {
+	nodesensors := &falconv1alpha1.FalconNodeSensorList{}
+	if err := r.List(ctx, nodesensors); err != nil {
+		logger.Error(err, "Failed to list FalconNodeSensors")
+		return nil, err
+	}
+
+	targeted := map[string]string{}
+	for i := range nodesensors.Items {
+		other := &nodesensors.Items[i]
+		if other.UID == nodesensor.UID || other.GetDeletionTimestamp() != nil || overlappingNodeSensor(other, nodesensors.Items) != nil {
+			continue
+		}
+
+		daemonset := &appsv1.DaemonSet{}
+		err := r.Get(ctx, types.NamespacedName{Name: other.Name, Namespace: other.Spec.InstallNamespace}, daemonset)
+		if err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, "Failed to get DaemonSet of FalconNodeSensor", "FalconNodeSensor", other.Name)
+			return nil, err
+		}
+
+		for name, node := range nodes {
+			switch {
+			case targeted[name] != "":
+			case err == nil && nodeExclusionReason(node, &daemonset.Spec.Template.Spec, logger) == "":
+				targeted[name] = other.Name
+			case err != nil && labels.SelectorFromSet(other.Spec.Node.NodeSelector).Matches(labels.Set(node.Labels)):
+				targeted[name] = other.Name
+			}
+		}
+	}
+
+	return targeted, nil
+}
+
+// trackRemovedNodes adds the nodes that the DaemonSet no longer targets to the removed nodes: nodes running a sensor pod
+// that the DaemonSet controller will remove, and nodes targeted at the last reconciliation whose sensor pod is already gone.
+func trackRemovedNodes(removedNodes []falconv1alpha1.FalconNodeSensorRemovedNode, previouslyTargeted []string, sensorPods []corev1.Pod, nodes map[string]*corev1.Node, podSpec *corev1.PodSpec, now time.Time, logger logr.Logger) []falconv1alpha1.FalconNodeSensorRemovedNode {
+	tracked := map[string]bool{}
+	for _, removedNode := range removedNodes {
+		tracked[removedNode.Name] = true
+	}
+
+	track := func(node *corev1.Node) {
+		tracked[node.Name] = true
+		removedNodes = append(removedNodes, falconv1alpha1.FalconNodeSensorRemovedNode{Name: node.Name, RemovedAt: metav1.NewTime(now)})
+	}
+
+	for _, pod := range sensorPods {
+		node := nodes[pod.Spec.NodeName]
+		if node == nil || tracked[node.Name] || evictionReason(node, podSpec, logger) == "" {
+			continue
+		}
+		track(node)
+	}
+
+	for _, name := range previouslyTargeted {
+		node := nodes[name]
+		if node == nil || tracked[name] || podOnNode(sensorPods, name) != nil || nodeExclusionReason(node, podSpec, logger) == "" {
+			continue
+		}
+		track(node)
+	}
+
+	return removedNodes
+}
+
+// targetedNodes returns the sorted names of the nodes that the DaemonSet with the pod spec schedules a sensor pod on
+func targetedNodes(nodes map[string]*corev1.Node, podSpec *corev1.PodSpec, logger logr.Logger) []string {
+	var names []string
+	for name, node := range nodes {
+		if node.GetDeletionTimestamp() == nil && nodeExclusionReason(node, podSpec, logger) == "" {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+func podOnNode(pods []corev1.Pod, nodeName string) *corev1.Pod {
+	for i := range pods {
+		if pods[i].Spec.NodeName == nodeName {
+			return &pods[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package falcon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestTrackRemovedNodes(t *testing.T) {
+	now := time.Now()
+	removedAt := metav1.NewTime(now.Add(-time.Minute))
+	podSpec := &corev1.PodSpec{NodeSelector: map[string]string{"pool": "general"}}
+
+	nodes := map[string]*corev1.Node{
+		"targeted":  {ObjectMeta: metav1.ObjectMeta{Name: "targeted", Labels: map[string]string{"pool": "general"}}},
+		"relabeled": {ObjectMeta: metav1.ObjectMeta{Name: "relabeled", Labels: map[string]string{"pool": "gpu"}}},
+		"cordoned": {
+			ObjectMeta: metav1.ObjectMeta{Name: "cordoned", Labels: map[string]string{"pool": "general"}},
+			Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: "dedicated", Value: "db", Effect: corev1.TaintEffectNoSchedule}}},
+		},
+		"evicted": {
+			ObjectMeta: metav1.ObjectMeta{Name: "evicted", Labels: map[string]string{"pool": "general"}},
+			Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: "dedicated", Value: "db", Effect: corev1.TaintEffectNoExecute}}},
+		},
+		"tracked": {ObjectMeta: metav1.ObjectMeta{Name: "tracked"}},
+		"gone":    {ObjectMeta: metav1.ObjectMeta{Name: "gone", Labels: map[string]string{"pool": "gpu"}}},
+		"pending": {ObjectMeta: metav1.ObjectMeta{Name: "pending", Labels: map[string]string{"pool": "general"}}},
+	}
+
+	pods := []corev1.Pod{
+		newTestSensorPod("targeted", "falcon-sensor", corev1.PodRunning, nil),
+		newTestSensorPod("relabeled", "falcon-sensor", corev1.PodRunning, nil),
+		newTestSensorPod("cordoned", "falcon-sensor", corev1.PodRunning, nil),
+		newTestSensorPod("evicted", "falcon-sensor", corev1.PodRunning, nil),
+		newTestSensorPod("tracked", "falcon-sensor", corev1.PodRunning, nil),
+		newTestSensorPod("deleted", "falcon-sensor", corev1.PodRunning, nil),
+	}
+
+	removedNodes := []falconv1alpha1.FalconNodeSensorRemovedNode{{Name: "tracked", RemovedAt: removedAt}}
+	previouslyTargeted := []string{"deleted", "gone", "pending", "relabeled", "tracked"}
+
+	want := []falconv1alpha1.FalconNodeSensorRemovedNode{
+		{Name: "tracked", RemovedAt: removedAt},
+		{Name: "relabeled", RemovedAt: metav1.NewTime(now)},
+		{Name: "evicted", RemovedAt: metav1.NewTime(now)},
+		{Name: "gone", RemovedAt: metav1.NewTime(now)},
+	}
+
+	got := trackRemovedNodes(removedNodes, previouslyTargeted, pods, nodes, podSpec, now, logr.Discard())
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("trackRemovedNodes() mismatch (-want +got): %s", diff)
+	}
+}
+
+func TestTargetedNodes(t *testing.T) {
+	podSpec := &corev1.PodSpec{NodeSelector: map[string]string{"pool": "general"}}
+	deletedAt := metav1.Now()
+
+	nodes := map[string]*corev1.Node{
+		"b":         {ObjectMeta: metav1.ObjectMeta{Name: "b", Labels: map[string]string{"pool": "general"}}},
+		"a":         {ObjectMeta: metav1.ObjectMeta{Name: "a", Labels: map[string]string{"pool": "general"}}},
+		"relabeled": {ObjectMeta: metav1.ObjectMeta{Name: "relabeled", Labels: map[string]string{"pool": "gpu"}}},
+		"deleting":  {ObjectMeta: metav1.ObjectMeta{Name: "deleting", Labels: map[string]string{"pool": "general"}, DeletionTimestamp: &deletedAt}},
+	}
+
+	want := []string{"a", "b"}
+	if diff := cmp.Diff(want, targetedNodes(nodes, podSpec, logr.Discard())); diff != "" {
+		t.Errorf("targetedNodes() mismatch (-want +got): %s", diff)
+	}
+}
+
+func TestNodesTargetedByOthers(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	general := newTestNodeSensor("general", now, map[string]string{"pool": "general"})
+	gpu := newTestNodeSensor("gpu", now.Add(time.Minute), map[string]string{"pool": "gpu"})
+	gpu.Spec.InstallNamespace = "falcon-system"
+	batch := newTestNodeSensor("batch", now.Add(2*time.Minute), map[string]string{"pool": "batch"})
+	batch.Spec.InstallNamespace = "falcon-system"
+	overlapping := newTestNodeSensor("overlapping", now.Add(3*time.Minute), map[string]string{"pool": "general"})
+
+	gpuDaemonSet := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: gpu.Name, Namespace: gpu.Spec.InstallNamespace}}
+	gpuDaemonSet.Spec.Template.Spec.NodeSelector = gpu.Spec.Node.NodeSelector
+
+	nodes := map[string]*corev1.Node{
+		"relabeled": {ObjectMeta: metav1.ObjectMeta{Name: "relabeled", Labels: map[string]string{"pool": "gpu"}}},
+		"batch":     {ObjectMeta: metav1.ObjectMeta{Name: "batch", Labels: map[string]string{"pool": "batch"}}},
+		"general":   {ObjectMeta: metav1.ObjectMeta{Name: "general", Labels: map[string]string{"pool": "general"}}},
+		"unlabeled": {ObjectMeta: metav1.ObjectMeta{Name: "unlabeled"}},
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, falconv1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(general, gpu, batch, overlapping, gpuDaemonSet).Build()
+
+	r := &FalconNodeSensorReconciler{Client: fakeClient, Scheme: scheme}
+	got, err := r.nodesTargetedByOthers(ctx, general, nodes, logr.Discard())
+	require.NoError(t, err)
+
+	// The DaemonSet of overlapping is not running, so the general node is not targeted by another FalconNodeSensor
+	want := map[string]string{"relabeled": "gpu", "batch": "batch"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("nodesTargetedByOthers() mismatch (-want +got): %s", diff)
+	}
+}
+
+func TestTargetedNodesConfigMap(t *testing.T) {
+	ctx := context.Background()
+	nodesensor := newTestNodeSensor("general", time.Now(), nil)
+	nodesensor.Spec.InstallNamespace = "falcon-system"
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, falconv1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(nodesensor).Build()
+	r := &FalconNodeSensorReconciler{Client: fakeClient, Reader: fakeClient, Scheme: scheme}
+
+	got, err := r.getTargetedNodes(ctx, nodesensor)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	require.NoError(t, r.updateTargetedNodes(ctx, nodesensor, []string{"a", "b"}, logr.Discard()))
+	got, err = r.getTargetedNodes(ctx, nodesensor)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, got)
+
+	require.NoError(t, r.updateTargetedNodes(ctx, nodesensor, []string{"b"}, logr.Discard()))
+	got, err = r.getTargetedNodes(ctx, nodesensor)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, got)
+
+	require.NoError(t, r.deleteTargetedNodes(ctx, nodesensor, logr.Discard()))
+	require.NoError(t, r.deleteTargetedNodes(ctx, nodesensor, logr.Discard()))
+	got, err = r.getTargetedNodes(ctx, nodesensor)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
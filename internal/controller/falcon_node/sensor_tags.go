@@ -0,0 +1,162 @@
+package falcon
+
+import (
+	"context"
+	"maps"
+	"strings"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/crowdstrike/falcon-operator/pkg/node"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	clog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// SensorTagsReconciler resolves the tag templates of a FalconNodeSensor for the node of each sensor pod.
+// The resolved tags are stored in an annotation of the pod, which the sensor pod reads through the downward API.
+// The sensor only reads the tags when it starts, so a sensor pod is restarted when the tags of its node change.
+type SensorTagsReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SensorTagsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("falconnodesensor-tags").
+		For(&corev1.Pod{}, builder.WithPredicates(predicate.NewPredicateFuncs(isSensorPod))).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.enqueueSensorPodsOnNode), builder.WithPredicates(nodeLabelsChanged)).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;patch;delete
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+
+// Reconcile annotates a sensor pod with the sensor grouping tags of its node.
+func (r *SensorTagsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := clog.FromContext(ctx).WithValues("Pod", req.NamespacedName)
+
+	pod := &corev1.Pod{}
+	if err := r.Get(ctx, req.NamespacedName, pod); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if pod.Spec.NodeName == "" || pod.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, nil
+	}
+
+	nodesensor := &falconv1alpha1.FalconNodeSensor{}
+	if err := r.Get(ctx, types.NamespacedName{Name: pod.Labels[common.FalconInstanceKey]}, nodesensor); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if len(nodesensor.Spec.Node.TagTemplates) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	if err := node.ValidateTagTemplates(nodesensor.Spec.Node.TagTemplates); err != nil {
+		// Reported in the FalconNodeSensor status
+		return ctrl.Result{}, nil
+	}
+
+	k8sNode := &corev1.Node{}
+	if err := r.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, k8sNode); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to get Node", "Node", pod.Spec.NodeName)
+		return ctrl.Result{}, err
+	}
+
+	tags := strings.Join(node.ResolveTags(nodesensor.Spec.Falcon.Tags, nodesensor.Spec.Node.TagTemplates, k8sNode), ",")
+	existing, annotated := pod.Annotations[common.FalconSensorTagsKey]
+	if annotated && existing == tags {
+		return ctrl.Result{}, nil
+	}
+
+	if annotated {
+		// The sensor already started with the previous tags; the DaemonSet recreates the pod, which is then annotated with the new tags
+		if err := r.Delete(ctx, pod, client.Preconditions{UID: &pod.UID}); err != nil {
+			if errors.IsNotFound(err) || errors.IsConflict(err) {
+				return ctrl.Result{}, nil
+			}
+			logger.Error(err, "Failed to restart sensor pod after the sensor grouping tags changed")
+			return ctrl.Result{}, err
+		}
+
+		logger.Info("Restarted sensor pod after the sensor grouping tags changed", "Node", pod.Spec.NodeName, "PreviousTags", existing, "Tags", tags)
+		return ctrl.Result{}, nil
+	}
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[common.FalconSensorTagsKey] = tags
+
+	if err := r.Patch(ctx, pod, patch); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to annotate sensor pod with the sensor grouping tags")
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("Annotated sensor pod with the sensor grouping tags", "Node", pod.Spec.NodeName, "Tags", tags)
+	return ctrl.Result{}, nil
+}
+
+// isSensorPod reports whether the object is a pod of a FalconNodeSensor DaemonSet
+func isSensorPod(obj client.Object) bool {
+	podLabels := obj.GetLabels()
+	return podLabels[common.FalconComponentKey] == common.FalconKernelSensor &&
+		podLabels[common.FalconInstanceNameKey] == "daemonset" &&
+		podLabels[common.FalconInstanceKey] != ""
+}
+
+// enqueueSensorPodsOnNode enqueues the sensor pods running on the node
+func (r *SensorTagsReconciler) enqueueSensorPodsOnNode(ctx context.Context, obj client.Object) []reconcile.Request {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.MatchingLabels{
+		common.FalconComponentKey:    common.FalconKernelSensor,
+		common.FalconInstanceNameKey: "daemonset",
+	}); err != nil {
+		clog.FromContext(ctx).Error(err, "Failed to list sensor pods", "Node", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == obj.GetName() && isSensorPod(&pod) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&pod)})
+		}
+	}
+
+	return requests
+}
+
+// nodeLabelsChanged only passes node updates that change the labels, which tag templates can reference
+var nodeLabelsChanged = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return false
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return !maps.Equal(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return false
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
@@ -0,0 +1,120 @@
+package falcon
+
+import (
+	"context"
+	"testing"
+
+	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestSensorTagsReconcile(t *testing.T) {
+	ctx := context.Background()
+
+	nodesensor := &falconv1alpha1.FalconNodeSensor{ObjectMeta: metav1.ObjectMeta{Name: "gpu"}}
+	nodesensor.Spec.Falcon.Tags = []string{"cluster"}
+	nodesensor.Spec.Node.TagTemplates = []string{`pool/{{ .Labels["pool"] }}`, "zone/{{ .Zone }}"}
+
+	k8sNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-1",
+			Labels: map[string]string{"pool": "gpu", corev1.LabelTopologyZone: "us-east-1a"},
+		},
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "falcon-node-sensor-abcde",
+			Namespace: "falcon-system",
+			Labels:    common.CRLabels("daemonset", nodesensor.Name, common.FalconKernelSensor),
+		},
+		Spec: corev1.PodSpec{NodeName: k8sNode.Name},
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, falconv1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(nodesensor, k8sNode, pod).Build()
+
+	r := &SensorTagsReconciler{Client: fakeClient, Scheme: scheme}
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pod)})
+	require.NoError(t, err)
+
+	got := &corev1.Pod{}
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}, got))
+	assert.Equal(t, "cluster,pool/gpu,zone/us-east-1a", got.Annotations[common.FalconSensorTagsKey])
+}
+
+func TestSensorTagsReconcileNodeLabelsChanged(t *testing.T) {
+	ctx := context.Background()
+
+	nodesensor := &falconv1alpha1.FalconNodeSensor{ObjectMeta: metav1.ObjectMeta{Name: "gpu"}}
+	nodesensor.Spec.Node.TagTemplates = []string{`pool/{{ .Labels["pool"] }}`}
+
+	k8sNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"pool": "cpu"}}}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "falcon-node-sensor-abcde",
+			Namespace:   "falcon-system",
+			Labels:      common.CRLabels("daemonset", nodesensor.Name, common.FalconKernelSensor),
+			Annotations: map[string]string{common.FalconSensorTagsKey: "pool/gpu"},
+		},
+		Spec: corev1.PodSpec{NodeName: k8sNode.Name},
+	}
+	otherPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "falcon-node-sensor-fghij",
+			Namespace: "falcon-system",
+			Labels:    common.CRLabels("daemonset", nodesensor.Name, common.FalconKernelSensor),
+		},
+		Spec: corev1.PodSpec{NodeName: "node-2"},
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, falconv1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(nodesensor, k8sNode, pod, otherPod).Build()
+
+	r := &SensorTagsReconciler{Client: fakeClient, Scheme: scheme}
+	requests := r.enqueueSensorPodsOnNode(ctx, k8sNode)
+	require.Len(t, requests, 1)
+	assert.Equal(t, client.ObjectKeyFromObject(pod), requests[0].NamespacedName)
+
+	_, err := r.Reconcile(ctx, requests[0])
+	require.NoError(t, err)
+
+	err = fakeClient.Get(ctx, client.ObjectKeyFromObject(pod), &corev1.Pod{})
+	assert.True(t, errors.IsNotFound(err), "sensor pod with stale tags should be restarted")
+}
+
+func TestNodeLabelsChanged(t *testing.T) {
+	oldNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"pool": "cpu"}}}
+
+	heartbeat := oldNode.DeepCopy()
+	heartbeat.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}
+	assert.False(t, nodeLabelsChanged.Update(event.UpdateEvent{ObjectOld: oldNode, ObjectNew: heartbeat}))
+
+	relabeled := oldNode.DeepCopy()
+	relabeled.Labels["pool"] = "gpu"
+	assert.True(t, nodeLabelsChanged.Update(event.UpdateEvent{ObjectOld: oldNode, ObjectNew: relabeled}))
+}
+
+func TestIsSensorPod(t *testing.T) {
+	sensorPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: common.CRLabels("daemonset", "falcon-node-sensor", common.FalconKernelSensor)}}
+	assert.True(t, isSensorPod(sensorPod))
+
+	cleanupPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: common.CRLabels("cleanup", "falcon-node-sensor-cleanup", common.FalconKernelSensor)}}
+	assert.False(t, isSensorPod(cleanupPod))
+}
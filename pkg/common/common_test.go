@@ -118,6 +118,58 @@ func TestMakeSensorEnvMap(t *testing.T) {
 	if got := MakeSensorEnvMap(falconSensor.FalconSensor); !reflect.DeepEqual(got, sensorConfig) {
 		t.Errorf("MakeSensorEnvMap() = %v, want %v", got, sensorConfig)
 	}
+
+	// Test the additional falconctl options
+	messageLog := true
+	waitTime := int64(60000)
+	falconSensor.Feature = []string{"enableLog", "disableLogBuffer"}
+	falconSensor.MessageLog = &messageLog
+	falconSensor.ProvisioningWaitTime = &waitTime
+	falconSensor.ExtraOptions = map[string]string{"metadata-query": "enable", "cid": "override", "trace": "err"}
+
+	sensorConfig["FALCONCTL_OPT_FEATURE"] = "enableLog,disableLogBuffer"
+	sensorConfig["FALCONCTL_OPT_MESSAGE_LOG"] = "true"
+	sensorConfig["FALCONCTL_OPT_PROVISIONING_WAIT_TIME"] = "60000"
+	sensorConfig["FALCONCTL_OPT_METADATA_QUERY"] = "enable"
+
+	if got := MakeSensorEnvMap(falconSensor.FalconSensor); !reflect.DeepEqual(got, sensorConfig) {
+		t.Errorf("MakeSensorEnvMap() = %v, want %v", got, sensorConfig)
+	}
+}
+
+func TestMaintenanceTokenEnvVars(t *testing.T) {
+	falconSensor := falconv1alpha1.FalconSensor{}
+	if got := MaintenanceTokenEnvVars(falconSensor); got != nil {
+		t.Errorf("MaintenanceTokenEnvVars() = %v, want nil", got)
+	}
+
+	falconSensor.MaintenanceTokenSecretRef = &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "falcon-secret"},
+		Key:                  "falcon-maintenance-token",
+	}
+	want := []corev1.EnvVar{
+		{
+			Name:      "FALCONCTL_OPT_MAINTENANCE_TOKEN",
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: falconSensor.MaintenanceTokenSecretRef},
+		},
+	}
+	if got := MaintenanceTokenEnvVars(falconSensor); !reflect.DeepEqual(got, want) {
+		t.Errorf("MaintenanceTokenEnvVars() = %v, want %v", got, want)
+	}
+	if _, ok := MakeSensorEnvMap(falconSensor)["FALCONCTL_OPT_MAINTENANCE_TOKEN"]; ok {
+		t.Errorf("MakeSensorEnvMap() must not render the maintenance token")
+	}
+}
+
+func TestReservedFalconctlOptionsRule(t *testing.T) {
+	src, err := os.ReadFile("../../api/falcon/v1alpha1/falcon.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(src), ReservedFalconctlOptionsRule()) {
+		t.Errorf("the extraOptions validation of FalconSensor is out of date with ReservedFalconctlOptions, run make manifests")
+	}
 }
 
 func TestAppendUniqueEnvVars(t *testing.T) {
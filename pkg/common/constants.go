@@ -47,6 +47,9 @@ const (
 	FalconAdmissionReviewKey = "falcon.crowdstrike.com/admission-review"
 
 	FalconOperatorVersionKey = "crowdstrike.com/operator-version"
+	FalconApproveVersionKey  = "falcon.crowdstrike.com/approve-version"
+	FalconSensorTagsKey      = "falcon.crowdstrike.com/sensor-tags"
+	FalconTLSCertificateKey  = "falcon.crowdstrike.com/tls-certificate"
 
 	FalconKernelSensor        = "kernel_sensor"
 	FalconSidecarSensor       = "container_sensor"
@@ -67,7 +70,6 @@ const (
 	ImageServiceAccountName     = "falcon-operator-image-analyzer"
 
 	// GKE Autopilot requires names to have an exact match for WorkloadAllowlists
-	GKEAutoPilotConfigMapName           = "falcon-node-sensor-config"
 	GKEAutoPilotAllowListLabelKey       = "cloud.google.com/matching-allowlist"
 	GKEAutoPilotDeployDSAllowlistPrefix = "crowdstrike-falconsensor-deploy-allowlist"
 	GKEAutoPilotCleanupAllowlistPrefix  = "crowdstrike-falconsensor-cleanup-allowlist"
@@ -1,16 +1,38 @@
 package common
 
 import (
+	"slices"
 	"strconv"
 	"strings"
 
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 )
 
+// ReservedFalconctlOptions are the falconctl options that cannot be set with extraOptions, because they have a dedicated
+// field or are managed by the operator. The extraOptions validation of FalconSensor is generated from this list.
+var ReservedFalconctlOptions = []string{
+	"aid", "apd", "aph", "app", "backend", "billing", "cid", "cloud", "feature", "maintenance-token", "message-log",
+	"provisioning-token", "provisioning-wait-time", "tags", "trace",
+}
+
+// FalconctlOptionEnvVar returns the environment variable that passes the falconctl option to the sensor, e.g. FALCONCTL_OPT_METADATA_QUERY for metadata-query
+func FalconctlOptionEnvVar(option string) string {
+	return "FALCONCTL_OPT_" + strings.ToUpper(strings.ReplaceAll(option, "-", "_"))
+}
+
 func MakeSensorEnvMap(falconSensor falconv1alpha1.FalconSensor) map[string]string {
 	sensorConfig := make(map[string]string)
 	proxy := NewProxyInfo()
 
+	// Extra options are set first so that they never override the options the operator sets
+	for option, value := range falconSensor.ExtraOptions {
+		if slices.Contains(ReservedFalconctlOptions, option) {
+			continue
+		}
+		sensorConfig[FalconctlOptionEnvVar(option)] = value
+	}
+
 	// Set proxy values from environment variables if they exist
 	if proxy.Host() != "" {
 		sensorConfig["FALCONCTL_OPT_APH"] = strings.TrimPrefix(proxy.Host(), "https://")
@@ -42,6 +64,43 @@ func MakeSensorEnvMap(falconSensor falconv1alpha1.FalconSensor) map[string]strin
 	if falconSensor.Trace != "" {
 		sensorConfig["FALCONCTL_OPT_TRACE"] = falconSensor.Trace
 	}
+	if len(falconSensor.Feature) > 0 {
+		sensorConfig["FALCONCTL_OPT_FEATURE"] = strings.Join(falconSensor.Feature, ",")
+	}
+	if falconSensor.MessageLog != nil {
+		sensorConfig["FALCONCTL_OPT_MESSAGE_LOG"] = strconv.FormatBool(*falconSensor.MessageLog)
+	}
+	if falconSensor.ProvisioningWaitTime != nil {
+		sensorConfig["FALCONCTL_OPT_PROVISIONING_WAIT_TIME"] = strconv.FormatInt(*falconSensor.ProvisioningWaitTime, 10)
+	}
 
 	return sensorConfig
 }
+
+// MaintenanceTokenEnvVars returns the environment variable that passes the maintenance token to the sensor from its Secret.
+// The token is kept out of MakeSensorEnvMap so that it is never written to a ConfigMap.
+func MaintenanceTokenEnvVars(falconSensor falconv1alpha1.FalconSensor) []corev1.EnvVar {
+	if falconSensor.MaintenanceTokenSecretRef == nil {
+		return nil
+	}
+
+	return []corev1.EnvVar{
+		{
+			Name: FalconctlOptionEnvVar("maintenance-token"),
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: falconSensor.MaintenanceTokenSecretRef.DeepCopy(),
+			},
+		},
+	}
+}
+
+// ReservedFalconctlOptionsRule returns the CEL rule of FalconSensor that rejects the reserved options in extraOptions.
+// The rule in api/falcon/v1alpha1/falcon.go is generated from ReservedFalconctlOptions with make manifests.
+func ReservedFalconctlOptionsRule() string {
+	options := make([]string, 0, len(ReservedFalconctlOptions))
+	for _, option := range ReservedFalconctlOptions {
+		options = append(options, "'"+option+"'")
+	}
+
+	return "self.all(k, !(k in [" + strings.Join(options, ", ") + "]))"
+}
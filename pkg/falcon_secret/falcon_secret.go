@@ -37,3 +37,15 @@ func GetFalconProvisioningTokenFromSecret(secret *corev1.Secret) (provisioningTo
 
 	return provisioningToken
 }
+
+// GetFalconMaintenanceTokenRefFromSecret returns a reference to the maintenance token of the secret, or nil if the secret has none
+func GetFalconMaintenanceTokenRefFromSecret(secret *corev1.Secret) *corev1.SecretKeySelector {
+	if _, exists := secret.Data["falcon-maintenance-token"]; !exists {
+		return nil
+	}
+
+	return &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+		Key:                  "falcon-maintenance-token",
+	}
+}
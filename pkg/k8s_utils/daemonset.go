@@ -3,13 +3,19 @@ package k8s_utils
 import (
 	"context"
 
-	"github.com/crowdstrike/falcon-operator/pkg/common"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // RestartDaemonSet restarts all pods that belong to the daemonset
+// Only the pods matched by the daemonset selector are deleted, so that other daemonsets and the cleanup pods in the namespace keep running.
 func RestartDaemonSet(ctx context.Context, cli client.Client, dsUpdate *appsv1.DaemonSet) error {
-	return cli.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace(dsUpdate.GetNamespace()), client.MatchingLabels{common.FalconComponentKey: common.FalconKernelSensor})
+	selector, err := metav1.LabelSelectorAsSelector(dsUpdate.Spec.Selector)
+	if err != nil {
+		return err
+	}
+
+	return cli.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace(dsUpdate.GetNamespace()), client.MatchingLabelsSelector{Selector: selector})
 }
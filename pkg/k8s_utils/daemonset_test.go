@@ -0,0 +1,51 @@
+package k8s_utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crowdstrike/falcon-operator/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRestartDaemonSet(t *testing.T) {
+	ctx := context.Background()
+	namespace := "falcon-system"
+
+	newPod := func(name string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels}}
+	}
+	gpuPod := newPod("gpu-abcde", common.CRLabels("daemonset", "falcon-node-sensor-gpu", common.FalconKernelSensor))
+	generalPod := newPod("general-abcde", common.CRLabels("daemonset", "falcon-node-sensor-general", common.FalconKernelSensor))
+	cleanupPod := newPod("cleanup-abcde", common.CRLabels("cleanup", "falcon-node-sensor-gpu-cleanup", common.FalconKernelSensor))
+
+	daemonset := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "falcon-node-sensor-gpu", Namespace: namespace},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: common.CRLabels("daemonset", "falcon-node-sensor-gpu", common.FalconKernelSensor)},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gpuPod, generalPod, cleanupPod).Build()
+
+	require.NoError(t, RestartDaemonSet(ctx, fakeClient, daemonset))
+
+	pods := &corev1.PodList{}
+	require.NoError(t, fakeClient.List(ctx, pods, client.InNamespace(namespace)))
+
+	names := []string{}
+	for _, pod := range pods.Items {
+		names = append(names, pod.Name)
+	}
+	assert.ElementsMatch(t, []string{generalPod.Name, cleanupPod.Name}, names)
+}
@@ -0,0 +1,168 @@
+package node
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// CompatibilityMatrixKey is the ConfigMap key holding a compatibility matrix
+const CompatibilityMatrixKey = "compatibility.yaml"
+
+var (
+	//go:embed compatibility.yaml
+	defaultCompatibilityMatrix []byte
+
+	// defaultMatrix is parsed once from the embedded compatibility matrix and must not be modified
+	defaultMatrix *CompatibilityMatrix
+
+	// leadingVersion matches the numeric part of kernel and sensor versions, e.g. 5.15.0 in 5.15.0-1051-aws
+	leadingVersion = regexp.MustCompile(`^\d+(\.\d+)*`)
+)
+
+// CompatibilityMatrix describes the nodes the Falcon sensor supports
+type CompatibilityMatrix struct {
+	// Architectures supported by the sensor, e.g. amd64. Any architecture is supported when empty.
+	Architectures []string `json:"architectures,omitempty"`
+
+	// ContainerRuntimes supported by the sensor, e.g. containerd. Any container runtime is supported when empty.
+	ContainerRuntimes []string `json:"containerRuntimes,omitempty"`
+
+	// Rules for the OS images supported by the sensor. The first rule matching the OS image of a node applies to it.
+	// OS images and kernels are not checked when empty.
+	Rules []CompatibilityRule `json:"rules,omitempty"`
+}
+
+// CompatibilityRule describes the kernels and sensor versions supported for the matching OS images
+type CompatibilityRule struct {
+	// OSImage is a regular expression matched against the OS image reported by the node
+	OSImage string `json:"osImage"`
+
+	// MinKernelVersion is the oldest supported kernel
+	MinKernelVersion string `json:"minKernelVersion,omitempty"`
+
+	// MaxKernelVersion is the newest supported kernel. Only the components it sets are compared, so 6.8 allows 6.8.12.
+	MaxKernelVersion string `json:"maxKernelVersion,omitempty"`
+
+	// MinSensorVersion is the oldest sensor version supporting the OS images
+	MinSensorVersion string `json:"minSensorVersion,omitempty"`
+
+	osImage *regexp.Regexp
+}
+
+func init() {
+	matrix, err := ParseCompatibilityMatrix(defaultCompatibilityMatrix)
+	if err != nil {
+		panic(fmt.Sprintf("invalid default compatibility matrix: %v", err))
+	}
+	defaultMatrix = matrix
+}
+
+// DefaultCompatibilityMatrix returns the compatibility matrix shipped with the operator. The matrix is shared and must not be modified.
+func DefaultCompatibilityMatrix() *CompatibilityMatrix {
+	return defaultMatrix
+}
+
+// ParseCompatibilityMatrix parses and validates a YAML compatibility matrix
+func ParseCompatibilityMatrix(data []byte) (*CompatibilityMatrix, error) {
+	matrix := &CompatibilityMatrix{}
+	if err := yaml.UnmarshalStrict(data, matrix); err != nil {
+		return nil, err
+	}
+
+	for i := range matrix.Rules {
+		rule := &matrix.Rules[i]
+
+		osImage, err := regexp.Compile(rule.OSImage)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: invalid osImage: %v", i, err)
+		}
+		rule.osImage = osImage
+
+		for field, version := range map[string]string{
+			"minKernelVersion": rule.MinKernelVersion,
+			"maxKernelVersion": rule.MaxKernelVersion,
+			"minSensorVersion": rule.MinSensorVersion,
+		} {
+			if version != "" && leadingVersion.FindString(version) != version {
+				return nil, fmt.Errorf("rule %d: invalid %s %q, expected a version such as 5.10", i, field, version)
+			}
+		}
+	}
+
+	return matrix, nil
+}
+
+// Incompatibility returns why the sensor with the given version does not support the node, or an empty string when it does.
+// Checks are skipped when the node does not report the information they need, and the sensor version check is skipped
+// when the version is unknown.
+func (m *CompatibilityMatrix) Incompatibility(node *corev1.Node, sensorVersion string) string {
+	info := node.Status.NodeInfo
+
+	if info.Architecture != "" && len(m.Architectures) > 0 && !slices.Contains(m.Architectures, info.Architecture) {
+		return fmt.Sprintf("unsupported architecture %s", info.Architecture)
+	}
+
+	if info.ContainerRuntimeVersion != "" && len(m.ContainerRuntimes) > 0 {
+		runtime, _, _ := strings.Cut(info.ContainerRuntimeVersion, "://")
+		if !slices.Contains(m.ContainerRuntimes, runtime) {
+			return fmt.Sprintf("unsupported container runtime %s", info.ContainerRuntimeVersion)
+		}
+	}
+
+	if info.OSImage == "" || len(m.Rules) == 0 {
+		return ""
+	}
+
+	idx := slices.IndexFunc(m.Rules, func(rule CompatibilityRule) bool {
+		return rule.osImage.MatchString(info.OSImage)
+	})
+	if idx < 0 {
+		return fmt.Sprintf("unsupported OS image %s", info.OSImage)
+	}
+	rule := m.Rules[idx]
+
+	if info.KernelVersion != "" {
+		kernel := leadingVersion.FindString(info.KernelVersion)
+		switch {
+		case kernel == "":
+			return fmt.Sprintf("unrecognized kernel version %s", info.KernelVersion)
+		case rule.MinKernelVersion != "" && compareVersions(kernel, rule.MinKernelVersion) < 0:
+			return fmt.Sprintf("kernel %s is older than %s, the oldest supported on %s", info.KernelVersion, rule.MinKernelVersion, info.OSImage)
+		case rule.MaxKernelVersion != "" && compareVersions(kernel, rule.MaxKernelVersion) > 0:
+			return fmt.Sprintf("kernel %s is newer than %s, the newest supported on %s", info.KernelVersion, rule.MaxKernelVersion, info.OSImage)
+		}
+	}
+
+	if sensor := leadingVersion.FindString(sensorVersion); sensor != "" && rule.MinSensorVersion != "" && compareVersions(sensor, rule.MinSensorVersion) < 0 {
+		return fmt.Sprintf("%s requires sensor version %s or later", info.OSImage, rule.MinSensorVersion)
+	}
+
+	return ""
+}
+
+// compareVersions compares the components of the version that the bound sets with the bound, treating missing components as 0
+func compareVersions(version, bound string) int {
+	versionParts := strings.Split(version, ".")
+	for i, boundPart := range strings.Split(bound, ".") {
+		versionPart := "0"
+		if i < len(versionParts) {
+			versionPart = versionParts[i]
+		}
+
+		// Both versions are validated to only contain digits and dots
+		v, _ := strconv.Atoi(versionPart)
+		b, _ := strconv.Atoi(boundPart)
+		if v != b {
+			return v - b
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,133 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDefaultCompatibilityMatrix(t *testing.T) {
+	// Guards the embedded matrix, which is otherwise only parsed when the package is initialized
+	_, err := ParseCompatibilityMatrix(defaultCompatibilityMatrix)
+	require.NoError(t, err)
+
+	matrix := DefaultCompatibilityMatrix()
+	assert.Same(t, matrix, DefaultCompatibilityMatrix())
+	assert.Empty(t, matrix.Rules)
+
+	node := newTestNodeInfo("amd64", "containerd://1.7.11", "Flatcar Container Linux", "6.1.73-flatcar")
+	assert.Empty(t, matrix.Incompatibility(node, "7.31.0-18410-1.falcon-linux.Release.US-1"))
+
+	node = newTestNodeInfo("s390x", "containerd://1.7.11", "Ubuntu 22.04.4 LTS", "5.15.0-1051-aws")
+	assert.Equal(t, "unsupported architecture s390x", matrix.Incompatibility(node, "7.31.0-18410-1.falcon-linux.Release.US-1"))
+}
+
+func TestParseCompatibilityMatrix(t *testing.T) {
+	invalid := map[string]string{
+		"unknown field":    "rules: [{osImage: .*, minKernel: '5.10'}]",
+		"invalid regexp":   "rules: [{osImage: '(Ubuntu'}]",
+		"invalid version":  "rules: [{osImage: .*, minKernelVersion: 5.10-generic}]",
+		"invalid document": "rules: {",
+	}
+	for name, data := range invalid {
+		_, err := ParseCompatibilityMatrix([]byte(data))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestIncompatibility(t *testing.T) {
+	matrix, err := ParseCompatibilityMatrix([]byte(`
+architectures: [amd64, arm64]
+containerRuntimes: [containerd, cri-o]
+rules:
+- osImage: ^Ubuntu
+  minKernelVersion: "4.15"
+  maxKernelVersion: "6.8"
+- osImage: ^Bottlerocket
+  minSensorVersion: "7.10"
+`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		node   *corev1.Node
+		sensor string
+		want   string
+	}{
+		{
+			name: "supported",
+			node: newTestNodeInfo("arm64", "cri-o://1.29.1", "Ubuntu 24.04 LTS", "6.8.0-1009-aws"),
+		},
+		{
+			name: "node without node info",
+			node: &corev1.Node{},
+		},
+		{
+			name: "unsupported architecture",
+			node: newTestNodeInfo("s390x", "containerd://1.7.11", "Ubuntu 22.04.4 LTS", "5.15.0"),
+			want: "unsupported architecture s390x",
+		},
+		{
+			name: "unsupported container runtime",
+			node: newTestNodeInfo("amd64", "docker://24.0.7", "Ubuntu 22.04.4 LTS", "5.15.0"),
+			want: "unsupported container runtime docker://24.0.7",
+		},
+		{
+			name: "unsupported OS image",
+			node: newTestNodeInfo("amd64", "containerd://1.7.11", "Flatcar Container Linux", "6.1.73-flatcar"),
+			want: "unsupported OS image Flatcar Container Linux",
+		},
+		{
+			name: "kernel older than the minimum",
+			node: newTestNodeInfo("amd64", "containerd://1.7.11", "Ubuntu 16.04.7 LTS", "4.4.0-210-generic"),
+			want: "kernel 4.4.0-210-generic is older than 4.15, the oldest supported on Ubuntu 16.04.7 LTS",
+		},
+		{
+			name: "kernel newer than the maximum",
+			node: newTestNodeInfo("amd64", "containerd://1.7.11", "Ubuntu 24.10", "6.11.0-8-generic"),
+			want: "kernel 6.11.0-8-generic is newer than 6.8, the newest supported on Ubuntu 24.10",
+		},
+		{
+			name: "unrecognized kernel",
+			node: newTestNodeInfo("amd64", "containerd://1.7.11", "Ubuntu 22.04.4 LTS", "custom"),
+			want: "unrecognized kernel version custom",
+		},
+		{
+			name:   "sensor older than the minimum",
+			node:   newTestNodeInfo("amd64", "containerd://1.7.11", "Bottlerocket OS 1.19.2 (aws-k8s-1.29)", "6.1.72"),
+			sensor: "7.9.0-17204-1.falcon-linux.Release.US-1",
+			want:   "Bottlerocket OS 1.19.2 (aws-k8s-1.29) requires sensor version 7.10 or later",
+		},
+		{
+			name:   "unknown sensor version",
+			node:   newTestNodeInfo("amd64", "containerd://1.7.11", "Bottlerocket OS 1.19.2 (aws-k8s-1.29)", "6.1.72"),
+			sensor: "latest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matrix.Incompatibility(tt.node, tt.sensor))
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Zero(t, compareVersions("6.8.12", "6.8"))
+	assert.Zero(t, compareVersions("5", "5.0"))
+	assert.Negative(t, compareVersions("4.4.0", "4.15"))
+	assert.Positive(t, compareVersions("6.11.0", "6.8"))
+}
+
+func newTestNodeInfo(architecture, containerRuntime, osImage, kernel string) *corev1.Node {
+	return &corev1.Node{
+		Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{
+			Architecture:            architecture,
+			ContainerRuntimeVersion: containerRuntime,
+			OSImage:                 osImage,
+			KernelVersion:           kernel,
+		}},
+	}
+}
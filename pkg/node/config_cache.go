@@ -6,10 +6,10 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"unicode"
 
 	falconv1alpha1 "github.com/crowdstrike/falcon-operator/api/falcon/v1alpha1"
 	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensor"
+	"github.com/crowdstrike/falcon-operator/internal/controller/common/sensorversion"
 	"github.com/crowdstrike/falcon-operator/pkg/common"
 	"github.com/crowdstrike/falcon-operator/pkg/falcon_api"
 	"github.com/crowdstrike/falcon-operator/pkg/registry/falcon_registry"
@@ -24,6 +24,7 @@ var ErrFalconAPINotConfigured = errors.New("missing falcon_api configuration")
 type ConfigCache struct {
 	cid             string
 	imageUri        string
+	clusterName     string
 	nodesensor      *falconv1alpha1.FalconNodeSensor
 	falconApiConfig *falcon.ApiConfig
 }
@@ -56,6 +57,11 @@ func (cc *ConfigCache) CID() string {
 	return cc.cid
 }
 
+// SetClusterName sets the cluster name reported by the sensor, either configured by the user or detected from the cluster
+func (cc *ConfigCache) SetClusterName(clusterName string) {
+	cc.clusterName = clusterName
+}
+
 func (cc *ConfigCache) UsingCrowdStrikeRegistry() bool {
 	if cc.nodesensor.Spec.Node.Image == "" && cc.falconApiConfig == nil {
 		return os.Getenv("RELATED_IMAGE_NODE_SENSOR") == ""
@@ -89,8 +95,8 @@ func (cc *ConfigCache) SensorEnvVars() map[string]string {
 	if cc.nodesensor.Spec.Falcon.Cloud != "" {
 		sensorConfig["FALCONCTL_OPT_CLOUD"] = cc.nodesensor.Spec.Falcon.Cloud
 	}
-	if cc.nodesensor.Spec.Node.ClusterName != nil {
-		sensorConfig["FALCON_CLUSTER_NAME"] = sanitizeClusterName(cc.nodesensor.Spec.Node.ClusterName)
+	if cc.clusterName != "" {
+		sensorConfig["FALCON_CLUSTER_NAME"] = cc.clusterName
 	}
 
 	for _, ev := range common.OperatorMetaEnvVars() {
@@ -100,35 +106,6 @@ func (cc *ConfigCache) SensorEnvVars() map[string]string {
 	return sensorConfig
 }
 
-func sanitizeClusterName(clusterName *string) string {
-	if clusterName == nil {
-		return ""
-	}
-
-	if !isClusterNameValid(*clusterName) {
-		return ""
-	}
-
-	return *clusterName
-}
-
-// isClusterNameValid validates the clusterName.
-// Those rules had been taken from EKS (Amazon AWS).
-// See more at: https://docs.aws.amazon.com/eks/latest/APIReference/API_CreateCluster.html#API_CreateCluster_RequestSyntax
-func isClusterNameValid(clusterName string) bool {
-	if len(clusterName) > 100 || len(clusterName) == 0 {
-		return false
-	}
-
-	if !unicode.IsLetter(rune(clusterName[0])) && !unicode.IsNumber(rune(clusterName[0])) {
-		return false
-	}
-
-	return !strings.ContainsFunc(clusterName, func(r rune) bool {
-		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '-' && r != '_'
-	})
-}
-
 func (cc *ConfigCache) getFalconImage(ctx context.Context, nodesensor *falconv1alpha1.FalconNodeSensor) (string, error) {
 	if nodesensor.Spec.Node.Image != "" {
 		return nodesensor.Spec.Node.Image, nil
@@ -162,6 +139,10 @@ func (cc *ConfigCache) getFalconImage(ctx context.Context, nodesensor *falconv1a
 		}
 	}
 
+	if approved, ok := sensorversion.ApprovedVersion(nodesensor, nodesensor.Status.AvailableUpdate); ok {
+		return fmt.Sprintf("%s:%s", imageUri, approved), nil
+	}
+
 	if versionLock(nodesensor) {
 		return fmt.Sprintf("%s:%s", imageUri, *nodesensor.Status.Sensor), nil
 	}
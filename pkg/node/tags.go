@@ -0,0 +1,112 @@
+package node
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	// tagPlaceholder matches {{ .Field }} and {{ .Labels["key"] }}
+	tagPlaceholder = regexp.MustCompile(`\{\{\s*\.(\w+)\s*(?:\[\s*"([^"]*)"\s*\])?\s*\}\}`)
+
+	// validTag matches the characters the sensor accepts in grouping tags
+	validTag = regexp.MustCompile(`^[a-zA-Z0-9/_-]+$`)
+
+	invalidTagChars = regexp.MustCompile(`[^a-zA-Z0-9/_-]`)
+)
+
+// tagFields resolves the fields that tag templates can reference, other than Labels.
+var tagFields = map[string]func(node *corev1.Node) string{
+	"NodeName": func(node *corev1.Node) string {
+		return node.Name
+	},
+	"Zone": func(node *corev1.Node) string {
+		return firstLabel(node, corev1.LabelTopologyZone, corev1.LabelFailureDomainBetaZone)
+	},
+	"Region": func(node *corev1.Node) string {
+		return firstLabel(node, corev1.LabelTopologyRegion, corev1.LabelFailureDomainBetaRegion)
+	},
+	"InstanceType": func(node *corev1.Node) string {
+		return firstLabel(node, corev1.LabelInstanceTypeStable, corev1.LabelInstanceType)
+	},
+}
+
+// ValidTag reports whether the tag only uses the characters allowed in sensor grouping tags: alphanumerics, '/', '-' and '_'.
+func ValidTag(tag string) bool {
+	return validTag.MatchString(tag)
+}
+
+// ValidateTagTemplates checks that tag templates only reference known fields and that their literal text is valid in a sensor grouping tag.
+func ValidateTagTemplates(templates []string) error {
+	for _, template := range templates {
+		for _, match := range tagPlaceholder.FindAllStringSubmatch(template, -1) {
+			field, key := match[1], match[2]
+			switch {
+			case field == "Labels" && key == "":
+				return fmt.Errorf("tag template %q must select a label with {{ .Labels[\"<label>\"] }}", template)
+			case field == "Labels":
+			case tagFields[field] == nil:
+				return fmt.Errorf("tag template %q references unknown field %q; supported fields are Labels, NodeName, Zone, Region and InstanceType", template, field)
+			case strings.Contains(match[0], "["):
+				return fmt.Errorf("tag template %q cannot index field %q", template, field)
+			}
+		}
+
+		literal := tagPlaceholder.ReplaceAllString(template, "")
+		if literal != "" && !ValidTag(literal) {
+			return fmt.Errorf("tag template %q contains characters that are not allowed in sensor grouping tags; allowed characters are alphanumerics, '/', '-' and '_'", template)
+		}
+
+		if literal == "" && !tagPlaceholder.MatchString(template) {
+			return fmt.Errorf("tag template %q is empty", template)
+		}
+	}
+
+	return nil
+}
+
+// ResolveTags returns the sensor grouping tags of a node: the static tags followed by the tag templates resolved against the node.
+// Characters that are not allowed in grouping tags are replaced with '-'. Templates that reference a missing label or field are skipped.
+func ResolveTags(tags []string, templates []string, node *corev1.Node) []string {
+	resolved := slices.Clone(tags)
+
+	for _, template := range templates {
+		missing := false
+		tag := tagPlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+			match := tagPlaceholder.FindStringSubmatch(placeholder)
+
+			value := ""
+			if match[1] == "Labels" {
+				value = node.Labels[match[2]]
+			} else if field := tagFields[match[1]]; field != nil {
+				value = field(node)
+			}
+
+			if value == "" {
+				missing = true
+			}
+
+			return invalidTagChars.ReplaceAllString(value, "-")
+		})
+
+		if !missing && tag != "" && !slices.Contains(resolved, tag) {
+			resolved = append(resolved, tag)
+		}
+	}
+
+	return resolved
+}
+
+func firstLabel(node *corev1.Node, keys ...string) string {
+	for _, key := range keys {
+		if value := node.Labels[key]; value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,91 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidTag(t *testing.T) {
+	assert.True(t, ValidTag("pool/gpu_nodes-1"))
+	assert.False(t, ValidTag(""))
+	assert.False(t, ValidTag("m5.large"))
+	assert.False(t, ValidTag("gpu,general"))
+	assert.False(t, ValidTag("gpu nodes"))
+}
+
+func TestValidateTagTemplates(t *testing.T) {
+	valid := []string{
+		"static",
+		`pool/{{ .Labels["eks.amazonaws.com/nodegroup"] }}`,
+		"zone/{{ .Zone }}",
+		"{{.Region}}-{{.InstanceType}}",
+		"{{ .NodeName }}",
+	}
+	assert.NoError(t, ValidateTagTemplates(valid))
+
+	invalid := []string{
+		"zone/{{ .Datacenter }}",
+		"pool/{{ .Labels }}",
+		`zone/{{ .Zone["a"] }}`,
+		"pool.{{ .Zone }}",
+		"pool/{{ .Zone }",
+		"",
+	}
+	for _, template := range invalid {
+		assert.Error(t, ValidateTagTemplates([]string{template}), "template %q", template)
+	}
+}
+
+func TestResolveTags(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ip-10-0-0-1",
+			Labels: map[string]string{
+				"eks.amazonaws.com/nodegroup":       "gpu.pool",
+				corev1.LabelTopologyZone:            "us-east-1a",
+				corev1.LabelFailureDomainBetaRegion: "us-east-1",
+				corev1.LabelInstanceTypeStable:      "p3.2xlarge",
+			},
+		},
+	}
+	templates := []string{
+		`pool/{{ .Labels["eks.amazonaws.com/nodegroup"] }}`,
+		"zone/{{ .Zone }}",
+		"region/{{ .Region }}",
+		"{{ .InstanceType }}",
+		"host/{{ .NodeName }}",
+		`team/{{ .Labels["example.com/team"] }}`,
+		"static",
+	}
+
+	got := ResolveTags([]string{"static", "cluster"}, templates, node)
+	want := []string{
+		"static",
+		"cluster",
+		"pool/gpu-pool",
+		"zone/us-east-1a",
+		"region/us-east-1",
+		"p3-2xlarge",
+		"host/ip-10-0-0-1",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ResolveTags() mismatch (-want +got): %s", diff)
+	}
+
+	for _, tag := range got {
+		assert.True(t, ValidTag(tag), "tag %q", tag)
+	}
+}
+
+func TestResolveTags_WithoutTemplates(t *testing.T) {
+	tags := []string{"a", "b"}
+	got := ResolveTags(tags, nil, &corev1.Node{})
+	assert.Equal(t, tags, got)
+
+	got[0] = "changed"
+	assert.Equal(t, "a", tags[0], "ResolveTags() modified the static tags")
+}
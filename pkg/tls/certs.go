@@ -1,112 +1,189 @@
 package tls
 
 import (
-	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha1" // #nosec G505 -- RFC 5280 section 4.2.1.2 derives key identifiers with SHA-1
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/asn1"
 	"encoding/pem"
+	"fmt"
 	"math/big"
 	"time"
 )
 
+// KeyAlgorithm is the algorithm of the generated private keys
+type KeyAlgorithm string
+
+const (
+	// ECDSAP256 generates ECDSA keys on the NIST P-256 curve
+	ECDSAP256 KeyAlgorithm = "ECDSA-P256"
+	// RSA generates RSA keys of CertInfo.RSAKeySize bits
+	RSA KeyAlgorithm = "RSA"
+
+	defaultRSAKeySize = 2048
+)
+
 type CertInfo struct {
 	CommonName string
 	DNSNames   []string
+
+	// KeyAlgorithm of the CA and serving keys. Defaults to ECDSAP256.
+	KeyAlgorithm KeyAlgorithm
+	// RSAKeySize is the size in bits of RSA keys. Defaults to 2048, and smaller sizes are rejected.
+	RSAKeySize int
+}
+
+// Bundle holds the PEM encoded serving certificate, its private key and the certificate of the CA that signed it
+type Bundle struct {
+	Cert []byte
+	Key  []byte
+	CA   []byte
+}
+
+// SecretData returns the bundle as the data of a kubernetes.io/tls Secret
+func (b *Bundle) SecretData() map[string][]byte {
+	return map[string][]byte{
+		"tls.crt": b.Cert,
+		"tls.key": b.Key,
+		"ca.crt":  b.CA,
+	}
 }
 
-// CertSetup will generate and return tls certs
-func CertSetup(namespace string, days int, certInfo CertInfo) ([]byte, []byte, []byte, error) {
-	// set up our CA certificate
-	ca := &x509.Certificate{
-		SerialNumber: new(big.Int).Lsh(big.NewInt(1), 128),
-		Subject: pkix.Name{
-			CommonName: namespace + " ca",
-		},
-		NotBefore:             time.Now(),
-		NotAfter:              time.Now().AddDate(0, 0, days),
-		IsCA:                  true,
-		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
-		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
-		BasicConstraintsValid: true,
-	}
-
-	// create our private and public key
-	caPrivKey, err := rsa.GenerateKey(rand.Reader, 2048)
+// NewBundle generates a CA valid for the given number of days, and a serving certificate signed by it for the names of certInfo.
+// The CA private key is discarded, so the CA cannot sign other certificates.
+func NewBundle(namespace string, days int, certInfo CertInfo) (*Bundle, error) {
+	now := time.Now()
+
+	caKey, err := generateKey(certInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA private key: %w", err)
+	}
+
+	caTemplate, err := newTemplate(caKey.Public(), pkix.Name{CommonName: namespace + " ca"}, now, days)
+	if err != nil {
+		return nil, err
+	}
+	caTemplate.IsCA = true
+	caTemplate.BasicConstraintsValid = true
+	caTemplate.MaxPathLenZero = true
+	caTemplate.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, caKey.Public(), caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %w", err)
+	}
+
+	key, err := generateKey(certInfo)
 	if err != nil {
-		return []byte{}, []byte{}, []byte{}, err
+		return nil, fmt.Errorf("failed to generate private key: %w", err)
 	}
 
-	// create the CA
-	caBytes, err := x509.CreateCertificate(rand.Reader, ca, ca, &caPrivKey.PublicKey, caPrivKey)
+	template, err := newTemplate(key.Public(), pkix.Name{CommonName: certInfo.CommonName}, now, days)
 	if err != nil {
-		return []byte{}, []byte{}, []byte{}, err
-	}
-
-	// pem encode
-	caPEM := new(bytes.Buffer)
-	if err = pem.Encode(caPEM, &pem.Block{
-		Type:  "CERTIFICATE",
-		Bytes: caBytes,
-	}); err != nil {
-		return []byte{}, []byte{}, []byte{}, err
-	}
-
-	caPrivKeyPEM := new(bytes.Buffer)
-	if err = pem.Encode(caPrivKeyPEM, &pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(caPrivKey),
-	}); err != nil {
-		return []byte{}, []byte{}, []byte{}, err
-	}
-
-	// set up our server certificate
-	cert := &x509.Certificate{
-		SerialNumber: new(big.Int).Lsh(big.NewInt(1), 128),
-		Subject: pkix.Name{
-			CommonName: certInfo.CommonName,
-		},
-		NotBefore:      time.Now(),
-		NotAfter:       time.Now().AddDate(0, 0, days),
-		AuthorityKeyId: ca.SubjectKeyId,
-		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
-		KeyUsage:       x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
-		IsCA:           false,
-		DNSNames:       certInfo.DNSNames,
-	}
-
-	certPrivKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		return nil, err
+	}
+	template.BasicConstraintsValid = true
+	template.AuthorityKeyId = caTemplate.SubjectKeyId
+	template.DNSNames = certInfo.DNSNames
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	if _, ok := key.(*rsa.PrivateKey); ok {
+		// RSA key exchange encrypts the session key with the certificate key
+		template.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, caTemplate, key.Public(), caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+
+	keyPEM, err := encodeKey(key)
 	if err != nil {
-		return []byte{}, []byte{}, []byte{}, err
+		return nil, err
+	}
+
+	return &Bundle{
+		Cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+		Key:  keyPEM,
+		CA:   pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+	}, nil
+}
+
+func generateKey(certInfo CertInfo) (crypto.Signer, error) {
+	switch certInfo.KeyAlgorithm {
+	case "", ECDSAP256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case RSA:
+		size := certInfo.RSAKeySize
+		if size == 0 {
+			size = defaultRSAKeySize
+		}
+		if size < defaultRSAKeySize {
+			return nil, fmt.Errorf("RSA key size %d is smaller than %d bits", size, defaultRSAKeySize)
+		}
+		return rsa.GenerateKey(rand.Reader, size)
+	default:
+		return nil, fmt.Errorf("unsupported key algorithm %q", certInfo.KeyAlgorithm)
 	}
+}
 
-	certBytes, err := x509.CreateCertificate(rand.Reader, cert, ca, &certPrivKey.PublicKey, caPrivKey)
+func encodeKey(key crypto.Signer) ([]byte, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}), nil
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode private key: %w", err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+}
+
+// newTemplate returns a certificate template with a random serial number and the key identifier of the public key
+func newTemplate(pub crypto.PublicKey, subject pkix.Name, now time.Time, days int) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
 	if err != nil {
-		return []byte{}, []byte{}, []byte{}, err
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
 	}
 
-	certPEM := new(bytes.Buffer)
-	if err = pem.Encode(certPEM, &pem.Block{
-		Type:  "CERTIFICATE",
-		Bytes: certBytes,
-	}); err != nil {
-		return []byte{}, []byte{}, []byte{}, err
+	keyID, err := subjectKeyID(pub)
+	if err != nil {
+		return nil, err
 	}
 
-	certPrivKeyPEM := new(bytes.Buffer)
-	if err = pem.Encode(certPrivKeyPEM, &pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(certPrivKey),
-	}); err != nil {
-		return []byte{}, []byte{}, []byte{}, err
+	return &x509.Certificate{
+		// Serial numbers must be positive
+		SerialNumber: serial.Add(serial, big.NewInt(1)),
+		Subject:      subject,
+		NotBefore:    now,
+		NotAfter:     now.AddDate(0, 0, days),
+		SubjectKeyId: keyID,
+	}, nil
+}
+
+// subjectKeyID returns the SHA-1 hash of the subject public key, as described by RFC 5280 section 4.2.1.2
+func subjectKeyID(pub crypto.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode public key: %w", err)
 	}
 
-	certpool := x509.NewCertPool()
-	certpool.AppendCertsFromPEM(caPEM.Bytes())
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(der, &spki); err != nil {
+		return nil, fmt.Errorf("failed to decode public key: %w", err)
+	}
 
-	return certPEM.Bytes(),
-		certPrivKeyPEM.Bytes(),
-		caPEM.Bytes(),
-		nil
+	sum := sha1.Sum(spki.PublicKey.Bytes) // #nosec G401
+	return sum[:], nil
 }
@@ -0,0 +1,204 @@
+package tls
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	cryptotls "crypto/tls"
+	"crypto/x509"
+	"testing"
+)
+
+func parseBundle(t *testing.T, bundle *Bundle) (*x509.Certificate, *x509.Certificate) {
+	t.Helper()
+
+	ca, err := ParseCertificate(bundle.CA)
+	if err != nil {
+		t.Fatalf("failed to parse CA certificate: %v", err)
+	}
+
+	cert, err := ParseCertificate(bundle.Cert)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	return ca, cert
+}
+
+func TestNewBundleVerifies(t *testing.T) {
+	certInfo := CertInfo{
+		CommonName: "test.test.svc",
+		DNSNames:   []string{"test.test.svc", "test.test.svc.cluster.local"},
+	}
+
+	bundle, err := NewBundle("test", 10, certInfo)
+	if err != nil {
+		t.Fatalf("NewBundle() error = %v", err)
+	}
+	ca, cert := parseBundle(t, bundle)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	for _, name := range certInfo.DNSNames {
+		if _, err := cert.Verify(x509.VerifyOptions{
+			DNSName:   name,
+			Roots:     roots,
+			KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}); err != nil {
+			t.Errorf("Verify() for %s error = %v", name, err)
+		}
+	}
+
+	if _, err := cert.Verify(x509.VerifyOptions{DNSName: "other.test.svc", Roots: roots}); err == nil {
+		t.Error("Verify() should reject a name that is not in the certificate")
+	}
+
+	other, err := NewBundle("test", 10, certInfo)
+	if err != nil {
+		t.Fatalf("NewBundle() error = %v", err)
+	}
+	otherCA, _ := parseBundle(t, other)
+	otherRoots := x509.NewCertPool()
+	otherRoots.AddCert(otherCA)
+
+	if _, err := cert.Verify(x509.VerifyOptions{DNSName: "test.test.svc", Roots: otherRoots}); err == nil {
+		t.Error("Verify() should reject a certificate signed by another CA")
+	}
+
+	if _, err := cryptotls.X509KeyPair(bundle.Cert, bundle.Key); err != nil {
+		t.Errorf("X509KeyPair() error = %v", err)
+	}
+}
+
+func TestNewBundleExtensions(t *testing.T) {
+	bundle, err := NewBundle("test", 10, CertInfo{CommonName: "test.test.svc"})
+	if err != nil {
+		t.Fatalf("NewBundle() error = %v", err)
+	}
+	ca, cert := parseBundle(t, bundle)
+
+	if !ca.IsCA || !ca.MaxPathLenZero {
+		t.Error("CA certificate should be a CA that cannot sign intermediates")
+	}
+	if ca.KeyUsage&x509.KeyUsageCertSign == 0 {
+		t.Error("CA certificate should allow certificate signing")
+	}
+	if len(ca.ExtKeyUsage) != 0 {
+		t.Errorf("CA certificate ExtKeyUsage = %v, want none", ca.ExtKeyUsage)
+	}
+
+	if cert.IsCA {
+		t.Error("serving certificate should not be a CA")
+	}
+	if cert.KeyUsage&x509.KeyUsageCertSign != 0 {
+		t.Error("serving certificate should not allow certificate signing")
+	}
+	if cert.KeyUsage&x509.KeyUsageKeyEncipherment != 0 {
+		t.Error("ECDSA serving certificate should not allow key encipherment")
+	}
+
+	if len(ca.SubjectKeyId) == 0 || len(cert.SubjectKeyId) == 0 {
+		t.Error("certificates should have a subject key identifier")
+	}
+	if !bytes.Equal(cert.AuthorityKeyId, ca.SubjectKeyId) {
+		t.Error("serving certificate authority key identifier should match the CA subject key identifier")
+	}
+
+	if ca.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+		t.Error("CA and serving certificate should have different serial numbers")
+	}
+
+	again, err := NewBundle("test", 10, CertInfo{CommonName: "test.test.svc"})
+	if err != nil {
+		t.Fatalf("NewBundle() error = %v", err)
+	}
+	_, againCert := parseBundle(t, again)
+	if againCert.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+		t.Error("serial numbers should be random")
+	}
+}
+
+func TestNewBundleKeyAlgorithm(t *testing.T) {
+	tests := []struct {
+		name     string
+		certInfo CertInfo
+		wantErr  bool
+		check    func(t *testing.T, cert *x509.Certificate)
+	}{
+		{
+			name:     "ECDSA P-256 by default",
+			certInfo: CertInfo{CommonName: "test"},
+			check: func(t *testing.T, cert *x509.Certificate) {
+				key, ok := cert.PublicKey.(*ecdsa.PublicKey)
+				if !ok || key.Curve != elliptic.P256() {
+					t.Errorf("public key = %T, want ECDSA P-256", cert.PublicKey)
+				}
+			},
+		},
+		{
+			name:     "RSA with the default size",
+			certInfo: CertInfo{CommonName: "test", KeyAlgorithm: RSA},
+			check: func(t *testing.T, cert *x509.Certificate) {
+				key, ok := cert.PublicKey.(*rsa.PublicKey)
+				if !ok || key.N.BitLen() != 2048 {
+					t.Errorf("public key = %T, want 2048 bit RSA", cert.PublicKey)
+				}
+				if cert.KeyUsage&x509.KeyUsageKeyEncipherment == 0 {
+					t.Error("RSA serving certificate should allow key encipherment")
+				}
+			},
+		},
+		{
+			name:     "RSA with a configured size",
+			certInfo: CertInfo{CommonName: "test", KeyAlgorithm: RSA, RSAKeySize: 3072},
+			check: func(t *testing.T, cert *x509.Certificate) {
+				key, ok := cert.PublicKey.(*rsa.PublicKey)
+				if !ok || key.N.BitLen() != 3072 {
+					t.Errorf("public key = %T, want 3072 bit RSA", cert.PublicKey)
+				}
+			},
+		},
+		{
+			name:     "RSA smaller than 2048 bits",
+			certInfo: CertInfo{CommonName: "test", KeyAlgorithm: RSA, RSAKeySize: 1024},
+			wantErr:  true,
+		},
+		{
+			name:     "unsupported algorithm",
+			certInfo: CertInfo{CommonName: "test", KeyAlgorithm: "DSA"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle, err := NewBundle("test", 10, tt.certInfo)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewBundle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			_, cert := parseBundle(t, bundle)
+			tt.check(t, cert)
+
+			if _, err := cryptotls.X509KeyPair(bundle.Cert, bundle.Key); err != nil {
+				t.Errorf("X509KeyPair() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestBundleSecretData(t *testing.T) {
+	bundle := &Bundle{Cert: []byte("cert"), Key: []byte("key"), CA: []byte("ca")}
+	data := bundle.SecretData()
+
+	for key, want := range map[string]string{"tls.crt": "cert", "tls.key": "key", "ca.crt": "ca"} {
+		if got := string(data[key]); got != want {
+			t.Errorf("SecretData()[%s] = %q, want %q", key, got, want)
+		}
+	}
+}
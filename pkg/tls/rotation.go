@@ -0,0 +1,90 @@
+package tls
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"time"
+)
+
+const (
+	// MinValidityDays is the shortest validity of the certificates generated for the webhooks
+	MinValidityDays = 7
+
+	// maxRenewBefore caps how long before expiry a long-lived certificate is renewed
+	maxRenewBefore = 30 * 24 * time.Hour
+	// minRenewAfter is the shortest time a certificate is kept before it is renewed, so that certificates with
+	// a very short lifetime are not reissued, and the pods serving them restarted, on every reconciliation
+	minRenewAfter = time.Hour
+)
+
+// ParseCertificate returns the first certificate of a PEM bundle
+func ParseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	for {
+		var block *pem.Block
+		block, certPEM = pem.Decode(certPEM)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM encoded certificate found")
+		}
+
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// RenewalTime returns when a certificate is due for renewal: a third of its lifetime before it expires, and at most 30 days before.
+// Certificates are kept for at least an hour after they are issued.
+func RenewalTime(cert *x509.Certificate) time.Time {
+	renewBefore := cert.NotAfter.Sub(cert.NotBefore) / 3
+	if renewBefore > maxRenewBefore {
+		renewBefore = maxRenewBefore
+	}
+
+	renewal := cert.NotAfter.Add(-renewBefore)
+	if earliest := cert.NotBefore.Add(minRenewAfter); renewal.Before(earliest) {
+		return earliest
+	}
+
+	return renewal
+}
+
+// CABundle concatenates the CA certificates of the given PEM bundles, dropping duplicates and certificates that expired before now.
+// Keeping the previous CA next to the new one lets clients trust both the old and the new serving certificate while the pods restart.
+func CABundle(now time.Time, bundles ...[]byte) []byte {
+	out := new(bytes.Buffer)
+	seen := map[string]bool{}
+
+	for _, bundle := range bundles {
+		for {
+			var block *pem.Block
+			block, bundle = pem.Decode(bundle)
+			if block == nil {
+				break
+			}
+
+			if block.Type != "CERTIFICATE" || seen[string(block.Bytes)] {
+				continue
+			}
+
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil || now.After(cert.NotAfter) {
+				continue
+			}
+
+			seen[string(block.Bytes)] = true
+			_ = pem.Encode(out, block)
+		}
+	}
+
+	return out.Bytes()
+}
+
+// Fingerprint returns a short digest of a PEM certificate, suitable for a pod template annotation that restarts the pods when the certificate changes
+func Fingerprint(certPEM []byte) string {
+	sum := sha256.Sum256(certPEM)
+	return hex.EncodeToString(sum[:8])
+}
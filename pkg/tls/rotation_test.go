@@ -0,0 +1,101 @@
+package tls
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+)
+
+func countCertificates(t *testing.T, bundle []byte) int {
+	t.Helper()
+
+	count := 0
+	for {
+		var block *pem.Block
+		block, bundle = pem.Decode(bundle)
+		if block == nil {
+			return count
+		}
+		count++
+	}
+}
+
+func TestParseCertificate(t *testing.T) {
+	bundle, err := NewBundle("test", 10, CertInfo{CommonName: "test.svc"})
+	if err != nil {
+		t.Fatalf("NewBundle() error = %v", err)
+	}
+
+	parsed, err := ParseCertificate(append(bundle.Key, bundle.Cert...))
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+	if parsed.Subject.CommonName != "test.svc" {
+		t.Errorf("ParseCertificate() CommonName = %s, want test.svc", parsed.Subject.CommonName)
+	}
+
+	if _, err := ParseCertificate([]byte("not a certificate")); err == nil {
+		t.Error("ParseCertificate() expected an error for invalid PEM data")
+	}
+}
+
+func TestRenewalTime(t *testing.T) {
+	notBefore := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		lifetime time.Duration
+		want     time.Duration
+	}{
+		{"long lived certificates renew 30 days before expiry", 3650 * 24 * time.Hour, 3620 * 24 * time.Hour},
+		{"short lived certificates renew after two thirds of their lifetime", 30 * 24 * time.Hour, 20 * 24 * time.Hour},
+		{"minimum validity", MinValidityDays * 24 * time.Hour, MinValidityDays * 16 * time.Hour},
+		{"very short lived certificates are kept for an hour", time.Minute, time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cert := &x509.Certificate{NotBefore: notBefore, NotAfter: notBefore.Add(tt.lifetime)}
+			if got := RenewalTime(cert).Sub(cert.NotBefore); got != tt.want {
+				t.Errorf("RenewalTime() = %s after issue, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCABundle(t *testing.T) {
+	oldBundle, err := NewBundle("test", 10, CertInfo{CommonName: "test.svc"})
+	if err != nil {
+		t.Fatalf("NewBundle() error = %v", err)
+	}
+
+	newBundle, err := NewBundle("test", 10, CertInfo{CommonName: "test.svc"})
+	if err != nil {
+		t.Fatalf("NewBundle() error = %v", err)
+	}
+	oldCA, newCA := oldBundle.CA, newBundle.CA
+
+	bundle := CABundle(time.Now(), newCA, append(oldCA, newCA...))
+	if got := countCertificates(t, bundle); got != 2 {
+		t.Fatalf("CABundle() has %d certificates, want 2", got)
+	}
+	if !bytes.HasPrefix(bundle, newCA) {
+		t.Error("CABundle() should list the new CA first")
+	}
+
+	if got := countCertificates(t, CABundle(time.Now().AddDate(0, 0, 11), newCA, oldCA)); got != 0 {
+		t.Errorf("CABundle() kept %d expired certificates", got)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	if Fingerprint([]byte("a")) == Fingerprint([]byte("b")) {
+		t.Error("Fingerprint() should differ for different certificates")
+	}
+
+	if got := len(Fingerprint([]byte("a"))); got != 16 {
+		t.Errorf("Fingerprint() length = %d, want 16", got)
+	}
+}